@@ -146,6 +146,8 @@ type DeliveryResponse struct {
 	StatusCode   int       `json:"statusCode"`
 	Success      bool      `json:"success"`
 	DispatchedAt time.Time `json:"dispatchedAt"`
+	// ExternalID identifies what the output created for the alert, e.g. the sys_id of a ServiceNow incident
+	ExternalID string `json:"externalId,omitempty"`
 }
 
 // UpdateAlertStatusOutput is an alias for an alert summary
@@ -28,6 +28,8 @@ const (
 	RuleData DataType = "RuleMatches"
 	// RuleData represents parsed log data that have generated an error while running over rules
 	RuleErrors DataType = "RuleErrors"
+	// ClassificationFailures represents log lines that could not be classified by any parser
+	ClassificationFailures DataType = "ClassificationFailures"
 )
 
 func (d DataType) String() string {
@@ -44,9 +44,11 @@ type LambdaInput struct {
 	// (hence 'Records' being the name of the field), but genericapi will route the
 	// request to the DispatchAlerts handler. This way all requests can be routed
 	// by genericapi without having to inspect the message ahead of time.
-	DispatchAlerts []*DispatchAlertsInput `json:"Records"`
-	DeliverAlert   *DeliverAlertInput     `json:"deliverAlert"`
-	SendTestAlert  *SendTestAlertInput    `json:"sendTestAlert"`
+	DispatchAlerts  []*DispatchAlertsInput `json:"Records"`
+	DeliverAlert    *DeliverAlertInput     `json:"deliverAlert"`
+	SendTestAlert   *SendTestAlertInput    `json:"sendTestAlert"`
+	RouteAlert      *RouteAlertInput       `json:"routeAlert"`
+	SyncAlertStatus *SyncAlertStatusInput  `json:"syncAlertStatus"`
 }
 
 // SendTestAlertInput sends a dummy alert to the specified destinations
@@ -84,6 +86,58 @@ type DeliverAlertInput struct {
 	OutputIds []string `json:"outputIds" validate:"gt=0,dive,uuid4"`
 }
 
+// RouteAlertInput reports the destinations an alert would be sent to, without sending it
+//
+// Example:
+// {
+//     "routeAlert": {
+//         "alert": {
+//             "analysisId": "AWS.GuardDuty.HighVolFindings",
+//             "type": "RULE",
+//             "createdAt": "2020-09-01T21:10:41.80307Z",
+//             "severity": "HIGH",
+//             "logTypes": ["AWS.GuardDuty"],
+//             "tags": ["prod"]
+//         }
+//     }
+// }
+type RouteAlertInput struct {
+	Alert *Alert `json:"alert" validate:"required"`
+}
+
+// RouteAlertOutput is the list of destinations an alert would be sent to
+type RouteAlertOutput = []*RoutedOutput
+
+// RoutedOutput is a destination an alert would be sent to and the reason it was selected
+type RoutedOutput struct {
+	OutputID    string `json:"outputId"`
+	DisplayName string `json:"displayName"`
+	OutputType  string `json:"outputType"`
+	// Reason is one of "alert output override", "routing rule" or "default for severity"
+	Reason string `json:"reason"`
+	// RuleIndex is the index of the matching routing rule of the output, if any
+	RuleIndex *int `json:"ruleIndex,omitempty"`
+}
+
+// SyncAlertStatusInput updates the status of open and triaged alerts from the state of the incidents created for them
+//
+// Example:
+// {
+//     "syncAlertStatus": {
+//         "lookbackDays": 7
+//     }
+// }
+type SyncAlertStatusInput struct {
+	// LookbackDays limits the sync to alerts created in the last days, 7 if not set
+	LookbackDays int `json:"lookbackDays" validate:"omitempty,min=1,max=90"`
+}
+
+// SyncAlertStatusOutput reports how many alerts were checked and updated
+type SyncAlertStatusOutput struct {
+	AlertsChecked int `json:"alertsChecked"`
+	AlertsUpdated int `json:"alertsUpdated"`
+}
+
 // DispatchAlertsInput is an alias for an SQSMessage
 //
 // Example:
@@ -158,6 +212,12 @@ type Alert struct {
 	// Title is the optional title for the alert generated by Python Rules engine
 	Title *string `json:"title,omitempty"`
 
+	// DedupString is the deduplication string of the events that triggered the alert.
+	DedupString *string `json:"dedupString,omitempty"`
+
+	// EventCount is the number of events in the alert at the time of delivery.
+	EventCount int `json:"eventCount,omitempty"`
+
 	// RetryCount is a counter for the nubmer of times we have attempted to send this alert to a destination.
 	RetryCount int `json:"retryCount,omitempty"`
 
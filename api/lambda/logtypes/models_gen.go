@@ -20,9 +20,25 @@ package logtypesapi
  * along with this program.  If not, see <https://www.gnu.org/licenses/>.
  */
 
+import "time"
+
 // LogTypesAPI available endpoints
 type LogTypesAPI interface {
 	ListAvailableLogTypes() (ListAvailableLogTypesResponse, error)
+
+	GetCustomLog(input GetCustomLogInput) (GetCustomLogResponse, error)
+
+	PutCustomLog(input PutCustomLogInput) (PutCustomLogResponse, error)
+
+	DelCustomLog(input DelCustomLogInput) (DelCustomLogResponse, error)
+
+	ListCustomLogs() (ListCustomLogsResponse, error)
+
+	GetRetention(input GetRetentionInput) (GetRetentionResponse, error)
+
+	PutRetention(input PutRetentionInput) (PutRetentionResponse, error)
+
+	ListRetention() (ListRetentionResponse, error)
 }
 
 // Models for LogTypesAPI
@@ -30,8 +46,84 @@ type LogTypesAPI interface {
 // LogTypesAPIPayload is the payload for calls to LogTypesAPI endpoints.
 type LogTypesAPIPayload struct {
 	ListAvailableLogTypes *struct{}
+	GetCustomLog          *GetCustomLogInput
+	PutCustomLog          *PutCustomLogInput
+	DelCustomLog          *DelCustomLogInput
+	ListCustomLogs        *struct{}
+	GetRetention          *GetRetentionInput
+	PutRetention          *PutRetentionInput
+	ListRetention         *struct{}
+}
+
+type DelCustomLogInput struct {
+	LogType  string `json:"logType" validate:"required"`
+	Revision int64  `json:"revision" validate:"required,min=1"`
+}
+
+type DelCustomLogResponse struct{}
+
+type GetCustomLogInput struct {
+	LogType string `json:"logType" validate:"required"`
+}
+
+type GetCustomLogResponse struct {
+	LogType   string    `json:"logType" validate:"required"`
+	Revision  int64     `json:"revision" validate:"required,min=1"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	LogSpec   string    `json:"logSpec" validate:"required"`
+}
+
+type GetRetentionInput struct {
+	LogType string `json:"logType" validate:"required"`
+}
+
+type GetRetentionResponse struct {
+	LogType       string    `json:"logType" validate:"required"`
+	RetentionDays int       `json:"retentionDays" validate:"min=0"`
+	UpdatedAt     time.Time `json:"updatedAt"`
 }
 
 type ListAvailableLogTypesResponse struct {
 	LogTypes []string `json:"logTypes"`
 }
+
+type ListCustomLogsResponse struct {
+	CustomLogs []struct {
+		LogType   string    `json:"logType" validate:"required"`
+		Revision  int64     `json:"revision" validate:"required,min=1"`
+		UpdatedAt time.Time `json:"updatedAt"`
+		LogSpec   string    `json:"logSpec" validate:"required"`
+	} `json:"customLogs"`
+}
+
+type ListRetentionResponse struct {
+	Retention []struct {
+		LogType       string    `json:"logType" validate:"required"`
+		RetentionDays int       `json:"retentionDays" validate:"min=0"`
+		UpdatedAt     time.Time `json:"updatedAt"`
+	} `json:"retention"`
+}
+
+type PutCustomLogInput struct {
+	LogType  string `json:"logType" validate:"required"`
+	Revision int64  `json:"revision" validate:"omitempty,min=1"`
+	LogSpec  string `json:"logSpec" validate:"required"`
+}
+
+type PutCustomLogResponse struct {
+	LogType   string    `json:"logType" validate:"required"`
+	Revision  int64     `json:"revision" validate:"required,min=1"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	LogSpec   string    `json:"logSpec" validate:"required"`
+}
+
+type PutRetentionInput struct {
+	LogType       string `json:"logType" validate:"required"`
+	RetentionDays int    `json:"retentionDays" validate:"min=0"`
+}
+
+type PutRetentionResponse struct {
+	LogType       string    `json:"logType" validate:"required"`
+	RetentionDays int       `json:"retentionDays" validate:"min=0"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
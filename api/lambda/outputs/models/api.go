@@ -43,10 +43,12 @@ type LambdaInput struct {
 //     }
 // }
 type AddOutputInput struct {
-	UserID             *string       `json:"userId" validate:"required,uuid4"`
-	DisplayName        *string       `json:"displayName" validate:"required,min=1,excludesall='<>&\""`
-	OutputConfig       *OutputConfig `json:"outputConfig" validate:"required"`
-	DefaultForSeverity []*string     `json:"defaultForSeverity"`
+	UserID             *string        `json:"userId" validate:"required,uuid4"`
+	DisplayName        *string        `json:"displayName" validate:"required,min=1,excludesall='<>&\""`
+	OutputConfig       *OutputConfig  `json:"outputConfig" validate:"required"`
+	DefaultForSeverity []*string      `json:"defaultForSeverity"`
+	RoutingRules       []*RoutingRule `json:"routingRules" validate:"omitempty,dive,required"`
+	MessageTemplate    *string        `json:"messageTemplate" validate:"omitempty,messagetemplate"`
 }
 
 // AddOutputOutput returns a randomly generated UUID for the output.
@@ -81,11 +83,13 @@ type DeleteOutputInput struct {
 //     }
 // }
 type UpdateOutputInput struct {
-	UserID             *string       `json:"userId" validate:"required,uuid4"`
-	DisplayName        *string       `json:"displayName" validate:"omitempty,min=1,excludesall='<>&\""`
-	OutputID           *string       `json:"outputId" validate:"required,uuid4"`
-	OutputConfig       *OutputConfig `json:"outputConfig"`
-	DefaultForSeverity []*string     `json:"defaultForSeverity"`
+	UserID             *string        `json:"userId" validate:"required,uuid4"`
+	DisplayName        *string        `json:"displayName" validate:"omitempty,min=1,excludesall='<>&\""`
+	OutputID           *string        `json:"outputId" validate:"required,uuid4"`
+	OutputConfig       *OutputConfig  `json:"outputConfig"`
+	DefaultForSeverity []*string      `json:"defaultForSeverity"`
+	RoutingRules       []*RoutingRule `json:"routingRules" validate:"omitempty,dive,required"`
+	MessageTemplate    *string        `json:"messageTemplate" validate:"omitempty,messagetemplate"`
 }
 
 // UpdateOutputOutput returns the new updated output
@@ -161,6 +165,68 @@ type AlertOutput struct {
 
 	// DefaultForSeverity defines the alert severities that will be forwarded through this output
 	DefaultForSeverity []*string `json:"defaultForSeverity"`
+
+	// RoutingRules are evaluated in order for alerts without explicit outputs.
+	// The first matching rule decides if an alert is sent through this output.
+	// If no rule matches, DefaultForSeverity applies.
+	RoutingRules []*RoutingRule `json:"routingRules"`
+
+	// MessageTemplate is a Go text/template that replaces the default message of the output.
+	// It is executed with the alert as data, e.g. "{{ .Title }} ({{ .EventCount }} events)"
+	MessageTemplate *string `json:"messageTemplate"`
+}
+
+const (
+	// RoutingActionRoute sends alerts matching a routing rule through the output
+	RoutingActionRoute = "route"
+	// RoutingActionSkip does not send alerts matching a routing rule through the output
+	RoutingActionSkip = "skip"
+)
+
+// RoutingRule matches alerts to route through an output.
+//
+// An alert matches a rule if it matches all the conditions set in the rule.
+// Each condition matches if the alert matches any of its values.
+//
+// Example:
+// {
+//     "action": "route",
+//     "severities": ["HIGH", "CRITICAL"],
+//     "logTypes": ["Okta.*"],
+//     "tags": ["prod"],
+//     "timeOfDay": {
+//         "start": "18:00",
+//         "end": "09:00",
+//         "timezone": "America/New_York"
+//     }
+// }
+type RoutingRule struct {
+	// Action is either "route" or "skip"
+	Action string `json:"action" validate:"oneof=route skip"`
+	// Severities matches the alert severity
+	Severities []string `json:"severities,omitempty" validate:"omitempty,dive,oneof=INFO LOW MEDIUM HIGH CRITICAL"`
+	// AlertTypes matches the alert type
+	AlertTypes []string `json:"alertTypes,omitempty" validate:"omitempty,dive,oneof=RULE POLICY"`
+	// RuleIDs matches the id of the rule or policy using glob patterns, e.g. "AWS.GuardDuty.*"
+	RuleIDs []string `json:"ruleIds,omitempty" validate:"omitempty,dive,required,glob"`
+	// Tags matches the tags of the rule or policy
+	Tags []string `json:"tags,omitempty" validate:"omitempty,dive,required"`
+	// LogTypes matches the log types of the alert using glob patterns, e.g. "Okta.*"
+	LogTypes []string `json:"logTypes,omitempty" validate:"omitempty,dive,required,glob"`
+	// TimeOfDay matches the time the alert was created
+	TimeOfDay *TimeOfDay `json:"timeOfDay,omitempty"`
+}
+
+// TimeOfDay is a daily time window.
+// If End is before Start, the window wraps around midnight.
+// If End equals Start, the window spans the whole day.
+type TimeOfDay struct {
+	// Start is the start of the window in 24-hour format, e.g. "09:00"
+	Start string `json:"start" validate:"required,clock"`
+	// End is the end of the window (exclusive) in 24-hour format, e.g. "17:30"
+	End string `json:"end" validate:"required,clock"`
+	// Timezone is the IANA time zone of the window, UTC if not set
+	Timezone string `json:"timezone,omitempty" validate:"omitempty,timezone"`
 }
 
 // OutputConfig contains the configuration for the output
@@ -194,6 +260,18 @@ type OutputConfig struct {
 
 	// CustomWebhook contains the configuration for a Custom Webhook alert output
 	CustomWebhook *CustomWebhookConfig `json:"customWebhook,omitempty"`
+
+	// Email contains the configuration for an SMTP Email alert output
+	Email *EmailConfig `json:"email,omitempty"`
+
+	// SplunkHEC contains the configuration for a Splunk HTTP Event Collector alert output
+	SplunkHEC *SplunkHECConfig `json:"splunkHec,omitempty"`
+
+	// Syslog contains the configuration for a syslog alert output
+	Syslog *SyslogConfig `json:"syslog,omitempty"`
+
+	// ServiceNow contains the configuration for a ServiceNow incident alert output
+	ServiceNow *ServiceNowConfig `json:"serviceNow,omitempty"`
 }
 
 // SlackConfig defines options for each Slack output.
@@ -252,3 +330,82 @@ type AsanaConfig struct {
 type CustomWebhookConfig struct {
 	WebhookURL string `json:"webhookURL" validate:"omitempty,url"`
 }
+
+const (
+	// EmailTLSModeStartTLS upgrades the SMTP connection to TLS with the STARTTLS command
+	EmailTLSModeStartTLS = "starttls"
+	// EmailTLSModeImplicit connects to the SMTP server over TLS
+	EmailTLSModeImplicit = "tls"
+)
+
+// EmailConfig defines options for each Email output
+type EmailConfig struct {
+	// Host is the hostname of the SMTP server
+	Host string `json:"host" validate:"omitempty,hostname|ip"`
+	// Port is the port of the SMTP server, 587 for STARTTLS and 465 for implicit TLS if not set
+	Port int `json:"port" validate:"omitempty,min=1,max=65535"`
+	// TLSMode is either "starttls" or "tls", "starttls" if not set
+	TLSMode string `json:"tlsMode" validate:"omitempty,oneof=starttls tls"`
+	// UserName is the user to authenticate to the SMTP server, no authentication is performed if not set
+	UserName string `json:"userName"`
+	// Password is the password to authenticate to the SMTP server
+	Password string `json:"password"`
+	// From is the sender address of the emails
+	From string `json:"from" validate:"omitempty,email"`
+	// To is the list of recipient addresses
+	To []string `json:"to" validate:"omitempty,dive,email"`
+	// Cc is the list of carbon copy recipient addresses
+	Cc []string `json:"cc" validate:"omitempty,dive,email"`
+}
+
+// SplunkHECConfig defines options for each Splunk HTTP Event Collector output
+type SplunkHECConfig struct {
+	// URL is the base URL of the HTTP Event Collector, e.g. https://splunk.example.com:8088
+	URL string `json:"url" validate:"omitempty,url"`
+	// Token is the HTTP Event Collector token
+	Token string `json:"token"`
+	// Index is the index to store alerts in, the default index of the token if not set
+	Index string `json:"index"`
+	// Source is the source of the events, "panther" if not set
+	Source string `json:"source"`
+	// SourceType is the source type of the events, "panther:alert" if not set
+	SourceType string `json:"sourceType"`
+	// CACertificate is a PEM encoded certificate to trust in addition to the system roots
+	CACertificate string `json:"caCertificate" validate:"omitempty,pemcert"`
+}
+
+const (
+	// SyslogProtocolTCP sends syslog messages over plain TCP
+	SyslogProtocolTCP = "tcp"
+	// SyslogProtocolTLS sends syslog messages over TLS
+	SyslogProtocolTLS = "tls"
+)
+
+// SyslogConfig defines options for each syslog output
+type SyslogConfig struct {
+	// Host is the hostname of the syslog server
+	Host string `json:"host" validate:"omitempty,hostname|ip"`
+	// Port is the port of the syslog server, 6514 for TLS and 514 for TCP if not set
+	Port int `json:"port" validate:"omitempty,min=1,max=65535"`
+	// Protocol is either "tls" or "tcp", "tls" if not set
+	Protocol string `json:"protocol" validate:"omitempty,oneof=tcp tls"`
+	// CACertificate is a PEM encoded certificate to trust in addition to the system roots
+	CACertificate string `json:"caCertificate" validate:"omitempty,pemcert"`
+	// AppName is the APP-NAME of the syslog messages, "panther" if not set
+	AppName string `json:"appName" validate:"omitempty,max=48,printascii,excludes= "`
+	// Facility is the facility code of the syslog messages, 13 (log audit) if not set.
+	// It is a pointer so that facility 0 (kernel messages) can be set.
+	Facility *int `json:"facility,omitempty" validate:"omitempty,min=0,max=23"`
+}
+
+// ServiceNowConfig defines options for each ServiceNow output
+type ServiceNowConfig struct {
+	// InstanceURL is the URL of the ServiceNow instance, e.g. https://example.service-now.com
+	InstanceURL string `json:"instanceUrl" validate:"omitempty,url"`
+	UserName    string `json:"userName"`
+	Password    string `json:"password"`
+	// AssignmentGroup is the sys_id or name of the group incidents are assigned to
+	AssignmentGroup string `json:"assignmentGroup"`
+	// Urgency maps alert severities to incident urgencies (1 is high, 3 is low), overriding the defaults
+	Urgency map[string]int `json:"urgency" validate:"omitempty,dive,keys,oneof=INFO LOW MEDIUM HIGH CRITICAL,endkeys,min=1,max=3"`
+}
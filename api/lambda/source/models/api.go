@@ -85,6 +85,13 @@ type PutIntegrationSettings struct {
 	LogTypes           []string `json:"logTypes" validate:"omitempty,min=1"`
 
 	SqsConfig *SqsConfig `json:"sqsConfig,omitempty"`
+
+	Transforms []FieldTransform `json:"transforms,omitempty" validate:"omitempty,dive"`
+	Multiline  *MultilineConfig `json:"multiline,omitempty"`
+
+	S3PrefixLogTypes []S3PrefixLogType `json:"s3PrefixLogTypes,omitempty" validate:"omitempty,dive"`
+
+	Lateness []LatenessPolicy `json:"lateness,omitempty" validate:"omitempty,dive"`
 }
 
 //
@@ -109,6 +116,13 @@ type UpdateIntegrationSettingsInput struct {
 	LogTypes           []string `json:"logTypes" validate:"omitempty,min=1"`
 
 	SqsConfig *SqsConfig `json:"sqsConfig,omitempty"`
+
+	Transforms []FieldTransform `json:"transforms,omitempty" validate:"omitempty,dive"`
+	Multiline  *MultilineConfig `json:"multiline,omitempty"`
+
+	S3PrefixLogTypes []S3PrefixLogType `json:"s3PrefixLogTypes,omitempty" validate:"omitempty,dive"`
+
+	Lateness []LatenessPolicy `json:"lateness,omitempty" validate:"omitempty,dive"`
 }
 
 // DeleteIntegrationInput is used to delete a specific item from the database.
@@ -19,6 +19,8 @@ package models
  */
 
 import (
+	"path"
+	"strings"
 	"time"
 )
 
@@ -61,6 +63,20 @@ type SourceIntegrationMetadata struct {
 	LogProcessingRole  string     `json:"logProcessingRole,omitempty"`
 	StackName          string     `json:"stackName,omitempty"`
 	SqsConfig          *SqsConfig `json:"sqsConfig,omitempty"`
+
+	// Transforms applied to the fields of events from this source before they are stored
+	Transforms []FieldTransform `json:"transforms,omitempty"`
+	// TransformsHashKey is the encrypted data key used by hash transforms
+	TransformsHashKey []byte `genericapi:"redact" json:"transformsHashKey,omitempty"`
+
+	// Multiline joins consecutive lines of the source into a single event
+	Multiline *MultilineConfig `json:"multiline,omitempty"`
+
+	// S3PrefixLogTypes assigns a single log type to the objects of an S3 source matching a prefix or pattern
+	S3PrefixLogTypes []S3PrefixLogType `json:"s3PrefixLogTypes,omitempty"`
+
+	// Lateness decides how late-arriving and future events of each log type are partitioned
+	Lateness []LatenessPolicy `json:"lateness,omitempty"`
 }
 
 func (info *SourceIntegration) RequiredLogTypes() (logTypes []string) {
@@ -73,6 +89,17 @@ func (info *SourceIntegration) RequiredLogTypes() (logTypes []string) {
 	}
 }
 
+// S3ObjectLogType returns the log type assigned to an S3 object key by the first matching S3PrefixLogTypes entry.
+// It returns an empty string if no entry matches and the object should be classified by all the source log types.
+func (info *SourceIntegration) S3ObjectLogType(objectKey string) string {
+	for i := range info.S3PrefixLogTypes {
+		if info.S3PrefixLogTypes[i].Match(objectKey) {
+			return info.S3PrefixLogTypes[i].LogType
+		}
+	}
+	return ""
+}
+
 func (info *SourceIntegration) IsLogAnalysisIntegration() bool {
 	switch integType := info.IntegrationType; integType {
 	case IntegrationTypeAWSScan:
@@ -132,3 +159,72 @@ type SqsConfig struct {
 	// THe URL of the SQS queue
 	QueueURL string `json:"queueUrl"`
 }
+
+// FieldTransform is a rule to redact a field of the events of a log type
+type FieldTransform struct {
+	// The log type of the events to transform
+	LogType string `json:"logType" validate:"required"`
+	// The dot-separated JSON path of the field (e.g. `userIdentity.userName`)
+	Path string `json:"path" validate:"required"`
+	// The action to apply to the field value
+	Action string `json:"action" validate:"oneof=drop replace hash"`
+	// The value to replace the field with if the action is `replace`
+	Value string `json:"value,omitempty"`
+}
+
+// S3PrefixLogType maps the S3 objects matching a key prefix or a glob pattern to exactly one log type.
+type S3PrefixLogType struct {
+	// An S3 key prefix (e.g. `logs/nginx/`) or a glob pattern matching the whole key (e.g. `logs/*/access-*.log`).
+	// Patterns contain at least one of `*`, `?` or `[` and use the syntax of path.Match, `*` does not match `/`.
+	Prefix string `json:"prefix" validate:"required,max=1024"`
+	// The log type of the matching objects
+	LogType string `json:"logType" validate:"required"`
+}
+
+// IsPattern checks if Prefix is a glob pattern
+func (p *S3PrefixLogType) IsPattern() bool {
+	return strings.ContainsAny(p.Prefix, "*?[")
+}
+
+// Match checks if an S3 object key matches the prefix or pattern
+func (p *S3PrefixLogType) Match(objectKey string) bool {
+	if !p.IsPattern() {
+		return strings.HasPrefix(objectKey, p.Prefix)
+	}
+	// Invalid patterns are rejected when the source is saved
+	match, _ := path.Match(p.Prefix, objectKey)
+	return match
+}
+
+// MultilineConfig defines how consecutive lines of a source are framed into multi-line events (ie stack traces).
+// A line starts a new event if it matches EventStartPattern and is not indented when IndentedContinuation is set.
+// All other lines are appended to the current event.
+// The messages of CloudWatch Logs subscription envelopes are framed within each envelope,
+// JSON and Zeek TSV streams are not framed.
+type MultilineConfig struct {
+	// Regular expression matching the first line of each event
+	EventStartPattern string `json:"eventStartPattern,omitempty" validate:"omitempty,max=1000"`
+	// Lines starting with a space or a tab continue the current event
+	IndentedContinuation bool `json:"indentedContinuation,omitempty"`
+	// The maximum number of lines in an event
+	MaxLines int `json:"maxLines,omitempty" validate:"omitempty,min=1,max=100000"`
+	// The maximum size of an event in bytes
+	MaxBytes int `json:"maxBytes,omitempty" validate:"omitempty,min=1,max=10485760"`
+	// The time to wait for the next line of an event before it is emitted
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty" validate:"omitempty,min=1,max=900"`
+}
+
+// LatenessPolicy defines how the events of a log type are partitioned when they arrive late or have timestamps
+// in the future. Log types without a policy are partitioned by event time.
+type LatenessPolicy struct {
+	// The log type of the events
+	LogType string `json:"logType" validate:"required"`
+	// The timestamp used to partition events, either `event_time` (default) or `parse_time`
+	PartitionBy string `json:"partitionBy,omitempty" validate:"omitempty,oneof=event_time parse_time"`
+	// Events delayed by more than this are stored in the partition of their parse time (0 disables the limit)
+	MaxDelaySeconds int `json:"maxDelaySeconds,omitempty" validate:"omitempty,min=0"`
+	// Events with timestamps ahead of their parse time by more than this are handled as future events
+	MaxFutureSkewSeconds int `json:"maxFutureSkewSeconds,omitempty" validate:"omitempty,min=0"`
+	// The action for future events: `allow` (default), `clamp` to the parse time or `quarantine`
+	FutureEvents string `json:"futureEvents,omitempty" validate:"omitempty,oneof=allow clamp quarantine"`
+}
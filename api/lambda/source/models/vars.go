@@ -32,4 +32,23 @@ const (
 	StatusOK = "ok"
 	// StatusScanning is the status set while a scan is underway.
 	StatusScanning = "scanning"
+
+	// FieldTransformDrop removes the field from events
+	FieldTransformDrop = "drop"
+	// FieldTransformReplace replaces the field value with a constant
+	FieldTransformReplace = "replace"
+	// FieldTransformHash replaces the field value with its HMAC-SHA256 hash
+	FieldTransformHash = "hash"
+
+	// PartitionByEventTime stores events in the partition of their event time
+	PartitionByEventTime = "event_time"
+	// PartitionByParseTime stores events in the partition of their parse time
+	PartitionByParseTime = "parse_time"
+
+	// FutureEventsAllow keeps the event time of events with timestamps in the future
+	FutureEventsAllow = "allow"
+	// FutureEventsClamp sets the event time of events with timestamps in the future to their parse time
+	FutureEventsClamp = "clamp"
+	// FutureEventsQuarantine stores events with timestamps in the future with the classification failures
+	FutureEventsQuarantine = "quarantine"
 )
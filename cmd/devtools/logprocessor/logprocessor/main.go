@@ -32,6 +32,7 @@ import (
 	"github.com/panther-labs/panther/api/lambda/source/models"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/enrichment"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
 )
@@ -52,6 +53,7 @@ var (
 	FILE            = flag.String("file", "", "The file to process (assumed to be gzipped).")
 	LOGTYPE         = flag.String("logtype", "", "The logType.")
 	MEMORYSIZE      = flag.Int("lambdaSize", 1024, "The memory size of the lambda")
+	ENRICHMENTDATA  = flag.String("enrichment-data", "", "Directory with GeoIP databases and threat intel lists to enrich events with")
 
 	VERBOSE = flag.Bool("verbose", false, "verbose logging")
 
@@ -141,9 +143,20 @@ func main() {
 	jsonAPI := common.BuildJSON()
 
 	// Use the global registry
-	dest := destinations.CreateS3Destination(jsonAPI)
+	resolver := registry.NativeLogTypesResolver()
+	dest := destinations.CreateS3Destination(resolver, jsonAPI)
 
-	newProcessor := processor.NewFactory(registry.NativeLogTypesResolver())
+	if *ENRICHMENTDATA != "" {
+		enricher, err := enrichment.Load(*ENRICHMENTDATA)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if enricher != nil {
+			processor.Enricher = enricher
+		}
+	}
+
+	newProcessor := processor.NewFactory(resolver)
 	err = processor.Process(streamChan, dest, newProcessor)
 	if err != nil {
 		log.Fatal(err)
@@ -0,0 +1,342 @@
+package replay
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
+	"github.com/panther-labs/panther/pkg/awsbatch/s3batch"
+)
+
+const (
+	pageSize = 1000
+	// the max size of compressed output buffers held in memory for each object
+	maxBufferedMemBytes = 512 * 1024 * 1024
+	// the prefix of the manifests listing the output files of each replayed object in the processed data bucket
+	manifestPrefix = "replay/"
+	// max time spent retrying the deletion of stale output files
+	maxDeleteElapsedTime = 2 * time.Minute
+)
+
+type Stats struct {
+	NumFiles uint64
+	NumBytes uint64
+}
+
+// Replay reprocesses the raw S3 objects of a source that were last modified in [Start, End).
+//
+// Output files have deterministic keys derived from the key of each input object so that replaying
+// the same objects again overwrites previous output instead of duplicating rows in Glue partitions.
+// The keys of the output files of each input object are recorded in a manifest so that files of
+// previous replays that were not overwritten (i.e. because events moved to other partitions) are deleted.
+// Notifications for output files are sent directly to the data catalog updater queue so that
+// partitions are created without sending the replayed events to the rules engine again.
+//
+// Output files written by the log processor have random keys and hold events from many input objects,
+// so rows already stored by the log processor for a replayed object are not removed.
+type Replay struct {
+	SourceID string
+	Start    time.Time
+	End      time.Time
+	// If non-zero, limit the number of objects to replay
+	Limit uint64
+	// The bucket to write processed data to
+	ProcessedDataBucket string
+	// The URL of the data catalog updater queue
+	UpdaterQueueURL string
+
+	Resolver   logtypes.Resolver
+	S3Uploader s3manageriface.UploaderAPI
+	// The client used to read and write manifests and to delete stale files in the processed data bucket
+	S3Client  s3iface.S3API
+	SQSClient sqsiface.SQSAPI
+	Logger    *zap.Logger
+
+	// used in tests
+	loadSource     func(id string) (*models.SourceIntegration, error)
+	sourceS3Client func(src *models.SourceIntegration, bucket string) (s3iface.S3API, error)
+	processFunc    processor.ProcessFunc
+}
+
+// Run replays all matching objects serially and returns on the first error.
+func (r *Replay) Run(stats *Stats) error {
+	if !r.Start.Before(r.End) {
+		return errors.Errorf("invalid time range [%s, %s)", r.Start, r.End)
+	}
+	loadSource := r.loadSource
+	if loadSource == nil {
+		loadSource = sources.LoadSource
+	}
+	src, err := loadSource(r.SourceID)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to load source %q", r.SourceID)
+	}
+	if src.IntegrationType != models.IntegrationTypeAWS3 {
+		return errors.Errorf("source %q is of type %q, only %q sources can be replayed",
+			r.SourceID, src.IntegrationType, models.IntegrationTypeAWS3)
+	}
+	sourceS3Client := r.sourceS3Client
+	if sourceS3Client == nil {
+		sourceS3Client = sources.SourceS3Client
+	}
+	s3Client, err := sourceS3Client(src, src.S3Bucket)
+	if err != nil {
+		return err
+	}
+
+	var replayErr error
+	err = r.listObjects(s3Client, src, func(object *s3.Object) bool {
+		if replayErr = r.replayObject(s3Client, src, aws.StringValue(object.Key)); replayErr != nil {
+			return false
+		}
+		stats.NumFiles++
+		stats.NumBytes += uint64(aws.Int64Value(object.Size))
+		return true
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list s3://%s/%s", src.S3Bucket, src.S3Prefix)
+	}
+	return replayErr
+}
+
+// listObjects calls fn for each non-empty object of the source modified in [Start, End) until fn returns false.
+func (r *Replay) listObjects(s3Client s3iface.S3API, src *models.SourceIntegration, fn func(object *s3.Object) bool) error {
+	limit := r.Limit
+	if limit == 0 {
+		limit = math.MaxUint64
+	}
+	numObjects := uint64(0)
+	input := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(src.S3Bucket),
+		Prefix:  aws.String(src.S3Prefix),
+		MaxKeys: aws.Int64(pageSize),
+	}
+	return s3Client.ListObjectsV2Pages(input, func(page *s3.ListObjectsV2Output, morePages bool) bool {
+		for _, object := range page.Contents {
+			if aws.Int64Value(object.Size) == 0 { // skip 'directories' and empty files
+				continue
+			}
+			modified := aws.TimeValue(object.LastModified)
+			if modified.Before(r.Start) || !modified.Before(r.End) {
+				continue
+			}
+			if !fn(object) {
+				return false
+			}
+			numObjects++
+			if numObjects >= limit {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func (r *Replay) replayObject(s3Client s3iface.S3API, src *models.SourceIntegration, key string) error {
+	r.Logger.Debug("replaying object", zap.String("bucket", src.S3Bucket), zap.String("key", key))
+	stream, err := sources.ReadS3Object(s3Client, src, src.S3Bucket, key)
+	if err != nil {
+		return err
+	}
+	streams := make(chan *common.DataStream, 1)
+	streams <- stream
+	close(streams)
+
+	id := objectID(src.S3Bucket, key)
+	manifestKey := path.Join(manifestPrefix, src.IntegrationID, id+".json")
+	previousKeys, err := r.readManifest(manifestKey)
+	if err != nil {
+		return err
+	}
+	uploader := &recordingUploader{
+		UploaderAPI: r.S3Uploader,
+		keys:        make(map[string]bool),
+	}
+	destination := destinations.CreateIdempotentS3Destination(r.Resolver, uploader,
+		&updaterQueue{
+			sqsClient: r.SQSClient,
+			queueURL:  r.UpdaterQueueURL,
+		},
+		r.ProcessedDataBucket,
+		"", // notifications are not sent to SNS (see updaterQueue)
+		id,
+		maxBufferedMemBytes,
+		common.BuildJSON(),
+	)
+	processFunc := r.processFunc
+	if processFunc == nil {
+		newProcessor := processor.NewFactory(r.Resolver)
+		processFunc = func(streams <-chan *common.DataStream, dest destinations.Destination) error {
+			return processor.Process(streams, dest, newProcessor)
+		}
+	}
+	if err := processFunc(streams, destination); err != nil {
+		return errors.WithMessagef(err, "failed to replay s3://%s/%s", src.S3Bucket, key)
+	}
+	return r.removeStaleFiles(manifestKey, previousKeys, uploader.keys)
+}
+
+// removeStaleFiles deletes the output files of previous replays that were not overwritten by the current replay.
+// The manifest lists the files of both replays until the stale files are deleted, so that no file is left behind
+// if the replay is interrupted.
+func (r *Replay) removeStaleFiles(manifestKey string, previousKeys []string, keys map[string]bool) error {
+	var staleKeys []string
+	for _, key := range previousKeys {
+		if !keys[key] {
+			staleKeys = append(staleKeys, key)
+		}
+	}
+	if len(staleKeys) == 0 {
+		return r.writeManifest(manifestKey, keys, nil)
+	}
+	if err := r.writeManifest(manifestKey, keys, staleKeys); err != nil {
+		return err
+	}
+	objects := make([]*s3.ObjectIdentifier, len(staleKeys))
+	for i, key := range staleKeys {
+		objects[i] = &s3.ObjectIdentifier{Key: aws.String(key)}
+	}
+	r.Logger.Debug("deleting stale replay output", zap.Strings("keys", staleKeys))
+	err := s3batch.DeleteObjects(r.S3Client, maxDeleteElapsedTime, &s3.DeleteObjectsInput{
+		Bucket: aws.String(r.ProcessedDataBucket),
+		Delete: &s3.Delete{Objects: objects},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete stale replay output of %s", manifestKey)
+	}
+	return r.writeManifest(manifestKey, keys, nil)
+}
+
+// replayManifest lists the output files of a replayed object
+type replayManifest struct {
+	Keys []string `json:"keys"`
+}
+
+func (r *Replay) readManifest(key string) ([]string, error) {
+	output, err := r.S3Client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(r.ProcessedDataBucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read replay manifest %s", key)
+	}
+	defer output.Body.Close()
+	manifest := replayManifest{}
+	if err := json.NewDecoder(output.Body).Decode(&manifest); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode replay manifest %s", key)
+	}
+	return manifest.Keys, nil
+}
+
+func (r *Replay) writeManifest(key string, keys map[string]bool, extraKeys []string) error {
+	manifest := replayManifest{
+		Keys: extraKeys,
+	}
+	for k := range keys {
+		manifest.Keys = append(manifest.Keys, k)
+	}
+	sort.Strings(manifest.Keys)
+	body, err := json.Marshal(&manifest)
+	if err != nil {
+		return err
+	}
+	_, err = r.S3Client.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(r.ProcessedDataBucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/json"),
+	})
+	return errors.Wrapf(err, "failed to write replay manifest %s", key)
+}
+
+// recordingUploader records the keys of uploaded files
+type recordingUploader struct {
+	s3manageriface.UploaderAPI
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+func (u *recordingUploader) Upload(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+	output, err := u.UploaderAPI.Upload(input, options...)
+	if err != nil {
+		return nil, err
+	}
+	u.mu.Lock()
+	u.keys[aws.StringValue(input.Key)] = true
+	u.mu.Unlock()
+	return output, nil
+}
+
+// objectID derives a stable id for the output files of an input object
+func objectID(bucket, key string) string {
+	h := sha256.Sum256([]byte(bucket + "/" + key))
+	return hex.EncodeToString(h[:16])
+}
+
+// updaterQueue sends the S3 notifications of a destination directly to the data catalog updater queue.
+// The updater queue subscribes to the processed data topic with raw message delivery, so the message body is
+// the same as the SNS message.
+type updaterQueue struct {
+	snsiface.SNSAPI
+	sqsClient sqsiface.SQSAPI
+	queueURL  string
+}
+
+func (q *updaterQueue) Publish(input *sns.PublishInput) (*sns.PublishOutput, error) {
+	output, err := q.sqsClient.SendMessage(&sqs.SendMessageInput{
+		QueueUrl:    aws.String(q.queueURL),
+		MessageBody: input.Message,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sns.PublishOutput{
+		MessageId: output.MessageId,
+	}, nil
+}
@@ -0,0 +1,166 @@
+package main
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"flag"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/cmd/opstools"
+	"github.com/panther-labs/panther/cmd/opstools/replay"
+	"github.com/panther-labs/panther/internal/core/logtypesapi"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/enrichment"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+)
+
+const (
+	logTypesAPIFunctionName = "panther-logtypes-api"
+)
+
+var (
+	version string // we expect this to be set by the build tool as `-X main.version=<some version>`
+)
+
+func main() {
+	opstools.SetUsage("reprocesses raw S3 objects of a source using the current parsers (Panther version %s)", version)
+	opts := struct {
+		MasterStack     *string
+		Debug           *bool
+		Region          *string
+		MaxRetries      *int
+		SourceID        *string
+		Start           *string
+		End             *string
+		Limit           *uint64
+		ProcessedBucket *string
+		UpdaterQueue    *string
+		EnrichmentData  *string
+	}{
+		MasterStack: flag.String("master-stack", "",
+			"if set, this is the name of the Panther master stack used to deploy, if not set the deployment is assumed from source"),
+		Debug:           flag.Bool("debug", false, "Enable additional logging"),
+		Region:          flag.String("region", "", "Set the AWS region to run on"),
+		MaxRetries:      flag.Int("max-retries", 12, "Max retries for AWS requests"),
+		SourceID:        flag.String("source", "", "The id of the S3 source to replay"),
+		Start:           flag.String("start", "", "Replay objects modified at or after this time (RFC3339)"),
+		End:             flag.String("end", "", "Replay objects modified before this time (RFC3339, defaults to now)"),
+		Limit:           flag.Uint64("limit", 0, "If non-zero, then limit the number of objects to this number"),
+		ProcessedBucket: flag.String("processed-bucket", "", "The name of the Panther processed data bucket"),
+		UpdaterQueue:    flag.String("updater-queue", "panther-datacatalog-updater-queue", "The name of the data catalog updater queue"),
+		EnrichmentData: flag.String("enrichment-data", "",
+			"Directory with the enrichment data of the deployment (EnrichmentDataPath), if not set events are not enriched"),
+	}
+	flag.Parse()
+
+	log := opstools.MustBuildLogger(*opts.Debug)
+	zap.ReplaceGlobals(log.Desugar())
+
+	if *opts.SourceID == "" {
+		log.Fatal("-source is required")
+	}
+	if *opts.ProcessedBucket == "" {
+		log.Fatal("-processed-bucket is required")
+	}
+	start, err := time.Parse(time.RFC3339, *opts.Start)
+	if err != nil {
+		log.Fatalf("invalid -start time %q: %s", *opts.Start, err)
+	}
+	end := time.Now()
+	if *opts.End != "" {
+		if end, err = time.Parse(time.RFC3339, *opts.End); err != nil {
+			log.Fatalf("invalid -end time %q: %s", *opts.End, err)
+		}
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:     opts.Region,
+		MaxRetries: opts.MaxRetries,
+	})
+	if err != nil {
+		log.Fatalf("failed to start AWS session: %s", err)
+	}
+
+	opstools.ValidatePantherVersion(sess, log, *opts.MasterStack, version)
+
+	// the log processor components load sources and assume source roles using these
+	common.Session = sess
+	common.LambdaClient = lambda.New(sess)
+
+	if *opts.EnrichmentData != "" {
+		enricher, err := enrichment.Load(*opts.EnrichmentData)
+		if err != nil {
+			log.Fatalf("failed to load enrichment data: %s", err)
+		}
+		if enricher != nil {
+			processor.Enricher = enricher
+		}
+	}
+
+	sqsClient := sqs.New(sess)
+	queueURL, err := sqsClient.GetQueueUrl(&sqs.GetQueueUrlInput{
+		QueueName: opts.UpdaterQueue,
+	})
+	if err != nil {
+		log.Fatalf("could not get queue url for %s: %s", *opts.UpdaterQueue, err)
+	}
+
+	r := replay.Replay{
+		SourceID:            *opts.SourceID,
+		Start:               start,
+		End:                 end,
+		Limit:               *opts.Limit,
+		ProcessedDataBucket: *opts.ProcessedBucket,
+		UpdaterQueueURL:     aws.StringValue(queueURL.QueueUrl),
+		Resolver: logtypes.ChainResolvers(
+			registry.NativeLogTypesResolver(),
+			&logtypesapi.CustomLogsResolver{
+				API: &logtypesapi.LogTypesAPILambdaClient{
+					LambdaName: logTypesAPIFunctionName,
+					LambdaAPI:  common.LambdaClient,
+				},
+				MaxAge: time.Hour,
+			},
+		),
+		S3Uploader: s3manager.NewUploader(sess),
+		S3Client:   s3.New(sess),
+		SQSClient:  sqsClient,
+		Logger:     log.Desugar(),
+	}
+
+	startTime := time.Now()
+	log.Infof("replay of source %s for objects modified in [%s, %s) started", *opts.SourceID, start, end)
+	stats := replay.Stats{}
+	if err := r.Run(&stats); err != nil {
+		log.Fatalf("replay failed after %d files (%.2fMB): %s",
+			stats.NumFiles, float32(stats.NumBytes)/(1024.0*1024.0), err)
+	}
+	log.Infof("replayed %d files (%.2fMB) in %v",
+		stats.NumFiles, float32(stats.NumBytes)/(1024.0*1024.0), time.Since(startTime))
+}
@@ -0,0 +1,260 @@
+package replay
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/customlogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+const (
+	testSourceID        = "source-id"
+	testBucket          = "source-bucket"
+	testPrefix          = "prefix/"
+	testProcessedBucket = "processed-bucket"
+	testQueueURL        = "https://sqs.us-east-1.amazonaws.com/012345678912/panther-datacatalog-updater-queue"
+	testLogSpec         = `
+name: Custom.Test
+fields:
+  - name: time
+    type: timestamp
+    timeFormat: rfc3339
+    isEventTime: true
+  - name: message
+    type: string
+`
+)
+
+var (
+	testStart = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	testEnd   = testStart.Add(24 * time.Hour)
+)
+
+func TestReplay(t *testing.T) {
+	r, s3Client, uploader, sqsClient := newTestReplay(t)
+	page := &s3.ListObjectsV2Output{
+		Contents: []*s3.Object{
+			testObject("prefix/before", testStart.Add(-time.Second)),
+			testObject("prefix/start", testStart),
+			testObject("prefix/end", testEnd),
+			{ // empty objects are skipped
+				Key:          aws.String("prefix/empty"),
+				Size:         aws.Int64(0),
+				LastModified: aws.Time(testStart),
+			},
+		},
+	}
+	s3Client.On("ListObjectsV2Pages", mock.Anything, mock.Anything).Return(page, nil).Once()
+	s3Client.On("GetObject", &s3.GetObjectInput{
+		Bucket: aws.String(testBucket),
+		Key:    aws.String("prefix/start"),
+	}).Return(&s3.GetObjectOutput{
+		Body: ioutil.NopCloser(strings.NewReader(`{"time":"2020-01-01T10:00:00Z","message":"foo"}` + "\n")),
+	}, nil).Once()
+	uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Once()
+	sqsClient.On("SendMessage", mock.Anything).Return(&sqs.SendMessageOutput{}, nil).Once()
+	manifestKey := "replay/" + testSourceID + "/" + objectID(testBucket, "prefix/start") + ".json"
+	s3Client.On("GetObject", &s3.GetObjectInput{
+		Bucket: aws.String(testProcessedBucket),
+		Key:    aws.String(manifestKey),
+	}).Return((*s3.GetObjectOutput)(nil), awserr.New(s3.ErrCodeNoSuchKey, "not found", nil)).Once()
+	s3Client.On("PutObject", mock.Anything).Return(&s3.PutObjectOutput{}, nil).Once()
+
+	stats := &Stats{}
+	require.NoError(t, r.Run(stats))
+	s3Client.AssertExpectations(t)
+	uploader.AssertExpectations(t)
+	sqsClient.AssertExpectations(t)
+	assert.Equal(t, uint64(1), stats.NumFiles)
+
+	// output keys are derived from the input object so that a replay overwrites previous output
+	upload := uploader.Calls[0].Arguments.Get(0).(*s3manager.UploadInput)
+	assert.Equal(t, testProcessedBucket, aws.StringValue(upload.Bucket))
+	expectKey := "logs/custom_test/year=2020/month=01/day=01/hour=10/20200101T100000Z-" +
+		objectID(testBucket, "prefix/start") + "-0.json.gz"
+	assert.Equal(t, expectKey, aws.StringValue(upload.Key))
+
+	// notifications are sent to the updater queue only
+	msg := sqsClient.Calls[0].Arguments.Get(0).(*sqs.SendMessageInput)
+	assert.Equal(t, testQueueURL, aws.StringValue(msg.QueueUrl))
+	assert.Contains(t, aws.StringValue(msg.MessageBody), expectKey)
+
+	// the output files are recorded in the manifest of the input object
+	put := s3Client.Calls[len(s3Client.Calls)-1].Arguments.Get(0).(*s3.PutObjectInput)
+	assert.Equal(t, testProcessedBucket, aws.StringValue(put.Bucket))
+	assert.Equal(t, manifestKey, aws.StringValue(put.Key))
+	body, err := ioutil.ReadAll(put.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"keys":["`+expectKey+`"]}`, string(body))
+}
+
+func TestReplaySecondRun(t *testing.T) {
+	r, s3Client, uploader, sqsClient := newTestReplay(t)
+	page := &s3.ListObjectsV2Output{
+		Contents: []*s3.Object{
+			testObject("prefix/start", testStart),
+		},
+	}
+	s3Client.On("ListObjectsV2Pages", mock.Anything, mock.Anything).Return(page, nil).Once()
+	s3Client.On("GetObject", &s3.GetObjectInput{
+		Bucket: aws.String(testBucket),
+		Key:    aws.String("prefix/start"),
+	}).Return(&s3.GetObjectOutput{
+		Body: ioutil.NopCloser(strings.NewReader(`{"time":"2020-01-01T10:00:00Z","message":"foo"}` + "\n")),
+	}, nil).Once()
+	uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Once()
+	sqsClient.On("SendMessage", mock.Anything).Return(&sqs.SendMessageOutput{}, nil).Once()
+
+	// a previous replay stored events in more files and in a partition that is no longer used
+	id := objectID(testBucket, "prefix/start")
+	key := "logs/custom_test/year=2020/month=01/day=01/hour=10/20200101T100000Z-" + id + "-0.json.gz"
+	staleKeys := []string{
+		"logs/custom_test/year=2020/month=01/day=01/hour=10/20200101T100000Z-" + id + "-1.json.gz",
+		"logs/custom_test/year=2020/month=01/day=01/hour=11/20200101T110000Z-" + id + "-0.json.gz",
+	}
+	manifestKey := "replay/" + testSourceID + "/" + id + ".json"
+	s3Client.On("GetObject", &s3.GetObjectInput{
+		Bucket: aws.String(testProcessedBucket),
+		Key:    aws.String(manifestKey),
+	}).Return(&s3.GetObjectOutput{
+		Body: ioutil.NopCloser(strings.NewReader(`{"keys":["` + key + `","` + staleKeys[0] + `","` + staleKeys[1] + `"]}`)),
+	}, nil).Once()
+	var manifests []string
+	s3Client.On("PutObject", mock.Anything).Return(&s3.PutObjectOutput{}, nil).Run(func(args mock.Arguments) {
+		input := args.Get(0).(*s3.PutObjectInput)
+		require.Equal(t, manifestKey, aws.StringValue(input.Key))
+		body, err := ioutil.ReadAll(input.Body)
+		require.NoError(t, err)
+		manifests = append(manifests, string(body))
+	}).Twice()
+	s3Client.On("DeleteObjects", &s3.DeleteObjectsInput{
+		Bucket: aws.String(testProcessedBucket),
+		Delete: &s3.Delete{
+			Objects: []*s3.ObjectIdentifier{
+				{Key: aws.String(staleKeys[0])},
+				{Key: aws.String(staleKeys[1])},
+			},
+		},
+	}).Return(&s3.DeleteObjectsOutput{}, nil).Once()
+
+	require.NoError(t, r.Run(&Stats{}))
+	s3Client.AssertExpectations(t)
+	uploader.AssertExpectations(t)
+	upload := uploader.Calls[0].Arguments.Get(0).(*s3manager.UploadInput)
+	assert.Equal(t, key, aws.StringValue(upload.Key))
+	// stale files are listed in the manifest until they are deleted
+	require.Len(t, manifests, 2)
+	assert.JSONEq(t, `{"keys":["`+key+`","`+staleKeys[0]+`","`+staleKeys[1]+`"]}`, manifests[0])
+	assert.JSONEq(t, `{"keys":["`+key+`"]}`, manifests[1])
+}
+
+func TestReplayLimit(t *testing.T) {
+	r, s3Client, _, _ := newTestReplay(t)
+	r.Limit = 1
+	page := &s3.ListObjectsV2Output{
+		Contents: []*s3.Object{
+			testObject("prefix/foo", testStart),
+			testObject("prefix/bar", testStart),
+		},
+	}
+	s3Client.On("ListObjectsV2Pages", mock.Anything, mock.Anything).Return(page, nil).Once()
+	var keys []string
+	err := r.listObjects(s3Client, &models.SourceIntegration{}, func(object *s3.Object) bool {
+		keys = append(keys, aws.StringValue(object.Key))
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prefix/foo"}, keys)
+}
+
+func TestReplaySourceType(t *testing.T) {
+	r, _, _, _ := newTestReplay(t)
+	r.loadSource = func(_ string) (*models.SourceIntegration, error) {
+		return &models.SourceIntegration{
+			SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+				IntegrationID:   testSourceID,
+				IntegrationType: models.IntegrationTypeSqs,
+			},
+		}, nil
+	}
+	require.Error(t, r.Run(&Stats{}))
+}
+
+func newTestReplay(t *testing.T) (*Replay, *testutils.S3Mock, *testutils.S3UploaderMock, *testutils.SqsMock) {
+	spec, err := customlogs.ParseSpec([]byte(testLogSpec))
+	require.NoError(t, err)
+	registry := &logtypes.Registry{}
+	require.NoError(t, customlogs.Register(registry, spec))
+
+	s3Client := &testutils.S3Mock{}
+	uploader := &testutils.S3UploaderMock{}
+	sqsClient := &testutils.SqsMock{}
+	return &Replay{
+		SourceID:            testSourceID,
+		Start:               testStart,
+		End:                 testEnd,
+		ProcessedDataBucket: testProcessedBucket,
+		UpdaterQueueURL:     testQueueURL,
+		Resolver:            registry,
+		S3Uploader:          uploader,
+		S3Client:            s3Client,
+		SQSClient:           sqsClient,
+		Logger:              zap.NewNop(),
+		loadSource: func(id string) (*models.SourceIntegration, error) {
+			return &models.SourceIntegration{
+				SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+					IntegrationID:   id,
+					IntegrationType: models.IntegrationTypeAWS3,
+					S3Bucket:        testBucket,
+					S3Prefix:        testPrefix,
+					LogTypes:        []string{"Custom.Test"},
+				},
+			}, nil
+		},
+		sourceS3Client: func(_ *models.SourceIntegration, _ string) (s3iface.S3API, error) {
+			return s3Client, nil
+		},
+	}, s3Client, uploader, sqsClient
+}
+
+func testObject(key string, modified time.Time) *s3.Object {
+	return &s3.Object{
+		Key:          aws.String(key),
+		Size:         aws.Int64(1),
+		LastModified: aws.Time(modified),
+	}
+}
@@ -25,6 +25,7 @@ require (
 	github.com/leodido/go-urn v1.2.0 // indirect
 	github.com/magefile/mage v1.10.0
 	github.com/modern-go/reflect2 v1.0.1
+	github.com/oschwald/maxminddb-golang v1.3.1
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.6.1
 	github.com/tidwall/gjson v1.6.1
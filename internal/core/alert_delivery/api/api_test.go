@@ -34,11 +34,27 @@ type mockOutputsClient struct {
 	mock.Mock
 }
 
-func (m *mockOutputsClient) Slack(alert *deliveryModels.Alert, config *outputModels.SlackConfig) *outputs.AlertDeliveryResponse {
-	args := m.Called(alert, config)
+func (m *mockOutputsClient) Slack(
+	alert *deliveryModels.Alert, config *outputModels.SlackConfig, message *string) *outputs.AlertDeliveryResponse {
+
+	args := m.Called(alert, config, message)
+	return args.Get(0).(*outputs.AlertDeliveryResponse)
+}
+
+func (m *mockOutputsClient) SplunkHEC(
+	alerts []*deliveryModels.Alert, config *outputModels.SplunkHECConfig, messages []*string) *outputs.AlertDeliveryResponse {
+
+	args := m.Called(alerts, config, messages)
 	return args.Get(0).(*outputs.AlertDeliveryResponse)
 }
 
+func (m *mockOutputsClient) ServiceNowIncidents(
+	config *outputModels.ServiceNowConfig, sysIDs []string) ([]*outputs.ServiceNowIncident, error) {
+
+	args := m.Called(config, sysIDs)
+	return args.Get(0).([]*outputs.ServiceNowIncident), args.Error(1)
+}
+
 func sampleAlert() *deliveryModels.Alert {
 	return &deliveryModels.Alert{
 		AlertID:      aws.String("alert-id"),
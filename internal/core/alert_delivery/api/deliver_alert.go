@@ -126,8 +126,11 @@ func populateAlertData(alertItem *alertTable.AlertItem) (*deliveryModels.Alert,
 		Version:             &alertItem.RuleVersion,
 		Runbook:             aws.String(string(rule.Runbook)),
 		Tags:                rule.Tags,
+		LogTypes:            alertItem.LogTypes,
 		AlertID:             &alertItem.AlertID,
 		Title:               alertItem.Title,
+		DedupString:         aws.String(alertItem.DedupString),
+		EventCount:          alertItem.EventCount,
 		RetryCount:          0,
 		IsTest:              false,
 		IsResent:            true,
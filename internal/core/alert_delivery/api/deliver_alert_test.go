@@ -148,6 +148,7 @@ func TestPopulateAlert(t *testing.T) {
 		Severity:            severity,
 		CreatedAt:           timeNow,
 		Version:             aws.String(versionID),
+		DedupString:         aws.String("dedup"),
 		IsResent:            true,
 	}
 
@@ -28,7 +28,7 @@ import (
 	"github.com/panther-labs/panther/pkg/genericapi"
 )
 
-// getAlertOutputs - Get output ids for an alert via the specified overrides or the defaults in panther
+// getAlertOutputs - Get output ids for an alert via the specified overrides, the routing rules or the defaults in panther
 func getAlertOutputs(alert *deliveryModels.Alert) ([]*outputModels.AlertOutput, error) {
 	// fetch available panther outputs
 	outputs, err := getOutputs()
@@ -36,30 +36,11 @@ func getAlertOutputs(alert *deliveryModels.Alert) ([]*outputModels.AlertOutput,
 		return nil, err
 	}
 
-	// If alert doesn't have outputs IDs specified, return the defaults for the severity
-	if len(alert.OutputIds) == 0 {
-		defaultsForSeverity := []*outputModels.AlertOutput{}
-		for _, output := range outputs {
-			// If `DefaultForSeverity` is nil or empty, this loop will skip
-			for _, outputSeverity := range output.DefaultForSeverity {
-				if alert.Severity == *outputSeverity {
-					defaultsForSeverity = append(defaultsForSeverity, output)
-				}
-			}
-		}
-		return defaultsForSeverity, nil
-	}
-
-	// Otherwise, return the specified output overrides for the alert
-	overrideOutputs := []*outputModels.AlertOutput{}
-	for _, output := range outputs {
-		for _, outputID := range alert.OutputIds {
-			if *output.OutputID == outputID {
-				overrideOutputs = append(overrideOutputs, output)
-			}
-		}
+	alertOutputs := []*outputModels.AlertOutput{}
+	for _, route := range routeAlertOutputs(alert, outputs) {
+		alertOutputs = append(alertOutputs, route.Output)
 	}
-	return overrideOutputs, nil
+	return alertOutputs, nil
 }
 
 // getOutputs - Gets a list of outputs from panther (using a cache)
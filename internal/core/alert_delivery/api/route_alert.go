@@ -0,0 +1,51 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"go.uber.org/zap"
+
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+)
+
+// RouteAlert reports the destinations an alert would be sent to, without sending it.
+func (API) RouteAlert(input *deliveryModels.RouteAlertInput) (deliveryModels.RouteAlertOutput, error) {
+	zap.L().Debug("Routing alert", zap.String("analysisId", input.Alert.AnalysisID))
+
+	outputs, err := getOutputs()
+	if err != nil {
+		return nil, err
+	}
+
+	result := deliveryModels.RouteAlertOutput{}
+	for _, route := range routeAlertOutputs(input.Alert, outputs) {
+		routed := &deliveryModels.RoutedOutput{
+			OutputID:    aws.StringValue(route.Output.OutputID),
+			DisplayName: aws.StringValue(route.Output.DisplayName),
+			OutputType:  aws.StringValue(route.Output.OutputType),
+			Reason:      route.Reason,
+		}
+		if route.RuleIndex >= 0 {
+			routed.RuleIndex = aws.Int(route.RuleIndex)
+		}
+		result = append(result, routed)
+	}
+	return result, nil
+}
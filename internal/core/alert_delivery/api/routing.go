@@ -0,0 +1,203 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"path"
+	"time"
+
+	"go.uber.org/zap"
+
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+const (
+	routeReasonOverride = "alert output override"
+	routeReasonRule     = "routing rule"
+	routeReasonSeverity = "default for severity"
+)
+
+// alertRoute is an output an alert is sent to and the reason it was selected
+type alertRoute struct {
+	Output *outputModels.AlertOutput
+	Reason string
+	// RuleIndex is the index of the routing rule that matched, or -1 if no rule matched
+	RuleIndex int
+}
+
+// routeAlertOutputs - Select the outputs to send an alert to.
+//
+// Outputs specified in the alert override any routing configuration.
+// Otherwise, the routing rules of each output are evaluated in order and the first matching rule decides
+// whether the alert is sent. If no rule matches, the output's `DefaultForSeverity` applies.
+func routeAlertOutputs(alert *deliveryModels.Alert, outputs []*outputModels.AlertOutput) []*alertRoute {
+	routes := []*alertRoute{}
+	if len(alert.OutputIds) != 0 {
+		for _, output := range outputs {
+			for _, outputID := range alert.OutputIds {
+				if *output.OutputID == outputID {
+					routes = append(routes, &alertRoute{
+						Output:    output,
+						Reason:    routeReasonOverride,
+						RuleIndex: -1,
+					})
+				}
+			}
+		}
+		return routes
+	}
+
+	for _, output := range outputs {
+		routed, ruleIndex := routeAlert(output, alert)
+		if !routed {
+			continue
+		}
+		reason := routeReasonSeverity
+		if ruleIndex >= 0 {
+			reason = routeReasonRule
+		}
+		routes = append(routes, &alertRoute{
+			Output:    output,
+			Reason:    reason,
+			RuleIndex: ruleIndex,
+		})
+	}
+	return routes
+}
+
+// routeAlert - Decide if an alert is sent through an output.
+//
+// Returns the index of the routing rule that made the decision or -1 if `DefaultForSeverity` was used.
+func routeAlert(output *outputModels.AlertOutput, alert *deliveryModels.Alert) (bool, int) {
+	for i, rule := range output.RoutingRules {
+		if rule == nil || !matchRoutingRule(rule, alert) {
+			continue
+		}
+		return rule.Action == outputModels.RoutingActionRoute, i
+	}
+	// If `DefaultForSeverity` is nil or empty, this loop will skip
+	for _, outputSeverity := range output.DefaultForSeverity {
+		if alert.Severity == *outputSeverity {
+			return true, -1
+		}
+	}
+	return false, -1
+}
+
+// matchRoutingRule checks if an alert matches all the conditions of a routing rule
+func matchRoutingRule(rule *outputModels.RoutingRule, alert *deliveryModels.Alert) bool {
+	if len(rule.Severities) != 0 && !containsString(rule.Severities, alert.Severity) {
+		return false
+	}
+	if len(rule.AlertTypes) != 0 && !containsString(rule.AlertTypes, alert.Type) {
+		return false
+	}
+	if len(rule.RuleIDs) != 0 && !matchAnyGlob(rule.RuleIDs, alert.AnalysisID) {
+		return false
+	}
+	if len(rule.Tags) != 0 && !containsAnyString(rule.Tags, alert.Tags) {
+		return false
+	}
+	if len(rule.LogTypes) != 0 {
+		matched := false
+		for _, logType := range alert.LogTypes {
+			if matchAnyGlob(rule.LogTypes, logType) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if rule.TimeOfDay != nil && !matchTimeOfDay(rule.TimeOfDay, alert.CreatedAt) {
+		return false
+	}
+	return true
+}
+
+// matchTimeOfDay checks if a timestamp falls in a daily time window
+func matchTimeOfDay(window *outputModels.TimeOfDay, tm time.Time) bool {
+	loc := time.UTC
+	if window.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(window.Timezone); err != nil {
+			zap.L().Warn("invalid routing rule timezone", zap.String("timezone", window.Timezone), zap.Error(err))
+			return false
+		}
+	}
+	start, err := time.Parse(clockLayout, window.Start)
+	if err != nil {
+		zap.L().Warn("invalid routing rule start time", zap.String("start", window.Start), zap.Error(err))
+		return false
+	}
+	end, err := time.Parse(clockLayout, window.End)
+	if err != nil {
+		zap.L().Warn("invalid routing rule end time", zap.String("end", window.End), zap.Error(err))
+		return false
+	}
+	tm = tm.In(loc)
+	minute := minuteOfDay(tm)
+	startMinute, endMinute := minuteOfDay(start), minuteOfDay(end)
+	switch {
+	case startMinute < endMinute:
+		return startMinute <= minute && minute < endMinute
+	case startMinute > endMinute:
+		// The window wraps around midnight
+		return minute >= startMinute || minute < endMinute
+	default:
+		// The window spans the whole day
+		return true
+	}
+}
+
+const clockLayout = "15:04"
+
+func minuteOfDay(tm time.Time) int {
+	return tm.Hour()*60 + tm.Minute()
+}
+
+func matchAnyGlob(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		// Patterns are validated by the outputs-api so errors can only mean no match
+		if matched, _ := path.Match(pattern, value); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAnyString(values []string, candidates []string) bool {
+	for _, candidate := range candidates {
+		if containsString(values, candidate) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+func TestMatchRoutingRule(t *testing.T) {
+	alert := &deliveryModels.Alert{
+		AnalysisID: "AWS.GuardDuty.HighVolFindings",
+		Type:       deliveryModels.RuleType,
+		Severity:   "HIGH",
+		CreatedAt:  time.Date(2020, 9, 1, 23, 30, 0, 0, time.UTC),
+		LogTypes:   []string{"AWS.GuardDuty"},
+		Tags:       []string{"prod", "aws"},
+	}
+	for _, tc := range []struct {
+		Name   string
+		Rule   outputModels.RoutingRule
+		Expect bool
+	}{
+		{"empty", outputModels.RoutingRule{}, true},
+		{"severity", outputModels.RoutingRule{Severities: []string{"HIGH", "CRITICAL"}}, true},
+		{"severity mismatch", outputModels.RoutingRule{Severities: []string{"INFO"}}, false},
+		{"alert type", outputModels.RoutingRule{AlertTypes: []string{deliveryModels.RuleType}}, true},
+		{"alert type mismatch", outputModels.RoutingRule{AlertTypes: []string{deliveryModels.PolicyType}}, false},
+		{"rule id", outputModels.RoutingRule{RuleIDs: []string{"AWS.GuardDuty.*"}}, true},
+		{"rule id mismatch", outputModels.RoutingRule{RuleIDs: []string{"Okta.*"}}, false},
+		{"tags", outputModels.RoutingRule{Tags: []string{"prod"}}, true},
+		{"tags mismatch", outputModels.RoutingRule{Tags: []string{"dev"}}, false},
+		{"log types", outputModels.RoutingRule{LogTypes: []string{"Okta.*", "AWS.*"}}, true},
+		{"log types mismatch", outputModels.RoutingRule{LogTypes: []string{"Okta.*"}}, false},
+		{"all conditions", outputModels.RoutingRule{
+			Severities: []string{"HIGH"},
+			RuleIDs:    []string{"AWS.GuardDuty.*"},
+			Tags:       []string{"prod"},
+		}, true},
+		{"any condition mismatch", outputModels.RoutingRule{
+			Severities: []string{"HIGH"},
+			RuleIDs:    []string{"AWS.GuardDuty.*"},
+			Tags:       []string{"dev"},
+		}, false},
+		{"time of day", outputModels.RoutingRule{TimeOfDay: &outputModels.TimeOfDay{Start: "23:00", End: "23:59"}}, true},
+		{"time of day end exclusive", outputModels.RoutingRule{TimeOfDay: &outputModels.TimeOfDay{Start: "09:00", End: "23:30"}}, false},
+		{"time of day wraps", outputModels.RoutingRule{TimeOfDay: &outputModels.TimeOfDay{Start: "18:00", End: "09:00"}}, true},
+		{"time of day whole day", outputModels.RoutingRule{TimeOfDay: &outputModels.TimeOfDay{Start: "00:00", End: "00:00"}}, true},
+		{"time of day timezone", outputModels.RoutingRule{TimeOfDay: &outputModels.TimeOfDay{
+			Start:    "19:00",
+			End:      "20:00",
+			Timezone: "America/New_York",
+		}}, true},
+		{"time of day timezone mismatch", outputModels.RoutingRule{TimeOfDay: &outputModels.TimeOfDay{
+			Start:    "09:00",
+			End:      "17:00",
+			Timezone: "Europe/Athens",
+		}}, false},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expect, matchRoutingRule(&tc.Rule, alert))
+		})
+	}
+}
+
+func TestRouteAlert(t *testing.T) {
+	output := &outputModels.AlertOutput{
+		OutputID:           aws.String("output-id"),
+		DefaultForSeverity: aws.StringSlice([]string{"INFO"}),
+		RoutingRules: []*outputModels.RoutingRule{
+			{
+				Action: outputModels.RoutingActionSkip,
+				Tags:   []string{"noisy"},
+			},
+			{
+				Action:   outputModels.RoutingActionRoute,
+				LogTypes: []string{"Okta.*"},
+			},
+		},
+	}
+	alert := sampleAlert()
+	alert.OutputIds = nil
+
+	// Falls back to default for severity
+	routed, ruleIndex := routeAlert(output, alert)
+	assert.True(t, routed)
+	assert.Equal(t, -1, ruleIndex)
+
+	// First matching rule wins
+	alert.Tags = []string{"noisy"}
+	alert.LogTypes = []string{"Okta.SystemLog"}
+	routed, ruleIndex = routeAlert(output, alert)
+	assert.False(t, routed)
+	assert.Equal(t, 0, ruleIndex)
+
+	alert.Tags = nil
+	alert.Severity = "HIGH"
+	routed, ruleIndex = routeAlert(output, alert)
+	assert.True(t, routed)
+	assert.Equal(t, 1, ruleIndex)
+
+	alert.LogTypes = nil
+	routed, ruleIndex = routeAlert(output, alert)
+	assert.False(t, routed)
+	assert.Equal(t, -1, ruleIndex)
+}
+
+func TestRouteAlertAPI(t *testing.T) {
+	mockClient := &testutils.LambdaMock{}
+	lambdaClient = mockClient
+	output := &outputModels.GetOutputsOutput{
+		{
+			OutputID:           aws.String("identity-slack"),
+			DisplayName:        aws.String("identity"),
+			OutputType:         aws.String("slack"),
+			DefaultForSeverity: aws.StringSlice([]string{"CRITICAL"}),
+			RoutingRules: []*outputModels.RoutingRule{
+				{
+					Action:   outputModels.RoutingActionRoute,
+					LogTypes: []string{"Okta.*"},
+				},
+			},
+		},
+		{
+			OutputID:           aws.String("default-info"),
+			DisplayName:        aws.String("info"),
+			OutputType:         aws.String("sns"),
+			DefaultForSeverity: aws.StringSlice([]string{"INFO"}),
+		},
+		{
+			OutputID:           aws.String("default-critical"),
+			DisplayName:        aws.String("critical"),
+			OutputType:         aws.String("pagerduty"),
+			DefaultForSeverity: aws.StringSlice([]string{"CRITICAL"}),
+		},
+	}
+	payload, err := jsoniter.Marshal(output)
+	require.NoError(t, err)
+	mockLambdaResponse := &lambda.InvokeOutput{Payload: payload}
+	// Need to expire the cache because other tests mutate this global when run in parallel
+	outputsCache = &alertOutputsCache{
+		RefreshInterval: time.Second * time.Duration(30),
+		Expiry:          time.Now().Add(time.Minute * time.Duration(-5)),
+	}
+	mockClient.On("Invoke", mock.Anything).Return(mockLambdaResponse, nil).Once()
+
+	alert := sampleAlert()
+	alert.OutputIds = nil
+	alert.LogTypes = []string{"Okta.SystemLog"}
+
+	result, err := (API{}).RouteAlert(&deliveryModels.RouteAlertInput{Alert: alert})
+	require.NoError(t, err)
+	expect := deliveryModels.RouteAlertOutput{
+		{
+			OutputID:    "identity-slack",
+			DisplayName: "identity",
+			OutputType:  "slack",
+			Reason:      routeReasonRule,
+			RuleIndex:   aws.Int(0),
+		},
+		{
+			OutputID:    "default-info",
+			DisplayName: "info",
+			OutputType:  "sns",
+			Reason:      routeReasonSeverity,
+		},
+	}
+	assert.Equal(t, expect, result)
+
+	// Alert output overrides skip routing
+	alert.OutputIds = []string{"default-critical"}
+	result, err = (API{}).RouteAlert(&deliveryModels.RouteAlertInput{Alert: alert})
+	require.NoError(t, err)
+	expect = deliveryModels.RouteAlertOutput{
+		{
+			OutputID:    "default-critical",
+			DisplayName: "critical",
+			OutputType:  "pagerduty",
+			Reason:      routeReasonOverride,
+		},
+	}
+	assert.Equal(t, expect, result)
+	mockClient.AssertExpectations(t)
+}
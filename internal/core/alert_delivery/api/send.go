@@ -3,6 +3,7 @@ package api
 import (
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"go.uber.org/zap"
 
 	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
@@ -40,6 +41,26 @@ type DispatchStatus struct {
 	Success      bool
 	NeedsRetry   bool
 	DispatchedAt time.Time
+	ExternalID   string
+}
+
+// maxAlertsPerBatch limits the number of alerts delivered in a single request to a batch output
+const maxAlertsPerBatch = 100
+
+// alertBatch holds the alerts to deliver together to a batch output
+type alertBatch struct {
+	output *outputModels.AlertOutput
+	alerts []*deliveryModels.Alert
+}
+
+// isBatchOutput returns true for outputs that deliver multiple alerts in a single request
+func isBatchOutput(output *outputModels.AlertOutput) bool {
+	switch aws.StringValue(output.OutputType) {
+	case "splunkhec", "syslog":
+		return true
+	default:
+		return false
+	}
 }
 
 // sendAlerts - dispatches alerts to their associated outputIds in parallel
@@ -48,12 +69,32 @@ func sendAlerts(alertOutputs AlertOutputMap) []DispatchStatus {
 	statusChannel := make(chan DispatchStatus)
 
 	// Extract the maps (k, v)
+	batches := make(map[string]*alertBatch)
 	for alert, outputIds := range alertOutputs {
 		for _, output := range outputIds {
+			if isBatchOutput(output) {
+				batch, ok := batches[*output.OutputID]
+				if !ok {
+					batch = &alertBatch{output: output}
+					batches[*output.OutputID] = batch
+				}
+				batch.alerts = append(batch.alerts, alert)
+				continue
+			}
 			dispatchedAt := time.Now().UTC()
 			go sendAlert(alert, output, dispatchedAt, statusChannel)
 		}
 	}
+	for _, batch := range batches {
+		for start := 0; start < len(batch.alerts); start += maxAlertsPerBatch {
+			end := start + maxAlertsPerBatch
+			if end > len(batch.alerts) {
+				end = len(batch.alerts)
+			}
+			dispatchedAt := time.Now().UTC()
+			go sendAlertBatch(batch.alerts[start:end], batch.output, dispatchedAt, statusChannel)
+		}
+	}
 
 	// Wait until all outputs have finished, gathering all the statuses of each delivery
 	deliveryStatuses := []DispatchStatus{}
@@ -94,28 +135,48 @@ func sendAlert(alert *deliveryModels.Alert, output *outputModels.AlertOutput, di
 		}
 	}()
 
+	message, err := outputs.RenderMessage(alert, output)
+	if err != nil {
+		// The template was validated when the output was saved, retrying will not help
+		zap.L().Warn("failed to render message template", append(commonFields, zap.Error(err))...)
+		statusChannel <- DispatchStatus{
+			Alert:        *alert,
+			OutputID:     *output.OutputID,
+			StatusCode:   500,
+			Success:      false,
+			Message:      "failed to render message template: " + err.Error(),
+			NeedsRetry:   false,
+			DispatchedAt: dispatchedAt,
+		}
+		return
+	}
+
 	response := (*outputs.AlertDeliveryResponse)(nil)
 	switch *output.OutputType {
 	case "slack":
-		response = outputClient.Slack(alert, output.OutputConfig.Slack)
+		response = outputClient.Slack(alert, output.OutputConfig.Slack, message)
 	case "pagerduty":
-		response = outputClient.PagerDuty(alert, output.OutputConfig.PagerDuty)
+		response = outputClient.PagerDuty(alert, output.OutputConfig.PagerDuty, message)
 	case "github":
-		response = outputClient.Github(alert, output.OutputConfig.Github)
+		response = outputClient.Github(alert, output.OutputConfig.Github, message)
 	case "opsgenie":
-		response = outputClient.Opsgenie(alert, output.OutputConfig.Opsgenie)
+		response = outputClient.Opsgenie(alert, output.OutputConfig.Opsgenie, message)
 	case "jira":
-		response = outputClient.Jira(alert, output.OutputConfig.Jira)
+		response = outputClient.Jira(alert, output.OutputConfig.Jira, message)
 	case "msteams":
-		response = outputClient.MsTeams(alert, output.OutputConfig.MsTeams)
+		response = outputClient.MsTeams(alert, output.OutputConfig.MsTeams, message)
 	case "sqs":
-		response = outputClient.Sqs(alert, output.OutputConfig.Sqs)
+		response = outputClient.Sqs(alert, output.OutputConfig.Sqs, message)
 	case "sns":
-		response = outputClient.Sns(alert, output.OutputConfig.Sns)
+		response = outputClient.Sns(alert, output.OutputConfig.Sns, message)
 	case "asana":
-		response = outputClient.Asana(alert, output.OutputConfig.Asana)
+		response = outputClient.Asana(alert, output.OutputConfig.Asana, message)
 	case "customwebhook":
-		response = outputClient.CustomWebhook(alert, output.OutputConfig.CustomWebhook)
+		response = outputClient.CustomWebhook(alert, output.OutputConfig.CustomWebhook, message)
+	case "email":
+		response = outputClient.Email(alert, output.OutputConfig.Email, message)
+	case "servicenow":
+		response = outputClient.ServiceNow(alert, output.OutputConfig.ServiceNow, message)
 	default:
 		zap.L().Warn("unsupported output type", commonFields...)
 		statusChannel <- DispatchStatus{
@@ -153,5 +214,97 @@ func sendAlert(alert *deliveryModels.Alert, output *outputModels.AlertOutput, di
 		Message:      response.Message,
 		NeedsRetry:   !response.Success && !response.Permanent,
 		DispatchedAt: dispatchedAt,
+		ExternalID:   response.ExternalID,
+	}
+}
+
+// sendAlertBatch sends multiple alerts to one batch output in a single request (run as a child goroutine).
+//
+// The statusChannel will be sent a message for each alert with the result of the send attempt.
+func sendAlertBatch(alerts []*deliveryModels.Alert, output *outputModels.AlertOutput, dispatchedAt time.Time,
+	statusChannel chan DispatchStatus) {
+
+	commonFields := []zap.Field{
+		zap.Stringp("outputID", output.OutputID),
+		zap.Int("alertCount", len(alerts)),
+	}
+	failedStatus := func(alert *deliveryModels.Alert, message string) DispatchStatus {
+		return DispatchStatus{
+			Alert:        *alert,
+			OutputID:     *output.OutputID,
+			StatusCode:   500,
+			Success:      false,
+			Message:      message,
+			NeedsRetry:   false,
+			DispatchedAt: dispatchedAt,
+		}
+	}
+
+	// Statuses are only reported once the batch is done, so a panic can report all of them
+	statuses := make([]DispatchStatus, 0, len(alerts))
+	defer func() {
+		if r := recover(); r != nil {
+			zap.L().Error("panic sending alert batch", append(commonFields, zap.Any("panic", r))...)
+			for _, alert := range alerts {
+				statusChannel <- failedStatus(alert, "panic sending alert")
+			}
+			return
+		}
+		for _, status := range statuses {
+			statusChannel <- status
+		}
+	}()
+
+	batchAlerts := make([]*deliveryModels.Alert, 0, len(alerts))
+	messages := make([]*string, 0, len(alerts))
+	for _, alert := range alerts {
+		message, err := outputs.RenderMessage(alert, output)
+		if err != nil {
+			// The template was validated when the output was saved, retrying will not help
+			zap.L().Warn("failed to render message template",
+				append(commonFields, zap.Stringp("alertID", alert.AlertID), zap.Error(err))...)
+			statuses = append(statuses, failedStatus(alert, "failed to render message template: "+err.Error()))
+			continue
+		}
+		batchAlerts = append(batchAlerts, alert)
+		messages = append(messages, message)
+	}
+	if len(batchAlerts) == 0 {
+		return
+	}
+
+	response := (*outputs.AlertDeliveryResponse)(nil)
+	switch *output.OutputType {
+	case "splunkhec":
+		response = outputClient.SplunkHEC(batchAlerts, output.OutputConfig.SplunkHEC, messages)
+	case "syslog":
+		response = outputClient.Syslog(batchAlerts, output.OutputConfig.Syslog, messages)
+	default:
+		zap.L().Warn("unsupported output type", commonFields...)
+		for _, alert := range batchAlerts {
+			statuses = append(statuses, failedStatus(alert, "unsupported output type"))
+		}
+		return
+	}
+
+	if response == nil {
+		zap.L().Warn("output response is nil", commonFields...)
+		for _, alert := range batchAlerts {
+			statuses = append(statuses, failedStatus(alert, "output response is nil"))
+		}
+		return
+	}
+
+	// The whole batch succeeds or fails together, retry only if we don't have a permanent failure
+	for _, alert := range batchAlerts {
+		statuses = append(statuses, DispatchStatus{
+			Alert:        *alert,
+			OutputID:     *output.OutputID,
+			StatusCode:   response.StatusCode,
+			Success:      response.Success && !response.Permanent,
+			Message:      response.Message,
+			NeedsRetry:   !response.Success && !response.Permanent,
+			DispatchedAt: dispatchedAt,
+		})
 	}
 }
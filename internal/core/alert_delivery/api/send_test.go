@@ -26,6 +26,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
 	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
 	"github.com/panther-labs/panther/internal/core/alert_delivery/outputs"
 )
@@ -60,7 +61,7 @@ func TestSendPanic(t *testing.T) {
 		NeedsRetry:   false,
 		DispatchedAt: dispatchedAt,
 	}
-	mockClient.On("Slack", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+	mockClient.On("Slack", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
 		panic("panicking")
 	})
 	go sendAlert(alert, alertOutput, dispatchedAt, ch)
@@ -119,7 +120,7 @@ func TestSendResponseNil(t *testing.T) {
 		NeedsRetry:   false,
 		DispatchedAt: dispatchedAt,
 	}
-	mockClient.On("Slack", mock.Anything, mock.Anything).Return(response)
+	mockClient.On("Slack", mock.Anything, mock.Anything, mock.Anything).Return(response)
 	sendAlert(alert, alertOutput, dispatchedAt, ch)
 	assert.Equal(t, expectedResponse, <-ch)
 	mockClient.AssertExpectations(t)
@@ -149,7 +150,7 @@ func TestSendPermanentFailure(t *testing.T) {
 		NeedsRetry:   false,
 		DispatchedAt: dispatchedAt,
 	}
-	mockClient.On("Slack", mock.Anything, mock.Anything).Return(response)
+	mockClient.On("Slack", mock.Anything, mock.Anything, mock.Anything).Return(response)
 	go sendAlert(alert, alertOutput, dispatchedAt, ch)
 	assert.Equal(t, expectedResponse, <-ch)
 	mockClient.AssertExpectations(t)
@@ -179,7 +180,7 @@ func TestSendTransientFailure(t *testing.T) {
 		NeedsRetry:   true,
 		DispatchedAt: dispatchedAt,
 	}
-	mockClient.On("Slack", mock.Anything, mock.Anything).Return(response)
+	mockClient.On("Slack", mock.Anything, mock.Anything, mock.Anything).Return(response)
 	go sendAlert(alert, alertOutput, dispatchedAt, ch)
 	assert.Equal(t, expectedResponse, <-ch)
 	mockClient.AssertExpectations(t)
@@ -209,8 +210,154 @@ func TestSendSuccess(t *testing.T) {
 		NeedsRetry:   false,
 		DispatchedAt: dispatchedAt,
 	}
-	mockClient.On("Slack", mock.Anything, mock.Anything).Return(response)
+	mockClient.On("Slack", mock.Anything, mock.Anything, mock.Anything).Return(response)
 	go sendAlert(alert, alertOutput, dispatchedAt, ch)
 	assert.Equal(t, expectedResponse, <-ch)
 	mockClient.AssertExpectations(t)
 }
+
+func TestSendMessageTemplate(t *testing.T) {
+	mockClient := &mockOutputsClient{}
+	outputClient = mockClient
+
+	ch := make(chan DispatchStatus, 1)
+	alert := sampleAlert()
+	alertOutput := genAlertOutput()
+	alertOutput.MessageTemplate = aws.String("{{ .AnalysisID }} triggered")
+	dispatchedAt := time.Now().UTC()
+
+	response := &outputs.AlertDeliveryResponse{
+		StatusCode: 200,
+		Success:    true,
+		Message:    "ok",
+	}
+	expectedResponse := DispatchStatus{
+		Alert:        *alert,
+		OutputID:     *alertOutput.OutputID,
+		StatusCode:   200,
+		Success:      true,
+		Message:      "ok",
+		NeedsRetry:   false,
+		DispatchedAt: dispatchedAt,
+	}
+	mockClient.On("Slack", alert, alertOutput.OutputConfig.Slack, aws.String("test-rule-id triggered")).Return(response)
+	sendAlert(alert, alertOutput, dispatchedAt, ch)
+	assert.Equal(t, expectedResponse, <-ch)
+	mockClient.AssertExpectations(t)
+}
+
+func TestSendMessageTemplateError(t *testing.T) {
+	mockClient := &mockOutputsClient{}
+	outputClient = mockClient
+
+	ch := make(chan DispatchStatus, 1)
+	alert := sampleAlert()
+	alertOutput := genAlertOutput()
+	alertOutput.MessageTemplate = aws.String("{{ .NoSuchField }}")
+	dispatchedAt := time.Now().UTC()
+
+	sendAlert(alert, alertOutput, dispatchedAt, ch)
+	status := <-ch
+	assert.Equal(t, 500, status.StatusCode)
+	assert.False(t, status.Success)
+	assert.False(t, status.NeedsRetry)
+	assert.Contains(t, status.Message, "failed to render message template")
+	mockClient.AssertExpectations(t)
+}
+
+func genBatchAlertOutput() *outputModels.AlertOutput {
+	return &outputModels.AlertOutput{
+		OutputID:    aws.String("splunk-output-id"),
+		OutputType:  aws.String("splunkhec"),
+		DisplayName: aws.String("splunk"),
+		OutputConfig: &outputModels.OutputConfig{
+			SplunkHEC: &outputModels.SplunkHECConfig{URL: "https://splunk.example.com:8088", Token: "token"},
+		},
+	}
+}
+
+func TestSendAlertsBatch(t *testing.T) {
+	mockClient := &mockOutputsClient{}
+	outputClient = mockClient
+
+	batchOutput := genBatchAlertOutput()
+	alertOutputs := AlertOutputMap{}
+	for i := 0; i < 3; i++ {
+		alertOutputs[sampleAlert()] = []*outputModels.AlertOutput{batchOutput, genAlertOutput()}
+	}
+
+	response := &outputs.AlertDeliveryResponse{
+		StatusCode: 200,
+		Success:    true,
+		Message:    "ok",
+	}
+	mockClient.On("Slack", mock.Anything, mock.Anything, mock.Anything).Return(response).Times(3)
+	mockClient.On("SplunkHEC", mock.Anything, batchOutput.OutputConfig.SplunkHEC, []*string{nil, nil, nil}).
+		Return(response).Once()
+
+	statuses := sendAlerts(alertOutputs)
+	assert.Len(t, statuses, 6)
+	batchStatuses := 0
+	for _, status := range statuses {
+		assert.True(t, status.Success)
+		if status.OutputID == *batchOutput.OutputID {
+			batchStatuses++
+		}
+	}
+	assert.Equal(t, 3, batchStatuses)
+	mockClient.AssertExpectations(t)
+}
+
+func TestSendAlertBatchTransientFailure(t *testing.T) {
+	mockClient := &mockOutputsClient{}
+	outputClient = mockClient
+
+	ch := make(chan DispatchStatus, 2)
+	alerts := []*deliveryModels.Alert{sampleAlert(), sampleAlert()}
+	alertOutput := genBatchAlertOutput()
+	alertOutput.MessageTemplate = aws.String("{{ .AnalysisID }}")
+	dispatchedAt := time.Now().UTC()
+
+	response := &outputs.AlertDeliveryResponse{
+		StatusCode: 503,
+		Success:    false,
+		Message:    "server busy",
+		Permanent:  false,
+	}
+	messages := []*string{aws.String("test-rule-id"), aws.String("test-rule-id")}
+	mockClient.On("SplunkHEC", alerts, alertOutput.OutputConfig.SplunkHEC, messages).Return(response)
+	sendAlertBatch(alerts, alertOutput, dispatchedAt, ch)
+	for _, alert := range alerts {
+		assert.Equal(t, DispatchStatus{
+			Alert:        *alert,
+			OutputID:     *alertOutput.OutputID,
+			StatusCode:   503,
+			Success:      false,
+			Message:      "server busy",
+			NeedsRetry:   true,
+			DispatchedAt: dispatchedAt,
+		}, <-ch)
+	}
+	mockClient.AssertExpectations(t)
+}
+
+func TestSendAlertBatchPanic(t *testing.T) {
+	mockClient := &mockOutputsClient{}
+	outputClient = mockClient
+
+	ch := make(chan DispatchStatus, 2)
+	alerts := []*deliveryModels.Alert{sampleAlert(), sampleAlert()}
+	alertOutput := genBatchAlertOutput()
+
+	mockClient.On("SplunkHEC", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		panic("panicking")
+	})
+	go sendAlertBatch(alerts, alertOutput, time.Now().UTC(), ch)
+	for range alerts {
+		status := <-ch
+		assert.False(t, status.Success)
+		assert.False(t, status.NeedsRetry)
+		assert.Equal(t, "panic sending alert", status.Message)
+	}
+	mockClient.AssertExpectations(t)
+}
@@ -28,6 +28,8 @@ import (
 )
 
 // SendTestAlert sends a dummy alert to the specified destinations.
+//
+// Message templates of the destinations are rendered with the dummy alert, so this can be used to preview them.
 func (API) SendTestAlert(input *deliveryModels.SendTestAlertInput) ([]*deliveryModels.SendTestAlertOutput, error) {
 	// First, fetch the alert
 	zap.L().Debug("Sending test alert")
@@ -67,6 +69,7 @@ func generateTestAlert() *deliveryModels.Alert {
 		CreatedAt:           time.Now().UTC(),
 		Severity:            "INFO",
 		OutputIds:           []string{},
+		LogTypes:            []string{"Test.Log"},
 		AnalysisDescription: aws.String("This is a Test Alert"),
 		AnalysisName:        aws.String("Test Alert"),
 		Version:             aws.String("abcdefg"),
@@ -74,6 +77,8 @@ func generateTestAlert() *deliveryModels.Alert {
 		Tags:                []string{"test"},
 		AlertID:             aws.String("Test.Alert"),
 		Title:               aws.String("This is a Test Alert"),
+		DedupString:         aws.String("Test.Dedup"),
+		EventCount:          1,
 		RetryCount:          0,
 		IsTest:              true,
 		IsResent:            false,
@@ -0,0 +1,212 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"go.uber.org/zap"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/alerts/models"
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+	"github.com/panther-labs/panther/internal/core/alert_delivery/outputs"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+const (
+	defaultSyncLookbackDays = 7
+	syncListPageSize        = 50
+	// serviceNowQueryBatchSize limits the number of incidents looked up in a single request
+	serviceNowQueryBatchSize = 100
+)
+
+// SyncAlertStatus updates the status of open and triaged alerts from the state of the ServiceNow incidents created for them.
+//
+// Statuses only move forward, an alert is never reopened or moved back to triaged by the sync.
+// The update is attributed to the output that created the incident.
+func (API) SyncAlertStatus(input *deliveryModels.SyncAlertStatusInput) (*deliveryModels.SyncAlertStatusOutput, error) {
+	result := &deliveryModels.SyncAlertStatusOutput{}
+
+	alertOutputs, err := getOutputs()
+	if err != nil {
+		return nil, err
+	}
+	serviceNowOutputs := make(map[string]*outputModels.AlertOutput)
+	for _, output := range alertOutputs {
+		if aws.StringValue(output.OutputType) == "servicenow" && output.OutputConfig.ServiceNow != nil {
+			serviceNowOutputs[*output.OutputID] = output
+		}
+	}
+	if len(serviceNowOutputs) == 0 {
+		return result, nil
+	}
+
+	lookbackDays := input.LookbackDays
+	if lookbackDays == 0 {
+		lookbackDays = defaultSyncLookbackDays
+	}
+	alerts, err := listUnresolvedAlerts(time.Now().UTC().AddDate(0, 0, -lookbackDays))
+	if err != nil {
+		return nil, err
+	}
+	result.AlertsChecked = len(alerts)
+
+	// Group the alerts by output and incident
+	incidents := make(map[string]map[string][]*alertModels.AlertSummary)
+	for _, alert := range alerts {
+		for outputID, sysID := range getAlertIncidents(alert, serviceNowOutputs) {
+			if incidents[outputID] == nil {
+				incidents[outputID] = make(map[string][]*alertModels.AlertSummary)
+			}
+			incidents[outputID][sysID] = append(incidents[outputID][sysID], alert)
+		}
+	}
+
+	for outputID, alertsBySysID := range incidents {
+		result.AlertsUpdated += syncOutputIncidents(serviceNowOutputs[outputID], alertsBySysID)
+	}
+	return result, nil
+}
+
+// syncOutputIncidents looks up the incidents of an output and updates their alerts, returning the number of updates
+func syncOutputIncidents(output *outputModels.AlertOutput, alertsBySysID map[string][]*alertModels.AlertSummary) int {
+	sysIDs := make([]string, 0, len(alertsBySysID))
+	for sysID := range alertsBySysID {
+		sysIDs = append(sysIDs, sysID)
+	}
+	sort.Strings(sysIDs)
+
+	updated := 0
+	for start := 0; start < len(sysIDs); start += serviceNowQueryBatchSize {
+		end := start + serviceNowQueryBatchSize
+		if end > len(sysIDs) {
+			end = len(sysIDs)
+		}
+		incidents, err := outputClient.ServiceNowIncidents(output.OutputConfig.ServiceNow, sysIDs[start:end])
+		if err != nil {
+			// Other outputs can still be synced, this one is tried again on the next run
+			zap.L().Warn("failed to look up ServiceNow incidents", zap.Stringp("outputID", output.OutputID), zap.Error(err))
+			continue
+		}
+		for _, incident := range incidents {
+			status := getIncidentAlertStatus(incident.State)
+			if status == "" {
+				continue
+			}
+			for _, alert := range alertsBySysID[incident.SysID] {
+				if alertStatusRank(status) <= alertStatusRank(alert.Status) {
+					continue
+				}
+				if err := updateAlertStatus(alert, status, *output.OutputID); err != nil {
+					zap.L().Error("failed to sync alert status", zap.Stringp("alertID", alert.AlertID), zap.Error(err))
+					continue
+				}
+				// Another output must not move the alert back
+				alert.Status = status
+				updated++
+			}
+		}
+	}
+	return updated
+}
+
+// listUnresolvedAlerts lists the open and triaged alerts created after the given time
+func listUnresolvedAlerts(createdAfter time.Time) ([]*alertModels.AlertSummary, error) {
+	var alerts []*alertModels.AlertSummary
+	listInput := &alertModels.ListAlertsInput{
+		PageSize:       aws.Int(syncListPageSize),
+		Status:         []string{alertModels.OpenStatus, alertModels.TriagedStatus},
+		CreatedAtAfter: &createdAfter,
+	}
+	for {
+		input := alertModels.LambdaInput{ListAlerts: listInput}
+		var output alertModels.ListAlertsOutput
+		if err := genericapi.Invoke(lambdaClient, env.AlertsAPI, &input, &output); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, output.Alerts...)
+		if output.LastEvaluatedKey == nil {
+			return alerts, nil
+		}
+		listInput.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// getAlertIncidents returns the sys_id of the latest incident created for an alert by each ServiceNow output
+func getAlertIncidents(alert *alertModels.AlertSummary, serviceNowOutputs map[string]*outputModels.AlertOutput) map[string]string {
+	latest := make(map[string]*alertModels.DeliveryResponse)
+	for _, response := range alert.DeliveryResponses {
+		if response == nil || !response.Success || response.ExternalID == "" {
+			continue
+		}
+		if _, ok := serviceNowOutputs[response.OutputID]; !ok {
+			continue
+		}
+		if previous, ok := latest[response.OutputID]; !ok || response.DispatchedAt.After(previous.DispatchedAt) {
+			latest[response.OutputID] = response
+		}
+	}
+	result := make(map[string]string, len(latest))
+	for outputID, response := range latest {
+		result[outputID] = response.ExternalID
+	}
+	return result
+}
+
+// getIncidentAlertStatus maps the state of an incident to an alert status, empty if the alert should not change
+func getIncidentAlertStatus(state string) string {
+	switch state {
+	case outputs.ServiceNowStateInProgress, outputs.ServiceNowStateOnHold:
+		return alertModels.TriagedStatus
+	case outputs.ServiceNowStateResolved, outputs.ServiceNowStateClosed:
+		return alertModels.ResolvedStatus
+	case outputs.ServiceNowStateCanceled:
+		return alertModels.ClosedStatus
+	default:
+		return ""
+	}
+}
+
+// alertStatusRank orders the alert statuses, a sync only moves an alert to a higher rank
+func alertStatusRank(status string) int {
+	switch status {
+	case alertModels.TriagedStatus:
+		return 1
+	case alertModels.ResolvedStatus, alertModels.ClosedStatus:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// updateAlertStatus invokes the alerts api to update the status of an alert
+func updateAlertStatus(alert *alertModels.AlertSummary, status, outputID string) error {
+	input := alertModels.LambdaInput{
+		UpdateAlertStatus: &alertModels.UpdateAlertStatusInput{
+			AlertID: alert.AlertID,
+			Status:  aws.String(status),
+			UserID:  aws.String(outputID),
+		},
+	}
+	var output alertModels.UpdateAlertStatusOutput
+	return genericapi.Invoke(lambdaClient, env.AlertsAPI, &input, &output)
+}
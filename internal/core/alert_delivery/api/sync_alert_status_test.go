@@ -0,0 +1,167 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/alerts/models"
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+	"github.com/panther-labs/panther/internal/core/alert_delivery/outputs"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+const serviceNowOutputID = "9f3ba4ab-1bd4-4b8d-ae14-4a6d3ebd2a3c"
+
+func incidentAlert(alertID, status string, responses ...*alertModels.DeliveryResponse) *alertModels.AlertSummary {
+	return &alertModels.AlertSummary{
+		AlertID:           aws.String(alertID),
+		Status:            status,
+		DeliveryResponses: responses,
+	}
+}
+
+func incidentResponse(sysID string, dispatchedAt time.Time) *alertModels.DeliveryResponse {
+	return &alertModels.DeliveryResponse{
+		OutputID:     serviceNowOutputID,
+		StatusCode:   201,
+		Success:      true,
+		DispatchedAt: dispatchedAt,
+		ExternalID:   sysID,
+	}
+}
+
+func invokedOperation(operation string) interface{} {
+	return mock.MatchedBy(func(input *lambda.InvokeInput) bool {
+		return strings.Contains(string(input.Payload), `"`+operation+`":{`)
+	})
+}
+
+func TestSyncAlertStatus(t *testing.T) {
+	mockLambda := &testutils.LambdaMock{}
+	lambdaClient = mockLambda
+	mockClient := &mockOutputsClient{}
+	outputClient = mockClient
+
+	serviceNowConfig := &outputModels.ServiceNowConfig{InstanceURL: "https://example.service-now.com"}
+	outputsCache = &alertOutputsCache{
+		Outputs: []*outputModels.AlertOutput{
+			{
+				OutputID:     aws.String(serviceNowOutputID),
+				OutputType:   aws.String("servicenow"),
+				OutputConfig: &outputModels.OutputConfig{ServiceNow: serviceNowConfig},
+			},
+			genAlertOutput(),
+		},
+		Expiry:          time.Now(),
+		RefreshInterval: time.Minute,
+	}
+
+	now := time.Now().UTC()
+	firstPage := alertModels.ListAlertsOutput{
+		Alerts: []*alertModels.AlertSummary{
+			// In progress incident triages the alert
+			incidentAlert("alert-1", "", incidentResponse("incident-1", now)),
+			// The latest incident of the alert is resolved
+			incidentAlert("alert-2", alertModels.TriagedStatus,
+				incidentResponse("incident-old", now.Add(-time.Hour)), incidentResponse("incident-2", now)),
+		},
+		LastEvaluatedKey: aws.String("page-2"),
+	}
+	secondPage := alertModels.ListAlertsOutput{
+		Alerts: []*alertModels.AlertSummary{
+			// New incidents do not change the alert
+			incidentAlert("alert-3", "", incidentResponse("incident-3", now)),
+			// An alert is never moved back
+			incidentAlert("alert-4", alertModels.TriagedStatus, incidentResponse("incident-4", now)),
+			// Alerts without incidents are not looked up
+			incidentAlert("alert-5", "", &alertModels.DeliveryResponse{OutputID: "output-id", Success: true}),
+		},
+	}
+	for _, page := range []alertModels.ListAlertsOutput{firstPage, secondPage} {
+		payload, err := jsoniter.Marshal(page)
+		require.NoError(t, err)
+		mockLambda.On("Invoke", invokedOperation("listAlerts")).Return(&lambda.InvokeOutput{Payload: payload}, nil).Once()
+	}
+
+	var updates []alertModels.UpdateAlertStatusInput
+	mockLambda.On("Invoke", invokedOperation("updateAlertStatus")).Run(func(args mock.Arguments) {
+		var input alertModels.LambdaInput
+		require.NoError(t, jsoniter.Unmarshal(args.Get(0).(*lambda.InvokeInput).Payload, &input))
+		updates = append(updates, *input.UpdateAlertStatus)
+	}).Return(&lambda.InvokeOutput{Payload: []byte("{}")}, nil).Twice()
+
+	mockClient.On("ServiceNowIncidents", serviceNowConfig, []string{"incident-1", "incident-2", "incident-3", "incident-4"}).
+		Return([]*outputs.ServiceNowIncident{
+			{SysID: "incident-1", State: outputs.ServiceNowStateInProgress},
+			{SysID: "incident-2", State: outputs.ServiceNowStateResolved},
+			{SysID: "incident-3", State: outputs.ServiceNowStateNew},
+			{SysID: "incident-4", State: outputs.ServiceNowStateOnHold},
+		}, nil)
+
+	result, err := (API{}).SyncAlertStatus(&deliveryModels.SyncAlertStatusInput{})
+	require.NoError(t, err)
+	assert.Equal(t, &deliveryModels.SyncAlertStatusOutput{AlertsChecked: 5, AlertsUpdated: 2}, result)
+	assert.ElementsMatch(t, []alertModels.UpdateAlertStatusInput{
+		{
+			AlertID: aws.String("alert-1"),
+			Status:  aws.String(alertModels.TriagedStatus),
+			UserID:  aws.String(serviceNowOutputID),
+		},
+		{
+			AlertID: aws.String("alert-2"),
+			Status:  aws.String(alertModels.ResolvedStatus),
+			UserID:  aws.String(serviceNowOutputID),
+		},
+	}, updates)
+	mockLambda.AssertExpectations(t)
+	mockClient.AssertExpectations(t)
+}
+
+func TestSyncAlertStatusNoServiceNowOutputs(t *testing.T) {
+	mockLambda := &testutils.LambdaMock{}
+	lambdaClient = mockLambda
+	outputsCache = &alertOutputsCache{
+		Outputs:         []*outputModels.AlertOutput{genAlertOutput()},
+		Expiry:          time.Now(),
+		RefreshInterval: time.Minute,
+	}
+
+	result, err := (API{}).SyncAlertStatus(&deliveryModels.SyncAlertStatusInput{})
+	require.NoError(t, err)
+	assert.Equal(t, &deliveryModels.SyncAlertStatusOutput{}, result)
+	mockLambda.AssertExpectations(t)
+}
+
+func TestGetIncidentAlertStatus(t *testing.T) {
+	assert.Equal(t, "", getIncidentAlertStatus(outputs.ServiceNowStateNew))
+	assert.Equal(t, alertModels.TriagedStatus, getIncidentAlertStatus(outputs.ServiceNowStateOnHold))
+	assert.Equal(t, alertModels.ResolvedStatus, getIncidentAlertStatus(outputs.ServiceNowStateClosed))
+	assert.Equal(t, alertModels.ClosedStatus, getIncidentAlertStatus(outputs.ServiceNowStateCanceled))
+}
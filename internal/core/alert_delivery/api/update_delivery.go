@@ -43,6 +43,7 @@ func updateAlerts(statuses []DispatchStatus) []*alertModels.AlertSummary {
 			StatusCode:   status.StatusCode,
 			Success:      status.Success,
 			DispatchedAt: status.DispatchedAt,
+			ExternalID:   status.ExternalID,
 		}
 		alertMap[*status.Alert.AlertID] = append(alertMap[*status.Alert.AlertID], deliveryResponse)
 	}
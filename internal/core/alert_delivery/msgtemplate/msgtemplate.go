@@ -0,0 +1,226 @@
+// Package msgtemplate renders user-defined alert messages for outputs.
+//
+// Templates use the Go `text/template` syntax and are executed with the alert as data.
+// Only a fixed set of functions is available to templates and the size of the output is limited.
+package msgtemplate
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+)
+
+const (
+	// MaxTemplateSize is the maximum size of a message template
+	MaxTemplateSize = 10 * 1024
+	// MaxMessageSize is the maximum size of a rendered message
+	MaxMessageSize = 64 * 1024
+)
+
+// Data is the data message templates are executed with.
+//
+// All fields of the alert are available to the template, e.g. `{{ .Severity }}` or `{{ .DedupString }}`.
+type Data struct {
+	*deliveryModels.Alert
+	// Link is the link to the alert in the Panther UI
+	Link string
+}
+
+// funcs is the set of functions available to message templates.
+// Functions take the piped value as their last argument so they can be used in pipelines.
+var funcs = template.FuncMap{
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+	"trim":       strings.TrimSpace,
+	"replace":    replace,
+	"contains":   contains,
+	"hasPrefix":  hasPrefix,
+	"join":       join,
+	"truncate":   truncate,
+	"default":    defaultValue,
+	"toJSON":     toJSON,
+	"formatTime": formatTime,
+}
+
+// Parse parses a message template.
+//
+// Templates cannot define or invoke nested templates.
+func Parse(text string) (*template.Template, error) {
+	if len(text) > MaxTemplateSize {
+		return nil, errors.Errorf("template exceeds %d bytes", MaxTemplateSize)
+	}
+	tmpl, err := template.New("message").Option("missingkey=error").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(tmpl.Templates()) > 1 || hasTemplateAction(tmpl.Tree.Root) {
+		return nil, errors.New("nested templates are not allowed")
+	}
+	return tmpl, nil
+}
+
+// hasTemplateAction checks if a parse tree invokes a template
+func hasTemplateAction(node parse.Node) bool {
+	switch node := node.(type) {
+	case *parse.TemplateNode:
+		return true
+	case *parse.ListNode:
+		if node == nil {
+			return false
+		}
+		for _, child := range node.Nodes {
+			if hasTemplateAction(child) {
+				return true
+			}
+		}
+	case *parse.IfNode:
+		return hasTemplateAction(node.List) || hasTemplateAction(node.ElseList)
+	case *parse.RangeNode:
+		return hasTemplateAction(node.List) || hasTemplateAction(node.ElseList)
+	case *parse.WithNode:
+		return hasTemplateAction(node.List) || hasTemplateAction(node.ElseList)
+	}
+	return false
+}
+
+// Execute renders a message template.
+func Execute(tmpl *template.Template, data *Data) (string, error) {
+	w := limitWriter{max: MaxMessageSize}
+	if err := tmpl.Execute(&w, data); err != nil {
+		return "", err
+	}
+	return w.buf.String(), nil
+}
+
+// Render parses and renders a message template.
+func Render(text string, data *Data) (string, error) {
+	tmpl, err := Parse(text)
+	if err != nil {
+		return "", err
+	}
+	return Execute(tmpl, data)
+}
+
+// Validate checks that a message template can be rendered.
+//
+// The template is executed with a sample alert so that references to unknown fields are reported.
+func Validate(text string) error {
+	_, err := Render(text, SampleData())
+	return err
+}
+
+// SampleData returns template data for a sample alert with all fields set.
+func SampleData() *Data {
+	return &Data{
+		Alert: &deliveryModels.Alert{
+			AnalysisID:          "Sample.Rule",
+			Type:                deliveryModels.RuleType,
+			CreatedAt:           time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Severity:            "INFO",
+			OutputIds:           []string{},
+			LogTypes:            []string{"Sample.LogType"},
+			AnalysisDescription: aws.String("This is a sample alert"),
+			AnalysisName:        aws.String("Sample Rule"),
+			Version:             aws.String("abcdefg"),
+			Runbook:             aws.String("Sample runbook"),
+			Tags:                []string{"sample"},
+			AlertID:             aws.String("0123456789abcdef0123456789abcdef"),
+			Title:               aws.String("This is a sample alert"),
+			DedupString:         aws.String("sample"),
+			EventCount:          1,
+		},
+		Link: "https://panther.example.com/alerts",
+	}
+}
+
+// limitWriter fails writes once the size limit is exceeded
+type limitWriter struct {
+	buf bytes.Buffer
+	max int
+}
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	if w.buf.Len()+len(p) > w.max {
+		return 0, errors.Errorf("message exceeds %d bytes", w.max)
+	}
+	return w.buf.Write(p)
+}
+
+func replace(old, replacement, s string) string {
+	return strings.ReplaceAll(s, old, replacement)
+}
+
+func contains(substr, s string) bool {
+	return strings.Contains(s, substr)
+}
+
+func hasPrefix(prefix, s string) bool {
+	return strings.HasPrefix(s, prefix)
+}
+
+func join(sep string, values []string) string {
+	return strings.Join(values, sep)
+}
+
+// truncate shortens a string to at most n characters
+func truncate(n int, s string) string {
+	if n < 0 {
+		n = 0
+	}
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// defaultValue returns the value as a string or the default if the value is nil or empty
+func defaultValue(def string, value interface{}) string {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return def
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.IsZero() {
+		return def
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func toJSON(value interface{}) (string, error) {
+	return jsoniter.MarshalToString(value)
+}
+
+func formatTime(layout string, tm time.Time) string {
+	return tm.Format(layout)
+}
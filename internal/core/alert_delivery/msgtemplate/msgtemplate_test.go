@@ -0,0 +1,88 @@
+package msgtemplate
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	deliveryModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+)
+
+func TestRender(t *testing.T) {
+	data := &Data{
+		Alert: &deliveryModels.Alert{
+			AnalysisID: "AWS.GuardDuty.HighVolFindings",
+			CreatedAt:  time.Date(2020, 9, 1, 21, 10, 41, 0, time.UTC),
+			Severity:   "HIGH",
+			Tags:       []string{"prod", "aws"},
+			Title:      aws.String("High volume of GuardDuty findings"),
+			EventCount: 42,
+		},
+		Link: "https://panther.example.com/alerts/1",
+	}
+	for _, tc := range []struct {
+		Template string
+		Expect   string
+	}{
+		{`{{ .AnalysisID }}`, `AWS.GuardDuty.HighVolFindings`},
+		{`{{ .Alert.Severity | lower }}`, `high`},
+		{`{{ .Title | upper | truncate 4 }}`, `HIGH`},
+		{`{{ join ", " .Tags }}`, `prod, aws`},
+		{`{{ .Runbook | default "no runbook" }}`, `no runbook`},
+		{`{{ .EventCount | default "0" }}`, `42`},
+		{`{{ .CreatedAt | formatTime "2006-01-02" }}`, `2020-09-01`},
+		{`{{ .AnalysisID | replace "." "/" }}`, `AWS/GuardDuty/HighVolFindings`},
+		{`{{ if .AnalysisID | hasPrefix "AWS." }}aws{{ end }}`, `aws`},
+		{`{{ if contains "Guard" .AnalysisID }}guard{{ end }}`, `guard`},
+		{`{{ toJSON .Tags }}`, `["prod","aws"]`},
+		{`{{ "  padded  " | trim }}`, `padded`},
+		{`<{{ .Link }}|{{ .Title }}>`, `<https://panther.example.com/alerts/1|High volume of GuardDuty findings>`},
+	} {
+		tc := tc
+		t.Run(tc.Template, func(t *testing.T) {
+			actual, err := Render(tc.Template, data)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expect, actual)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(`{{ .Title }} [{{ .DedupString }}] {{ range .LogTypes }}{{ . }} {{ end }}`))
+	// Syntax errors
+	assert.Error(t, Validate(`{{ .Title `))
+	// Unknown fields
+	assert.Error(t, Validate(`{{ .NoSuchField }}`))
+	// Functions outside the allowed set
+	assert.Error(t, Validate(`{{ env "HOME" }}`))
+	// Nested templates
+	assert.Error(t, Validate(`{{ define "nested" }}{{ template "nested" }}{{ end }}{{ template "nested" }}`))
+	assert.Error(t, Validate(`{{ template "message" }}`))
+	assert.Error(t, Validate(`{{ if .Title }}{{ else }}{{ range .Tags }}{{ template "message" }}{{ end }}{{ end }}`))
+	// Size limits
+	assert.Error(t, Validate(strings.Repeat("x", MaxTemplateSize+1)))
+	assert.NoError(t, Validate(strings.Repeat(`{{ toJSON $ }}`, 10)))
+	assert.Error(t, Validate(strings.Repeat(`{{ toJSON $ }}`, 200)))
+}
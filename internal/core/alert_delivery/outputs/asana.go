@@ -33,13 +33,17 @@ const (
 )
 
 // Asana creates a task in Asana projects
-func (client *OutputClient) Asana(alert *alertModels.Alert, config *outputModels.AsanaConfig) *AlertDeliveryResponse {
+func (client *OutputClient) Asana(alert *alertModels.Alert, config *outputModels.AsanaConfig, message *string) *AlertDeliveryResponse {
 	zap.L().Debug("sending alert to Asana")
+	notes := generateDetailedAlertMessage(alert)
+	if message != nil {
+		notes = *message
+	}
 	payload := map[string]interface{}{
 		"data": map[string]interface{}{
 			"name":     generateAlertTitle(alert),
 			"projects": config.ProjectGids,
-			"notes":    generateDetailedAlertMessage(alert),
+			"notes":    notes,
 		},
 	}
 
@@ -67,6 +67,6 @@ func TestAsanaAlert(t *testing.T) {
 
 	httpWrapper.On("post", expectedPostInput).Return((*AlertDeliveryResponse)(nil))
 
-	require.Nil(t, client.Asana(alert, asanaConfig))
+	require.Nil(t, client.Asana(alert, asanaConfig, nil))
 	httpWrapper.AssertExpectations(t)
 }
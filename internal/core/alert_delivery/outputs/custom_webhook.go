@@ -19,17 +19,29 @@ package outputs
  */
 
 import (
+	jsoniter "github.com/json-iterator/go"
+
 	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
 	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
 )
 
 // CustomWebhook alert send an alert.
 func (client *OutputClient) CustomWebhook(
-	alert *alertModels.Alert, config *outputModels.CustomWebhookConfig) *AlertDeliveryResponse {
+	alert *alertModels.Alert, config *outputModels.CustomWebhookConfig, message *string) *AlertDeliveryResponse {
+
+	var body interface{} = generateNotificationFromAlert(alert)
+	if message != nil {
+		// Templates rendering JSON are posted as is, any other text is posted as a JSON string
+		if jsoniter.Valid([]byte(*message)) {
+			body = jsoniter.RawMessage(*message)
+		} else {
+			body = *message
+		}
+	}
 
 	postInput := &PostInput{
 		url:  config.WebhookURL,
-		body: generateNotificationFromAlert(alert),
+		body: body,
 	}
 	return client.httpWrapper.post(postInput)
 }
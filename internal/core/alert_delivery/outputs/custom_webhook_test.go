@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/require"
 
 	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
@@ -71,6 +72,34 @@ func TestCustomWebhookAlert(t *testing.T) {
 
 	httpWrapper.On("post", expectedPostInput).Return((*AlertDeliveryResponse)(nil))
 
-	require.Nil(t, client.CustomWebhook(alert, customWebhookConfig))
+	require.Nil(t, client.CustomWebhook(alert, customWebhookConfig, nil))
+	httpWrapper.AssertExpectations(t)
+}
+
+func TestCustomWebhookAlertMessageTemplate(t *testing.T) {
+	httpWrapper := &mockHTTPWrapper{}
+	client := &OutputClient{httpWrapper: httpWrapper}
+	alert := &alertModels.Alert{
+		AnalysisID: "policyId",
+		CreatedAt:  time.Now().UTC(),
+		Severity:   "INFO",
+	}
+
+	// Messages that are valid JSON are posted as is
+	message := `{"text": "policyId failed"}`
+	expectedPostInput := &PostInput{
+		url:  "custom-webhook-url",
+		body: jsoniter.RawMessage(message),
+	}
+	httpWrapper.On("post", expectedPostInput).Return((*AlertDeliveryResponse)(nil)).Once()
+	require.Nil(t, client.CustomWebhook(alert, customWebhookConfig, &message))
+
+	message = "policyId failed"
+	expectedPostInput = &PostInput{
+		url:  "custom-webhook-url",
+		body: message,
+	}
+	httpWrapper.On("post", expectedPostInput).Return((*AlertDeliveryResponse)(nil)).Once()
+	require.Nil(t, client.CustomWebhook(alert, customWebhookConfig, &message))
 	httpWrapper.AssertExpectations(t)
 }
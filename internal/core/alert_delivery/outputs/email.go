@@ -0,0 +1,267 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+const (
+	smtpStartTLSPort    = 587
+	smtpImplicitTLSPort = 465
+	smtpDialTimeout     = 10 * time.Second
+	// smtpTimeout bounds the whole SMTP conversation
+	smtpTimeout = 30 * time.Second
+)
+
+// Tests can replace this to trust a local SMTP server
+var getSMTPTLSConfig = buildSMTPTLSConfig
+
+var errSMTPNoStartTLS = errors.New("smtp server does not support STARTTLS")
+
+var emailHTMLTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<h2>{{ .Title }}</h2>
+{{- if .Message }}
+<pre style="white-space: pre-wrap;">{{ .Message }}</pre>
+{{- else }}
+<p><strong>Severity:</strong> {{ .Severity }}</p>
+<p><strong>Description:</strong> {{ .Description }}</p>
+<p><strong>Runbook:</strong> {{ .Runbook }}</p>
+{{- if .Tags }}
+<p><strong>Tags:</strong> {{ .Tags }}</p>
+{{- end }}
+{{- end }}
+<p><a href="{{ .Link }}">Click here to view in the Panther UI</a></p>
+</body>
+</html>
+`))
+
+// Email sends an alert to a list of recipients through an SMTP server.
+func (client *OutputClient) Email(
+	alert *alertModels.Alert, config *outputModels.EmailConfig, message *string) *AlertDeliveryResponse {
+
+	emailMessage, err := composeEmail(alert, config, message, time.Now().UTC())
+	if err != nil {
+		zap.L().Error("Failed to compose email", zap.Error(errors.WithStack(err)))
+		return &AlertDeliveryResponse{
+			StatusCode: 500,
+			Message:    "Failed to compose email",
+			Permanent:  true,
+			Success:    false,
+		}
+	}
+
+	recipients := append(append([]string{}, config.To...), config.Cc...)
+	if err := sendEmail(config, recipients, emailMessage); err != nil {
+		zap.L().Error("Failed to send email", zap.String("host", config.Host), zap.Error(err))
+		return getAlertResponseFromSMTPError(err)
+	}
+
+	return &AlertDeliveryResponse{
+		StatusCode: 200,
+		Message:    "email sent",
+		Permanent:  false,
+		Success:    true,
+	}
+}
+
+// sendEmail delivers a message over a TLS protected SMTP connection
+func sendEmail(config *outputModels.EmailConfig, recipients []string, message []byte) error {
+	implicitTLS := config.TLSMode == outputModels.EmailTLSModeImplicit
+	port := config.Port
+	if port == 0 {
+		port = smtpStartTLSPort
+		if implicitTLS {
+			port = smtpImplicitTLSPort
+		}
+	}
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(port))
+	tlsConfig := getSMTPTLSConfig(config.Host)
+
+	dialer := &net.Dialer{Timeout: smtpDialTimeout}
+	var conn net.Conn
+	var err error
+	if implicitTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(smtpTimeout)); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	smtpClient, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer smtpClient.Close()
+
+	if !implicitTLS {
+		// Never send credentials or alert contents in the clear
+		if ok, _ := smtpClient.Extension("STARTTLS"); !ok {
+			return errSMTPNoStartTLS
+		}
+		if err := smtpClient.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if config.UserName != "" {
+		if err := smtpClient.Auth(smtp.PlainAuth("", config.UserName, config.Password, config.Host)); err != nil {
+			return err
+		}
+	}
+	if err := smtpClient.Mail(config.From); err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err := smtpClient.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	w, err := smtpClient.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return smtpClient.Quit()
+}
+
+// composeEmail builds a multipart message with a plain text and an HTML version of the alert
+func composeEmail(alert *alertModels.Alert, config *outputModels.EmailConfig, message *string, date time.Time) ([]byte, error) {
+	title := generateAlertTitle(alert)
+	text := generateDetailedAlertMessage(alert)
+	if message != nil {
+		text = *message
+	}
+	var htmlBody bytes.Buffer
+	err := emailHTMLTemplate.Execute(&htmlBody, map[string]interface{}{
+		"Title":       title,
+		"Message":     aws.StringValue(message),
+		"Severity":    alert.Severity,
+		"Description": aws.StringValue(alert.AnalysisDescription),
+		"Runbook":     aws.StringValue(alert.Runbook),
+		"Tags":        strings.Join(alert.Tags, ", "),
+		"Link":        generateURL(alert),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	if err := writeEmailPart(parts, "text/plain; charset=utf-8", []byte(text)); err != nil {
+		return nil, err
+	}
+	if err := writeEmailPart(parts, "text/html; charset=utf-8", htmlBody.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	messageID, err := generateMessageID(config.From)
+	if err != nil {
+		return nil, err
+	}
+
+	var email bytes.Buffer
+	writeHeader := func(key, value string) {
+		email.WriteString(key + ": " + value + "\r\n")
+	}
+	writeHeader("From", config.From)
+	writeHeader("To", strings.Join(config.To, ", "))
+	if len(config.Cc) != 0 {
+		writeHeader("Cc", strings.Join(config.Cc, ", "))
+	}
+	// Encoding also protects against header injection through the alert title
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", title))
+	writeHeader("Date", date.Format(time.RFC1123Z))
+	writeHeader("Message-ID", messageID)
+	writeHeader("MIME-Version", "1.0")
+	writeHeader("Content-Type", "multipart/alternative; boundary="+parts.Boundary())
+	email.WriteString("\r\n")
+	email.Write(body.Bytes())
+	return email.Bytes(), nil
+}
+
+func writeEmailPart(parts *multipart.Writer, contentType string, content []byte) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	part, err := parts.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	w := quotedprintable.NewWriter(part)
+	if _, err := w.Write(content); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func generateMessageID(from string) (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	domain := "panther"
+	if at := strings.LastIndex(from, "@"); at != -1 {
+		domain = from[at+1:]
+	}
+	return "<" + hex.EncodeToString(id) + "@" + domain + ">", nil
+}
+
+func buildSMTPTLSConfig(host string) *tls.Config {
+	return &tls.Config{
+		ServerName: host,
+		MinVersion: tls.VersionTLS12,
+	}
+}
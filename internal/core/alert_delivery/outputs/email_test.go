@@ -0,0 +1,366 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io/ioutil"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+var emailAlert = &alertModels.Alert{
+	AnalysisID:          "policyId",
+	Type:                alertModels.PolicyType,
+	CreatedAt:           time.Now().UTC(),
+	Severity:            "HIGH",
+	AnalysisDescription: aws.String("<b>description</b>"),
+	Runbook:             aws.String("runbook"),
+	Tags:                []string{"prod"},
+}
+
+func TestEmailStartTLS(t *testing.T) {
+	server := startSMTPStandIn(t, false)
+	client := &OutputClient{}
+	config := server.config(outputModels.EmailTLSModeStartTLS)
+	config.Cc = []string{"cc@example.com"}
+
+	response := client.Email(emailAlert, config, nil)
+	require.NotNil(t, response)
+	require.True(t, response.Success, response.Message)
+	assert.Equal(t, 200, response.StatusCode)
+
+	envelope := server.lastEnvelope(t)
+	assert.True(t, envelope.TLS)
+	assert.Equal(t, "panther", envelope.User)
+	assert.Equal(t, "alerts@example.com", envelope.From)
+	assert.Equal(t, []string{"security@example.com", "cc@example.com"}, envelope.To)
+
+	msg, err := mail.ReadMessage(bytes.NewReader(envelope.Data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Policy Failure: policyId", subject)
+	assert.Equal(t, "security@example.com", msg.Header.Get("To"))
+	assert.Equal(t, "cc@example.com", msg.Header.Get("Cc"))
+	assert.True(t, strings.HasSuffix(msg.Header.Get("Message-ID"), "@example.com>"))
+
+	text, html := readEmailParts(t, msg)
+	assert.Equal(t, generateDetailedAlertMessage(emailAlert), text)
+	assert.Contains(t, html, "&lt;b&gt;description&lt;/b&gt;")
+	assert.Contains(t, html, `<a href="https://panther.io/policies/policyId">`)
+}
+
+func TestEmailImplicitTLSMessageTemplate(t *testing.T) {
+	server := startSMTPStandIn(t, true)
+	client := &OutputClient{}
+	config := server.config(outputModels.EmailTLSModeImplicit)
+
+	message := "policyId failed\non <resources>"
+	response := client.Email(emailAlert, config, &message)
+	require.True(t, response.Success, response.Message)
+
+	envelope := server.lastEnvelope(t)
+	assert.True(t, envelope.TLS)
+	msg, err := mail.ReadMessage(bytes.NewReader(envelope.Data))
+	require.NoError(t, err)
+	text, html := readEmailParts(t, msg)
+	assert.Equal(t, message, text)
+	assert.Contains(t, html, "policyId failed\non &lt;resources&gt;")
+}
+
+func TestEmailSMTPErrors(t *testing.T) {
+	server := startSMTPStandIn(t, false)
+	client := &OutputClient{}
+
+	// Transient failures are retried
+	server.rcptReply = "450 mailbox busy"
+	response := client.Email(emailAlert, server.config(outputModels.EmailTLSModeStartTLS), nil)
+	assert.False(t, response.Success)
+	assert.False(t, response.Permanent)
+	assert.Equal(t, 450, response.StatusCode)
+
+	// Permanent failures are not
+	server.rcptReply = "550 no such user"
+	response = client.Email(emailAlert, server.config(outputModels.EmailTLSModeStartTLS), nil)
+	assert.False(t, response.Success)
+	assert.True(t, response.Permanent)
+	assert.Equal(t, 550, response.StatusCode)
+
+	server.rcptReply = ""
+	config := server.config(outputModels.EmailTLSModeStartTLS)
+	config.Password = "wrong"
+	response = client.Email(emailAlert, config, nil)
+	assert.False(t, response.Success)
+	assert.True(t, response.Permanent)
+	assert.Equal(t, 535, response.StatusCode)
+}
+
+func TestEmailTLSErrors(t *testing.T) {
+	server := startSMTPStandIn(t, false)
+	client := &OutputClient{}
+
+	// Servers without STARTTLS are refused
+	server.noStartTLS = true
+	response := client.Email(emailAlert, server.config(outputModels.EmailTLSModeStartTLS), nil)
+	assert.False(t, response.Success)
+	assert.True(t, response.Permanent)
+	server.noStartTLS = false
+
+	// Untrusted certificates are refused
+	getSMTPTLSConfig = buildSMTPTLSConfig
+	response = client.Email(emailAlert, server.config(outputModels.EmailTLSModeStartTLS), nil)
+	assert.False(t, response.Success)
+	assert.True(t, response.Permanent)
+	assert.Contains(t, response.Message, "tls")
+}
+
+func TestEmailNetworkError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	client := &OutputClient{}
+	response := client.Email(emailAlert, &outputModels.EmailConfig{
+		Host: "127.0.0.1",
+		Port: port,
+		From: "alerts@example.com",
+		To:   []string{"security@example.com"},
+	}, nil)
+	assert.False(t, response.Success)
+	assert.False(t, response.Permanent)
+}
+
+func readEmailParts(t *testing.T, msg *mail.Message) (text, html string) {
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err != nil {
+			break
+		}
+		body, err := ioutil.ReadAll(part)
+		require.NoError(t, err)
+		switch part.Header.Get("Content-Type") {
+		case "text/plain; charset=utf-8":
+			text = string(body)
+		case "text/html; charset=utf-8":
+			html = string(body)
+		}
+	}
+	return text, html
+}
+
+// smtpStandIn is a minimal SMTP server to test email delivery
+type smtpStandIn struct {
+	listener    net.Listener
+	tlsConfig   *tls.Config
+	implicitTLS bool
+	noStartTLS  bool
+	rcptReply   string
+
+	mu        sync.Mutex
+	envelopes []smtpEnvelope
+}
+
+type smtpEnvelope struct {
+	TLS  bool
+	User string
+	From string
+	To   []string
+	Data []byte
+}
+
+func startSMTPStandIn(t *testing.T, implicitTLS bool) *smtpStandIn {
+	cert, pool := generateTestCertificate(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &smtpStandIn{
+		listener:    listener,
+		tlsConfig:   &tls.Config{Certificates: []tls.Certificate{cert}},
+		implicitTLS: implicitTLS,
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	getSMTPTLSConfig = func(host string) *tls.Config {
+		config := buildSMTPTLSConfig(host)
+		config.RootCAs = pool
+		return config
+	}
+	t.Cleanup(func() {
+		getSMTPTLSConfig = buildSMTPTLSConfig
+		_ = listener.Close()
+	})
+	return server
+}
+
+func (s *smtpStandIn) config(tlsMode string) *outputModels.EmailConfig {
+	return &outputModels.EmailConfig{
+		Host:     "127.0.0.1",
+		Port:     s.listener.Addr().(*net.TCPAddr).Port,
+		TLSMode:  tlsMode,
+		UserName: "panther",
+		Password: "secret",
+		From:     "alerts@example.com",
+		To:       []string{"security@example.com"},
+	}
+}
+
+func (s *smtpStandIn) lastEnvelope(t *testing.T) smtpEnvelope {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	require.NotEmpty(t, s.envelopes)
+	return s.envelopes[len(s.envelopes)-1]
+}
+
+// nolint: gocyclo
+func (s *smtpStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+	if s.implicitTLS {
+		conn = tls.Server(conn, s.tlsConfig)
+	}
+	tp := textproto.NewConn(conn)
+	envelope := smtpEnvelope{TLS: s.implicitTLS}
+	reply := func(lines ...string) {
+		for _, line := range lines {
+			_ = tp.PrintfLine("%s", line)
+		}
+	}
+	reply("220 localhost ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			reply("500 empty command")
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "EHLO", "HELO":
+			if !envelope.TLS && !s.noStartTLS {
+				reply("250-localhost", "250-STARTTLS", "250 AUTH PLAIN")
+			} else {
+				reply("250-localhost", "250 AUTH PLAIN")
+			}
+		case "STARTTLS":
+			reply("220 ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			tp = textproto.NewConn(conn)
+			envelope.TLS = true
+		case "AUTH":
+			credentials, err := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+			parts := strings.Split(string(credentials), "\x00")
+			if err != nil || len(parts) != 3 || parts[2] != "secret" {
+				reply("535 authentication failed")
+				continue
+			}
+			envelope.User = parts[1]
+			reply("235 authenticated")
+		case "MAIL":
+			envelope.From = strings.Trim(strings.TrimPrefix(line[len("MAIL "):], "FROM:"), "<>")
+			reply("250 OK")
+		case "RCPT":
+			if s.rcptReply != "" {
+				reply(s.rcptReply)
+				continue
+			}
+			envelope.To = append(envelope.To, strings.Trim(strings.TrimPrefix(line[len("RCPT "):], "TO:"), "<>"))
+			reply("250 OK")
+		case "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			envelope.Data = data
+			s.mu.Lock()
+			s.envelopes = append(s.envelopes, envelope)
+			s.mu.Unlock()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+// generateTestCertificate creates a self-signed certificate for 127.0.0.1
+func generateTestCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "smtp stand-in"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, pool
+}
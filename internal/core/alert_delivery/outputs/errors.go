@@ -33,6 +33,9 @@ type AlertDeliveryResponse struct {
 
 	// Success is true if we determine the request executed successfully. False otherwise.
 	Success bool
+
+	// ExternalID identifies the item the output created for the alert, if any.
+	ExternalID string
 }
 
 func (e *AlertDeliveryResponse) Error() string { return e.Message }
@@ -35,7 +35,7 @@ const (
 
 // Github alert send an issue.
 func (client *OutputClient) Github(
-	alert *alertModels.Alert, config *outputModels.GithubConfig) *AlertDeliveryResponse {
+	alert *alertModels.Alert, config *outputModels.GithubConfig, message *string) *AlertDeliveryResponse {
 
 	description := "**Description:** " + aws.StringValue(alert.AnalysisDescription)
 	link := "\n [Click here to view in the Panther UI](" + generateURL(alert) + ")"
@@ -43,9 +43,14 @@ func (client *OutputClient) Github(
 	severity := "\n **Severity:** " + alert.Severity
 	tags := "\n **Tags:** " + strings.Join(alert.Tags, ", ")
 
+	body := description + link + runBook + severity + tags
+	if message != nil {
+		body = *message
+	}
+
 	githubRequest := map[string]interface{}{
 		"title": generateAlertTitle(alert),
-		"body":  description + link + runBook + severity + tags,
+		"body":  body,
 	}
 
 	token := "token " + config.Token
@@ -34,7 +34,7 @@ const (
 
 // Jira alert send an issue.
 func (client *OutputClient) Jira(
-	alert *alertModels.Alert, config *outputModels.JiraConfig) *AlertDeliveryResponse {
+	alert *alertModels.Alert, config *outputModels.JiraConfig, message *string) *AlertDeliveryResponse {
 
 	description := "*Description:* " + aws.StringValue(alert.AnalysisDescription)
 	link := "\n [Click here to view in the Panther UI](" + generateURL(alert) + ")"
@@ -42,9 +42,14 @@ func (client *OutputClient) Jira(
 	severity := "\n *Severity:* " + alert.Severity
 	tags := "\n *Tags:* " + strings.Join(alert.Tags, ", ")
 
+	body := description + link + runBook + severity + tags
+	if message != nil {
+		body = *message
+	}
+
 	fields := map[string]interface{}{
 		"summary":     generateAlertTitle(alert),
-		"description": description + link + runBook + severity + tags,
+		"description": body,
 		"project": map[string]*string{
 			"key": aws.String(config.ProjectKey),
 		},
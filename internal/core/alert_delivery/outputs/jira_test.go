@@ -83,6 +83,6 @@ func TestJiraAlert(t *testing.T) {
 
 	httpWrapper.On("post", expectedPostInput).Return((*AlertDeliveryResponse)(nil))
 
-	require.Nil(t, client.Jira(alert, jiraConfig))
+	require.Nil(t, client.Jira(alert, jiraConfig, nil))
 	httpWrapper.AssertExpectations(t)
 }
@@ -29,25 +29,30 @@ import (
 
 // MsTeams alert send an alert.
 func (client *OutputClient) MsTeams(
-	alert *alertModels.Alert, config *outputModels.MsTeamsConfig) *AlertDeliveryResponse {
+	alert *alertModels.Alert, config *outputModels.MsTeamsConfig, message *string) *AlertDeliveryResponse {
 
 	link := "[Click here to view in the Panther UI](" + policyURLPrefix + alert.AnalysisID + ").\n"
 
+	section := map[string]interface{}{
+		"facts": []interface{}{
+			map[string]string{"name": "Description", "value": aws.StringValue(alert.AnalysisDescription)},
+			map[string]string{"name": "Runbook", "value": aws.StringValue(alert.Runbook)},
+			map[string]string{"name": "Severity", "value": alert.Severity},
+			map[string]string{"name": "Tags", "value": strings.Join(alert.Tags, ", ")},
+		},
+		"text": link,
+	}
+	if message != nil {
+		section = map[string]interface{}{
+			"text": *message,
+		}
+	}
+
 	msTeamsRequestBody := map[string]interface{}{
 		"@context": "http://schema.org/extensions",
 		"@type":    "MessageCard",
 		"text":     generateAlertTitle(alert),
-		"sections": []interface{}{
-			map[string]interface{}{
-				"facts": []interface{}{
-					map[string]string{"name": "Description", "value": aws.StringValue(alert.AnalysisDescription)},
-					map[string]string{"name": "Runbook", "value": aws.StringValue(alert.Runbook)},
-					map[string]string{"name": "Severity", "value": alert.Severity},
-					map[string]string{"name": "Tags", "value": strings.Join(alert.Tags, ", ")},
-				},
-				"text": link,
-			},
-		},
+		"sections": []interface{}{section},
 		"potentialAction": []interface{}{
 			map[string]interface{}{
 				"@type": "OpenUri",
@@ -84,6 +84,6 @@ func TestMsTeamsAlert(t *testing.T) {
 
 	httpWrapper.On("post", expectedPostInput).Return((*AlertDeliveryResponse)(nil))
 
-	require.Nil(t, client.MsTeams(alert, msTeamConfig))
+	require.Nil(t, client.MsTeams(alert, msTeamConfig, nil))
 	httpWrapper.AssertExpectations(t)
 }
@@ -37,16 +37,21 @@ var pantherToOpsGeniePriority = map[string]string{
 
 // Opsgenie alert send an alert.
 func (client *OutputClient) Opsgenie(
-	alert *alertModels.Alert, config *outputModels.OpsgenieConfig) *AlertDeliveryResponse {
+	alert *alertModels.Alert, config *outputModels.OpsgenieConfig, message *string) *AlertDeliveryResponse {
 
 	description := "<strong>Description:</strong> " + aws.StringValue(alert.AnalysisDescription)
 	link := "\n<a href=\"" + generateURL(alert) + "\">Click here to view in the Panther UI</a>"
 	runBook := "\n <strong>Runbook:</strong> " + aws.StringValue(alert.Runbook)
 	severity := "\n <strong>Severity:</strong> " + alert.Severity
 
+	body := description + link + runBook + severity
+	if message != nil {
+		body = *message
+	}
+
 	opsgenieRequest := map[string]interface{}{
 		"message":     generateAlertTitle(alert),
-		"description": description + link + runBook + severity,
+		"description": body,
 		"tags":        alert.Tags,
 		"priority":    pantherToOpsGeniePriority[alert.Severity],
 	}
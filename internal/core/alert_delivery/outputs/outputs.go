@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -29,6 +30,7 @@ import (
 
 	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
 	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+	"github.com/panther-labs/panther/internal/core/alert_delivery/msgtemplate"
 	"github.com/panther-labs/panther/pkg/gatewayapi"
 )
 
@@ -41,6 +43,9 @@ var (
 // HTTPWrapper encapsulates the Golang's http client
 type HTTPWrapper struct {
 	httpClient HTTPiface
+	// tlsClients caches the clients of outputs with a custom CA certificate by certificate
+	tlsClientsMu sync.Mutex
+	tlsClients   map[string]HTTPiface
 }
 
 // PostInput type
@@ -48,11 +53,20 @@ type PostInput struct {
 	url     string
 	body    interface{}
 	headers map[string]string
+	// caCertificate is a PEM encoded CA certificate trusted in addition to the system roots
+	caCertificate string
+}
+
+// GetInput type
+type GetInput struct {
+	url     string
+	headers map[string]string
 }
 
 // HTTPWrapperiface is the interface for our wrapper around Golang's http client
 type HTTPWrapperiface interface {
 	post(*PostInput) *AlertDeliveryResponse
+	get(*GetInput) *AlertDeliveryResponse
 }
 
 // HTTPiface is an interface for http.Client to simplify unit testing.
@@ -61,17 +75,26 @@ type HTTPiface interface {
 }
 
 // API is the interface for output delivery that can be used for mocks in tests.
+//
+// The message is the rendered message template of the output, nil if the output uses the default message.
+// Batch outputs receive multiple alerts with one message per alert.
+// ServiceNowIncidents is used to sync the status of alerts with the incidents created for them.
 type API interface {
-	Slack(*alertModels.Alert, *outputModels.SlackConfig) *AlertDeliveryResponse
-	PagerDuty(*alertModels.Alert, *outputModels.PagerDutyConfig) *AlertDeliveryResponse
-	Github(*alertModels.Alert, *outputModels.GithubConfig) *AlertDeliveryResponse
-	Jira(*alertModels.Alert, *outputModels.JiraConfig) *AlertDeliveryResponse
-	Opsgenie(*alertModels.Alert, *outputModels.OpsgenieConfig) *AlertDeliveryResponse
-	MsTeams(*alertModels.Alert, *outputModels.MsTeamsConfig) *AlertDeliveryResponse
-	Sqs(*alertModels.Alert, *outputModels.SqsConfig) *AlertDeliveryResponse
-	Sns(*alertModels.Alert, *outputModels.SnsConfig) *AlertDeliveryResponse
-	Asana(*alertModels.Alert, *outputModels.AsanaConfig) *AlertDeliveryResponse
-	CustomWebhook(*alertModels.Alert, *outputModels.CustomWebhookConfig) *AlertDeliveryResponse
+	Slack(*alertModels.Alert, *outputModels.SlackConfig, *string) *AlertDeliveryResponse
+	PagerDuty(*alertModels.Alert, *outputModels.PagerDutyConfig, *string) *AlertDeliveryResponse
+	Github(*alertModels.Alert, *outputModels.GithubConfig, *string) *AlertDeliveryResponse
+	Jira(*alertModels.Alert, *outputModels.JiraConfig, *string) *AlertDeliveryResponse
+	Opsgenie(*alertModels.Alert, *outputModels.OpsgenieConfig, *string) *AlertDeliveryResponse
+	MsTeams(*alertModels.Alert, *outputModels.MsTeamsConfig, *string) *AlertDeliveryResponse
+	Sqs(*alertModels.Alert, *outputModels.SqsConfig, *string) *AlertDeliveryResponse
+	Sns(*alertModels.Alert, *outputModels.SnsConfig, *string) *AlertDeliveryResponse
+	Asana(*alertModels.Alert, *outputModels.AsanaConfig, *string) *AlertDeliveryResponse
+	CustomWebhook(*alertModels.Alert, *outputModels.CustomWebhookConfig, *string) *AlertDeliveryResponse
+	Email(*alertModels.Alert, *outputModels.EmailConfig, *string) *AlertDeliveryResponse
+	SplunkHEC([]*alertModels.Alert, *outputModels.SplunkHECConfig, []*string) *AlertDeliveryResponse
+	Syslog([]*alertModels.Alert, *outputModels.SyslogConfig, []*string) *AlertDeliveryResponse
+	ServiceNow(*alertModels.Alert, *outputModels.ServiceNowConfig, *string) *AlertDeliveryResponse
+	ServiceNowIncidents(*outputModels.ServiceNowConfig, []string) ([]*ServiceNowIncident, error)
 }
 
 // OutputClient encapsulates the clients that allow sending alerts to multiple outputs
@@ -157,6 +180,23 @@ func generateNotificationFromAlert(alert *alertModels.Alert) Notification {
 	return notification
 }
 
+// RenderMessage renders the message template of an output for an alert.
+//
+// Returns nil if the output does not define a message template.
+func RenderMessage(alert *alertModels.Alert, output *outputModels.AlertOutput) (*string, error) {
+	if aws.StringValue(output.MessageTemplate) == "" {
+		return nil, nil
+	}
+	message, err := msgtemplate.Render(*output.MessageTemplate, &msgtemplate.Data{
+		Alert: alert,
+		Link:  generateURL(alert),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
 func generateAlertMessage(alert *alertModels.Alert) string {
 	if alert.Type == alertModels.RuleType {
 		return getDisplayName(alert) + " triggered"
@@ -26,6 +26,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	alertModel "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
 )
 
 func init() {
@@ -82,3 +83,33 @@ func TestGenerateAlertTitlePolicyId(t *testing.T) {
 	}
 	assert.Equal(t, "Policy Failure: policy.id", generateAlertTitle(alert))
 }
+
+func TestRenderMessage(t *testing.T) {
+	alert := &alertModel.Alert{
+		AnalysisID:  "rule.id",
+		Type:        alertModel.RuleType,
+		Severity:    "HIGH",
+		AlertID:     aws.String("alert-id"),
+		DedupString: aws.String("10.0.0.1"),
+		EventCount:  3,
+	}
+
+	// Outputs without a template use the default message
+	message, err := RenderMessage(alert, &outputModels.AlertOutput{})
+	assert.NoError(t, err)
+	assert.Nil(t, message)
+	message, err = RenderMessage(alert, &outputModels.AlertOutput{MessageTemplate: aws.String("")})
+	assert.NoError(t, err)
+	assert.Nil(t, message)
+
+	output := &outputModels.AlertOutput{
+		MessageTemplate: aws.String(`{{ .Severity | lower }}: {{ .DedupString }} ({{ .EventCount }} events) {{ .Link }}`),
+	}
+	message, err = RenderMessage(alert, output)
+	assert.NoError(t, err)
+	assert.Equal(t, aws.String("high: 10.0.0.1 (3 events) https://panther.io/alerts/alert-id"), message)
+
+	output.MessageTemplate = aws.String(`{{ .NoSuchField }}`)
+	_, err = RenderMessage(alert, output)
+	assert.Error(t, err)
+}
@@ -28,17 +28,26 @@ import (
 const (
 	pagerDutyEndpoint  = "https://events.pagerduty.com/v2/enqueue"
 	triggerEventAction = "trigger"
+	// PagerDuty rejects events with longer summaries
+	pagerDutyMaxSummaryLength = 1024
 )
 
 // PagerDuty sends an alert to a pager duty integration endpoint.
-func (client *OutputClient) PagerDuty(alert *alertModels.Alert, config *outputModels.PagerDutyConfig) *AlertDeliveryResponse {
+func (client *OutputClient) PagerDuty(
+	alert *alertModels.Alert, config *outputModels.PagerDutyConfig, message *string) *AlertDeliveryResponse {
+
 	severity, err := pantherSeverityToPagerDuty(alert.Severity)
 	if err != nil {
 		return err
 	}
 
+	summary := generateAlertTitle(alert)
+	if message != nil {
+		summary = truncateString(*message, pagerDutyMaxSummaryLength)
+	}
+
 	payload := map[string]interface{}{
-		"summary":        generateAlertTitle(alert),
+		"summary":        summary,
 		"severity":       severity,
 		"timestamp":      alert.CreatedAt.Format(time.RFC3339),
 		"source":         "pantherlabs",
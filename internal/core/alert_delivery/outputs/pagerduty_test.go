@@ -78,7 +78,7 @@ func TestSendPagerDutyAlert(t *testing.T) {
 	}
 
 	httpWrapper.On("post", expectedPostInput).Return((*AlertDeliveryResponse)(nil))
-	result := outputClient.PagerDuty(pagerDutyAlert, pagerDutyConfig)
+	result := outputClient.PagerDuty(pagerDutyAlert, pagerDutyConfig, nil)
 
 	assert.Nil(t, result)
 	httpWrapper.AssertExpectations(t)
@@ -90,6 +90,6 @@ func TestSendPagerDutyAlertPostError(t *testing.T) {
 
 	httpWrapper.On("post", mock.Anything).Return(&AlertDeliveryResponse{Message: "Exception"})
 
-	require.Error(t, outputClient.PagerDuty(pagerDutyAlert, pagerDutyConfig))
+	require.Error(t, outputClient.PagerDuty(pagerDutyAlert, pagerDutyConfig, nil))
 	httpWrapper.AssertExpectations(t)
 }
@@ -22,6 +22,7 @@ import (
 	"bytes"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	jsoniter "github.com/json-iterator/go"
 )
@@ -44,6 +45,18 @@ func (client *HTTPWrapper) post(input *PostInput) *AlertDeliveryResponse {
 		}
 	}
 
+	httpClient, err := client.clientFor(input.caCertificate)
+
+	// If the CA certificate of the output is invalid
+	if err != nil {
+		return &AlertDeliveryResponse{
+			StatusCode: 500, // Internal server error
+			Success:    false,
+			Message:    "tls error: " + err.Error(),
+			Permanent:  true,
+		}
+	}
+
 	request, err := http.NewRequest("POST", input.url, bytes.NewBuffer(payload))
 
 	// If there was an error creating the request
@@ -57,14 +70,46 @@ func (client *HTTPWrapper) post(input *PostInput) *AlertDeliveryResponse {
 	}
 
 	request.Header.Set("Content-Type", "application/json")
+	return client.send(httpClient, request, input.headers)
+}
+
+// get requests a JSON document from an endpoint.
+func (client *HTTPWrapper) get(input *GetInput) *AlertDeliveryResponse {
+	request, err := http.NewRequest("GET", input.url, nil)
+
+	// If there was an error creating the request
+	if err != nil {
+		return &AlertDeliveryResponse{
+			StatusCode: 500, // Internal server error
+			Success:    false,
+			Message:    "http request error: " + err.Error(),
+			Permanent:  true,
+		}
+	}
+
+	return client.send(client.httpClient, request, input.headers)
+}
+
+// send runs a request, the response body is the message of a successful response.
+func (client *HTTPWrapper) send(httpClient HTTPiface, request *http.Request, headers map[string]string) *AlertDeliveryResponse {
 	request.Header.Set("Accept", "application/json")
 
 	//Adding dynamic headers
-	for key, value := range input.headers {
+	for key, value := range headers {
 		request.Header.Set(key, value)
 	}
 
-	response, err := client.httpClient.Do(request)
+	response, err := httpClient.Do(request)
+
+	// Certificate errors are configuration errors that will not go away by retrying
+	if err != nil && isTLSError(err) {
+		return &AlertDeliveryResponse{
+			StatusCode: 500, // Internal server error
+			Success:    false,
+			Message:    "tls error: " + err.Error(),
+			Permanent:  true,
+		}
+	}
 
 	// If there was an error sending the request
 	if err != nil {
@@ -97,3 +142,28 @@ func (client *HTTPWrapper) post(input *PostInput) *AlertDeliveryResponse {
 		Permanent:  false,
 	}
 }
+
+// clientFor returns the client for requests to an output that trusts a CA certificate in addition to the system roots.
+// The clients are cached so that connections to the output are reused across deliveries.
+func (client *HTTPWrapper) clientFor(caCertificate string) (HTTPiface, error) {
+	if strings.TrimSpace(caCertificate) == "" {
+		return client.httpClient, nil
+	}
+	client.tlsClientsMu.Lock()
+	defer client.tlsClientsMu.Unlock()
+	if httpClient, ok := client.tlsClients[caCertificate]; ok {
+		return httpClient, nil
+	}
+	tlsConfig, err := buildTLSConfig("", caCertificate)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	httpClient := &http.Client{Transport: transport}
+	if client.tlsClients == nil {
+		client.tlsClients = make(map[string]HTTPiface)
+	}
+	client.tlsClients[caCertificate] = httpClient
+	return httpClient, nil
+}
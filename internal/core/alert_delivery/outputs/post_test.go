@@ -41,11 +41,13 @@ func (m *mockHTTPClient) Do(request *http.Request) (*http.Response, error) {
 	if m.requestError {
 		return nil, errors.New("endpoint unreachable")
 	}
-	requestBytes, err := ioutil.ReadAll(request.Body)
-	if err != nil {
-		panic(err)
+	if request.Body != nil {
+		requestBytes, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			panic(err)
+		}
+		m.requestBody = string(requestBytes)
 	}
-	m.requestBody = string(requestBytes)
 
 	responseBody := ioutil.NopCloser(bytes.NewReader([]byte("response")))
 	return &http.Response{Body: responseBody, StatusCode: m.statusCode}, nil
@@ -106,3 +108,10 @@ func TestPostCreated(t *testing.T) {
 		Permanent:  false,
 	}, c.post(postInput))
 }
+
+func TestGetNotOk(t *testing.T) {
+	c := &HTTPWrapper{httpClient: &mockHTTPClient{statusCode: http.StatusNotFound}}
+	response := c.get(&GetInput{url: requestEndpoint})
+	assert.False(t, response.Success)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
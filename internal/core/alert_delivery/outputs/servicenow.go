@@ -0,0 +1,160 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"go.uber.org/zap"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+const (
+	serviceNowIncidentEndpoint = "/api/now/table/incident"
+	serviceNowIncidentFields   = "sys_id,number,state"
+	// serviceNowMaxShortDescription is the size of the short_description column
+	serviceNowMaxShortDescription = 160
+)
+
+// Incident states of ServiceNow
+const (
+	ServiceNowStateNew        = "1"
+	ServiceNowStateInProgress = "2"
+	ServiceNowStateOnHold     = "3"
+	ServiceNowStateResolved   = "6"
+	ServiceNowStateClosed     = "7"
+	ServiceNowStateCanceled   = "8"
+)
+
+// serviceNowUrgencies maps alert severities to incident urgencies unless the output overrides them
+var serviceNowUrgencies = map[string]int{
+	"CRITICAL": 1,
+	"HIGH":     1,
+	"MEDIUM":   2,
+	"LOW":      3,
+	"INFO":     3,
+}
+
+// ServiceNowIncident is an incident returned by the ServiceNow Table API
+type ServiceNowIncident struct {
+	SysID  string `json:"sys_id"`
+	Number string `json:"number"`
+	State  string `json:"state"`
+}
+
+// ServiceNow alert creates an incident.
+//
+// The sys_id of the incident is returned as the external ID so its state can be synced back to the alert.
+func (client *OutputClient) ServiceNow(
+	alert *alertModels.Alert, config *outputModels.ServiceNowConfig, message *string) *AlertDeliveryResponse {
+
+	description := generateDetailedAlertMessage(alert)
+	if message != nil {
+		description = *message
+	}
+	correlationID := alert.AnalysisID
+	if alert.AlertID != nil {
+		correlationID = *alert.AlertID
+	}
+
+	incident := map[string]string{
+		"short_description":   truncateString(generateAlertTitle(alert), serviceNowMaxShortDescription),
+		"description":         description,
+		"urgency":             strconv.Itoa(getServiceNowUrgency(alert.Severity, config)),
+		"correlation_id":      correlationID,
+		"correlation_display": "Panther",
+	}
+	if config.AssignmentGroup != "" {
+		incident["assignment_group"] = config.AssignmentGroup
+	}
+
+	postInput := &PostInput{
+		url:     strings.TrimSuffix(config.InstanceURL, "/") + serviceNowIncidentEndpoint,
+		body:    incident,
+		headers: serviceNowHeaders(config),
+	}
+	response := client.httpWrapper.post(postInput)
+	if response == nil || !response.Success {
+		return response
+	}
+
+	var created struct {
+		Result ServiceNowIncident `json:"result"`
+	}
+	if err := jsoniter.UnmarshalFromString(response.Message, &created); err != nil || created.Result.SysID == "" {
+		// The incident was created, but its state cannot be synced
+		zap.L().Warn("failed to read the created ServiceNow incident", zap.String("response", response.Message))
+		return response
+	}
+	response.ExternalID = created.Result.SysID
+	response.Message = "created incident " + created.Result.Number
+	return response
+}
+
+// ServiceNowIncidents looks up the incidents with the given sys_ids.
+func (client *OutputClient) ServiceNowIncidents(
+	config *outputModels.ServiceNowConfig, sysIDs []string) ([]*ServiceNowIncident, error) {
+
+	query := url.Values{}
+	query.Set("sysparm_query", "sys_idIN"+strings.Join(sysIDs, ","))
+	query.Set("sysparm_fields", serviceNowIncidentFields)
+	query.Set("sysparm_limit", strconv.Itoa(len(sysIDs)))
+	getInput := &GetInput{
+		url:     strings.TrimSuffix(config.InstanceURL, "/") + serviceNowIncidentEndpoint + "?" + query.Encode(),
+		headers: serviceNowHeaders(config),
+	}
+	response := client.httpWrapper.get(getInput)
+	if response == nil {
+		return nil, &AlertDeliveryResponse{StatusCode: 500, Message: "response is nil"}
+	}
+	if !response.Success {
+		return nil, response
+	}
+
+	var result struct {
+		Result []*ServiceNowIncident `json:"result"`
+	}
+	if err := jsoniter.UnmarshalFromString(response.Message, &result); err != nil {
+		return nil, err
+	}
+	return result.Result, nil
+}
+
+func getServiceNowUrgency(severity string, config *outputModels.ServiceNowConfig) int {
+	if urgency, ok := config.Urgency[severity]; ok {
+		return urgency
+	}
+	if urgency, ok := serviceNowUrgencies[severity]; ok {
+		return urgency
+	}
+	return serviceNowUrgencies["INFO"]
+}
+
+func serviceNowHeaders(config *outputModels.ServiceNowConfig) map[string]string {
+	auth := config.UserName + ":" + config.Password
+	return map[string]string{
+		AuthorizationHTTPHeader: "Basic " + base64.StdEncoding.EncodeToString([]byte(auth)),
+	}
+}
@@ -0,0 +1,148 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+var serviceNowAlert = &alertModels.Alert{
+	AlertID:      aws.String("alertId"),
+	AnalysisID:   "ruleId",
+	AnalysisName: aws.String("Rule Name"),
+	Type:         alertModels.RuleType,
+	CreatedAt:    time.Now().UTC(),
+	Severity:     "MEDIUM",
+}
+
+// startServiceNowStandIn starts a local Table API that creates and returns incidents
+func startServiceNowStandIn(t *testing.T) (*httptest.Server, *[]map[string]string) {
+	var created []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "panther" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"message":"User Not Authenticated"}}`))
+			return
+		}
+		if r.URL.Path != "/api/now/table/incident" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case "POST":
+			var incident map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&incident); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			created = append(created, incident)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"result":{"sys_id":"46d44a5ddb6e3300c39e9c4b5e9619ec","number":"INC0010001","state":"1"}}`))
+		case "GET":
+			assert.Equal(t, "sys_idIN46d44a5ddb6e3300c39e9c4b5e9619ec,unknown", r.URL.Query().Get("sysparm_query"))
+			assert.Equal(t, "sys_id,number,state", r.URL.Query().Get("sysparm_fields"))
+			_, _ = w.Write([]byte(`{"result":[{"sys_id":"46d44a5ddb6e3300c39e9c4b5e9619ec","number":"INC0010001","state":"6"}]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &created
+}
+
+func serviceNowTestClient() *OutputClient {
+	return &OutputClient{httpWrapper: &HTTPWrapper{httpClient: &http.Client{}}}
+}
+
+func TestServiceNowCreateIncident(t *testing.T) {
+	server, created := startServiceNowStandIn(t)
+	config := &outputModels.ServiceNowConfig{
+		InstanceURL:     server.URL + "/",
+		UserName:        "panther",
+		Password:        "secret",
+		AssignmentGroup: "Security Incident Response",
+		Urgency:         map[string]int{"MEDIUM": 1},
+	}
+
+	response := serviceNowTestClient().ServiceNow(serviceNowAlert, config, aws.String("custom description"))
+	require.NotNil(t, response)
+	assert.Equal(t, &AlertDeliveryResponse{
+		StatusCode: http.StatusCreated,
+		Message:    "created incident INC0010001",
+		Success:    true,
+		ExternalID: "46d44a5ddb6e3300c39e9c4b5e9619ec",
+	}, response)
+
+	require.Len(t, *created, 1)
+	assert.Equal(t, map[string]string{
+		"short_description":   "New Alert: Rule Name",
+		"description":         "custom description",
+		"urgency":             "1",
+		"assignment_group":    "Security Incident Response",
+		"correlation_id":      "alertId",
+		"correlation_display": "Panther",
+	}, (*created)[0])
+}
+
+func TestServiceNowDefaultUrgency(t *testing.T) {
+	server, created := startServiceNowStandIn(t)
+	config := &outputModels.ServiceNowConfig{InstanceURL: server.URL, UserName: "panther", Password: "secret"}
+
+	response := serviceNowTestClient().ServiceNow(serviceNowAlert, config, nil)
+	require.NotNil(t, response)
+	assert.True(t, response.Success)
+	require.Len(t, *created, 1)
+	assert.Equal(t, "2", (*created)[0]["urgency"])
+	assert.NotContains(t, (*created)[0], "assignment_group")
+}
+
+func TestServiceNowAuthenticationFailure(t *testing.T) {
+	server, _ := startServiceNowStandIn(t)
+	config := &outputModels.ServiceNowConfig{InstanceURL: server.URL, UserName: "panther", Password: "wrong"}
+
+	response := serviceNowTestClient().ServiceNow(serviceNowAlert, config, nil)
+	require.NotNil(t, response)
+	assert.False(t, response.Success)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	assert.Empty(t, response.ExternalID)
+
+	_, err := serviceNowTestClient().ServiceNowIncidents(config, []string{"unknown"})
+	assert.Error(t, err)
+}
+
+func TestServiceNowIncidents(t *testing.T) {
+	server, _ := startServiceNowStandIn(t)
+	config := &outputModels.ServiceNowConfig{InstanceURL: server.URL, UserName: "panther", Password: "secret"}
+
+	incidents, err := serviceNowTestClient().ServiceNowIncidents(config, []string{"46d44a5ddb6e3300c39e9c4b5e9619ec", "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, []*ServiceNowIncident{
+		{SysID: "46d44a5ddb6e3300c39e9c4b5e9619ec", Number: "INC0010001", State: ServiceNowStateResolved},
+	}, incidents)
+}
@@ -37,7 +37,7 @@ var severityColors = map[string]string{
 }
 
 // Slack sends an alert to a slack channel.
-func (client *OutputClient) Slack(alert *alertModels.Alert, config *outputModels.SlackConfig) *AlertDeliveryResponse {
+func (client *OutputClient) Slack(alert *alertModels.Alert, config *outputModels.SlackConfig, message *string) *AlertDeliveryResponse {
 	messageField := fmt.Sprintf("<%s|%s>",
 		generateURL(alert),
 		"Click here to view in the Panther UI")
@@ -58,15 +58,19 @@ func (client *OutputClient) Slack(alert *alertModels.Alert, config *outputModels
 		},
 	}
 
+	attachment := map[string]interface{}{
+		"fallback": generateAlertTitle(alert),
+		"color":    severityColors[alert.Severity],
+		"title":    generateAlertTitle(alert),
+		"fields":   fields,
+	}
+	if message != nil {
+		delete(attachment, "fields")
+		attachment["text"] = *message
+	}
+
 	payload := map[string]interface{}{
-		"attachments": []map[string]interface{}{
-			{
-				"fallback": generateAlertTitle(alert),
-				"color":    severityColors[alert.Severity],
-				"title":    generateAlertTitle(alert),
-				"fields":   fields,
-			},
-		},
+		"attachments": []map[string]interface{}{attachment},
 	}
 	postInput := &PostInput{
 		url:  config.WebhookURL,
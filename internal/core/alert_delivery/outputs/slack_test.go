@@ -76,6 +76,6 @@ func TestSlackAlert(t *testing.T) {
 
 	httpWrapper.On("post", expectedPostInput).Return((*AlertDeliveryResponse)(nil))
 
-	require.Nil(t, client.Slack(alert, slackConfig))
+	require.Nil(t, client.Slack(alert, slackConfig, nil))
 	httpWrapper.AssertExpectations(t)
 }
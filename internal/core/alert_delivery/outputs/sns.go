@@ -43,7 +43,7 @@ var getSnsClient = buildSnsClient
 
 // Sns sends an alert to an SNS Topic.
 // nolint: dupl
-func (client *OutputClient) Sns(alert *alertModels.Alert, config *outputModels.SnsConfig) *AlertDeliveryResponse {
+func (client *OutputClient) Sns(alert *alertModels.Alert, config *outputModels.SnsConfig, message *string) *AlertDeliveryResponse {
 	notification := generateNotificationFromAlert(alert)
 	serializedDefaultMessage, err := jsoniter.MarshalToString(notification)
 	if err != nil {
@@ -61,6 +61,12 @@ func (client *OutputClient) Sns(alert *alertModels.Alert, config *outputModels.S
 		DefaultMessage: serializedDefaultMessage,
 		EmailMessage:   generateDetailedAlertMessage(alert),
 	}
+	if message != nil {
+		outputMessage = &snsMessage{
+			DefaultMessage: *message,
+			EmailMessage:   *message,
+		}
+	}
 
 	serializedMessage, err := jsoniter.MarshalToString(outputMessage)
 	if err != nil {
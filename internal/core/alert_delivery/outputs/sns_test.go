@@ -91,7 +91,7 @@ func TestSendSns(t *testing.T) {
 		return client, nil
 	}
 
-	result := outputClient.Sns(alert, snsOutputConfig)
+	result := outputClient.Sns(alert, snsOutputConfig, nil)
 	assert.NotNil(t, result)
 	assert.Equal(t, &AlertDeliveryResponse{
 		Message:    "messageId",
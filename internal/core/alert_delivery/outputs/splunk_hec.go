@@ -0,0 +1,143 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+const (
+	splunkHECEventPath         = "/services/collector/event"
+	splunkHECDefaultSource     = "panther"
+	splunkHECDefaultSourceType = "panther:alert"
+)
+
+// splunkHECEvent is the envelope of an event sent to the HTTP Event Collector
+type splunkHECEvent struct {
+	Time       float64     `json:"time"`
+	Source     string      `json:"source"`
+	SourceType string      `json:"sourcetype"`
+	Index      string      `json:"index,omitempty"`
+	Event      interface{} `json:"event"`
+}
+
+// SplunkHEC sends a batch of alerts to a Splunk HTTP Event Collector in a single request.
+//
+// The messages are the rendered message templates of the alerts, a nil message uses the default event.
+func (client *OutputClient) SplunkHEC(
+	alerts []*alertModels.Alert, config *outputModels.SplunkHECConfig, messages []*string) *AlertDeliveryResponse {
+
+	// The collector accepts a stream of concatenated JSON events
+	var payload bytes.Buffer
+	for i, alert := range alerts {
+		event, err := jsoniter.Marshal(generateSplunkHECEvent(alert, config, messages[i]))
+		if err != nil {
+			return &AlertDeliveryResponse{
+				StatusCode: 500,
+				Success:    false,
+				Message:    "json marshal error: " + err.Error(),
+				Permanent:  true,
+			}
+		}
+		payload.Write(event)
+		payload.WriteByte('\n')
+	}
+
+	endpoint, err := getSplunkHECEndpoint(config.URL)
+	if err != nil {
+		return &AlertDeliveryResponse{
+			StatusCode: 500,
+			Success:    false,
+			Message:    "invalid splunk url: " + err.Error(),
+			Permanent:  true,
+		}
+	}
+
+	postInput := &PostInput{
+		url: endpoint.String(),
+		// The raw message is sent as is, so the events are not wrapped in a JSON array
+		body: jsoniter.RawMessage(payload.Bytes()),
+		headers: map[string]string{
+			AuthorizationHTTPHeader: "Splunk " + config.Token,
+		},
+		caCertificate: config.CACertificate,
+	}
+	response := client.httpWrapper.post(postInput)
+	if !response.Success && isPermanentSplunkHECStatus(response.StatusCode) {
+		// Invalid tokens, disabled collectors and malformed events are not fixed by retrying
+		response.Permanent = true
+	}
+	return response
+}
+
+func generateSplunkHECEvent(alert *alertModels.Alert, config *outputModels.SplunkHECConfig, message *string) *splunkHECEvent {
+	var event interface{} = generateNotificationFromAlert(alert)
+	if message != nil {
+		// Templates rendering JSON are indexed as JSON events, any other text as a raw event
+		if jsoniter.Valid([]byte(*message)) {
+			event = jsoniter.RawMessage(*message)
+		} else {
+			event = *message
+		}
+	}
+	source, sourceType := config.Source, config.SourceType
+	if source == "" {
+		source = splunkHECDefaultSource
+	}
+	if sourceType == "" {
+		sourceType = splunkHECDefaultSourceType
+	}
+	return &splunkHECEvent{
+		Time:       float64(alert.CreatedAt.UnixNano()/int64(time.Millisecond)) / 1000,
+		Source:     source,
+		SourceType: sourceType,
+		Index:      config.Index,
+		Event:      event,
+	}
+}
+
+// getSplunkHECEndpoint appends the event endpoint to the collector URL unless it is already there
+func getSplunkHECEndpoint(collectorURL string) (*url.URL, error) {
+	endpoint, err := url.Parse(collectorURL)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(endpoint.Path, "/services/collector") {
+		endpoint.Path = strings.TrimSuffix(endpoint.Path, "/") + splunkHECEventPath
+	}
+	return endpoint, nil
+}
+
+func isPermanentSplunkHECStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	default:
+		return statusCode >= 400 && statusCode < 500
+	}
+}
@@ -0,0 +1,200 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+var splunkHECAlerts = []*alertModels.Alert{
+	{
+		AlertID:    aws.String("alert-1"),
+		AnalysisID: "ruleId",
+		Type:       alertModels.RuleType,
+		CreatedAt:  time.Date(2020, 6, 1, 12, 0, 0, 250000000, time.UTC),
+		Severity:   "HIGH",
+		Title:      aws.String("title"),
+	},
+	{
+		AlertID:    aws.String("alert-2"),
+		AnalysisID: "ruleId",
+		Type:       alertModels.RuleType,
+		CreatedAt:  time.Date(2020, 6, 1, 12, 0, 1, 0, time.UTC),
+		Severity:   "LOW",
+	},
+	{
+		AlertID:    aws.String("alert-3"),
+		AnalysisID: "ruleId",
+		Type:       alertModels.RuleType,
+		CreatedAt:  time.Date(2020, 6, 1, 12, 0, 2, 0, time.UTC),
+		Severity:   "LOW",
+	},
+}
+
+type splunkHECRequest struct {
+	path          string
+	authorization string
+	events        []map[string]interface{}
+}
+
+// startSplunkHECStandIn starts a TLS server that records the events it receives
+func startSplunkHECStandIn(t *testing.T, statusCode int) (*httptest.Server, chan splunkHECRequest) {
+	requests := make(chan splunkHECRequest, 1)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := splunkHECRequest{
+			path:          r.URL.Path,
+			authorization: r.Header.Get(AuthorizationHTTPHeader),
+		}
+		decoder := json.NewDecoder(r.Body)
+		for {
+			var event map[string]interface{}
+			if err := decoder.Decode(&event); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Error(err)
+				break
+			}
+			request.events = append(request.events, event)
+		}
+		requests <- request
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(`{"text":"Success","code":0}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func splunkHECTestConfig(server *httptest.Server) *outputModels.SplunkHECConfig {
+	return &outputModels.SplunkHECConfig{
+		URL:   server.URL,
+		Token: "token",
+		Index: "alerts",
+		CACertificate: string(pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: server.Certificate().Raw,
+		})),
+	}
+}
+
+func TestSplunkHECBatch(t *testing.T) {
+	server, requests := startSplunkHECStandIn(t, http.StatusOK)
+
+	messages := []*string{nil, aws.String(`{"custom": "event"}`), aws.String("text event")}
+	response := New(nil).SplunkHEC(splunkHECAlerts, splunkHECTestConfig(server), messages)
+	require.NotNil(t, response)
+	assert.True(t, response.Success)
+	assert.Equal(t, 200, response.StatusCode)
+
+	request := <-requests
+	assert.Equal(t, "/services/collector/event", request.path)
+	assert.Equal(t, "Splunk token", request.authorization)
+	require.Len(t, request.events, 3)
+
+	first := request.events[0]
+	assert.Equal(t, 1591012800.25, first["time"])
+	assert.Equal(t, "panther", first["source"])
+	assert.Equal(t, "panther:alert", first["sourcetype"])
+	assert.Equal(t, "alerts", first["index"])
+	event := first["event"].(map[string]interface{})
+	assert.Equal(t, "alert-1", event["alertId"])
+	assert.Equal(t, "New Alert: title", event["title"])
+
+	assert.Equal(t, map[string]interface{}{"custom": "event"}, request.events[1]["event"])
+	assert.Equal(t, "text event", request.events[2]["event"])
+}
+
+func TestSplunkHECEndpoint(t *testing.T) {
+	endpoint, err := getSplunkHECEndpoint("https://splunk.example.com:8088/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://splunk.example.com:8088/services/collector/event", endpoint.String())
+
+	endpoint, err = getSplunkHECEndpoint("https://splunk.example.com/services/collector/raw")
+	require.NoError(t, err)
+	assert.Equal(t, "https://splunk.example.com/services/collector/raw", endpoint.String())
+}
+
+func TestSplunkHECErrors(t *testing.T) {
+	testCases := []struct {
+		statusCode int
+		permanent  bool
+	}{
+		{statusCode: http.StatusForbidden, permanent: true},
+		{statusCode: http.StatusBadRequest, permanent: true},
+		{statusCode: http.StatusTooManyRequests, permanent: false},
+		{statusCode: http.StatusServiceUnavailable, permanent: false},
+	}
+	for _, tc := range testCases {
+		server, requests := startSplunkHECStandIn(t, tc.statusCode)
+		response := New(nil).SplunkHEC(splunkHECAlerts[:1], splunkHECTestConfig(server), []*string{nil})
+		<-requests
+		require.NotNil(t, response)
+		assert.False(t, response.Success)
+		assert.Equal(t, tc.statusCode, response.StatusCode)
+		assert.Equal(t, tc.permanent, response.Permanent, tc.statusCode)
+	}
+}
+
+func TestSplunkHECUntrustedCertificate(t *testing.T) {
+	server, _ := startSplunkHECStandIn(t, http.StatusOK)
+	config := splunkHECTestConfig(server)
+	config.CACertificate = ""
+
+	response := New(nil).SplunkHEC(splunkHECAlerts[:1], config, []*string{nil})
+	require.NotNil(t, response)
+	assert.False(t, response.Success)
+	assert.True(t, response.Permanent)
+	assert.Contains(t, response.Message, "tls error")
+}
+
+func TestSplunkHECClientReuse(t *testing.T) {
+	server, requests := startSplunkHECStandIn(t, http.StatusOK)
+	config := splunkHECTestConfig(server)
+	client := New(nil)
+	wrapper := client.httpWrapper.(*HTTPWrapper)
+
+	for i := 0; i < 2; i++ {
+		response := client.SplunkHEC(splunkHECAlerts[:1], config, []*string{nil})
+		<-requests
+		require.True(t, response.Success)
+	}
+	// Deliveries to outputs with the same CA certificate share a client
+	require.Len(t, wrapper.tlsClients, 1)
+	httpClient, err := wrapper.clientFor(config.CACertificate)
+	require.NoError(t, err)
+	require.Same(t, wrapper.tlsClients[config.CACertificate], httpClient)
+	httpClient, err = wrapper.clientFor("")
+	require.NoError(t, err)
+	require.Same(t, wrapper.httpClient, httpClient)
+
+	_, err = wrapper.clientFor("not a certificate")
+	require.Error(t, err)
+}
@@ -37,7 +37,7 @@ var getSqsClient = buildSqsClient
 
 // Sqs sends an alert to an SQS Queue.
 // nolint: dupl
-func (client *OutputClient) Sqs(alert *alertModels.Alert, config *outputModels.SqsConfig) *AlertDeliveryResponse {
+func (client *OutputClient) Sqs(alert *alertModels.Alert, config *outputModels.SqsConfig, message *string) *AlertDeliveryResponse {
 	notification := generateNotificationFromAlert(alert)
 
 	serializedMessage, err := jsoniter.MarshalToString(notification)
@@ -50,6 +50,9 @@ func (client *OutputClient) Sqs(alert *alertModels.Alert, config *outputModels.S
 			Success:    false,
 		}
 	}
+	if message != nil {
+		serializedMessage = *message
+	}
 
 	sqsSendMessageInput := &sqs.SendMessageInput{
 		QueueUrl:    aws.String(config.QueueURL),
@@ -71,7 +71,7 @@ func TestSendSqs(t *testing.T) {
 		return client
 	}
 
-	result := outputClient.Sqs(alert, sqsOutputConfig)
+	result := outputClient.Sqs(alert, sqsOutputConfig, nil)
 	assert.NotNil(t, result)
 	assert.Equal(t, &AlertDeliveryResponse{
 		Message:    "messageId",
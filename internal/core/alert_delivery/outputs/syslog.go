@@ -0,0 +1,210 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"crypto/tls"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+const (
+	syslogTLSPort         = 6514
+	syslogTCPPort         = 514
+	syslogDefaultAppName  = "panther"
+	syslogDefaultFacility = 13 // log audit
+	syslogDialTimeout     = 10 * time.Second
+	syslogTimeout         = 30 * time.Second
+	// syslogSDID is the SD-ID of the structured data element with the alert fields.
+	// 32473 is the private enterprise number reserved for documentation by RFC 5612.
+	syslogSDID = "alert@32473"
+	// syslogTimestampLayout is RFC3339 with the maximum precision allowed by RFC5424
+	syslogTimestampLayout = "2006-01-02T15:04:05.000000Z07:00"
+	// syslogBOM marks the MSG part as UTF-8
+	syslogBOM = "\ufeff"
+	syslogNil = "-"
+)
+
+// syslogSeverities maps alert severities to syslog severity codes
+var syslogSeverities = map[string]int{
+	"CRITICAL": 2, // critical
+	"HIGH":     3, // error
+	"MEDIUM":   4, // warning
+	"LOW":      5, // notice
+	"INFO":     6, // informational
+}
+
+var syslogParamEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// Syslog sends a batch of alerts to a syslog server as RFC5424 messages over a single connection.
+//
+// Messages are framed with octet counting (RFC6587) so they can contain new lines.
+// The messages are the rendered message templates of the alerts, a nil message uses the alert title.
+func (client *OutputClient) Syslog(
+	alerts []*alertModels.Alert, config *outputModels.SyslogConfig, messages []*string) *AlertDeliveryResponse {
+
+	var payload bytes.Buffer
+	for i, alert := range alerts {
+		message := formatSyslogMessage(alert, config, messages[i])
+		payload.WriteString(strconv.Itoa(len(message)))
+		payload.WriteByte(' ')
+		payload.WriteString(message)
+	}
+
+	if err := sendSyslog(config, payload.Bytes()); err != nil {
+		zap.L().Error("Failed to send syslog messages", zap.String("host", config.Host), zap.Error(err))
+		if isTLSError(err) {
+			return &AlertDeliveryResponse{
+				StatusCode: 500,
+				Success:    false,
+				Message:    "syslog tls error: " + err.Error(),
+				Permanent:  true,
+			}
+		}
+		return getResponse(500, "network error: "+err.Error())
+	}
+
+	return &AlertDeliveryResponse{
+		StatusCode: 200,
+		Message:    "syslog messages sent",
+		Permanent:  false,
+		Success:    true,
+	}
+}
+
+// sendSyslog writes framed messages to the syslog server
+func sendSyslog(config *outputModels.SyslogConfig, payload []byte) error {
+	useTLS := config.Protocol != outputModels.SyslogProtocolTCP
+	port := config.Port
+	if port == 0 {
+		port = syslogTLSPort
+		if !useTLS {
+			port = syslogTCPPort
+		}
+	}
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(port))
+
+	dialer := &net.Dialer{Timeout: syslogDialTimeout}
+	var conn net.Conn
+	if useTLS {
+		tlsConfig, err := buildTLSConfig(config.Host, config.CACertificate)
+		if err != nil {
+			return err
+		}
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		conn, err = dialer.Dial("tcp", addr)
+		if err != nil {
+			return err
+		}
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(syslogTimeout)); err != nil {
+		return err
+	}
+	_, err := conn.Write(payload)
+	return err
+}
+
+// formatSyslogMessage formats an alert as an RFC5424 syslog message
+func formatSyslogMessage(alert *alertModels.Alert, config *outputModels.SyslogConfig, message *string) string {
+	facility := syslogDefaultFacility
+	if config.Facility != nil {
+		facility = *config.Facility
+	}
+	severity, ok := syslogSeverities[alert.Severity]
+	if !ok {
+		severity = syslogSeverities["INFO"]
+	}
+	appName := config.AppName
+	if appName == "" {
+		appName = syslogDefaultAppName
+	}
+	msgID := alert.Type
+	if msgID == "" {
+		msgID = syslogNil
+	}
+	msg := generateAlertTitle(alert)
+	if message != nil {
+		msg = *message
+	}
+
+	var b strings.Builder
+	b.WriteString("<" + strconv.Itoa(facility*8+severity) + ">1 ")
+	b.WriteString(alert.CreatedAt.UTC().Format(syslogTimestampLayout) + " ")
+	b.WriteString(getSyslogHostname() + " ")
+	b.WriteString(appName + " ")
+	b.WriteString(syslogNil + " ")
+	b.WriteString(msgID + " ")
+	b.WriteString(formatSyslogStructuredData(alert))
+	b.WriteString(" " + syslogBOM + msg)
+	return b.String()
+}
+
+// formatSyslogStructuredData encodes the alert fields as a single structured data element
+func formatSyslogStructuredData(alert *alertModels.Alert) string {
+	var b strings.Builder
+	writeParam := func(name, value string) {
+		b.WriteString(" " + name + `="` + syslogParamEscaper.Replace(value) + `"`)
+	}
+	b.WriteString("[" + syslogSDID)
+	writeParam("id", alert.AnalysisID)
+	if alert.AlertID != nil {
+		writeParam("alertId", *alert.AlertID)
+	}
+	writeParam("name", getDisplayName(alert))
+	writeParam("severity", alert.Severity)
+	writeParam("type", alert.Type)
+	writeParam("title", generateAlertTitle(alert))
+	writeParam("link", generateURL(alert))
+	if alert.EventCount != 0 {
+		writeParam("eventCount", strconv.Itoa(alert.EventCount))
+	}
+	// Parameters can be repeated within an element
+	for _, logType := range alert.LogTypes {
+		writeParam("logType", logType)
+	}
+	for _, tag := range alert.Tags {
+		writeParam("tag", tag)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// getSyslogHostname identifies the Panther deployment that sent the message
+func getSyslogHostname() string {
+	if parsed, err := url.Parse(appDomainURL); err == nil && parsed.Hostname() != "" {
+		return parsed.Hostname()
+	}
+	return syslogNil
+}
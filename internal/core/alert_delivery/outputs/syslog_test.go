@@ -0,0 +1,172 @@
+package outputs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/pem"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	alertModels "github.com/panther-labs/panther/api/lambda/delivery/models"
+	outputModels "github.com/panther-labs/panther/api/lambda/outputs/models"
+)
+
+var syslogAlert = &alertModels.Alert{
+	AlertID:      aws.String("alertId"),
+	AnalysisID:   "ruleId",
+	AnalysisName: aws.String("Rule [Name]"),
+	Type:         alertModels.RuleType,
+	CreatedAt:    time.Date(2020, 6, 1, 12, 0, 0, 123456789, time.UTC),
+	Severity:     "HIGH",
+	Title:        aws.String(`say "hi" \o/`),
+	LogTypes:     []string{"AWS.CloudTrail"},
+	Tags:         []string{"prod", "pci"},
+	EventCount:   3,
+}
+
+func TestFormatSyslogMessage(t *testing.T) {
+	config := &outputModels.SyslogConfig{Host: "syslog.example.com"}
+	expected := `<107>1 2020-06-01T12:00:00.123456Z - panther - RULE ` +
+		`[alert@32473 id="ruleId" alertId="alertId" name="Rule [Name\]" severity="HIGH" type="RULE" ` +
+		`title="New Alert: say \"hi\" \\o/" link="https://panther.io/alerts/alertId" eventCount="3" logType="AWS.CloudTrail" tag="prod" tag="pci"] ` +
+		"\ufeffNew Alert: say \"hi\" \\o/"
+	assert.Equal(t, expected, formatSyslogMessage(syslogAlert, config, nil))
+
+	appDomainURL = "https://panther.example.com"
+	defer func() { appDomainURL = "" }()
+	config = &outputModels.SyslogConfig{Host: "syslog.example.com", AppName: "soc", Facility: aws.Int(4)}
+	message := formatSyslogMessage(syslogAlert, config, aws.String("custom\nmessage"))
+	assert.True(t, strings.HasPrefix(message, "<35>1 2020-06-01T12:00:00.123456Z panther.example.com soc - RULE [alert@32473 "))
+	assert.True(t, strings.HasSuffix(message, "] \ufeffcustom\nmessage"))
+
+	// Facility 0 is kernel messages
+	config.Facility = aws.Int(0)
+	assert.True(t, strings.HasPrefix(formatSyslogMessage(syslogAlert, config, nil), "<3>1 "))
+}
+
+// startSyslogStandIn starts a syslog server that sends the messages of each connection to the returned channel
+func startSyslogStandIn(t *testing.T, useTLS bool) (*outputModels.SyslogConfig, chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	config := &outputModels.SyslogConfig{
+		Host:     "127.0.0.1",
+		Port:     listener.Addr().(*net.TCPAddr).Port,
+		Protocol: outputModels.SyslogProtocolTCP,
+	}
+	if useTLS {
+		cert, _ := generateTestCertificate(t)
+		listener = tls.NewListener(listener, &tls.Config{Certificates: []tls.Certificate{cert}})
+		config.Protocol = outputModels.SyslogProtocolTLS
+		config.CACertificate = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}))
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	received := make(chan []string, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			received <- readSyslogFrames(conn)
+			_ = conn.Close()
+		}
+	}()
+	return config, received
+}
+
+// readSyslogFrames reads octet counted messages until the connection is closed
+func readSyslogFrames(conn net.Conn) []string {
+	var messages []string
+	reader := bufio.NewReader(conn)
+	for {
+		length, err := reader.ReadString(' ')
+		if err != nil {
+			return messages
+		}
+		size, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+		if err != nil {
+			return messages
+		}
+		message := make([]byte, size)
+		if _, err := io.ReadFull(reader, message); err != nil {
+			return messages
+		}
+		messages = append(messages, string(message))
+	}
+}
+
+func TestSyslogTLSBatch(t *testing.T) {
+	config, received := startSyslogStandIn(t, true)
+	alerts := []*alertModels.Alert{syslogAlert, syslogAlert}
+	messages := []*string{nil, aws.String("line 1\nline 2")}
+
+	response := (&OutputClient{}).Syslog(alerts, config, messages)
+	require.NotNil(t, response)
+	assert.True(t, response.Success)
+
+	frames := <-received
+	require.Len(t, frames, 2)
+	assert.Equal(t, formatSyslogMessage(syslogAlert, config, nil), frames[0])
+	assert.True(t, strings.HasSuffix(frames[1], "\ufeffline 1\nline 2"))
+}
+
+func TestSyslogTCP(t *testing.T) {
+	config, received := startSyslogStandIn(t, false)
+
+	response := (&OutputClient{}).Syslog([]*alertModels.Alert{syslogAlert}, config, []*string{nil})
+	require.NotNil(t, response)
+	assert.True(t, response.Success)
+	assert.Len(t, <-received, 1)
+}
+
+func TestSyslogUntrustedCertificate(t *testing.T) {
+	config, _ := startSyslogStandIn(t, true)
+	config.CACertificate = ""
+
+	response := (&OutputClient{}).Syslog([]*alertModels.Alert{syslogAlert}, config, []*string{nil})
+	require.NotNil(t, response)
+	assert.False(t, response.Success)
+	assert.True(t, response.Permanent)
+	assert.Contains(t, response.Message, "syslog tls error")
+}
+
+func TestSyslogNetworkError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	config := &outputModels.SyslogConfig{Host: "127.0.0.1", Port: port}
+	response := (&OutputClient{}).Syslog([]*alertModels.Alert{syslogAlert}, config, []*string{nil})
+	require.NotNil(t, response)
+	assert.False(t, response.Success)
+	assert.False(t, response.Permanent)
+	assert.Contains(t, response.Message, "network error")
+}
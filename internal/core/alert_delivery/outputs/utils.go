@@ -18,6 +18,11 @@ package outputs
  * along with this program.  If not, see <https://www.gnu.org/licenses/>.
  */
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/textproto"
+	"strings"
+
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/aws/aws-sdk-go/service/sqs"
@@ -42,6 +47,38 @@ func getAlertResponseFromSNSError(err error) *AlertDeliveryResponse {
 	return getResponse(500, err.Error())
 }
 
+// getAlertResponseFromSMTPError classifies SMTP failures
+//
+// SMTP replies in the 4xx range are transient and replies in the 5xx range are permanent.
+// The reply code is used as the response status code.
+// TLS failures are configuration errors that will not go away by retrying.
+// Any other error is a network error that can be retried.
+func getAlertResponseFromSMTPError(err error) *AlertDeliveryResponse {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		response := getResponse(protoErr.Code, "smtp error: "+err.Error())
+		response.Permanent = protoErr.Code >= 500
+		return response
+	}
+	if isTLSError(err) || errors.Is(err, errSMTPNoStartTLS) {
+		response := getResponse(500, "smtp tls error: "+err.Error())
+		response.Permanent = true
+		return response
+	}
+	return getResponse(500, "network error: "+err.Error())
+}
+
+func isTLSError(err error) bool {
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certificateInvalidErr x509.CertificateInvalidError
+	var recordHeaderErr tls.RecordHeaderError
+	return errors.As(err, &unknownAuthorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &certificateInvalidErr) ||
+		errors.As(err, &recordHeaderErr)
+}
+
 // getResponse - generates a failed response that can be retried
 func getResponse(statusCode int, message string) *AlertDeliveryResponse {
 	return &AlertDeliveryResponse{
@@ -99,3 +136,32 @@ func mapSQSSendMessageErrorCodeToStatusCode(awsErr awserr.Error) int {
 		return 500
 	}
 }
+
+// truncateString shortens a string to at most n characters
+func truncateString(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// buildTLSConfig trusts the PEM encoded CA certificate of an output in addition to the system roots
+func buildTLSConfig(serverName, caCertificate string) (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName: serverName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if strings.TrimSpace(caCertificate) == "" {
+		return config, nil
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM([]byte(caCertificate)) {
+		return nil, errors.New("invalid CA certificate")
+	}
+	config.RootCAs = roots
+	return config, nil
+}
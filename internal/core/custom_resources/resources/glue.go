@@ -32,6 +32,7 @@ import (
 	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
 	"github.com/panther-labs/panther/internal/log_analysis/datacatalog_updater/process"
 	"github.com/panther-labs/panther/internal/log_analysis/gluetables"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
 	"github.com/panther-labs/panther/pkg/awsutils"
 )
 
@@ -87,11 +88,22 @@ func updateGlueTables(ctx context.Context, props *UpdateGlueTablesProperties) er
 		}
 	}
 
+	// the classification failures table does not depend on any log type so it is always deployed
+	failuresTable := classification.FailuresTable()
+	zap.L().Info("updating table", zap.String("database", failuresTable.DatabaseName()), zap.String("table", failuresTable.TableName()))
+	if err := failuresTable.CreateOrUpdateTable(glueClient, props.ProcessedDataBucket); err != nil {
+		return errors.Wrap(err, "failed updating classification failures table")
+	}
+
 	// update schemas for tables that are deployed
 	deployedLogTables, err := gluetables.DeployedLogTables(glueClient)
 	if err != nil {
 		return err
 	}
+	// refuse to update tables if existing data would become unreadable
+	if err := gluetables.ValidateSchemaChanges(glueClient, gluetables.ExpandLogTables(deployedLogTables...)...); err != nil {
+		return err
+	}
 	logTypes := make([]string, len(deployedLogTables))
 	for i, logTable := range deployedLogTables {
 		zap.L().Info("updating table", zap.String("database", logTable.DatabaseName()), zap.String("table", logTable.TableName()))
@@ -39,4 +39,22 @@ type LogTypesAPI struct {
 type LogTypesDatabase interface {
 	// Return an index of available log types
 	IndexLogTypes(ctx context.Context) ([]string, error)
+
+	// GetCustomLog returns a user-defined log type or nil if it does not exist
+	GetCustomLog(ctx context.Context, logType string) (*CustomLogRecord, error)
+	// CreateCustomLog stores a new user-defined log type failing if it already exists
+	CreateCustomLog(ctx context.Context, record *CustomLogRecord) error
+	// UpdateCustomLog stores a new revision of a user-defined log type failing if the previous revision is not current
+	UpdateCustomLog(ctx context.Context, record *CustomLogRecord) error
+	// DeleteCustomLog deletes a user-defined log type failing if the revision is not current
+	DeleteCustomLog(ctx context.Context, logType string, revision int64) error
+	// ListCustomLogs lists all user-defined log types
+	ListCustomLogs(ctx context.Context) ([]*CustomLogRecord, error)
+
+	// GetRetention returns the retention setting of a log type or nil if it is not set
+	GetRetention(ctx context.Context, logType string) (*RetentionRecord, error)
+	// PutRetention stores the retention setting of a log type
+	PutRetention(ctx context.Context, record *RetentionRecord) error
+	// ListRetention lists the retention settings of all log types
+	ListRetention(ctx context.Context) ([]*RetentionRecord, error)
 }
@@ -18,14 +18,89 @@ package logtypesapi_test
  * along with this program.  If not, see <https://www.gnu.org/licenses/>.
  */
 
-import "context"
+import (
+	"context"
+	"sort"
+
+	"github.com/panther-labs/panther/internal/core/logtypesapi"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
 
 // TestCase implements logtypes.ExternalAPI
 // TODO: Generate test cases with go generate
 type TestCase struct {
 	ListLogTypesOutput []string
+	CustomLogs         map[string]*logtypesapi.CustomLogRecord
+	Retention          map[string]*logtypesapi.RetentionRecord
 }
 
 func (t *TestCase) IndexLogTypes(_ context.Context) ([]string, error) {
 	return t.ListLogTypesOutput, nil
 }
+
+func (t *TestCase) GetCustomLog(_ context.Context, logType string) (*logtypesapi.CustomLogRecord, error) {
+	return t.CustomLogs[logType], nil
+}
+
+func (t *TestCase) CreateCustomLog(_ context.Context, record *logtypesapi.CustomLogRecord) error {
+	if _, exists := t.CustomLogs[record.LogType]; exists {
+		return &genericapi.AlreadyExistsError{}
+	}
+	if t.CustomLogs == nil {
+		t.CustomLogs = map[string]*logtypesapi.CustomLogRecord{}
+	}
+	t.CustomLogs[record.LogType] = record
+	return nil
+}
+
+func (t *TestCase) UpdateCustomLog(_ context.Context, record *logtypesapi.CustomLogRecord) error {
+	current, exists := t.CustomLogs[record.LogType]
+	if !exists || current.Revision != record.Revision-1 {
+		return &genericapi.InvalidInputError{}
+	}
+	t.CustomLogs[record.LogType] = record
+	return nil
+}
+
+func (t *TestCase) DeleteCustomLog(_ context.Context, logType string, revision int64) error {
+	current, exists := t.CustomLogs[logType]
+	if !exists || current.Revision != revision {
+		return &genericapi.InvalidInputError{}
+	}
+	delete(t.CustomLogs, logType)
+	return nil
+}
+
+func (t *TestCase) ListCustomLogs(_ context.Context) ([]*logtypesapi.CustomLogRecord, error) {
+	records := make([]*logtypesapi.CustomLogRecord, 0, len(t.CustomLogs))
+	for _, record := range t.CustomLogs {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].LogType < records[j].LogType
+	})
+	return records, nil
+}
+
+func (t *TestCase) GetRetention(_ context.Context, logType string) (*logtypesapi.RetentionRecord, error) {
+	return t.Retention[logType], nil
+}
+
+func (t *TestCase) PutRetention(_ context.Context, record *logtypesapi.RetentionRecord) error {
+	if t.Retention == nil {
+		t.Retention = map[string]*logtypesapi.RetentionRecord{}
+	}
+	t.Retention[record.LogType] = record
+	return nil
+}
+
+func (t *TestCase) ListRetention(_ context.Context) ([]*logtypesapi.RetentionRecord, error) {
+	records := make([]*logtypesapi.RetentionRecord, 0, len(t.Retention))
+	for _, record := range t.Retention {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].LogType < records[j].LogType
+	})
+	return records, nil
+}
@@ -31,6 +31,13 @@ func (api *LogTypesAPI) ListAvailableLogTypes(ctx context.Context) (*AvailableLo
 	if err != nil {
 		return nil, err
 	}
+	customLogs, err := api.Database.ListCustomLogs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range customLogs {
+		logTypes = appendDistinct(logTypes, record.LogType)
+	}
 	if api.NativeLogTypes != nil {
 		native := api.NativeLogTypes()
 		L(ctx).Debug(`merging native log types with database log types`,
@@ -0,0 +1,222 @@
+package logtypesapi
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/customlogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+// CustomLogRecord is a stored user-defined log type
+type CustomLogRecord struct {
+	LogType   string    `json:"logType" validate:"required"`
+	Revision  int64     `json:"revision" validate:"required,min=1"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// LogSpec is the YAML or JSON document describing the log type (see customlogs.Spec)
+	LogSpec string `json:"logSpec" validate:"required"`
+}
+
+// GetCustomLogInput is the input for GetCustomLog
+type GetCustomLogInput struct {
+	LogType string `json:"logType" validate:"required"`
+}
+
+// GetCustomLog gets a user-defined log type
+func (api *LogTypesAPI) GetCustomLog(ctx context.Context, input *GetCustomLogInput) (*CustomLogRecord, error) {
+	record, err := api.Database.GetCustomLog(ctx, input.LogType)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, &genericapi.DoesNotExistError{
+			Message: "custom log type " + input.LogType + " does not exist",
+		}
+	}
+	return record, nil
+}
+
+// PutCustomLogInput is the input for PutCustomLog
+type PutCustomLogInput struct {
+	LogType string `json:"logType" validate:"required"`
+	// Revision is the current revision of an existing log type or zero to create a new log type
+	Revision int64  `json:"revision" validate:"omitempty,min=1"`
+	LogSpec  string `json:"logSpec" validate:"required"`
+}
+
+// PutCustomLog creates or updates a user-defined log type.
+// The log spec is compiled to verify it describes a valid log type before it is stored.
+func (api *LogTypesAPI) PutCustomLog(ctx context.Context, input *PutCustomLogInput) (*CustomLogRecord, error) {
+	if _, err := BuildCustomLog(input.LogType, input.LogSpec); err != nil {
+		return nil, &genericapi.InvalidInputError{
+			Message: err.Error(),
+		}
+	}
+	record := CustomLogRecord{
+		LogType:   input.LogType,
+		Revision:  input.Revision + 1,
+		UpdatedAt: time.Now().UTC(),
+		LogSpec:   input.LogSpec,
+	}
+	if input.Revision == 0 {
+		if api.NativeLogTypes != nil && contains(api.NativeLogTypes(), input.LogType) {
+			return nil, &genericapi.AlreadyExistsError{
+				Message: "log type " + input.LogType + " already exists",
+			}
+		}
+		if err := api.Database.CreateCustomLog(ctx, &record); err != nil {
+			return nil, err
+		}
+		return &record, nil
+	}
+	if err := api.Database.UpdateCustomLog(ctx, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// DelCustomLogInput is the input for DelCustomLog
+type DelCustomLogInput struct {
+	LogType  string `json:"logType" validate:"required"`
+	Revision int64  `json:"revision" validate:"required,min=1"`
+}
+
+// DelCustomLogOutput is the output of DelCustomLog
+type DelCustomLogOutput struct{}
+
+// DelCustomLog deletes a user-defined log type.
+// The revision must match the current revision of the log type.
+func (api *LogTypesAPI) DelCustomLog(ctx context.Context, input *DelCustomLogInput) (*DelCustomLogOutput, error) {
+	if err := api.Database.DeleteCustomLog(ctx, input.LogType, input.Revision); err != nil {
+		return nil, err
+	}
+	return &DelCustomLogOutput{}, nil
+}
+
+// ListCustomLogsOutput is the output of ListCustomLogs
+type ListCustomLogsOutput struct {
+	CustomLogs []*CustomLogRecord `json:"customLogs"`
+}
+
+// ListCustomLogs lists all user-defined log types
+func (api *LogTypesAPI) ListCustomLogs(ctx context.Context) (*ListCustomLogsOutput, error) {
+	records, err := api.Database.ListCustomLogs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if records == nil {
+		records = []*CustomLogRecord{}
+	}
+	return &ListCustomLogsOutput{
+		CustomLogs: records,
+	}, nil
+}
+
+// BuildCustomLog parses and compiles a log spec to a log type config
+func BuildCustomLog(logType, logSpec string) (*logtypes.Config, error) {
+	spec, err := customlogs.ParseSpec([]byte(logSpec))
+	if err != nil {
+		return nil, err
+	}
+	if spec.Name != logType {
+		return nil, errors.Errorf("log spec name %q does not match log type %q", spec.Name, logType)
+	}
+	return customlogs.Build(spec)
+}
+
+// CustomLogsLister lists user-defined log types.
+// Both LogTypesAPI and LogTypesAPILambdaClient implement this interface.
+type CustomLogsLister interface {
+	ListCustomLogs(ctx context.Context) (*ListCustomLogsOutput, error)
+}
+
+// LoadCustomLogs loads all user-defined log types to a registry.
+// Log types that fail to build are skipped so that a single broken log type does not affect other log types.
+func LoadCustomLogs(ctx context.Context, api CustomLogsLister, r *logtypes.Registry) error {
+	reply, err := api.ListCustomLogs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list custom log types")
+	}
+	for _, record := range reply.CustomLogs {
+		config, err := BuildCustomLog(record.LogType, record.LogSpec)
+		if err != nil {
+			zap.L().Error("failed to build custom log type", zap.String("logType", record.LogType), zap.Error(err))
+			continue
+		}
+		if _, err := r.Register(config); err != nil {
+			zap.L().Error("failed to register custom log type", zap.String("logType", record.LogType), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// DefaultCustomLogsMaxAge is how long a CustomLogsResolver caches user-defined log types if MaxAge is not set
+const DefaultCustomLogsMaxAge = time.Minute
+
+// CustomLogsResolver resolves user-defined log types using the log types API.
+// All user-defined log types are loaded at once and cached for MaxAge to avoid calling the API for every resolve.
+type CustomLogsResolver struct {
+	API    CustomLogsLister
+	MaxAge time.Duration
+
+	mu        sync.Mutex
+	registry  *logtypes.Registry
+	expiresAt time.Time
+}
+
+var _ logtypes.Resolver = (*CustomLogsResolver)(nil)
+
+// Resolve implements logtypes.Resolver
+func (r *CustomLogsResolver) Resolve(ctx context.Context, name string) (logtypes.Entry, error) {
+	if !strings.HasPrefix(name, customlogs.LogTypePrefix) {
+		return nil, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if now := time.Now(); r.registry == nil || now.After(r.expiresAt) {
+		registry := &logtypes.Registry{}
+		if err := LoadCustomLogs(ctx, r.API, registry); err != nil {
+			return nil, err
+		}
+		maxAge := r.MaxAge
+		if maxAge == 0 {
+			maxAge = DefaultCustomLogsMaxAge
+		}
+		r.registry = registry
+		r.expiresAt = now.Add(maxAge)
+	}
+	return r.registry.Resolve(ctx, name)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package logtypesapi_test
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/core/logtypesapi"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+const testLogSpec = `
+name: Custom.Foo
+fields:
+  - name: time
+    type: timestamp
+    timeFormat: rfc3339
+    isEventTime: true
+  - name: ip
+    type: string
+    indicators: [ip]
+`
+
+func TestAPI_CustomLogs(t *testing.T) {
+	assert := require.New(t)
+	ctx := context.Background()
+	api := logtypesapi.LogTypesAPI{
+		Database: &TestCase{
+			ListLogTypesOutput: []string{"foo"},
+		},
+		NativeLogTypes: func() []string {
+			return []string{"AWS.CloudTrail"}
+		},
+	}
+
+	_, err := api.GetCustomLog(ctx, &logtypesapi.GetCustomLogInput{LogType: "Custom.Foo"})
+	assert.IsType(&genericapi.DoesNotExistError{}, err)
+
+	record, err := api.PutCustomLog(ctx, &logtypesapi.PutCustomLogInput{
+		LogType: "Custom.Foo",
+		LogSpec: testLogSpec,
+	})
+	assert.NoError(err)
+	assert.Equal(int64(1), record.Revision)
+
+	// Creating an existing log type fails
+	_, err = api.PutCustomLog(ctx, &logtypesapi.PutCustomLogInput{
+		LogType: "Custom.Foo",
+		LogSpec: testLogSpec,
+	})
+	assert.IsType(&genericapi.AlreadyExistsError{}, err)
+
+	// Updating requires the current revision
+	record, err = api.PutCustomLog(ctx, &logtypesapi.PutCustomLogInput{
+		LogType:  "Custom.Foo",
+		Revision: 1,
+		LogSpec:  testLogSpec,
+	})
+	assert.NoError(err)
+	assert.Equal(int64(2), record.Revision)
+	_, err = api.PutCustomLog(ctx, &logtypesapi.PutCustomLogInput{
+		LogType:  "Custom.Foo",
+		Revision: 1,
+		LogSpec:  testLogSpec,
+	})
+	assert.Error(err)
+
+	// Invalid specs are rejected
+	_, err = api.PutCustomLog(ctx, &logtypesapi.PutCustomLogInput{
+		LogType: "Custom.Bar",
+		LogSpec: testLogSpec,
+	})
+	assert.IsType(&genericapi.InvalidInputError{}, err)
+	_, err = api.PutCustomLog(ctx, &logtypesapi.PutCustomLogInput{
+		LogType: "Custom.Bar",
+		LogSpec: "name: Custom.Bar\nfields: [{name: foo, type: uuid}]",
+	})
+	assert.IsType(&genericapi.InvalidInputError{}, err)
+
+	available, err := api.ListAvailableLogTypes(ctx)
+	assert.NoError(err)
+	assert.Equal([]string{"AWS.CloudTrail", "Custom.Foo", "foo"}, available.LogTypes)
+
+	r := &logtypes.Registry{}
+	assert.NoError(logtypesapi.LoadCustomLogs(ctx, &api, r))
+	assert.NotNil(r.Get("Custom.Foo"))
+
+	resolver := &logtypesapi.CustomLogsResolver{
+		API:    &api,
+		MaxAge: time.Minute,
+	}
+	entry, err := resolver.Resolve(ctx, "Custom.Foo")
+	assert.NoError(err)
+	assert.NotNil(entry)
+	entry, err = resolver.Resolve(ctx, "Custom.Bar")
+	assert.NoError(err)
+	assert.Nil(entry)
+
+	_, err = api.DelCustomLog(ctx, &logtypesapi.DelCustomLogInput{LogType: "Custom.Foo", Revision: 1})
+	assert.Error(err)
+	_, err = api.DelCustomLog(ctx, &logtypesapi.DelCustomLogInput{LogType: "Custom.Foo", Revision: 2})
+	assert.NoError(err)
+	list, err := api.ListCustomLogs(ctx)
+	assert.NoError(err)
+	assert.Empty(list.CustomLogs)
+}
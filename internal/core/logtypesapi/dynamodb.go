@@ -20,13 +20,16 @@ package logtypesapi
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"go.uber.org/zap"
 
+	"github.com/panther-labs/panther/pkg/genericapi"
 	"github.com/panther-labs/panther/pkg/lambdalogger"
 )
 
@@ -41,7 +44,10 @@ var L = lambdalogger.FromContext
 
 const (
 	recordKindStatus      = "status"
+	recordKindCustom      = "custom"
+	recordKindRetention   = "retention"
 	attrAvailableLogTypes = "AvailableLogTypes"
+	attrRevision          = "revision"
 )
 
 func (d *DynamoDBLogTypes) IndexLogTypes(ctx context.Context) ([]string, error) {
@@ -87,3 +93,226 @@ func statusRecordKey() map[string]*dynamodb.AttributeValue {
 		RecordKind: recordKindStatus,
 	})
 }
+
+type customLogItem struct {
+	recordKey
+	CustomLogRecord
+}
+
+func customRecordKey(logType string) map[string]*dynamodb.AttributeValue {
+	return mustMarshalMap(&recordKey{
+		RecordID:   logType,
+		RecordKind: recordKindCustom,
+	})
+}
+
+func (d *DynamoDBLogTypes) GetCustomLog(ctx context.Context, logType string) (*CustomLogRecord, error) {
+	input := dynamodb.GetItemInput{
+		TableName: aws.String(d.TableName),
+		Key:       customRecordKey(logType),
+	}
+	output, err := d.DB.GetItemWithContext(ctx, &input)
+	if err != nil {
+		L(ctx).Error(`failed to get DynamoDB item`, zap.Error(err))
+		return nil, err
+	}
+	if output.Item == nil {
+		return nil, nil
+	}
+	item := customLogItem{}
+	if err := dynamodbattribute.UnmarshalMap(output.Item, &item); err != nil {
+		L(ctx).Error(`failed to unmarshal DynamoDB item`, zap.Error(err))
+		return nil, err
+	}
+	return &item.CustomLogRecord, nil
+}
+
+func (d *DynamoDBLogTypes) CreateCustomLog(ctx context.Context, record *CustomLogRecord) error {
+	input := dynamodb.PutItemInput{
+		TableName:           aws.String(d.TableName),
+		Item:                mustMarshalCustomLogItem(record),
+		ConditionExpression: aws.String("attribute_not_exists(RecordID)"),
+	}
+	if _, err := d.DB.PutItemWithContext(ctx, &input); err != nil {
+		if isConditionalCheckFailed(err) {
+			return &genericapi.AlreadyExistsError{
+				Message: "custom log type " + record.LogType + " already exists",
+			}
+		}
+		L(ctx).Error(`failed to put DynamoDB item`, zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (d *DynamoDBLogTypes) UpdateCustomLog(ctx context.Context, record *CustomLogRecord) error {
+	input := dynamodb.PutItemInput{
+		TableName:           aws.String(d.TableName),
+		Item:                mustMarshalCustomLogItem(record),
+		ConditionExpression: aws.String("#revision = :revision"),
+		ExpressionAttributeNames: map[string]*string{
+			"#revision": aws.String(attrRevision),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":revision": {N: aws.String(strconv.FormatInt(record.Revision-1, 10))},
+		},
+	}
+	if _, err := d.DB.PutItemWithContext(ctx, &input); err != nil {
+		if isConditionalCheckFailed(err) {
+			return &genericapi.InvalidInputError{
+				Message: "custom log type " + record.LogType + " was modified or does not exist",
+			}
+		}
+		L(ctx).Error(`failed to put DynamoDB item`, zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (d *DynamoDBLogTypes) DeleteCustomLog(ctx context.Context, logType string, revision int64) error {
+	input := dynamodb.DeleteItemInput{
+		TableName:           aws.String(d.TableName),
+		Key:                 customRecordKey(logType),
+		ConditionExpression: aws.String("#revision = :revision"),
+		ExpressionAttributeNames: map[string]*string{
+			"#revision": aws.String(attrRevision),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":revision": {N: aws.String(strconv.FormatInt(revision, 10))},
+		},
+	}
+	if _, err := d.DB.DeleteItemWithContext(ctx, &input); err != nil {
+		if isConditionalCheckFailed(err) {
+			return &genericapi.InvalidInputError{
+				Message: "custom log type " + logType + " was modified or does not exist",
+			}
+		}
+		L(ctx).Error(`failed to delete DynamoDB item`, zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (d *DynamoDBLogTypes) ListCustomLogs(ctx context.Context) ([]*CustomLogRecord, error) {
+	input := dynamodb.QueryInput{
+		TableName:              aws.String(d.TableName),
+		KeyConditionExpression: aws.String("RecordKind = :kind"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":kind": {S: aws.String(recordKindCustom)},
+		},
+	}
+	var records []*CustomLogRecord
+	var itemErr error
+	err := d.DB.QueryPagesWithContext(ctx, &input, func(page *dynamodb.QueryOutput, _ bool) bool {
+		for _, attr := range page.Items {
+			item := customLogItem{}
+			if itemErr = dynamodbattribute.UnmarshalMap(attr, &item); itemErr != nil {
+				return false
+			}
+			records = append(records, &item.CustomLogRecord)
+		}
+		return true
+	})
+	if err == nil {
+		err = itemErr
+	}
+	if err != nil {
+		L(ctx).Error(`failed to query DynamoDB items`, zap.Error(err))
+		return nil, err
+	}
+	return records, nil
+}
+
+func mustMarshalCustomLogItem(record *CustomLogRecord) map[string]*dynamodb.AttributeValue {
+	return mustMarshalMap(&customLogItem{
+		recordKey: recordKey{
+			RecordID:   record.LogType,
+			RecordKind: recordKindCustom,
+		},
+		CustomLogRecord: *record,
+	})
+}
+
+type retentionItem struct {
+	recordKey
+	RetentionRecord
+}
+
+func (d *DynamoDBLogTypes) GetRetention(ctx context.Context, logType string) (*RetentionRecord, error) {
+	input := dynamodb.GetItemInput{
+		TableName: aws.String(d.TableName),
+		Key: mustMarshalMap(&recordKey{
+			RecordID:   logType,
+			RecordKind: recordKindRetention,
+		}),
+	}
+	output, err := d.DB.GetItemWithContext(ctx, &input)
+	if err != nil {
+		L(ctx).Error(`failed to get DynamoDB item`, zap.Error(err))
+		return nil, err
+	}
+	if output.Item == nil {
+		return nil, nil
+	}
+	item := retentionItem{}
+	if err := dynamodbattribute.UnmarshalMap(output.Item, &item); err != nil {
+		L(ctx).Error(`failed to unmarshal DynamoDB item`, zap.Error(err))
+		return nil, err
+	}
+	return &item.RetentionRecord, nil
+}
+
+func (d *DynamoDBLogTypes) PutRetention(ctx context.Context, record *RetentionRecord) error {
+	input := dynamodb.PutItemInput{
+		TableName: aws.String(d.TableName),
+		Item: mustMarshalMap(&retentionItem{
+			recordKey: recordKey{
+				RecordID:   record.LogType,
+				RecordKind: recordKindRetention,
+			},
+			RetentionRecord: *record,
+		}),
+	}
+	if _, err := d.DB.PutItemWithContext(ctx, &input); err != nil {
+		L(ctx).Error(`failed to put DynamoDB item`, zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (d *DynamoDBLogTypes) ListRetention(ctx context.Context) ([]*RetentionRecord, error) {
+	input := dynamodb.QueryInput{
+		TableName:              aws.String(d.TableName),
+		KeyConditionExpression: aws.String("RecordKind = :kind"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":kind": {S: aws.String(recordKindRetention)},
+		},
+	}
+	var records []*RetentionRecord
+	var itemErr error
+	err := d.DB.QueryPagesWithContext(ctx, &input, func(page *dynamodb.QueryOutput, _ bool) bool {
+		for _, attr := range page.Items {
+			item := retentionItem{}
+			if itemErr = dynamodbattribute.UnmarshalMap(attr, &item); itemErr != nil {
+				return false
+			}
+			records = append(records, &item.RetentionRecord)
+		}
+		return true
+	})
+	if err == nil {
+		err = itemErr
+	}
+	if err != nil {
+		L(ctx).Error(`failed to query DynamoDB items`, zap.Error(err))
+		return nil, err
+	}
+	return records, nil
+}
+
+func isConditionalCheckFailed(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException
+	}
+	return false
+}
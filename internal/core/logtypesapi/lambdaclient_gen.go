@@ -39,7 +39,14 @@ type LogTypesAPILambdaClient struct {
 }
 
 type LogTypesAPIPayload struct {
-	ListAvailableLogTypes *struct{} `json:"ListAvailableLogTypes,omitempty"`
+	ListAvailableLogTypes *struct{}          `json:"ListAvailableLogTypes,omitempty"`
+	GetCustomLog          *GetCustomLogInput `json:"GetCustomLog,omitempty"`
+	PutCustomLog          *PutCustomLogInput `json:"PutCustomLog,omitempty"`
+	DelCustomLog          *DelCustomLogInput `json:"DelCustomLog,omitempty"`
+	ListCustomLogs        *struct{}          `json:"ListCustomLogs,omitempty"`
+	GetRetention          *GetRetentionInput `json:"GetRetention,omitempty"`
+	PutRetention          *PutRetentionInput `json:"PutRetention,omitempty"`
+	ListRetention         *struct{}          `json:"ListRetention,omitempty"`
 }
 
 func (c *LogTypesAPILambdaClient) ListAvailableLogTypes(ctx context.Context) (*AvailableLogTypes, error) {
@@ -53,6 +60,98 @@ func (c *LogTypesAPILambdaClient) ListAvailableLogTypes(ctx context.Context) (*A
 	return &reply, nil
 }
 
+func (c *LogTypesAPILambdaClient) GetCustomLog(ctx context.Context, input *GetCustomLogInput) (*CustomLogRecord, error) {
+	if input == nil {
+		input = &GetCustomLogInput{}
+	}
+	payload := LogTypesAPIPayload{
+		GetCustomLog: input,
+	}
+	reply := CustomLogRecord{}
+	if err := c.invoke(ctx, &payload, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+func (c *LogTypesAPILambdaClient) PutCustomLog(ctx context.Context, input *PutCustomLogInput) (*CustomLogRecord, error) {
+	if input == nil {
+		input = &PutCustomLogInput{}
+	}
+	payload := LogTypesAPIPayload{
+		PutCustomLog: input,
+	}
+	reply := CustomLogRecord{}
+	if err := c.invoke(ctx, &payload, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+func (c *LogTypesAPILambdaClient) DelCustomLog(ctx context.Context, input *DelCustomLogInput) (*DelCustomLogOutput, error) {
+	if input == nil {
+		input = &DelCustomLogInput{}
+	}
+	payload := LogTypesAPIPayload{
+		DelCustomLog: input,
+	}
+	reply := DelCustomLogOutput{}
+	if err := c.invoke(ctx, &payload, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+func (c *LogTypesAPILambdaClient) ListCustomLogs(ctx context.Context) (*ListCustomLogsOutput, error) {
+	payload := LogTypesAPIPayload{
+		ListCustomLogs: &struct{}{},
+	}
+	reply := ListCustomLogsOutput{}
+	if err := c.invoke(ctx, &payload, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+func (c *LogTypesAPILambdaClient) GetRetention(ctx context.Context, input *GetRetentionInput) (*RetentionRecord, error) {
+	if input == nil {
+		input = &GetRetentionInput{}
+	}
+	payload := LogTypesAPIPayload{
+		GetRetention: input,
+	}
+	reply := RetentionRecord{}
+	if err := c.invoke(ctx, &payload, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+func (c *LogTypesAPILambdaClient) PutRetention(ctx context.Context, input *PutRetentionInput) (*RetentionRecord, error) {
+	if input == nil {
+		input = &PutRetentionInput{}
+	}
+	payload := LogTypesAPIPayload{
+		PutRetention: input,
+	}
+	reply := RetentionRecord{}
+	if err := c.invoke(ctx, &payload, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+func (c *LogTypesAPILambdaClient) ListRetention(ctx context.Context) (*ListRetentionOutput, error) {
+	payload := LogTypesAPIPayload{
+		ListRetention: &struct{}{},
+	}
+	reply := ListRetentionOutput{}
+	if err := c.invoke(ctx, &payload, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
 func (c *LogTypesAPILambdaClient) invoke(ctx context.Context, payload, reply interface{}) error {
 	if validate := c.Validate; validate != nil {
 		if err := validate(payload); err != nil {
@@ -0,0 +1,107 @@
+package logtypesapi
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"time"
+
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+// RetentionRecord is the data retention setting of a log type
+type RetentionRecord struct {
+	LogType string `json:"logType" validate:"required"`
+	// RetentionDays is the number of days to keep processed data of the log type, zero keeps data forever
+	RetentionDays int       `json:"retentionDays" validate:"min=0"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// GetRetentionInput is the input for GetRetention
+type GetRetentionInput struct {
+	LogType string `json:"logType" validate:"required"`
+}
+
+// GetRetention gets the data retention setting of a log type.
+// Log types without a retention setting keep their data forever.
+func (api *LogTypesAPI) GetRetention(ctx context.Context, input *GetRetentionInput) (*RetentionRecord, error) {
+	record, err := api.Database.GetRetention(ctx, input.LogType)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return &RetentionRecord{
+			LogType: input.LogType,
+		}, nil
+	}
+	return record, nil
+}
+
+// PutRetentionInput is the input for PutRetention
+type PutRetentionInput struct {
+	LogType       string `json:"logType" validate:"required"`
+	RetentionDays int    `json:"retentionDays" validate:"min=0"`
+}
+
+// PutRetention sets the data retention setting of an available log type
+func (api *LogTypesAPI) PutRetention(ctx context.Context, input *PutRetentionInput) (*RetentionRecord, error) {
+	available, err := api.ListAvailableLogTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !contains(available.LogTypes, input.LogType) {
+		return nil, &genericapi.DoesNotExistError{
+			Message: "log type " + input.LogType + " does not exist",
+		}
+	}
+	record := RetentionRecord{
+		LogType:       input.LogType,
+		RetentionDays: input.RetentionDays,
+		UpdatedAt:     time.Now().UTC(),
+	}
+	if err := api.Database.PutRetention(ctx, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// ListRetentionOutput is the output of ListRetention
+type ListRetentionOutput struct {
+	Retention []*RetentionRecord `json:"retention"`
+}
+
+// ListRetention lists the data retention settings of all log types that have one
+func (api *LogTypesAPI) ListRetention(ctx context.Context) (*ListRetentionOutput, error) {
+	records, err := api.Database.ListRetention(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if records == nil {
+		records = []*RetentionRecord{}
+	}
+	return &ListRetentionOutput{
+		Retention: records,
+	}, nil
+}
+
+// RetentionLister lists the data retention settings of log types.
+// Both LogTypesAPI and LogTypesAPILambdaClient implement this interface.
+type RetentionLister interface {
+	ListRetention(ctx context.Context) (*ListRetentionOutput, error)
+}
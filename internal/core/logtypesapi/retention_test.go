@@ -0,0 +1,75 @@
+package logtypesapi_test
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/core/logtypesapi"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+func TestAPI_Retention(t *testing.T) {
+	assert := require.New(t)
+	ctx := context.Background()
+	api := logtypesapi.LogTypesAPI{
+		Database: &TestCase{},
+		NativeLogTypes: func() []string {
+			return []string{"AWS.CloudTrail", "AWS.VPCFlow"}
+		},
+	}
+
+	// Log types without a setting keep data forever
+	record, err := api.GetRetention(ctx, &logtypesapi.GetRetentionInput{LogType: "AWS.VPCFlow"})
+	assert.NoError(err)
+	assert.Equal(&logtypesapi.RetentionRecord{LogType: "AWS.VPCFlow"}, record)
+
+	record, err = api.PutRetention(ctx, &logtypesapi.PutRetentionInput{
+		LogType:       "AWS.VPCFlow",
+		RetentionDays: 30,
+	})
+	assert.NoError(err)
+	assert.Equal(30, record.RetentionDays)
+	_, err = api.PutRetention(ctx, &logtypesapi.PutRetentionInput{
+		LogType:       "AWS.CloudTrail",
+		RetentionDays: 365,
+	})
+	assert.NoError(err)
+
+	// Unknown log types are rejected
+	_, err = api.PutRetention(ctx, &logtypesapi.PutRetentionInput{
+		LogType:       "Custom.Foo",
+		RetentionDays: 1,
+	})
+	assert.IsType(&genericapi.DoesNotExistError{}, err)
+
+	record, err = api.GetRetention(ctx, &logtypesapi.GetRetentionInput{LogType: "AWS.VPCFlow"})
+	assert.NoError(err)
+	assert.Equal(30, record.RetentionDays)
+
+	list, err := api.ListRetention(ctx)
+	assert.NoError(err)
+	assert.Len(list.Retention, 2)
+	assert.Equal("AWS.CloudTrail", list.Retention[0].LogType)
+	assert.Equal(365, list.Retention[0].RetentionDays)
+	assert.Equal("AWS.VPCFlow", list.Retention[1].LogType)
+}
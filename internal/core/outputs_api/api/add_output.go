@@ -61,6 +61,8 @@ func (API) AddOutput(input *models.AddOutputInput) (*models.AddOutputOutput, err
 		OutputType:         outputType,
 		OutputConfig:       input.OutputConfig,
 		DefaultForSeverity: input.DefaultForSeverity,
+		RoutingRules:       input.RoutingRules,
+		MessageTemplate:    input.MessageTemplate,
 	}
 
 	alertOutputItem, err := AlertOutputToItem(alertOutput)
@@ -278,3 +278,161 @@ func TestAddOutputAsana(t *testing.T) {
 	_, err = uuid.Parse(*result.OutputID)
 	assert.NoError(t, err)
 }
+
+func TestAddOutputEmail(t *testing.T) {
+	mockEncryptionKey := &mockEncryptionKey{}
+	encryptionKey = mockEncryptionKey
+	mockOutputTable := &mockOutputTable{}
+	outputsTable = mockOutputTable
+
+	mockOutputTable.On("GetOutputByName", aws.String("my-email-destination")).Return(nil, nil)
+	mockEncryptionKey.On("EncryptConfig", mock.Anything).Return(make([]byte, 1), nil)
+	mockOutputTable.On("PutOutput", mock.Anything).Return(nil)
+
+	input := &models.AddOutputInput{
+		UserID:      aws.String("userId"),
+		DisplayName: aws.String("my-email-destination"),
+		OutputConfig: &models.OutputConfig{
+			Email: &models.EmailConfig{
+				Host:     "smtp.example.com",
+				UserName: "panther",
+				Password: "secret",
+				From:     "alerts@example.com",
+				To:       []string{"security@example.com"},
+			},
+		},
+	}
+
+	result, err := (API{}).AddOutput(input)
+	require.NoError(t, err)
+
+	expected := &models.AddOutputOutput{
+		DisplayName:    aws.String("my-email-destination"),
+		OutputType:     aws.String("email"),
+		LastModifiedBy: aws.String("userId"),
+		CreatedBy:      aws.String("userId"),
+		OutputConfig: &models.OutputConfig{
+			Email: &models.EmailConfig{
+				Host:     "smtp.example.com",
+				UserName: "panther",
+				Password: "",
+				From:     "alerts@example.com",
+				To:       []string{"security@example.com"},
+			},
+		},
+		OutputID:         result.OutputID,
+		CreationTime:     result.CreationTime,
+		LastModifiedTime: result.LastModifiedTime,
+	}
+	assert.Equal(t, expected, result)
+
+	// A user name without a password is rejected
+	input.OutputConfig.Email.Password = ""
+	_, err = (API{}).AddOutput(input)
+	assert.Error(t, err)
+}
+
+func TestAddOutputSplunkHEC(t *testing.T) {
+	mockEncryptionKey := &mockEncryptionKey{}
+	encryptionKey = mockEncryptionKey
+	mockOutputTable := &mockOutputTable{}
+	outputsTable = mockOutputTable
+
+	mockOutputTable.On("GetOutputByName", aws.String("my-splunk-destination")).Return(nil, nil)
+	mockEncryptionKey.On("EncryptConfig", mock.Anything).Return(make([]byte, 1), nil)
+	mockOutputTable.On("PutOutput", mock.Anything).Return(nil)
+
+	input := &models.AddOutputInput{
+		UserID:      aws.String("userId"),
+		DisplayName: aws.String("my-splunk-destination"),
+		OutputConfig: &models.OutputConfig{
+			SplunkHEC: &models.SplunkHECConfig{
+				URL:   "https://splunk.example.com:8088",
+				Token: "token",
+				Index: "alerts",
+			},
+		},
+	}
+
+	result, err := (API{}).AddOutput(input)
+	require.NoError(t, err)
+	assert.Equal(t, aws.String("splunkhec"), result.OutputType)
+	assert.Equal(t, &models.SplunkHECConfig{
+		URL:   "https://splunk.example.com:8088",
+		Token: "",
+		Index: "alerts",
+	}, result.OutputConfig.SplunkHEC)
+
+	// The token is required
+	input.OutputConfig.SplunkHEC.Token = ""
+	_, err = (API{}).AddOutput(input)
+	assert.Error(t, err)
+}
+
+func TestAddOutputSyslog(t *testing.T) {
+	mockEncryptionKey := &mockEncryptionKey{}
+	encryptionKey = mockEncryptionKey
+	mockOutputTable := &mockOutputTable{}
+	outputsTable = mockOutputTable
+
+	mockOutputTable.On("GetOutputByName", aws.String("my-syslog-destination")).Return(nil, nil)
+	mockEncryptionKey.On("EncryptConfig", mock.Anything).Return(make([]byte, 1), nil)
+	mockOutputTable.On("PutOutput", mock.Anything).Return(nil)
+
+	input := &models.AddOutputInput{
+		UserID:      aws.String("userId"),
+		DisplayName: aws.String("my-syslog-destination"),
+		OutputConfig: &models.OutputConfig{
+			Syslog: &models.SyslogConfig{Host: "syslog.example.com"},
+		},
+	}
+
+	result, err := (API{}).AddOutput(input)
+	require.NoError(t, err)
+	assert.Equal(t, aws.String("syslog"), result.OutputType)
+	assert.Equal(t, &models.SyslogConfig{Host: "syslog.example.com"}, result.OutputConfig.Syslog)
+
+	// The host is required
+	input.OutputConfig.Syslog.Host = ""
+	_, err = (API{}).AddOutput(input)
+	assert.Error(t, err)
+}
+
+func TestAddOutputServiceNow(t *testing.T) {
+	mockEncryptionKey := &mockEncryptionKey{}
+	encryptionKey = mockEncryptionKey
+	mockOutputTable := &mockOutputTable{}
+	outputsTable = mockOutputTable
+
+	mockOutputTable.On("GetOutputByName", aws.String("my-servicenow-destination")).Return(nil, nil)
+	mockEncryptionKey.On("EncryptConfig", mock.Anything).Return(make([]byte, 1), nil)
+	mockOutputTable.On("PutOutput", mock.Anything).Return(nil)
+
+	input := &models.AddOutputInput{
+		UserID:      aws.String("userId"),
+		DisplayName: aws.String("my-servicenow-destination"),
+		OutputConfig: &models.OutputConfig{
+			ServiceNow: &models.ServiceNowConfig{
+				InstanceURL:     "https://example.service-now.com",
+				UserName:        "panther",
+				Password:        "secret",
+				AssignmentGroup: "Security",
+			},
+		},
+	}
+
+	result, err := (API{}).AddOutput(input)
+	require.NoError(t, err)
+	assert.Equal(t, aws.String("servicenow"), result.OutputType)
+	assert.Equal(t, &models.ServiceNowConfig{
+		InstanceURL:     "https://example.service-now.com",
+		UserName:        "panther",
+		Password:        "",
+		AssignmentGroup: "Security",
+	}, result.OutputConfig.ServiceNow)
+
+	// Credentials are required
+	input.OutputConfig.ServiceNow.Password = ""
+	_, err = (API{}).AddOutput(input)
+	assert.Error(t, err)
+}
@@ -68,12 +68,17 @@ func (m *mockOutputTable) PutOutput(output *table.AlertOutputItem) error {
 type mockEncryptionKey struct {
 	encryption.Key
 	mock.Mock
+	// plaintext is the decrypted config, a Slack config if not set
+	plaintext string
 }
 
 func (m *mockEncryptionKey) DecryptConfig(ciphertext []byte, config interface{}) error {
 	args := m.Called(ciphertext, config)
-	plaintext := []byte(`{"slack": {"webhookURL": "https://hooks.slack.com/services/bb/aa/11"}}`)
-	_ = jsoniter.Unmarshal(plaintext, config)
+	plaintext := m.plaintext
+	if plaintext == "" {
+		plaintext = `{"slack": {"webhookURL": "https://hooks.slack.com/services/bb/aa/11"}}`
+	}
+	_ = jsoniter.UnmarshalFromString(plaintext, config)
 	return args.Error(0)
 }
 
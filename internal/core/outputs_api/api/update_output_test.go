@@ -128,3 +128,114 @@ func TestUpdateSameOutputOutput(t *testing.T) {
 
 	mockOutputsTable.AssertExpectations(t)
 }
+
+func TestMergeConfigsEmail(t *testing.T) {
+	oldConfig := &models.OutputConfig{
+		Email: &models.EmailConfig{
+			Host:     "smtp.example.com",
+			Port:     465,
+			TLSMode:  models.EmailTLSModeImplicit,
+			UserName: "panther",
+			Password: "secret",
+			From:     "alerts@example.com",
+			To:       []string{"security@example.com"},
+			Cc:       []string{"cc@example.com"},
+		},
+	}
+	// Redacted secrets and missing fields are kept from the old config, empty lists are cleared
+	newConfig := &models.OutputConfig{
+		Email: &models.EmailConfig{
+			Host:     "smtp.example.com",
+			Port:     465,
+			UserName: "panther",
+			From:     "alerts@example.com",
+			To:       []string{"security@example.com", "soc@example.com"},
+			Cc:       []string{},
+		},
+	}
+
+	result, err := mergeConfigs(oldConfig, newConfig)
+	require.NoError(t, err)
+	expected := &models.OutputConfig{
+		Email: &models.EmailConfig{
+			Host:     "smtp.example.com",
+			Port:     465,
+			TLSMode:  models.EmailTLSModeImplicit,
+			UserName: "panther",
+			Password: "secret",
+			From:     "alerts@example.com",
+			To:       []string{"security@example.com", "soc@example.com"},
+			Cc:       []string{},
+		},
+	}
+	assert.Equal(t, expected, result)
+}
+
+func TestMergeConfigsServiceNow(t *testing.T) {
+	oldConfig := &models.OutputConfig{
+		ServiceNow: &models.ServiceNowConfig{
+			InstanceURL: "https://example.service-now.com",
+			UserName:    "panther",
+			Password:    "secret",
+			Urgency:     map[string]int{"HIGH": 2},
+		},
+	}
+	// The password and urgency mapping are kept when they are not updated
+	newConfig := &models.OutputConfig{
+		ServiceNow: &models.ServiceNowConfig{
+			InstanceURL:     "https://example.service-now.com",
+			UserName:        "panther",
+			AssignmentGroup: "Security",
+		},
+	}
+
+	result, err := mergeConfigs(oldConfig, newConfig)
+	require.NoError(t, err)
+	assert.Equal(t, &models.OutputConfig{
+		ServiceNow: &models.ServiceNowConfig{
+			InstanceURL:     "https://example.service-now.com",
+			UserName:        "panther",
+			Password:        "secret",
+			AssignmentGroup: "Security",
+			Urgency:         map[string]int{"HIGH": 2},
+		},
+	}, result)
+}
+
+func TestUpdateOutputSyslogFacilityZero(t *testing.T) {
+	mockOutputsTable := &mockOutputTable{}
+	outputsTable = mockOutputsTable
+	mockEncryptionKey := &mockEncryptionKey{
+		plaintext: `{"syslog": {"host": "syslog.example.com", "facility": 4}}`,
+	}
+	encryptionKey = mockEncryptionKey
+
+	alertOutputItem := &table.AlertOutputItem{
+		OutputID:        aws.String("outputId"),
+		DisplayName:     aws.String("displayName"),
+		OutputType:      aws.String("syslog"),
+		EncryptedConfig: make([]byte, 1),
+	}
+	mockOutputsTable.On("UpdateOutput", mock.Anything).Return(alertOutputItem, nil)
+	mockOutputsTable.On("GetOutputByName", aws.String("displayName")).Return(nil, nil)
+	mockOutputsTable.On("GetOutput", aws.String("outputId")).Return(alertOutputItem, nil)
+	mockEncryptionKey.On("DecryptConfig", mock.Anything, mock.Anything).Return(nil)
+	mockEncryptionKey.On("EncryptConfig", mock.Anything).Return(make([]byte, 1), nil)
+
+	input := &models.UpdateOutputInput{
+		OutputID:    aws.String("outputId"),
+		DisplayName: aws.String("displayName"),
+		UserID:      aws.String("userId"),
+		OutputConfig: &models.OutputConfig{
+			Syslog: &models.SyslogConfig{Host: "syslog.example.com", Facility: aws.Int(0)},
+		},
+	}
+	_, err := (API{}).UpdateOutput(input)
+	require.NoError(t, err)
+
+	// The kernel facility replaces the old facility
+	config := mockEncryptionKey.Calls[1].Arguments.Get(0).(*models.OutputConfig)
+	require.NotNil(t, config.Syslog.Facility)
+	assert.Equal(t, 0, *config.Syslog.Facility)
+	mockOutputsTable.AssertExpectations(t)
+}
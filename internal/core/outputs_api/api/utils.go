@@ -42,6 +42,8 @@ func AlertOutputToItem(input *models.AlertOutput) (*table.AlertOutputItem, error
 		OutputID:           input.OutputID,
 		OutputType:         input.OutputType,
 		DefaultForSeverity: input.DefaultForSeverity,
+		RoutingRules:       input.RoutingRules,
+		MessageTemplate:    input.MessageTemplate,
 	}
 
 	if input.OutputConfig != nil {
@@ -66,6 +68,8 @@ func ItemToAlertOutput(input *table.AlertOutputItem) (alertOutput *models.AlertO
 		OutputID:           input.OutputID,
 		OutputType:         input.OutputType,
 		DefaultForSeverity: input.DefaultForSeverity,
+		RoutingRules:       input.RoutingRules,
+		MessageTemplate:    input.MessageTemplate,
 	}
 
 	// Decrypt the output before returning to the caller
@@ -103,6 +107,15 @@ func redactOutput(outputConfig *models.OutputConfig) {
 	if outputConfig.CustomWebhook != nil {
 		outputConfig.CustomWebhook.WebhookURL = redacted
 	}
+	if outputConfig.Email != nil {
+		outputConfig.Email.Password = redacted
+	}
+	if outputConfig.SplunkHEC != nil {
+		outputConfig.SplunkHEC.Token = redacted
+	}
+	if outputConfig.ServiceNow != nil {
+		outputConfig.ServiceNow.Password = redacted
+	}
 }
 
 func getOutputType(outputConfig *models.OutputConfig) (*string, error) {
@@ -136,6 +149,18 @@ func getOutputType(outputConfig *models.OutputConfig) (*string, error) {
 	if outputConfig.CustomWebhook != nil {
 		return aws.String("customwebhook"), nil
 	}
+	if outputConfig.Email != nil {
+		return aws.String("email"), nil
+	}
+	if outputConfig.SplunkHEC != nil {
+		return aws.String("splunkhec"), nil
+	}
+	if outputConfig.Syslog != nil {
+		return aws.String("syslog"), nil
+	}
+	if outputConfig.ServiceNow != nil {
+		return aws.String("servicenow"), nil
+	}
 
 	return nil, errors.New("no valid output configuration specified for alert output")
 }
@@ -152,7 +177,7 @@ func mergeConfigs(oldConfig, newConfig *models.OutputConfig) (*models.OutputConf
 		}
 	}
 	// Turn the bytes into a map so we can work with it more easily
-	var oldMap map[string]map[string]string
+	var oldMap map[string]map[string]interface{}
 	err = jsoniter.Unmarshal(oldBytes, &oldMap)
 	if err != nil {
 		return nil, &genericapi.InternalError{
@@ -167,7 +192,7 @@ func mergeConfigs(oldConfig, newConfig *models.OutputConfig) (*models.OutputConf
 			Message: "Unable to extract the new configuration",
 		}
 	}
-	var newMap map[string]map[string]string
+	var newMap map[string]map[string]interface{}
 	err = jsoniter.Unmarshal(newBytes, &newMap)
 	if err != nil {
 		return nil, &genericapi.InternalError{
@@ -178,7 +203,7 @@ func mergeConfigs(oldConfig, newConfig *models.OutputConfig) (*models.OutputConf
 	// Overwrite the existing configurations with the new configurations
 	for configType, configMap := range newMap {
 		for configKey, configValue := range configMap {
-			if configValue == "" {
+			if isEmptyConfigValue(configValue) {
 				continue
 			}
 			oldMap[configType][configKey] = configValue
@@ -205,6 +230,20 @@ func mergeConfigs(oldConfig, newConfig *models.OutputConfig) (*models.OutputConf
 	return combinedConfig, nil
 }
 
+// isEmptyConfigValue checks if a config value was left unchanged in a new config.
+// Only missing values and empty strings, which are also the value of redacted secrets, keep the old value,
+// so numbers can be set to zero and lists can be cleared.
+func isEmptyConfigValue(value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case string:
+		return value == redacted
+	default:
+		return false
+	}
+}
+
 func validateConfigByType(config *models.OutputConfig, outputType *string) error {
 	switch *outputType {
 	case "slack":
@@ -248,6 +287,24 @@ func validateConfigByType(config *models.OutputConfig, outputType *string) error
 		if config.CustomWebhook.WebhookURL != "" {
 			return nil
 		}
+	case "email":
+		// Authentication is optional, but a password is required when a user name is set
+		hasAuth := config.Email.UserName == "" || config.Email.Password != ""
+		if config.Email.Host != "" && config.Email.From != "" && len(config.Email.To) != 0 && hasAuth {
+			return nil
+		}
+	case "splunkhec":
+		if config.SplunkHEC.URL != "" && config.SplunkHEC.Token != "" {
+			return nil
+		}
+	case "syslog":
+		if config.Syslog.Host != "" {
+			return nil
+		}
+	case "servicenow":
+		if config.ServiceNow.InstanceURL != "" && config.ServiceNow.UserName != "" && config.ServiceNow.Password != "" {
+			return nil
+		}
 	}
 
 	return errors.New("invalid output configuration specified for alert output, missing required fields")
@@ -44,6 +44,13 @@ func (table *OutputsTable) UpdateOutput(alertOutput *AlertOutputItem) (*AlertOut
 	if alertOutput.DefaultForSeverity != nil {
 		updateExpression.Set(expression.Name("defaultForSeverity"), expression.Value(alertOutput.DefaultForSeverity))
 	}
+	if alertOutput.RoutingRules != nil {
+		updateExpression.Set(expression.Name("routingRules"), expression.Value(alertOutput.RoutingRules))
+	}
+
+	if alertOutput.MessageTemplate != nil {
+		updateExpression.Set(expression.Name("messageTemplate"), expression.Value(alertOutput.MessageTemplate))
+	}
 
 	conditionExpression := expression.Name("outputId").Equal(expression.Value(alertOutput.OutputID))
 	combinedExpression, err := expression.NewBuilder().
@@ -19,8 +19,15 @@ package validator
  */
 
 import (
+	"crypto/x509"
+	"encoding/pem"
+	"path"
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/panther-labs/panther/internal/core/alert_delivery/msgtemplate"
 )
 
 // Validator builds a custom struct validator.
@@ -29,6 +36,21 @@ func Validator() (*validator.Validate, error) {
 	if err := result.RegisterValidation("snsArn", validateAwsArn); err != nil {
 		return nil, err
 	}
+	if err := result.RegisterValidation("glob", validateGlob); err != nil {
+		return nil, err
+	}
+	if err := result.RegisterValidation("clock", validateClock); err != nil {
+		return nil, err
+	}
+	if err := result.RegisterValidation("timezone", validateTimezone); err != nil {
+		return nil, err
+	}
+	if err := result.RegisterValidation("messagetemplate", validateMessageTemplate); err != nil {
+		return nil, err
+	}
+	if err := result.RegisterValidation("pemcert", validatePEMCertificate); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
@@ -36,3 +58,36 @@ func validateAwsArn(fl validator.FieldLevel) bool {
 	fieldArn, err := arn.Parse(fl.Field().String())
 	return err == nil && fieldArn.Service == "sns"
 }
+
+// validateGlob checks that a field is a valid glob pattern
+func validateGlob(fl validator.FieldLevel) bool {
+	_, err := path.Match(fl.Field().String(), "")
+	return err == nil
+}
+
+// validateClock checks that a field is a time of day in 24-hour format
+func validateClock(fl validator.FieldLevel) bool {
+	_, err := time.Parse("15:04", fl.Field().String())
+	return err == nil
+}
+
+// validateTimezone checks that a field is an IANA time zone name
+func validateTimezone(fl validator.FieldLevel) bool {
+	_, err := time.LoadLocation(fl.Field().String())
+	return err == nil
+}
+
+// validateMessageTemplate checks that a field is a message template that renders without errors
+func validateMessageTemplate(fl validator.FieldLevel) bool {
+	return msgtemplate.Validate(fl.Field().String()) == nil
+}
+
+// validatePEMCertificate checks that a field is a PEM encoded X.509 certificate
+func validatePEMCertificate(fl validator.FieldLevel) bool {
+	block, _ := pem.Decode([]byte(fl.Field().String()))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return false
+	}
+	_, err := x509.ParseCertificate(block.Bytes)
+	return err == nil
+}
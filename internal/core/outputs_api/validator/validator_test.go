@@ -19,8 +19,16 @@ package validator
  */
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
@@ -87,3 +95,194 @@ func TestAddNonSnsArn(t *testing.T) {
 	require.Error(t, err)
 	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.Sns", "TopicArn", "snsArn"), err.Error())
 }
+
+func TestAddOutputRoutingRules(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	input := models.AddOutputInput{
+		UserID:      aws.String("3601990c-b566-404b-b367-3c6eacd6fe60"),
+		DisplayName: aws.String("mychannel"),
+		OutputConfig: &models.OutputConfig{
+			Slack: &models.SlackConfig{WebhookURL: "https://hooks.slack.com"},
+		},
+		RoutingRules: []*models.RoutingRule{
+			{
+				Action:     models.RoutingActionRoute,
+				Severities: []string{"HIGH"},
+				RuleIDs:    []string{"AWS.GuardDuty.*"},
+				LogTypes:   []string{"Okta.*"},
+				TimeOfDay: &models.TimeOfDay{
+					Start:    "18:00",
+					End:      "09:00",
+					Timezone: "America/New_York",
+				},
+			},
+		},
+	}
+	assert.NoError(t, validator.Struct(&input))
+
+	input.RoutingRules[0].RuleIDs = []string{"AWS.[GuardDuty"}
+	assert.Equal(t, expectedMsg("AddOutputInput.RoutingRules[0]", "RuleIDs[0]", "glob"), validator.Struct(&input).Error())
+	input.RoutingRules[0].RuleIDs = nil
+
+	input.RoutingRules[0].TimeOfDay.End = "25:00"
+	assert.Equal(t, expectedMsg("AddOutputInput.RoutingRules[0].TimeOfDay", "End", "clock"), validator.Struct(&input).Error())
+	input.RoutingRules[0].TimeOfDay.End = "09:00"
+
+	input.RoutingRules[0].TimeOfDay.Timezone = "Mars/Olympus_Mons"
+	assert.Equal(t, expectedMsg("AddOutputInput.RoutingRules[0].TimeOfDay", "Timezone", "timezone"), validator.Struct(&input).Error())
+	input.RoutingRules[0].TimeOfDay.Timezone = ""
+
+	input.RoutingRules[0].Action = "forward"
+	assert.Equal(t, expectedMsg("AddOutputInput.RoutingRules[0]", "Action", "oneof"), validator.Struct(&input).Error())
+}
+
+func TestAddOutputMessageTemplate(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	input := models.AddOutputInput{
+		UserID:      aws.String("3601990c-b566-404b-b367-3c6eacd6fe60"),
+		DisplayName: aws.String("mychannel"),
+		OutputConfig: &models.OutputConfig{
+			Slack: &models.SlackConfig{WebhookURL: "https://hooks.slack.com"},
+		},
+		MessageTemplate: aws.String(`{{ .Title | upper }} [{{ .DedupString | default "-" }}] {{ .EventCount }} events: {{ .Link }}`),
+	}
+	assert.NoError(t, validator.Struct(&input))
+
+	// Syntax error
+	input.MessageTemplate = aws.String("{{ .Title ")
+	assert.Equal(t, expectedMsg("AddOutputInput", "MessageTemplate", "messagetemplate"), validator.Struct(&input).Error())
+
+	// Unknown field
+	input.MessageTemplate = aws.String("{{ .NoSuchField }}")
+	assert.Equal(t, expectedMsg("AddOutputInput", "MessageTemplate", "messagetemplate"), validator.Struct(&input).Error())
+
+	// Function outside the allowed set
+	input.MessageTemplate = aws.String(`{{ env "HOME" }}`)
+	assert.Equal(t, expectedMsg("AddOutputInput", "MessageTemplate", "messagetemplate"), validator.Struct(&input).Error())
+}
+
+func TestAddOutputEmailConfig(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	input := models.AddOutputInput{
+		UserID:      aws.String("3601990c-b566-404b-b367-3c6eacd6fe60"),
+		DisplayName: aws.String("myemail"),
+		OutputConfig: &models.OutputConfig{
+			Email: &models.EmailConfig{
+				Host:    "smtp.example.com",
+				Port:    587,
+				TLSMode: models.EmailTLSModeStartTLS,
+				From:    "alerts@example.com",
+				To:      []string{"security@example.com"},
+			},
+		},
+	}
+	assert.NoError(t, validator.Struct(&input))
+
+	input.OutputConfig.Email.To = []string{"security"}
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.Email", "To[0]", "email"), validator.Struct(&input).Error())
+	input.OutputConfig.Email.To = []string{"security@example.com"}
+
+	input.OutputConfig.Email.TLSMode = "none"
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.Email", "TLSMode", "oneof"), validator.Struct(&input).Error())
+}
+
+func TestAddOutputSplunkHECConfig(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	input := models.AddOutputInput{
+		UserID:      aws.String("3601990c-b566-404b-b367-3c6eacd6fe60"),
+		DisplayName: aws.String("mysplunk"),
+		OutputConfig: &models.OutputConfig{
+			SplunkHEC: &models.SplunkHECConfig{
+				URL:           "https://splunk.example.com:8088",
+				Token:         "token",
+				CACertificate: generateTestCertificatePEM(t),
+			},
+		},
+	}
+	assert.NoError(t, validator.Struct(&input))
+
+	input.OutputConfig.SplunkHEC.CACertificate = "not a certificate"
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.SplunkHEC", "CACertificate", "pemcert"),
+		validator.Struct(&input).Error())
+	input.OutputConfig.SplunkHEC.CACertificate = ""
+
+	input.OutputConfig.SplunkHEC.URL = "splunk"
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.SplunkHEC", "URL", "url"), validator.Struct(&input).Error())
+}
+
+func TestAddOutputSyslogConfig(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	input := models.AddOutputInput{
+		UserID:      aws.String("3601990c-b566-404b-b367-3c6eacd6fe60"),
+		DisplayName: aws.String("mysyslog"),
+		OutputConfig: &models.OutputConfig{
+			Syslog: &models.SyslogConfig{
+				Host:     "10.0.0.1",
+				Port:     6514,
+				Protocol: models.SyslogProtocolTLS,
+				AppName:  "panther",
+				Facility: aws.Int(4),
+			},
+		},
+	}
+	assert.NoError(t, validator.Struct(&input))
+
+	input.OutputConfig.Syslog.Protocol = "udp"
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.Syslog", "Protocol", "oneof"), validator.Struct(&input).Error())
+	input.OutputConfig.Syslog.Protocol = models.SyslogProtocolTCP
+
+	input.OutputConfig.Syslog.AppName = "panther alerts"
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.Syslog", "AppName", "excludes"), validator.Struct(&input).Error())
+	input.OutputConfig.Syslog.AppName = ""
+
+	input.OutputConfig.Syslog.Facility = aws.Int(0)
+	assert.NoError(t, validator.Struct(&input))
+
+	input.OutputConfig.Syslog.Facility = aws.Int(24)
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.Syslog", "Facility", "max"), validator.Struct(&input).Error())
+}
+
+func generateTestCertificatePEM(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestAddOutputServiceNowConfig(t *testing.T) {
+	validator, err := Validator()
+	require.NoError(t, err)
+	input := models.AddOutputInput{
+		UserID:      aws.String("3601990c-b566-404b-b367-3c6eacd6fe60"),
+		DisplayName: aws.String("myservicenow"),
+		OutputConfig: &models.OutputConfig{
+			ServiceNow: &models.ServiceNowConfig{
+				InstanceURL: "https://example.service-now.com",
+				UserName:    "panther",
+				Password:    "secret",
+				Urgency:     map[string]int{"CRITICAL": 1, "INFO": 3},
+			},
+		},
+	}
+	assert.NoError(t, validator.Struct(&input))
+
+	input.OutputConfig.ServiceNow.Urgency = map[string]int{"CRITICAL": 4}
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.ServiceNow", "Urgency[CRITICAL]", "max"),
+		validator.Struct(&input).Error())
+
+	input.OutputConfig.ServiceNow.Urgency = map[string]int{"URGENT": 1}
+	assert.Equal(t, expectedMsg("AddOutputInput.OutputConfig.ServiceNow", "Urgency[URGENT]", "oneof"),
+		validator.Struct(&input).Error())
+}
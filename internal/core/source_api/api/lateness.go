@@ -0,0 +1,81 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"fmt"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+// validateLateness checks that the lateness policies of a source apply to distinct log types of the source
+func validateLateness(policies []models.LatenessPolicy, logTypes []string) error {
+	distinct := make(map[string]bool, len(policies))
+	for i := range policies {
+		p := &policies[i]
+		if !containsLogType(logTypes, p.LogType) {
+			return &genericapi.InvalidInputError{
+				Message: fmt.Sprintf("Cannot set lateness policy for %s, it is not a log type of the source", p.LogType),
+			}
+		}
+		if distinct[p.LogType] {
+			return &genericapi.InvalidInputError{
+				Message: fmt.Sprintf("Duplicate lateness policy for %s", p.LogType),
+			}
+		}
+		distinct[p.LogType] = true
+	}
+	return nil
+}
+
+func latenessToItem(policies []models.LatenessPolicy) []ddb.LatenessPolicy {
+	if policies == nil {
+		return nil
+	}
+	items := make([]ddb.LatenessPolicy, len(policies))
+	for i, p := range policies {
+		items[i] = ddb.LatenessPolicy{
+			LogType:              p.LogType,
+			PartitionBy:          p.PartitionBy,
+			MaxDelaySeconds:      p.MaxDelaySeconds,
+			MaxFutureSkewSeconds: p.MaxFutureSkewSeconds,
+			FutureEvents:         p.FutureEvents,
+		}
+	}
+	return items
+}
+
+func itemToLateness(items []ddb.LatenessPolicy) []models.LatenessPolicy {
+	if items == nil {
+		return nil
+	}
+	policies := make([]models.LatenessPolicy, len(items))
+	for i, item := range items {
+		policies[i] = models.LatenessPolicy{
+			LogType:              item.LogType,
+			PartitionBy:          item.PartitionBy,
+			MaxDelaySeconds:      item.MaxDelaySeconds,
+			MaxFutureSkewSeconds: item.MaxFutureSkewSeconds,
+			FutureEvents:         item.FutureEvents,
+		}
+	}
+	return policies
+}
@@ -0,0 +1,60 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+func TestValidateLateness(t *testing.T) {
+	logTypes := []string{"Nginx.Access", "AWS.CloudTrail"}
+	require.NoError(t, validateLateness(nil, logTypes))
+	require.NoError(t, validateLateness([]models.LatenessPolicy{
+		{LogType: "Nginx.Access", PartitionBy: models.PartitionByParseTime},
+		{LogType: "AWS.CloudTrail", MaxDelaySeconds: 3600, FutureEvents: models.FutureEventsClamp},
+	}, logTypes))
+
+	err := validateLateness([]models.LatenessPolicy{{LogType: "Syslog.RFC5424"}}, logTypes)
+	require.Error(t, err)
+	assert.IsType(t, &genericapi.InvalidInputError{}, err)
+	err = validateLateness([]models.LatenessPolicy{{LogType: "Nginx.Access"}, {LogType: "Nginx.Access"}}, logTypes)
+	require.Error(t, err)
+	assert.IsType(t, &genericapi.InvalidInputError{}, err)
+}
+
+func TestLatenessItem(t *testing.T) {
+	policies := []models.LatenessPolicy{
+		{
+			LogType:              "Nginx.Access",
+			PartitionBy:          models.PartitionByEventTime,
+			MaxDelaySeconds:      3600,
+			MaxFutureSkewSeconds: 60,
+			FutureEvents:         models.FutureEventsQuarantine,
+		},
+	}
+	assert.Equal(t, policies, itemToLateness(latenessToItem(policies)))
+	assert.Nil(t, latenessToItem(nil))
+	assert.Nil(t, itemToLateness(nil))
+}
@@ -0,0 +1,67 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"fmt"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/multiline"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+// validateMultiline checks that the multi-line framing rules of a source can be used by the log processor
+func validateMultiline(config *models.MultilineConfig) error {
+	if config == nil {
+		return nil
+	}
+	if _, err := multiline.New(config); err != nil {
+		return &genericapi.InvalidInputError{
+			Message: fmt.Sprintf("Invalid multi-line config: %s", err),
+		}
+	}
+	return nil
+}
+
+func multilineToItem(config *models.MultilineConfig) *ddb.MultilineConfig {
+	if config == nil {
+		return nil
+	}
+	return &ddb.MultilineConfig{
+		EventStartPattern:    config.EventStartPattern,
+		IndentedContinuation: config.IndentedContinuation,
+		MaxLines:             config.MaxLines,
+		MaxBytes:             config.MaxBytes,
+		TimeoutSeconds:       config.TimeoutSeconds,
+	}
+}
+
+func itemToMultiline(item *ddb.MultilineConfig) *models.MultilineConfig {
+	if item == nil {
+		return nil
+	}
+	return &models.MultilineConfig{
+		EventStartPattern:    item.EventStartPattern,
+		IndentedContinuation: item.IndentedContinuation,
+		MaxLines:             item.MaxLines,
+		MaxBytes:             item.MaxBytes,
+		TimeoutSeconds:       item.TimeoutSeconds,
+	}
+}
@@ -0,0 +1,55 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+func TestValidateMultiline(t *testing.T) {
+	require.NoError(t, validateMultiline(nil))
+	require.NoError(t, validateMultiline(&models.MultilineConfig{EventStartPattern: `^\d{4}-`}))
+	require.NoError(t, validateMultiline(&models.MultilineConfig{IndentedContinuation: true, MaxLines: 100}))
+
+	err := validateMultiline(&models.MultilineConfig{EventStartPattern: `^(`})
+	require.Error(t, err)
+	assert.IsType(t, &genericapi.InvalidInputError{}, err)
+	err = validateMultiline(&models.MultilineConfig{MaxLines: 100})
+	require.Error(t, err)
+	assert.IsType(t, &genericapi.InvalidInputError{}, err)
+}
+
+func TestMultilineItem(t *testing.T) {
+	config := &models.MultilineConfig{
+		EventStartPattern:    `^\d{4}-`,
+		IndentedContinuation: true,
+		MaxLines:             10,
+		MaxBytes:             1000,
+		TimeoutSeconds:       5,
+	}
+	assert.Equal(t, config, itemToMultiline(multilineToItem(config)))
+	assert.Nil(t, multilineToItem(nil))
+	assert.Nil(t, itemToMultiline(nil))
+}
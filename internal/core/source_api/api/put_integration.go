@@ -57,6 +57,11 @@ func (api API) PutIntegration(input *models.PutIntegrationInput) (newIntegration
 
 	// Generate the new integration from the input
 	newIntegration = generateNewIntegration(input)
+	newIntegration.TransformsHashKey, err = transformsHashKey(newIntegration.Transforms, nil)
+	if err != nil {
+		zap.L().Error("failed to generate transforms key", zap.Error(err))
+		return nil, putIntegrationInternalError
+	}
 
 	item := integrationToItem(newIntegration)
 
@@ -137,7 +142,26 @@ func (api API) validateIntegration(input *models.PutIntegrationInput) error {
 				input.IntegrationLabel, reason),
 		}
 	}
-	return nil
+
+	var logTypes []string
+	switch input.IntegrationType {
+	case models.IntegrationTypeAWS3:
+		logTypes = input.LogTypes
+		if err := validateS3PrefixLogTypes(input.S3PrefixLogTypes, logTypes); err != nil {
+			return err
+		}
+	case models.IntegrationTypeSqs:
+		if input.SqsConfig != nil {
+			logTypes = input.SqsConfig.LogTypes
+		}
+	}
+	if err := validateTransforms(input.Transforms, logTypes); err != nil {
+		return err
+	}
+	if err := validateMultiline(input.Multiline); err != nil {
+		return err
+	}
+	return validateLateness(input.Lateness, logTypes)
 }
 
 func (api API) integrationAlreadyExists(input *models.PutIntegrationInput) error {
@@ -255,6 +279,10 @@ func generateNewIntegration(input *models.PutIntegrationInput) *models.SourceInt
 		metadata.LogTypes = input.LogTypes
 		metadata.StackName = getStackName(input.IntegrationType, input.IntegrationLabel)
 		metadata.LogProcessingRole = generateLogProcessingRoleArn(input.AWSAccountID, input.IntegrationLabel)
+		metadata.Transforms = input.Transforms
+		metadata.Multiline = input.Multiline
+		metadata.S3PrefixLogTypes = input.S3PrefixLogTypes
+		metadata.Lateness = input.Lateness
 	case models.IntegrationTypeSqs:
 		metadata.SqsConfig = &models.SqsConfig{
 			S3Bucket:             env.InputDataBucketName,
@@ -265,6 +293,9 @@ func generateNewIntegration(input *models.PutIntegrationInput) *models.SourceInt
 			LogTypes:             input.SqsConfig.LogTypes,
 			QueueURL:             SourceSqsQueueURL(metadata.IntegrationID),
 		}
+		metadata.Transforms = input.Transforms
+		metadata.Multiline = input.Multiline
+		metadata.Lateness = input.Lateness
 	}
 	return &models.SourceIntegration{
 		SourceIntegrationMetadata: metadata,
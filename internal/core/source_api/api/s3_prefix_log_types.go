@@ -0,0 +1,77 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+// validateS3PrefixLogTypes checks that the S3 prefix log types of a source are valid patterns of its log types
+func validateS3PrefixLogTypes(prefixes []models.S3PrefixLogType, logTypes []string) error {
+	for i := range prefixes {
+		p := &prefixes[i]
+		if !containsLogType(logTypes, p.LogType) {
+			return &genericapi.InvalidInputError{
+				Message: fmt.Sprintf("Cannot assign %s to prefix %q, it is not a log type of the source", p.LogType, p.Prefix),
+			}
+		}
+		if !p.IsPattern() {
+			continue
+		}
+		if _, err := path.Match(p.Prefix, ""); err != nil {
+			return &genericapi.InvalidInputError{
+				Message: fmt.Sprintf("Invalid S3 key pattern %q: %s", p.Prefix, err),
+			}
+		}
+	}
+	return nil
+}
+
+func s3PrefixLogTypesToItem(prefixes []models.S3PrefixLogType) []ddb.S3PrefixLogType {
+	if prefixes == nil {
+		return nil
+	}
+	items := make([]ddb.S3PrefixLogType, len(prefixes))
+	for i, p := range prefixes {
+		items[i] = ddb.S3PrefixLogType{
+			Prefix:  p.Prefix,
+			LogType: p.LogType,
+		}
+	}
+	return items
+}
+
+func itemToS3PrefixLogTypes(items []ddb.S3PrefixLogType) []models.S3PrefixLogType {
+	if items == nil {
+		return nil
+	}
+	prefixes := make([]models.S3PrefixLogType, len(items))
+	for i, item := range items {
+		prefixes[i] = models.S3PrefixLogType{
+			Prefix:  item.Prefix,
+			LogType: item.LogType,
+		}
+	}
+	return prefixes
+}
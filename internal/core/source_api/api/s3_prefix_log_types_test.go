@@ -0,0 +1,54 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+func TestValidateS3PrefixLogTypes(t *testing.T) {
+	logTypes := []string{"Nginx.Access", "Apache.AccessCombined"}
+	require.NoError(t, validateS3PrefixLogTypes(nil, logTypes))
+	require.NoError(t, validateS3PrefixLogTypes([]models.S3PrefixLogType{
+		{Prefix: "nginx/", LogType: "Nginx.Access"},
+		{Prefix: "*/apache/access-*.log", LogType: "Apache.AccessCombined"},
+	}, logTypes))
+
+	err := validateS3PrefixLogTypes([]models.S3PrefixLogType{{Prefix: "syslog/", LogType: "Syslog.RFC5424"}}, logTypes)
+	require.Error(t, err)
+	assert.IsType(t, &genericapi.InvalidInputError{}, err)
+	err = validateS3PrefixLogTypes([]models.S3PrefixLogType{{Prefix: "nginx/[a-", LogType: "Nginx.Access"}}, logTypes)
+	require.Error(t, err)
+	assert.IsType(t, &genericapi.InvalidInputError{}, err)
+}
+
+func TestS3PrefixLogTypesItem(t *testing.T) {
+	prefixes := []models.S3PrefixLogType{
+		{Prefix: "nginx/", LogType: "Nginx.Access"},
+	}
+	assert.Equal(t, prefixes, itemToS3PrefixLogTypes(s3PrefixLogTypesToItem(prefixes)))
+	assert.Nil(t, s3PrefixLogTypesToItem(nil))
+	assert.Nil(t, itemToS3PrefixLogTypes(nil))
+}
@@ -0,0 +1,87 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/transform"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+// validateTransforms checks that the field transforms of a source are valid for the schemas of its log types
+func validateTransforms(transforms []models.FieldTransform, logTypes []string) error {
+	for i := range transforms {
+		t := &transforms[i]
+		if !containsLogType(logTypes, t.LogType) {
+			return &genericapi.InvalidInputError{
+				Message: fmt.Sprintf("Cannot transform fields of %s, it is not a log type of the source", t.LogType),
+			}
+		}
+		entry, err := logtypesResolver.Resolve(context.TODO(), t.LogType)
+		if err != nil {
+			zap.L().Error("failed to resolve log type", zap.String("logType", t.LogType), zap.Error(err))
+			return &genericapi.InternalError{Message: "Failed to validate field transforms"}
+		}
+		if entry == nil {
+			return &genericapi.InvalidInputError{
+				Message: fmt.Sprintf("Cannot transform fields of %s, log type not found", t.LogType),
+			}
+		}
+		if err := transform.Validate(entry.Schema(), t); err != nil {
+			return &genericapi.InvalidInputError{
+				Message: fmt.Sprintf("Invalid %s transform for %s field %q: %s", t.Action, t.LogType, t.Path, err),
+			}
+		}
+	}
+	return nil
+}
+
+// transformsHashKey returns the encrypted key for the hash transforms of a source.
+// The existing key of a source is kept so that hashed values do not change when transforms are updated.
+func transformsHashKey(transforms []models.FieldTransform, existingKey []byte) ([]byte, error) {
+	if len(existingKey) > 0 {
+		return existingKey, nil
+	}
+	for i := range transforms {
+		if transforms[i].Action != models.FieldTransformHash {
+			continue
+		}
+		// The plaintext key is only needed by the log processor
+		_, ciphertext, err := transformsKey.GenerateDataKey()
+		if err != nil {
+			return nil, err
+		}
+		return ciphertext, nil
+	}
+	return nil, nil
+}
+
+func containsLogType(logTypes []string, logType string) bool {
+	for _, t := range logTypes {
+		if t == logType {
+			return true
+		}
+	}
+	return false
+}
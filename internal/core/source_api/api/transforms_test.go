@@ -0,0 +1,99 @@
+package api
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+type mockDataKey struct {
+	calls int
+	err   error
+}
+
+func (m *mockDataKey) GenerateDataKey() ([]byte, []byte, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, nil, m.err
+	}
+	return []byte("plaintext"), []byte("ciphertext"), nil
+}
+
+func (m *mockDataKey) DecryptDataKey(_ []byte) ([]byte, error) {
+	return []byte("plaintext"), m.err
+}
+
+func TestValidateTransforms(t *testing.T) {
+	logtypesResolver = registry.NativeLogTypesResolver()
+	logTypes := []string{"AWS.CloudTrail"}
+
+	require.NoError(t, validateTransforms([]models.FieldTransform{
+		{LogType: "AWS.CloudTrail", Path: "userIdentity.userName", Action: models.FieldTransformHash},
+		{LogType: "AWS.CloudTrail", Path: "sourceIPAddress", Action: models.FieldTransformReplace, Value: "0.0.0.0"},
+		{LogType: "AWS.CloudTrail", Path: "requestParameters", Action: models.FieldTransformDrop},
+	}, logTypes))
+	require.NoError(t, validateTransforms(nil, nil))
+
+	for _, invalid := range []models.FieldTransform{
+		{LogType: "AWS.VPCFlow", Path: "srcAddr", Action: models.FieldTransformDrop},
+		{LogType: "AWS.CloudTrail", Path: "userIdentity.missing", Action: models.FieldTransformDrop},
+		{LogType: "AWS.CloudTrail", Path: "requestParameters", Action: models.FieldTransformHash},
+		{LogType: "AWS.CloudTrail", Path: "p_any_ip_addresses", Action: models.FieldTransformDrop},
+	} {
+		err := validateTransforms([]models.FieldTransform{invalid}, logTypes)
+		require.Error(t, err, invalid.Path)
+		assert.IsType(t, &genericapi.InvalidInputError{}, err)
+	}
+}
+
+func TestTransformsHashKey(t *testing.T) {
+	mockKey := &mockDataKey{}
+	transformsKey = mockKey
+
+	key, err := transformsHashKey([]models.FieldTransform{
+		{LogType: "AWS.CloudTrail", Path: "userIdentity.userName", Action: models.FieldTransformDrop},
+	}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, key)
+
+	hashTransforms := []models.FieldTransform{
+		{LogType: "AWS.CloudTrail", Path: "userIdentity.userName", Action: models.FieldTransformHash},
+	}
+	key, err = transformsHashKey(hashTransforms, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ciphertext"), key)
+
+	// existing keys are kept
+	key, err = transformsHashKey(hashTransforms, []byte("existing"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("existing"), key)
+	assert.Equal(t, 1, mockKey.calls)
+
+	mockKey.err = errors.New("access denied")
+	_, err = transformsHashKey(hashTransforms, nil)
+	require.Error(t, err)
+}
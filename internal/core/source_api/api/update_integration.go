@@ -73,6 +73,28 @@ func (api API) UpdateIntegrationSettings(input *models.UpdateIntegrationSettings
 		}
 	}
 
+	var logTypes []string
+	switch existingIntegrationItem.IntegrationType {
+	case models.IntegrationTypeAWS3:
+		logTypes = input.LogTypes
+		if err := validateS3PrefixLogTypes(input.S3PrefixLogTypes, logTypes); err != nil {
+			return nil, err
+		}
+	case models.IntegrationTypeSqs:
+		if input.SqsConfig != nil {
+			logTypes = input.SqsConfig.LogTypes
+		}
+	}
+	if err := validateTransforms(input.Transforms, logTypes); err != nil {
+		return nil, err
+	}
+	if err := validateMultiline(input.Multiline); err != nil {
+		return nil, err
+	}
+	if err := validateLateness(input.Lateness, logTypes); err != nil {
+		return nil, err
+	}
+
 	if err := normalizeIntegration(existingIntegrationItem, input); err != nil {
 		zap.L().Error("failed to normalize integration", zap.Error(err))
 		return nil, err
@@ -105,6 +127,12 @@ func normalizeIntegration(item *ddb.Integration, input *models.UpdateIntegration
 		item.S3Prefix = input.S3Prefix
 		item.KmsKey = input.KmsKey
 		item.LogTypes = input.LogTypes
+		if err := normalizeTransforms(item, input.Transforms); err != nil {
+			return err
+		}
+		item.Multiline = multilineToItem(input.Multiline)
+		item.S3PrefixLogTypes = s3PrefixLogTypesToItem(input.S3PrefixLogTypes)
+		item.Lateness = latenessToItem(input.Lateness)
 	case models.IntegrationTypeSqs:
 		item.IntegrationLabel = input.IntegrationLabel
 		item.SqsConfig.LogTypes = input.SqsConfig.LogTypes
@@ -116,7 +144,23 @@ func normalizeIntegration(item *ddb.Integration, input *models.UpdateIntegration
 		if err := UpdateSourceSqsQueue(item.IntegrationID, newAllowedPrincipals, newAllowedSources); err != nil {
 			return updateIntegrationInternalError
 		}
+		if err := normalizeTransforms(item, input.Transforms); err != nil {
+			return err
+		}
+		item.Multiline = multilineToItem(input.Multiline)
+		item.Lateness = latenessToItem(input.Lateness)
+	}
+	return nil
+}
+
+func normalizeTransforms(item *ddb.Integration, transforms []models.FieldTransform) error {
+	key, err := transformsHashKey(transforms, item.TransformsHashKey)
+	if err != nil {
+		zap.L().Error("failed to generate transforms key", zap.Error(err))
+		return updateIntegrationInternalError
 	}
+	item.Transforms = transformsToItem(transforms)
+	item.TransformsHashKey = key
 	return nil
 }
 
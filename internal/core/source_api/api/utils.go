@@ -43,6 +43,11 @@ func integrationToItem(input *models.SourceIntegration) *ddb.Integration {
 		item.LogTypes = input.LogTypes
 		item.StackName = input.StackName
 		item.LogProcessingRole = generateLogProcessingRoleArn(input.AWSAccountID, input.IntegrationLabel)
+		item.Transforms = transformsToItem(input.Transforms)
+		item.TransformsHashKey = input.TransformsHashKey
+		item.Multiline = multilineToItem(input.Multiline)
+		item.S3PrefixLogTypes = s3PrefixLogTypesToItem(input.S3PrefixLogTypes)
+		item.Lateness = latenessToItem(input.Lateness)
 	case models.IntegrationTypeAWSScan:
 		item.AWSAccountID = input.AWSAccountID
 		item.CWEEnabled = input.CWEEnabled
@@ -64,6 +69,10 @@ func integrationToItem(input *models.SourceIntegration) *ddb.Integration {
 			AllowedPrincipalArns: input.SqsConfig.AllowedPrincipalArns,
 			AllowedSourceArns:    input.SqsConfig.AllowedSourceArns,
 		}
+		item.Transforms = transformsToItem(input.Transforms)
+		item.TransformsHashKey = input.TransformsHashKey
+		item.Multiline = multilineToItem(input.Multiline)
+		item.Lateness = latenessToItem(input.Lateness)
 	}
 	return item
 }
@@ -87,6 +96,11 @@ func itemToIntegration(item *ddb.Integration) *models.SourceIntegration {
 		integration.LogTypes = item.LogTypes
 		integration.StackName = item.StackName
 		integration.LogProcessingRole = item.LogProcessingRole
+		integration.Transforms = itemToTransforms(item.Transforms)
+		integration.TransformsHashKey = item.TransformsHashKey
+		integration.Multiline = itemToMultiline(item.Multiline)
+		integration.S3PrefixLogTypes = itemToS3PrefixLogTypes(item.S3PrefixLogTypes)
+		integration.Lateness = itemToLateness(item.Lateness)
 	case models.IntegrationTypeAWSScan:
 		integration.AWSAccountID = item.AWSAccountID
 		integration.CWEEnabled = item.CWEEnabled
@@ -108,6 +122,42 @@ func itemToIntegration(item *ddb.Integration) *models.SourceIntegration {
 			AllowedPrincipalArns: item.SqsConfig.AllowedPrincipalArns,
 			AllowedSourceArns:    item.SqsConfig.AllowedSourceArns,
 		}
+		integration.Transforms = itemToTransforms(item.Transforms)
+		integration.TransformsHashKey = item.TransformsHashKey
+		integration.Multiline = itemToMultiline(item.Multiline)
+		integration.Lateness = itemToLateness(item.Lateness)
 	}
 	return integration
 }
+
+func transformsToItem(transforms []models.FieldTransform) []ddb.FieldTransform {
+	if transforms == nil {
+		return nil
+	}
+	items := make([]ddb.FieldTransform, len(transforms))
+	for i, t := range transforms {
+		items[i] = ddb.FieldTransform{
+			LogType: t.LogType,
+			Path:    t.Path,
+			Action:  t.Action,
+			Value:   t.Value,
+		}
+	}
+	return items
+}
+
+func itemToTransforms(items []ddb.FieldTransform) []models.FieldTransform {
+	if items == nil {
+		return nil
+	}
+	transforms := make([]models.FieldTransform, len(items))
+	for i, item := range items {
+		transforms[i] = models.FieldTransform{
+			LogType: item.LogType,
+			Path:    item.Path,
+			Action:  item.Action,
+			Value:   item.Value,
+		}
+	}
+	return transforms
+}
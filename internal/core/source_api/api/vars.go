@@ -32,12 +32,18 @@ import (
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/kelseyhightower/envconfig"
 
+	"github.com/panther-labs/panther/internal/core/logtypesapi"
 	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+	"github.com/panther-labs/panther/pkg/encryption"
 )
 
 const (
 	maxElapsedTime       = 5 * time.Second
 	templateBucketRegion = endpoints.UsWest2RegionID
+
+	logTypesAPIFunctionName = "panther-logtypes-api"
 )
 
 var (
@@ -48,6 +54,8 @@ var (
 	sqsClient        sqsiface.SQSAPI
 	templateS3Client s3iface.S3API
 	lambdaClient     lambdaiface.LambdaAPI
+	transformsKey    encryption.DataKeyAPI
+	logtypesResolver logtypes.Resolver
 )
 
 type envConfig struct {
@@ -60,6 +68,7 @@ type envConfig struct {
 	InputDataRoleArn           string `required:"true" split_words:"true"`
 	InputDataBucketName        string `required:"true" split_words:"true"`
 	InputDataTopicArn          string `required:"true" split_words:"true"`
+	TransformsKeyID            string `required:"true" split_words:"true"`
 }
 
 // Setup parses the environment and constructs AWS and http clients on a cold Lambda start.
@@ -72,6 +81,16 @@ func Setup() {
 	sqsClient = sqs.New(awsSession)
 	templateS3Client = s3.New(awsSession, aws.NewConfig().WithRegion(templateBucketRegion))
 	lambdaClient = lambda.New(awsSession)
+	transformsKey = encryption.New(env.TransformsKeyID, awsSession)
+	logtypesResolver = logtypes.ChainResolvers(
+		registry.NativeLogTypesResolver(),
+		&logtypesapi.CustomLogsResolver{
+			API: &logtypesapi.LogTypesAPILambdaClient{
+				LambdaName: logTypesAPIFunctionName,
+				LambdaAPI:  lambdaClient,
+			},
+		},
+	)
 }
 
 // API provides receiver methods for each route handler.
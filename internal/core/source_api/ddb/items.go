@@ -46,6 +46,15 @@ type Integration struct {
 	LogProcessingRole string   `json:"logProcessingRole,omitempty"`
 
 	SqsConfig *SqsConfig `json:"sqsConfig,omitempty"`
+
+	Transforms        []FieldTransform `json:"transforms,omitempty"`
+	TransformsHashKey []byte           `json:"transformsHashKey,omitempty"`
+
+	Multiline *MultilineConfig `json:"multiline,omitempty"`
+
+	S3PrefixLogTypes []S3PrefixLogType `json:"s3PrefixLogTypes,omitempty"`
+
+	Lateness []LatenessPolicy `json:"lateness,omitempty"`
 }
 
 type IntegrationStatus struct {
@@ -63,3 +72,31 @@ type SqsConfig struct {
 	AllowedSourceArns    []string `json:"allowedSourceArns" dynamodbav:",stringset"`
 	QueueURL             string   `json:"queueUrl,omitempty"`
 }
+
+type FieldTransform struct {
+	LogType string `json:"logType"`
+	Path    string `json:"path"`
+	Action  string `json:"action"`
+	Value   string `json:"value,omitempty"`
+}
+
+type MultilineConfig struct {
+	EventStartPattern    string `json:"eventStartPattern,omitempty"`
+	IndentedContinuation bool   `json:"indentedContinuation,omitempty"`
+	MaxLines             int    `json:"maxLines,omitempty"`
+	MaxBytes             int    `json:"maxBytes,omitempty"`
+	TimeoutSeconds       int    `json:"timeoutSeconds,omitempty"`
+}
+
+type S3PrefixLogType struct {
+	Prefix  string `json:"prefix"`
+	LogType string `json:"logType"`
+}
+
+type LatenessPolicy struct {
+	LogType              string `json:"logType"`
+	PartitionBy          string `json:"partitionBy,omitempty"`
+	MaxDelaySeconds      int    `json:"maxDelaySeconds,omitempty"`
+	MaxFutureSkewSeconds int    `json:"maxFutureSkewSeconds,omitempty"`
+	FutureEvents         string `json:"futureEvents,omitempty"`
+}
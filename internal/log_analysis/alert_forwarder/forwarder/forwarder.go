@@ -203,7 +203,10 @@ func (h *Handler) sendAlertNotification(rule *ruleModel.Rule, alertDedup *AlertD
 		Tags:         rule.Tags,
 		Type:         alertModel.RuleType,
 		Title:        aws.String(getAlertTitle(rule, alertDedup)),
+		LogTypes:     alertDedup.LogTypes,
 		Version:      &alertDedup.RuleVersion,
+		DedupString:  aws.String(alertDedup.DeduplicationString),
+		EventCount:   int(alertDedup.EventCount),
 	}
 
 	msgBody, err := jsoniter.MarshalToString(alertNotification)
@@ -115,6 +115,9 @@ func TestHandleStoreAndSendNotification(t *testing.T) {
 		Severity:            string(testRuleResponse.Severity),
 		Tags:                []string{"Tag"},
 		Type:                alertModel.RuleType,
+		LogTypes:            newAlertDedupEvent.LogTypes,
+		DedupString:         aws.String(newAlertDedupEvent.DeduplicationString),
+		EventCount:          int(newAlertDedupEvent.EventCount),
 		AlertID:             aws.String("b25dc23fb2a0b362da8428dbec1381a8"),
 		Title:               newAlertDedupEvent.GeneratedTitle,
 	}
@@ -203,6 +206,9 @@ func TestHandleStoreAndSendNotificationNoRuleDisplayNameNoTitle(t *testing.T) {
 		Severity:            string(testRuleResponse.Severity),
 		Tags:                []string{"Tag"},
 		Type:                alertModel.RuleType,
+		LogTypes:            newAlertDedupEventWithoutTitle.LogTypes,
+		DedupString:         aws.String(newAlertDedupEventWithoutTitle.DeduplicationString),
+		EventCount:          int(newAlertDedupEventWithoutTitle.EventCount),
 		AlertID:             aws.String("b25dc23fb2a0b362da8428dbec1381a8"),
 		Title:               aws.String(newAlertDedupEventWithoutTitle.RuleID),
 	}
@@ -288,6 +294,9 @@ func TestHandleStoreAndSendNotificationNoGeneratedTitle(t *testing.T) {
 		Severity:            string(testRuleResponse.Severity),
 		Tags:                []string{"Tag"},
 		Type:                alertModel.RuleType,
+		LogTypes:            newAlertDedupEvent.LogTypes,
+		DedupString:         aws.String(newAlertDedupEvent.DeduplicationString),
+		EventCount:          int(newAlertDedupEvent.EventCount),
 		AlertID:             aws.String("b25dc23fb2a0b362da8428dbec1381a8"),
 		Title:               aws.String("DisplayName"),
 	}
@@ -377,6 +386,9 @@ func TestHandleStoreAndSendNotificationNilOldDedup(t *testing.T) {
 		Severity:            string(testRuleResponse.Severity),
 		Tags:                []string{"Tag"},
 		Type:                alertModel.RuleType,
+		LogTypes:            newAlertDedupEvent.LogTypes,
+		DedupString:         aws.String(newAlertDedupEvent.DeduplicationString),
+		EventCount:          int(newAlertDedupEvent.EventCount),
 		AlertID:             aws.String("b25dc23fb2a0b362da8428dbec1381a8"),
 		Title:               newAlertDedupEvent.GeneratedTitle,
 	}
@@ -27,9 +27,10 @@ import (
 // This file registers the Panther specific assumptions about tables and partition formats with associated functions.
 
 const (
-	logS3Prefix        = "logs"
-	ruleMatchS3Prefix  = "rules"
-	ruleErrorsS3Prefix = "rule_errors"
+	logS3Prefix                    = "logs"
+	ruleMatchS3Prefix              = "rules"
+	ruleErrorsS3Prefix             = "rule_errors"
+	classificationFailuresS3Prefix = "classification_failures"
 
 	LogProcessingDatabaseName        = "panther_logs"
 	LogProcessingDatabaseDescription = "Holds tables with data from Panther log processing"
@@ -43,6 +44,9 @@ const (
 	RuleErrorsDatabaseName        = "panther_rule_errors"
 	RuleErrorsDatabaseDescription = "Holds tables with data that failed Panther rule matching (same table structure as panther_logs)"
 
+	ClassificationFailuresDatabaseName        = "panther_classification_failures"
+	ClassificationFailuresDatabaseDescription = "Holds tables with log lines that could not be classified by Panther log processing"
+
 	TempDatabaseName        = "panther_temp"
 	TempDatabaseDescription = "Holds temporary tables used for processing tasks"
 )
@@ -50,11 +54,12 @@ const (
 var (
 	// PantherDatabases is exposed as public var to allow code to get/lookup the Panther databases
 	PantherDatabases = map[string]string{
-		LogProcessingDatabaseName: LogProcessingDatabaseDescription,
-		RuleMatchDatabaseName:     RuleMatchDatabaseDescription,
-		RuleErrorsDatabaseName:    RuleErrorsDatabaseDescription,
-		ViewsDatabaseName:         ViewsDatabaseDescription,
-		TempDatabaseName:          TempDatabaseDescription,
+		LogProcessingDatabaseName:          LogProcessingDatabaseDescription,
+		RuleMatchDatabaseName:              RuleMatchDatabaseDescription,
+		RuleErrorsDatabaseName:             RuleErrorsDatabaseDescription,
+		ClassificationFailuresDatabaseName: ClassificationFailuresDatabaseDescription,
+		ViewsDatabaseName:                  ViewsDatabaseDescription,
+		TempDatabaseName:                   TempDatabaseDescription,
 	}
 )
 
@@ -67,6 +72,8 @@ func getDatabase(dataType models.DataType) string {
 		return RuleMatchDatabaseName
 	case models.RuleErrors:
 		return RuleErrorsDatabaseName
+	case models.ClassificationFailures:
+		return ClassificationFailuresDatabaseName
 	default:
 		panic("Invalid DataType provided " + dataType)
 	}
@@ -81,6 +88,8 @@ func getTablePrefix(dataType models.DataType, tableName string) string {
 		return ruleMatchS3Prefix + "/" + tableName + "/"
 	case models.RuleErrors:
 		return ruleErrorsS3Prefix + "/" + tableName + "/"
+	case models.ClassificationFailures:
+		return classificationFailuresS3Prefix + "/" + tableName + "/"
 	default:
 		panic("Invalid DataType provided " + dataType)
 	}
@@ -100,6 +109,8 @@ func GetDataPrefix(databaseName string) string {
 		return ruleMatchS3Prefix
 	case RuleErrorsDatabaseName:
 		return ruleErrorsS3Prefix
+	case ClassificationFailuresDatabaseName:
+		return classificationFailuresS3Prefix
 	default:
 		if strings.Contains(databaseName, "test") {
 			return logS3Prefix // assume logs, used for integration tests
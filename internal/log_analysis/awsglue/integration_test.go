@@ -114,7 +114,7 @@ func TestIntegrationGlueMetadataPartitions(t *testing.T) {
 	assert.Nil(t, getPartitionOutput) // should not be there yet
 
 	expectedPath := "s3://" + testBucket + "/rules/" + testTable + "/year=2020/month=01/day=03/hour=01/"
-	created, err := table.CreateJSONPartition(glueClient, refTime)
+	created, err := table.CreateDataPartition(glueClient, refTime)
 	require.NoError(t, err)
 	assert.True(t, created)
 	partitionLocation := getPartitionLocation(t, []string{"2020", "01", "03", "01"})
@@ -93,6 +93,16 @@ func TestCreatePartitionFromS3Log(t *testing.T) {
 	assert.Equal(t, expectedPartitionValues, partition.GetPartitionColumnsInfo())
 }
 
+func TestCreatePartitionFromS3ClassificationFailures(t *testing.T) {
+	s3ObjectKey := "classification_failures/table/year=2020/month=02/day=26/hour=15/item.json.gz"
+	partition, err := GetPartitionFromS3("bucket", s3ObjectKey)
+	require.NoError(t, err)
+
+	assert.Equal(t, ClassificationFailuresDatabaseName, partition.GetDatabase())
+	assert.Equal(t, "table", partition.GetTable())
+	assert.Equal(t, "s3://bucket/classification_failures/table/year=2020/month=02/day=26/hour=15/", partition.GetPartitionLocation())
+}
+
 func TestCreatePartitionUnknownPrefix(t *testing.T) {
 	s3ObjectKey := "wrong_prefix/table/year=2020/month=02/day=26/hour=15/rule_id=Rule.Id/item.json.gz"
 	_, err := GetPartitionFromS3("bucket", s3ObjectKey)
@@ -120,7 +130,7 @@ func TestCreatePartitionLog(t *testing.T) {
 	mockClient.On("GetTable", mock.Anything).Return(testGetTableOutput, nil).Once()
 	mockClient.On("CreatePartition", mock.Anything).Return(&glue.CreatePartitionOutput{}, nil).Once()
 
-	created, err := partition.GetGlueTableMetadata().CreateJSONPartition(mockClient, partition.GetTime())
+	created, err := partition.GetGlueTableMetadata().CreateDataPartition(mockClient, partition.GetTime())
 	assert.NoError(t, err)
 	assert.True(t, created)
 	mockClient.AssertExpectations(t)
@@ -135,7 +145,7 @@ func TestCreateParitionRule(t *testing.T) {
 	mockClient.On("GetTable", mock.Anything).Return(testGetTableOutput, nil).Once()
 	mockClient.On("CreatePartition", mock.Anything).Return(&glue.CreatePartitionOutput{}, nil).Once()
 
-	created, err := partition.GetGlueTableMetadata().CreateJSONPartition(mockClient, partition.GetTime())
+	created, err := partition.GetGlueTableMetadata().CreateDataPartition(mockClient, partition.GetTime())
 	assert.NoError(t, err)
 	assert.True(t, created)
 	mockClient.AssertExpectations(t)
@@ -151,7 +161,7 @@ func TestCreatePartitionPartitionAlreadExists(t *testing.T) {
 	mockClient.On("CreatePartition", mock.Anything).
 		Return(&glue.CreatePartitionOutput{}, awserr.New(glue.ErrCodeAlreadyExistsException, "error", nil)).Once()
 
-	created, err := partition.GetGlueTableMetadata().CreateJSONPartition(mockClient, partition.GetTime())
+	created, err := partition.GetGlueTableMetadata().CreateDataPartition(mockClient, partition.GetTime())
 	assert.NoError(t, err)
 	assert.False(t, created)
 	mockClient.AssertExpectations(t)
@@ -167,7 +177,7 @@ func TestCreatePartitionAwsError(t *testing.T) {
 	mockClient.On("CreatePartition", mock.Anything).
 		Return(&glue.CreatePartitionOutput{}, awserr.New(glue.ErrCodeInternalServiceException, "error", nil)).Once()
 
-	created, err := partition.GetGlueTableMetadata().CreateJSONPartition(mockClient, partition.GetTime())
+	created, err := partition.GetGlueTableMetadata().CreateDataPartition(mockClient, partition.GetTime())
 	assert.Error(t, err)
 	assert.False(t, created)
 	mockClient.AssertExpectations(t)
@@ -182,7 +192,7 @@ func TestCreatePartitionGeneralError(t *testing.T) {
 	mockClient.On("GetTable", mock.Anything).Return(testGetTableOutput, nil).Once()
 	mockClient.On("CreatePartition", mock.Anything).Return(&glue.CreatePartitionOutput{}, errors.New("error")).Once()
 
-	created, err := partition.GetGlueTableMetadata().CreateJSONPartition(mockClient, partition.GetTime())
+	created, err := partition.GetGlueTableMetadata().CreateDataPartition(mockClient, partition.GetTime())
 	assert.Error(t, err)
 	assert.False(t, created)
 	mockClient.AssertExpectations(t)
@@ -0,0 +1,379 @@
+package awsglue
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/pkg/errors"
+)
+
+// ColumnChangeKind classifies the change of a column between two versions of a table schema
+type ColumnChangeKind int
+
+const (
+	// ColumnAdded is a new column, old data will read it as NULL
+	ColumnAdded ColumnChangeKind = iota
+	// ColumnRemoved is a column that is no longer part of the schema, old data is ignored
+	ColumnRemoved
+	// ColumnWidened is a column whose type can hold all values of the previous type
+	ColumnWidened
+	// ColumnIncompatible is a column whose type cannot read data written with the previous type
+	ColumnIncompatible
+)
+
+func (k ColumnChangeKind) String() string {
+	switch k {
+	case ColumnAdded:
+		return "add column"
+	case ColumnRemoved:
+		return "remove column"
+	case ColumnWidened:
+		return "widen type"
+	case ColumnIncompatible:
+		return "incompatible change"
+	default:
+		return fmt.Sprintf("ColumnChangeKind(%d)", int(k))
+	}
+}
+
+// ColumnChange describes the change of a single column
+type ColumnChange struct {
+	Kind ColumnChangeKind
+	Name string
+	From string
+	To   string
+}
+
+func (c *ColumnChange) String() string {
+	switch c.Kind {
+	case ColumnAdded:
+		return fmt.Sprintf("%s %s %s", c.Kind, c.Name, c.To)
+	case ColumnRemoved:
+		return fmt.Sprintf("%s %s %s", c.Kind, c.Name, c.From)
+	default:
+		return fmt.Sprintf("%s %s %s -> %s", c.Kind, c.Name, c.From, c.To)
+	}
+}
+
+// SchemaDiff holds the column changes needed to go from a deployed table schema to the current one
+type SchemaDiff struct {
+	DatabaseName string
+	TableName    string
+	Changes      []ColumnChange
+}
+
+// IsEmpty checks if the schemas are equivalent
+func (d *SchemaDiff) IsEmpty() bool {
+	return len(d.Changes) == 0
+}
+
+// IsSafe checks if data written with the old schema can be read using the new schema
+func (d *SchemaDiff) IsSafe() bool {
+	return len(d.Incompatible()) == 0
+}
+
+// Incompatible returns the changes that would break reading existing data
+func (d *SchemaDiff) Incompatible() (changes []ColumnChange) {
+	for _, c := range d.Changes {
+		if c.Kind == ColumnIncompatible {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// Report returns a human readable report of all changes
+func (d *SchemaDiff) Report() string {
+	var sb strings.Builder
+	sb.WriteString(d.DatabaseName)
+	sb.WriteByte('.')
+	sb.WriteString(d.TableName)
+	if d.IsEmpty() {
+		sb.WriteString(": no changes")
+		return sb.String()
+	}
+	sb.WriteByte(':')
+	for i := range d.Changes {
+		sb.WriteString("\n  ")
+		sb.WriteString(d.Changes[i].String())
+	}
+	return sb.String()
+}
+
+// DiffTable compares the schema of a deployed table to the schema defined by the table metadata
+func (gm *GlueTableMetadata) DiffTable(tbl *glue.TableData) (*SchemaDiff, error) {
+	var have []*glue.Column
+	if tbl.StorageDescriptor != nil {
+		have = tbl.StorageDescriptor.Columns
+	}
+	want := gm.glueTableInput("").StorageDescriptor.Columns
+	changes, err := DiffColumns(have, want)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to diff schema of %s.%s", gm.databaseName, gm.tableName)
+	}
+	return &SchemaDiff{
+		DatabaseName: gm.databaseName,
+		TableName:    gm.tableName,
+		Changes:      changes,
+	}, nil
+}
+
+// DiffColumns classifies the changes needed to go from the `from` columns to the `to` columns.
+// Column names are compared case insensitively since Glue stores them in lower case.
+func DiffColumns(from, to []*glue.Column) ([]ColumnChange, error) {
+	index := make(map[string]*glue.Column, len(from))
+	for _, col := range from {
+		index[strings.ToLower(aws.StringValue(col.Name))] = col
+	}
+	var changes []ColumnChange
+	for _, col := range to {
+		name := aws.StringValue(col.Name)
+		key := strings.ToLower(name)
+		toType := aws.StringValue(col.Type)
+		old, ok := index[key]
+		if !ok {
+			changes = append(changes, ColumnChange{
+				Kind: ColumnAdded,
+				Name: name,
+				To:   toType,
+			})
+			continue
+		}
+		delete(index, key)
+		fromType := aws.StringValue(old.Type)
+		kind, changed, err := classifyTypeChange(fromType, toType)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "column %q", name)
+		}
+		if !changed {
+			continue
+		}
+		changes = append(changes, ColumnChange{
+			Kind: kind,
+			Name: name,
+			From: fromType,
+			To:   toType,
+		})
+	}
+	// Keep the original column order for removed columns
+	for _, col := range from {
+		key := strings.ToLower(aws.StringValue(col.Name))
+		if _, removed := index[key]; !removed {
+			continue
+		}
+		changes = append(changes, ColumnChange{
+			Kind: ColumnRemoved,
+			Name: aws.StringValue(col.Name),
+			From: aws.StringValue(col.Type),
+		})
+	}
+	return changes, nil
+}
+
+func classifyTypeChange(from, to string) (kind ColumnChangeKind, changed bool, err error) {
+	fromType, err := parseColumnType(from)
+	if err != nil {
+		return 0, false, err
+	}
+	toType, err := parseColumnType(to)
+	if err != nil {
+		return 0, false, err
+	}
+	if fromType.String() == toType.String() {
+		return 0, false, nil
+	}
+	if toType.canRead(fromType) {
+		return ColumnWidened, true, nil
+	}
+	return ColumnIncompatible, true, nil
+}
+
+// columnType is a parsed Hive type expression (ie `struct<foo:array<string>>`)
+type columnType struct {
+	Name   string
+	Args   []*columnType // element type for arrays, key and value types for maps
+	Fields []columnField // fields for structs
+}
+
+type columnField struct {
+	Name string
+	Type *columnType
+}
+
+func (t *columnType) String() string {
+	switch t.Name {
+	case "struct":
+		fields := make([]string, len(t.Fields))
+		for i, f := range t.Fields {
+			fields[i] = f.Name + ":" + f.Type.String()
+		}
+		return "struct<" + strings.Join(fields, ",") + ">"
+	case "array", "map":
+		args := make([]string, len(t.Args))
+		for i, a := range t.Args {
+			args[i] = a.String()
+		}
+		return t.Name + "<" + strings.Join(args, ",") + ">"
+	default:
+		return t.Name
+	}
+}
+
+// integer types in order of size
+var integerTypeRanks = map[string]int{
+	"tinyint":  1,
+	"smallint": 2,
+	"int":      3,
+	"bigint":   4,
+}
+
+// canRead checks if all values of type `old` can be read by type `t`
+func (t *columnType) canRead(old *columnType) bool {
+	if t.Name != old.Name {
+		oldRank, oldInt := integerTypeRanks[old.Name]
+		newRank, newInt := integerTypeRanks[t.Name]
+		if oldInt && newInt {
+			return newRank > oldRank
+		}
+		return old.Name == "float" && t.Name == "double"
+	}
+	switch t.Name {
+	case "array", "map":
+		if len(t.Args) != len(old.Args) {
+			return false
+		}
+		for i, arg := range t.Args {
+			if !arg.canRead(old.Args[i]) {
+				return false
+			}
+		}
+		return true
+	case "struct":
+		// Struct fields are resolved by name so new fields can be added and old fields can be dropped
+		fields := make(map[string]*columnType, len(t.Fields))
+		for _, f := range t.Fields {
+			fields[f.Name] = f.Type
+		}
+		for _, f := range old.Fields {
+			typ, ok := fields[f.Name]
+			if !ok {
+				continue
+			}
+			if !typ.canRead(f.Type) {
+				return false
+			}
+		}
+		return true
+	default:
+		// Parameterized types like decimal(10,2) or varchar(255) must match exactly
+		return t.Name == old.Name
+	}
+}
+
+func parseColumnType(s string) (*columnType, error) {
+	p := typeParser{input: strings.ToLower(s)}
+	typ, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.input) {
+		return nil, errors.Errorf("invalid column type %q: unexpected input at %d", s, p.pos)
+	}
+	return typ, nil
+}
+
+type typeParser struct {
+	input string
+	pos   int
+}
+
+func (p *typeParser) parseType() (*columnType, error) {
+	name := p.readUntil("<,>:")
+	if name == "" {
+		return nil, p.errorf("missing type name")
+	}
+	typ := columnType{
+		Name: name,
+	}
+	if !p.consume('<') {
+		return &typ, nil
+	}
+	for {
+		if typ.Name == "struct" {
+			fieldName := p.readUntil(":<,>")
+			if !p.consume(':') {
+				return nil, p.errorf("missing ':' after struct field %q", fieldName)
+			}
+			fieldType, err := p.parseType()
+			if err != nil {
+				return nil, err
+			}
+			typ.Fields = append(typ.Fields, columnField{
+				Name: fieldName,
+				Type: fieldType,
+			})
+		} else {
+			arg, err := p.parseType()
+			if err != nil {
+				return nil, err
+			}
+			typ.Args = append(typ.Args, arg)
+		}
+		if p.consume('>') {
+			return &typ, nil
+		}
+		if !p.consume(',') {
+			return nil, p.errorf("expected ',' or '>'")
+		}
+	}
+}
+
+func (p *typeParser) readUntil(delimiters string) string {
+	start := p.pos
+	// Parameterized types (ie `decimal(10,2)`) contain commas inside parentheses
+	depth := 0
+	for ; p.pos < len(p.input); p.pos++ {
+		c := p.input[p.pos]
+		switch {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && strings.IndexByte(delimiters, c) != -1:
+			return strings.TrimSpace(p.input[start:p.pos])
+		}
+	}
+	return strings.TrimSpace(p.input[start:])
+}
+
+func (p *typeParser) consume(c byte) bool {
+	if p.pos < len(p.input) && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *typeParser) errorf(format string, args ...interface{}) error {
+	return errors.Errorf("invalid column type %q at %d: %s", p.input, p.pos, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,113 @@
+package awsglue
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/core/log_analysis/log_processor/models"
+)
+
+func TestDiffColumns(t *testing.T) {
+	col := func(name, typ string) *glue.Column {
+		return &glue.Column{Name: aws.String(name), Type: aws.String(typ)}
+	}
+	from := []*glue.Column{
+		col("same", "string"),
+		col("Case", "STRUCT<Foo:INT>"),
+		col("num", "int"),
+		col("ratio", "float"),
+		col("tags", "array<int>"),
+		col("obj", "struct<foo:string,bar:int>"),
+		col("attrs", "map<string,int>"),
+		col("changed", "string"),
+		col("shrunk", "bigint"),
+		col("nested", "struct<foo:struct<bar:int>>"),
+		col("dec", "decimal(10,2)"),
+		col("removed", "string"),
+	}
+	to := []*glue.Column{
+		col("same", "string"),
+		col("case", "struct<foo:int>"),
+		col("num", "bigint"),
+		col("ratio", "double"),
+		col("tags", "array<bigint>"),
+		col("obj", "struct<foo:string,bar:bigint,baz:boolean>"),
+		col("attrs", "map<string,bigint>"),
+		col("changed", "int"),
+		col("shrunk", "int"),
+		col("nested", "struct<foo:struct<bar:string>>"),
+		col("dec", "decimal(12,2)"),
+		col("added", "timestamp"),
+	}
+	changes, err := DiffColumns(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []ColumnChange{
+		{Kind: ColumnWidened, Name: "num", From: "int", To: "bigint"},
+		{Kind: ColumnWidened, Name: "ratio", From: "float", To: "double"},
+		{Kind: ColumnWidened, Name: "tags", From: "array<int>", To: "array<bigint>"},
+		{Kind: ColumnWidened, Name: "obj", From: "struct<foo:string,bar:int>", To: "struct<foo:string,bar:bigint,baz:boolean>"},
+		{Kind: ColumnWidened, Name: "attrs", From: "map<string,int>", To: "map<string,bigint>"},
+		{Kind: ColumnIncompatible, Name: "changed", From: "string", To: "int"},
+		{Kind: ColumnIncompatible, Name: "shrunk", From: "bigint", To: "int"},
+		{Kind: ColumnIncompatible, Name: "nested", From: "struct<foo:struct<bar:int>>", To: "struct<foo:struct<bar:string>>"},
+		{Kind: ColumnIncompatible, Name: "dec", From: "decimal(10,2)", To: "decimal(12,2)"},
+		{Kind: ColumnAdded, Name: "added", To: "timestamp"},
+		{Kind: ColumnRemoved, Name: "removed", From: "string"},
+	}, changes)
+
+	_, err = DiffColumns([]*glue.Column{col("bad", "struct<foo>")}, []*glue.Column{col("bad", "string")})
+	require.Error(t, err)
+}
+
+func TestSchemaDiff(t *testing.T) {
+	type event struct {
+		Foo string `json:"foo" description:"foo"`
+		Bar int64  `json:"bar" description:"bar"`
+	}
+	gm := NewGlueTableMetadata(models.LogData, "My.Logs", "description", GlueTableHourly, &event{})
+	tbl := &glue.TableData{
+		StorageDescriptor: &glue.StorageDescriptor{
+			Columns: []*glue.Column{
+				{Name: aws.String("foo"), Type: aws.String("string")},
+				{Name: aws.String("bar"), Type: aws.String("int")},
+			},
+		},
+	}
+	diff, err := gm.DiffTable(tbl)
+	require.NoError(t, err)
+	require.False(t, diff.IsEmpty())
+	require.True(t, diff.IsSafe())
+	require.Equal(t, "panther_logs.my_logs:\n  widen type bar int -> bigint", diff.Report())
+
+	tbl.StorageDescriptor.Columns[0].Type = aws.String("struct<foo:string>")
+	diff, err = gm.DiffTable(tbl)
+	require.NoError(t, err)
+	require.False(t, diff.IsSafe())
+	require.Equal(t, []ColumnChange{
+		{Kind: ColumnIncompatible, Name: "foo", From: "struct<foo:string>", To: "string"},
+	}, diff.Incompatible())
+	require.Equal(t, "panther_logs.my_logs:\n"+
+		"  incompatible change foo struct<foo:string> -> string\n"+
+		"  widen type bar int -> bigint", diff.Report())
+}
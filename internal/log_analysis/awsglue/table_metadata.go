@@ -39,6 +39,30 @@ import (
 	"github.com/panther-labs/panther/pkg/box"
 )
 
+// DataFormat is the storage format of the data files of a table
+type DataFormat int
+
+const (
+	// DataFormatJSON stores data as gzipped newline delimited JSON (the default)
+	DataFormatJSON DataFormat = iota
+	// DataFormatParquet stores data as Parquet files
+	DataFormatParquet
+)
+
+// ParquetTableSuffix is appended to the name of a log table to name the table holding the Parquet copy of its data
+const ParquetTableSuffix = "_parquet"
+
+func (f DataFormat) String() string {
+	switch f {
+	case DataFormatJSON:
+		return "json"
+	case DataFormatParquet:
+		return "parquet"
+	default:
+		return fmt.Sprintf("DataFormat(%d)", int(f))
+	}
+}
+
 type PartitionKey struct {
 	Name string
 	Type string
@@ -54,6 +78,9 @@ type GlueTableMetadata struct {
 	prefix       string
 	timebin      GlueTableTimebin // at what time resolution is this table partitioned
 	eventStruct  interface{}
+	dataFormat   DataFormat
+	// hasParquetTable is set for log tables whose data are also stored in a Parquet table
+	hasParquetTable bool
 }
 
 // Creates a new GlueTableMetadata object for Panther log sources
@@ -107,6 +134,47 @@ func (gm *GlueTableMetadata) EventStruct() interface{} {
 	return gm.eventStruct
 }
 
+// The storage format of the data files of this table
+func (gm *GlueTableMetadata) DataFormat() DataFormat {
+	return gm.dataFormat
+}
+
+// WithParquetTable returns a copy of a log table whose data are also stored in a Parquet table.
+func (gm *GlueTableMetadata) WithParquetTable() *GlueTableMetadata {
+	table := *gm
+	table.hasParquetTable = gm.dataType == models.LogData && gm.dataFormat == DataFormatJSON
+	return &table
+}
+
+// ParquetTable returns the table holding the Parquet copy of the data of a log table or nil if there is none.
+//
+// The Parquet table has its own S3 prefix in the log processing database, so the partitions of the JSON table are
+// left as is and each table uses the SerDe matching its files.
+func (gm *GlueTableMetadata) ParquetTable() *GlueTableMetadata {
+	if gm.dataFormat == DataFormatParquet {
+		return gm
+	}
+	if !gm.hasParquetTable {
+		return nil
+	}
+	return gm.parquetTable()
+}
+
+func (gm *GlueTableMetadata) parquetTable() *GlueTableMetadata {
+	tableName := gm.tableName + ParquetTableSuffix
+	return &GlueTableMetadata{
+		dataType:     gm.dataType,
+		databaseName: gm.databaseName,
+		tableName:    tableName,
+		description:  gm.description,
+		logType:      gm.logType,
+		prefix:       getTablePrefix(gm.dataType, tableName),
+		timebin:      gm.timebin,
+		eventStruct:  gm.eventStruct,
+		dataFormat:   DataFormatParquet,
+	}
+}
+
 func (gm *GlueTableMetadata) HasPartitions(glueClient glueiface.GlueAPI) (bool, error) {
 	return TableHasPartitions(glueClient, gm.databaseName, gm.tableName)
 }
@@ -171,36 +239,50 @@ func (gm *GlueTableMetadata) glueTableInput(bucketName string) *glue.TableInput
 		}
 	}
 
-	// Need to be case sensitive to deal with columns that have same name but different casing
-	descriptorParameters := map[string]*string{
-		"serialization.format": aws.String("1"),
-		"case.insensitive":     aws.String("false"),
+	storageDescriptor := &glue.StorageDescriptor{
+		Columns:  glueColumns,
+		Location: aws.String("s3://" + bucketName + "/" + gm.prefix),
 	}
+	switch gm.dataFormat {
+	case DataFormatParquet:
+		// Parquet columns are resolved by name so no column mappings are needed
+		storageDescriptor.InputFormat = aws.String("org.apache.hadoop.hive.ql.io.parquet.MapredParquetInputFormat")
+		storageDescriptor.OutputFormat = aws.String("org.apache.hadoop.hive.ql.io.parquet.MapredParquetOutputFormat")
+		storageDescriptor.SerdeInfo = &glue.SerDeInfo{
+			SerializationLibrary: aws.String("org.apache.hadoop.hive.ql.io.parquet.serde.ParquetHiveSerDe"),
+			Parameters: map[string]*string{
+				"serialization.format": aws.String("1"),
+			},
+		}
+	default: // configure as JSON
+		// Need to be case sensitive to deal with columns that have same name but different casing
+		descriptorParameters := map[string]*string{
+			"serialization.format": aws.String("1"),
+			"case.insensitive":     aws.String("false"),
+		}
 
-	// Add mapping for column names. This is required when columns are case sensitive
-	for _, column := range glueColumns {
-		descriptorParameters[fmt.Sprintf("mapping.%s", strings.ToLower(*column.Name))] = column.Name
-	}
-	// Add mapping for field names inside columns names. This is required when columns are case sensitive
-	for _, name := range structFieldNames {
-		descriptorParameters[fmt.Sprintf("mapping.%s", strings.ToLower(name))] = box.String(name)
+		// Add mapping for column names. This is required when columns are case sensitive
+		for _, column := range glueColumns {
+			descriptorParameters[fmt.Sprintf("mapping.%s", strings.ToLower(*column.Name))] = column.Name
+		}
+		// Add mapping for field names inside columns names. This is required when columns are case sensitive
+		for _, name := range structFieldNames {
+			descriptorParameters[fmt.Sprintf("mapping.%s", strings.ToLower(name))] = box.String(name)
+		}
+		storageDescriptor.InputFormat = aws.String("org.apache.hadoop.mapred.TextInputFormat")
+		storageDescriptor.OutputFormat = aws.String("org.apache.hadoop.hive.ql.io.HiveIgnoreKeyTextOutputFormat")
+		storageDescriptor.SerdeInfo = &glue.SerDeInfo{
+			SerializationLibrary: aws.String("org.openx.data.jsonserde.JsonSerDe"),
+			Parameters:           descriptorParameters,
+		}
 	}
 
 	return &glue.TableInput{
-		Name:          &gm.tableName,
-		Description:   &gm.description,
-		PartitionKeys: partitionColumns,
-		StorageDescriptor: &glue.StorageDescriptor{ // configure as JSON
-			Columns:      glueColumns,
-			Location:     aws.String("s3://" + bucketName + "/" + gm.prefix),
-			InputFormat:  aws.String("org.apache.hadoop.mapred.TextInputFormat"),
-			OutputFormat: aws.String("org.apache.hadoop.hive.ql.io.HiveIgnoreKeyTextOutputFormat"),
-			SerdeInfo: &glue.SerDeInfo{
-				SerializationLibrary: aws.String("org.openx.data.jsonserde.JsonSerDe"),
-				Parameters:           descriptorParameters,
-			},
-		},
-		TableType: aws.String("EXTERNAL_TABLE"),
+		Name:              &gm.tableName,
+		Description:       &gm.description,
+		PartitionKeys:     partitionColumns,
+		StorageDescriptor: storageDescriptor,
+		TableType:         aws.String("EXTERNAL_TABLE"),
 	}
 }
 
@@ -365,21 +447,43 @@ func (gm *GlueTableMetadata) SyncPartitions(glueClient glueiface.GlueAPI, s3Clie
 	return nextTimeBin, <-errChan
 }
 
-func (gm *GlueTableMetadata) CreateJSONPartition(client glueiface.GlueAPI, t time.Time) (created bool, err error) {
+// CreateDataPartition creates the partition for time t inheriting the storage descriptor of the table.
+// The table must store data as JSON or Parquet.
+func (gm *GlueTableMetadata) CreateDataPartition(client glueiface.GlueAPI, t time.Time) (created bool, err error) {
 	// inherit StorageDescriptor from table
 	tableOutput, err := GetTable(client, gm.databaseName, gm.tableName)
 	if err != nil {
 		return false, err
 	}
 
-	// ensure this is a JSON table, use Contains() because there are multiple json serdes
-	if !IsJSONPartition(tableOutput.Table.StorageDescriptor) {
-		return false, errors.Errorf("not a JSON table: %#v", *tableOutput.Table.StorageDescriptor)
+	// ensure this is a JSON or Parquet table, use Contains() because there are multiple json serdes
+	storageDescriptor := tableOutput.Table.StorageDescriptor
+	if !IsJSONPartition(storageDescriptor) && !IsParquetPartition(storageDescriptor) {
+		return false, errors.Errorf("not a JSON or Parquet table: %#v", *storageDescriptor)
 	}
 
 	return gm.createPartition(client, t, tableOutput)
 }
 
+// CreateParquetPartition creates the partition of the Parquet table of a log table if the Parquet table exists.
+// The log processor does not send notifications for Parquet files, so their partitions are created along with the
+// partitions of the JSON table.
+func (gm *GlueTableMetadata) CreateParquetPartition(client glueiface.GlueAPI, t time.Time) (created bool, err error) {
+	if gm.dataType != models.LogData || gm.dataFormat != DataFormatJSON {
+		return false, nil
+	}
+	table := gm.parquetTable()
+	tableOutput, err := GetTable(client, table.databaseName, table.tableName)
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == glue.ErrCodeEntityNotFoundException {
+			return false, nil
+		}
+		return false, err
+	}
+	return table.createPartition(client, t, tableOutput)
+}
+
 func (gm *GlueTableMetadata) createPartition(client glueiface.GlueAPI, t time.Time,
 	tableOutput *glue.GetTableOutput) (created bool, err error) {
 
@@ -147,51 +147,122 @@ func TestGlueTableMetadataSignature(t *testing.T) {
 	assert.Equal(t, "53372e1ee5b73d1e73594335e6df94489d0a759106fa2119fca66844f7ee5618", sig)
 }
 
-func TestCreateJSONPartition(t *testing.T) {
+func TestGlueTableMetadataParquet(t *testing.T) {
+	gm := NewGlueTableMetadata(models.LogData, "My.Logs.Type", "description", GlueTableHourly, partitionTestEvent{})
+	assert.Nil(t, gm.ParquetTable())
+	logTable := gm.WithParquetTable()
+	parquetTable := logTable.ParquetTable()
+	require.NotNil(t, parquetTable)
+	assert.Equal(t, DataFormatJSON, logTable.DataFormat())
+	assert.Equal(t, DataFormatParquet, parquetTable.DataFormat())
+	assert.Equal(t, parquetTable, parquetTable.ParquetTable())
+	assert.Equal(t, LogProcessingDatabaseName, parquetTable.DatabaseName())
+	assert.Equal(t, "my_logs_type_parquet", parquetTable.TableName())
+	assert.Equal(t, "logs/my_logs_type_parquet/year=2020/month=01/day=03/hour=01/", parquetTable.GetPartitionPrefix(refTime))
+
+	// the log table is not modified
+	sig, err := gm.Signature()
+	require.NoError(t, err)
+	logSig, err := logTable.Signature()
+	require.NoError(t, err)
+	assert.Equal(t, sig, logSig)
+
+	tableInput := parquetTable.glueTableInput(metadataTestBucket)
+	storageDescriptor := tableInput.StorageDescriptor
+	assert.Equal(t, "org.apache.hadoop.hive.ql.io.parquet.MapredParquetInputFormat", aws.StringValue(storageDescriptor.InputFormat))
+	assert.Equal(t, "org.apache.hadoop.hive.ql.io.parquet.MapredParquetOutputFormat", aws.StringValue(storageDescriptor.OutputFormat))
+	assert.True(t, IsParquetPartition(storageDescriptor))
+	assert.False(t, IsJSONPartition(storageDescriptor))
+	assert.Equal(t, "s3://testbucket/logs/my_logs_type_parquet/", aws.StringValue(storageDescriptor.Location))
+
+	// rule tables are written by the rules engine as JSON
+	assert.Equal(t, DataFormatJSON, logTable.RuleTable().DataFormat())
+	assert.Nil(t, logTable.RuleTable().WithParquetTable().ParquetTable())
+}
+
+func TestCreateDataPartition(t *testing.T) {
 	gm := NewGlueTableMetadata(models.LogData, "Test.Logs", "Description", GlueTableHourly, partitionTestEvent{})
 
 	// test no errors and partition does not exist (no error)
 	glueClient := &testutils.GlueMock{}
 	glueClient.On("GetTable", mock.Anything).Return(testGetTableOutput, nil).Once()
 	glueClient.On("CreatePartition", mock.Anything).Return(testCreatePartitionOutput, nil).Once()
-	created, err := gm.CreateJSONPartition(glueClient, refTime)
+	created, err := gm.CreateDataPartition(glueClient, refTime)
 	assert.NoError(t, err)
 	assert.True(t, created)
 	glueClient.AssertExpectations(t)
 }
 
-func TestCreateJSONPartitionPartitionExists(t *testing.T) {
+func TestCreateParquetPartition(t *testing.T) {
+	gm := NewGlueTableMetadata(models.LogData, "Test.Logs", "Description", GlueTableHourly, partitionTestEvent{})
+
+	storageDescriptor := *testStorageDescriptor
+	storageDescriptor.SerdeInfo = &glue.SerDeInfo{
+		SerializationLibrary: aws.String("org.apache.hadoop.hive.ql.io.parquet.serde.ParquetHiveSerDe"),
+	}
+	getTableOutput := &glue.GetTableOutput{
+		Table: &glue.TableData{
+			CreateTime:        aws.Time(refTime),
+			StorageDescriptor: &storageDescriptor,
+		},
+	}
+	glueClient := &testutils.GlueMock{}
+	glueClient.On("GetTable", &glue.GetTableInput{
+		DatabaseName: aws.String(LogProcessingDatabaseName),
+		Name:         aws.String("test_logs_parquet"),
+	}).Return(getTableOutput, nil).Once()
+	glueClient.On("CreatePartition", mock.Anything).Return(testCreatePartitionOutput, nil).Once()
+	created, err := gm.CreateParquetPartition(glueClient, refTime)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	glueClient.AssertExpectations(t)
+	input := glueClient.Calls[1].Arguments.Get(0).(*glue.CreatePartitionInput)
+	assert.Equal(t, "test_logs_parquet", aws.StringValue(input.TableName))
+	assert.Equal(t, "s3://testbucket/logs/test_logs_parquet/year=2020/month=01/day=03/hour=01/",
+		aws.StringValue(input.PartitionInput.StorageDescriptor.Location))
+	assert.True(t, IsParquetPartition(input.PartitionInput.StorageDescriptor))
+
+	// log types without a Parquet table are skipped
+	glueClient = &testutils.GlueMock{}
+	glueClient.On("GetTable", mock.Anything).Return(getTableOutput, entityNotFoundError).Once()
+	created, err = gm.CreateParquetPartition(glueClient, refTime)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	glueClient.AssertExpectations(t)
+}
+
+func TestCreateDataPartitionPartitionExists(t *testing.T) {
 	gm := NewGlueTableMetadata(models.LogData, "Test.Logs", "Description", GlueTableHourly, partitionTestEvent{})
 
 	// test partition exists at start
 	glueClient := &testutils.GlueMock{}
 	glueClient.On("GetTable", mock.Anything).Return(testGetTableOutput, nil)
 	glueClient.On("CreatePartition", mock.Anything).Return(testCreatePartitionOutput, entityExistsError)
-	created, err := gm.CreateJSONPartition(glueClient, refTime)
+	created, err := gm.CreateDataPartition(glueClient, refTime)
 	assert.NoError(t, err)
 	assert.False(t, created)
 	glueClient.AssertExpectations(t)
 }
 
-func TestCreateJSONPartitionErrorGettingTable(t *testing.T) {
+func TestCreateDataPartitionErrorGettingTable(t *testing.T) {
 	gm := NewGlueTableMetadata(models.LogData, "Test.Logs", "Description", GlueTableHourly, partitionTestEvent{})
 	// test error in GetTable
 	glueClient := &testutils.GlueMock{}
 	glueClient.On("GetTable", mock.Anything).Return(testGetTableOutput, nonAWSError).Once()
-	created, err := gm.CreateJSONPartition(glueClient, refTime)
+	created, err := gm.CreateDataPartition(glueClient, refTime)
 	assert.Error(t, err)
 	assert.False(t, created)
 	assert.Equal(t, nonAWSError, err)
 	glueClient.AssertExpectations(t)
 }
 
-func TestCreateJSONPartitionNonAWSError(t *testing.T) {
+func TestCreateDataPartitionNonAWSError(t *testing.T) {
 	gm := NewGlueTableMetadata(models.LogData, "Test.Logs", "Description", GlueTableHourly, partitionTestEvent{})
 	// test error in CreatePartition
 	glueClient := &testutils.GlueMock{}
 	glueClient.On("GetTable", mock.Anything).Return(testGetTableOutput, nil).Once()
 	glueClient.On("CreatePartition", mock.Anything).Return(testCreatePartitionOutput, nonAWSError).Once()
-	created, err := gm.CreateJSONPartition(glueClient, refTime)
+	created, err := gm.CreateDataPartition(glueClient, refTime)
 	assert.Error(t, err)
 	assert.False(t, created)
 	assert.Equal(t, nonAWSError, err)
@@ -43,6 +43,8 @@ type DataCatalogEvent struct {
 	events.SQSEvent
 	SyncDatabaseEvent   *SyncEvent
 	SyncTablePartitions *SyncTableEvent
+	RetentionEvent      *RetentionEvent
+	ExpireTableEvent    *ExpireTableEvent
 }
 
 // InvokeBackgroundSync triggers a database sync in the background.
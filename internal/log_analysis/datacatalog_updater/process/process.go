@@ -40,7 +40,7 @@ func Handle(ctx context.Context, event *DataCatalogEvent) (err error) {
 			zap.Int("sqsMessageCount", len(event.Records)))
 	}()
 
-	// This lambda handles 3 type of events:
+	// This lambda handles 5 type of events:
 	switch {
 	// 1. A SyncDatabase event to trigger a full database sync (used by custom resource manager)
 	case event.SyncDatabaseEvent != nil:
@@ -50,7 +50,15 @@ func Handle(ctx context.Context, event *DataCatalogEvent) (err error) {
 	case event.SyncTablePartitions != nil:
 		ctx = lambdalogger.Context(ctx, logger)
 		err = HandleSyncTableEvent(ctx, event.SyncTablePartitions)
-	// 3. An SQS message. See handleSQSEvent() for the supported message types.
+	// 3. A Retention event to delete expired data of all log types (triggered by a daily schedule)
+	case event.RetentionEvent != nil:
+		ctx = lambdalogger.Context(ctx, logger)
+		err = HandleRetentionEvent(ctx, event.RetentionEvent)
+	// 4. An ExpireTable event to delete expired data of a single table (triggered by retention events)
+	case event.ExpireTableEvent != nil:
+		ctx = lambdalogger.Context(ctx, logger)
+		err = HandleExpireTableEvent(ctx, event.ExpireTableEvent)
+	// 5. An SQS message. See handleSQSEvent() for the supported message types.
 	default:
 		err = handleSQSEvent(event.SQSEvent)
 	}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/glue"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/assert"
@@ -119,6 +120,38 @@ func TestProcessGlueFailure(t *testing.T) {
 	mockGlueClient.AssertExpectations(t)
 }
 
+func TestProcessSuccessParquetPartition(t *testing.T) {
+	initProcessTest()
+
+	// Log data are also stored in the Parquet table of the log type if it exists
+	isParquetTable := func(input *glue.GetTableInput) bool {
+		return aws.StringValue(input.Name) == "table"+awsglue.ParquetTableSuffix
+	}
+	mockGlueClient.On("GetTable", mock.MatchedBy(isParquetTable)).Return(testGetTableOutput, nil).Once()
+	mockGlueClient.On("GetTable", mock.Anything).Return(testGetTableOutput, nil).Once()
+	mockGlueClient.On("CreatePartition", mock.Anything).Return(&glue.CreatePartitionOutput{}, nil).Twice()
+
+	assert.NoError(t, handleSQSEvent(getEvent(t, "logs/table/year=2020/month=02/day=26/hour=15/item.json.gz")))
+	mockGlueClient.AssertExpectations(t)
+	parquetPartition := mockGlueClient.Calls[3].Arguments.Get(0).(*glue.CreatePartitionInput)
+	assert.Equal(t, "table"+awsglue.ParquetTableSuffix, aws.StringValue(parquetPartition.TableName))
+}
+
+func TestProcessSuccessNoParquetTable(t *testing.T) {
+	initProcessTest()
+
+	isParquetTable := func(input *glue.GetTableInput) bool {
+		return aws.StringValue(input.Name) == "table"+awsglue.ParquetTableSuffix
+	}
+	notFound := awserr.New(glue.ErrCodeEntityNotFoundException, "not found", nil)
+	mockGlueClient.On("GetTable", mock.MatchedBy(isParquetTable)).Return(&glue.GetTableOutput{}, notFound).Once()
+	mockGlueClient.On("GetTable", mock.Anything).Return(testGetTableOutput, nil).Once()
+	mockGlueClient.On("CreatePartition", mock.Anything).Return(&glue.CreatePartitionOutput{}, nil).Once()
+
+	assert.NoError(t, handleSQSEvent(getEvent(t, "logs/table/year=2020/month=02/day=26/hour=15/item.json.gz")))
+	mockGlueClient.AssertExpectations(t)
+}
+
 func TestProcessInvalidS3Key(t *testing.T) {
 	initProcessTest()
 	//Invalid keys should just be ignored
@@ -0,0 +1,224 @@
+package process
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/s3"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+	"github.com/panther-labs/panther/internal/log_analysis/gluetasks"
+	"github.com/panther-labs/panther/pkg/lambdalogger"
+)
+
+// RetentionEvent is a request to delete processed log data older than the retention period of each log type.
+type RetentionEvent struct {
+	// An identifier to use in order to keep track of all 'child' Lambda invocations.
+	TraceID string
+	// If set to true data will only be scanned and not deleted
+	DryRun bool
+}
+
+// ExpireTableEvent initializes or continues a gluetasks.ExpireTablePartitions task
+type ExpireTableEvent struct {
+	TraceID       string
+	LogType       string
+	RetentionDays int
+	// NumCalls keeps track of the number of recursive calls for the specific table.
+	NumCalls int
+	gluetasks.ExpireTablePartitions
+}
+
+// RetentionAudit is the record of the data deleted from a table.
+// A record is stored in the processed data bucket for each invocation that deleted data.
+type RetentionAudit struct {
+	TraceID       string                       `json:"traceId"`
+	DatabaseName  string                       `json:"databaseName"`
+	TableName     string                       `json:"tableName"`
+	LogType       string                       `json:"logType"`
+	RetentionDays int                          `json:"retentionDays"`
+	Before        time.Time                    `json:"before"`
+	DryRun        bool                         `json:"dryRun"`
+	Time          time.Time                    `json:"time"`
+	Partitions    []gluetasks.ExpiredPartition `json:"partitions"`
+	Error         string                       `json:"error,omitempty"`
+}
+
+const (
+	retentionAuditPrefix = "retention_audit"
+	// Max number of calls for a single table expiration
+	maxExpireNumCalls = 100
+)
+
+// HandleRetentionEvent starts an ExpireTableEvent in the background for each log type with a retention setting.
+// Retention only applies to processed log data, rule matches and errors are kept along with their alerts.
+// The Parquet table of a log type is expired along with its log table, it is skipped if it does not exist.
+func HandleRetentionEvent(ctx context.Context, event *RetentionEvent) error {
+	traceID := event.TraceID
+	if traceID == "" {
+		if lambdaCtx, ok := lambdacontext.FromContext(ctx); ok {
+			traceID = lambdaCtx.AwsRequestID
+		}
+	}
+	log := lambdalogger.FromContext(ctx).With(
+		zap.String("traceId", traceID),
+		zap.Bool("dryRun", event.DryRun),
+	)
+	reply, err := retentionLister.ListRetention(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to list log type retention")
+	}
+	now := time.Now().UTC()
+	numTasks := 0
+	for _, record := range reply.Retention {
+		if record.RetentionDays <= 0 {
+			continue
+		}
+		before := now.Add(-time.Duration(record.RetentionDays) * 24 * time.Hour)
+		tableName := awsglue.GetTableName(record.LogType)
+		for _, name := range []string{tableName, tableName + awsglue.ParquetTableSuffix} {
+			invokeErr := invokeEvent(ctx, lambdaClient, &DataCatalogEvent{
+				ExpireTableEvent: &ExpireTableEvent{
+					TraceID:       traceID,
+					LogType:       record.LogType,
+					RetentionDays: record.RetentionDays,
+					ExpireTablePartitions: gluetasks.ExpireTablePartitions{
+						DatabaseName: awsglue.LogProcessingDatabaseName,
+						TableName:    name,
+						Before:       before,
+						DryRun:       event.DryRun,
+					},
+				},
+			})
+			if invokeErr != nil {
+				log.Error("failed to invoke table expiration",
+					zap.String("logType", record.LogType), zap.String("table", name), zap.Error(invokeErr))
+				err = multierr.Append(err, invokeErr)
+				continue
+			}
+			numTasks++
+		}
+	}
+	log.Info("retention started", zap.Int("numTasks", numTasks))
+	return err
+}
+
+// HandleExpireTableEvent starts or continues a gluetasks.ExpireTablePartitions task and stores an audit record.
+func HandleExpireTableEvent(ctx context.Context, event *ExpireTableEvent) error {
+	// Reserve some time for storing the audit record and continuing the task in a new lambda invocation
+	if deadline, ok := ctx.Deadline(); ok {
+		const gracefulExitTimeout = time.Minute
+		if timeout := time.Until(deadline); timeout > gracefulExitTimeout {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout-gracefulExitTimeout)
+			defer cancel()
+		}
+	}
+	logger := lambdalogger.FromContext(ctx).With(
+		zap.String("traceId", event.TraceID),
+		zap.String("logType", event.LogType),
+		zap.Int("numCalls", event.NumCalls),
+	)
+	task := event.ExpireTablePartitions
+	err := task.Run(ctx, glueClient, s3Client, logger)
+	if isEntityNotFound(err) {
+		// There is no table if no data were ever stored for the log type
+		logger.Info("skipping retention for missing table")
+		return nil
+	}
+	// Store the audit record even if the task did not complete
+	if len(task.Expired) > 0 {
+		if auditErr := putRetentionAudit(context.Background(), event, &task, err); auditErr != nil {
+			logger.Error("failed to store retention audit", zap.Error(auditErr))
+			err = multierr.Append(err, auditErr)
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		numCalls := event.NumCalls + 1
+		if numCalls > maxExpireNumCalls {
+			return errors.Errorf("retention for %s did not complete after %d lambda calls", event.LogType, numCalls)
+		}
+		// Continue with a new invocation, partitions already deleted will not be scanned again
+		next := *event
+		next.NumCalls = numCalls
+		next.Expired = nil
+		next.Stats = gluetasks.ExpireStats{}
+		return invokeEvent(context.Background(), lambdaClient, &DataCatalogEvent{
+			ExpireTableEvent: &next,
+		})
+	}
+	if err != nil {
+		return errors.WithMessagef(err, "retention for %s failed", event.LogType)
+	}
+	return nil
+}
+
+func putRetentionAudit(ctx context.Context, event *ExpireTableEvent, task *gluetasks.ExpireTablePartitions, taskErr error) error {
+	now := time.Now().UTC()
+	audit := RetentionAudit{
+		TraceID:       event.TraceID,
+		DatabaseName:  task.DatabaseName,
+		TableName:     task.TableName,
+		LogType:       event.LogType,
+		RetentionDays: event.RetentionDays,
+		Before:        task.Before,
+		DryRun:        task.DryRun,
+		Time:          now,
+		Partitions:    task.Expired,
+	}
+	if taskErr != nil {
+		audit.Error = taskErr.Error()
+	}
+	body, err := jsoniter.Marshal(&audit)
+	if err != nil {
+		return err
+	}
+	_, err = s3Client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(config.ProcessedDataBucket),
+		Key:         aws.String(retentionAuditKey(task.TableName, now)),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/json"),
+	})
+	return err
+}
+
+func retentionAuditKey(tableName string, tm time.Time) string {
+	return fmt.Sprintf("%s/%s%s-%s.json", retentionAuditPrefix,
+		awsglue.GlueTableDaily.PartitionPathS3(tm), tableName, tm.Format("20060102T150405Z"))
+}
+
+func isEntityNotFound(err error) bool {
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		return awsErr.Code() == glue.ErrCodeEntityNotFoundException
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package process
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/core/logtypesapi"
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+)
+
+type testRetentionLister []*logtypesapi.RetentionRecord
+
+func (l testRetentionLister) ListRetention(_ context.Context) (*logtypesapi.ListRetentionOutput, error) {
+	return &logtypesapi.ListRetentionOutput{Retention: l}, nil
+}
+
+type testLambdaInvoker struct {
+	lambdaiface.LambdaAPI
+	events []*DataCatalogEvent
+}
+
+func (l *testLambdaInvoker) InvokeWithContext(_ aws.Context, input *lambda.InvokeInput, _ ...request.Option) (*lambda.InvokeOutput, error) {
+	event := DataCatalogEvent{}
+	if err := jsoniter.Unmarshal(input.Payload, &event); err != nil {
+		return nil, err
+	}
+	l.events = append(l.events, &event)
+	return &lambda.InvokeOutput{}, nil
+}
+
+func TestHandleRetentionEvent(t *testing.T) {
+	invoker := &testLambdaInvoker{}
+	lambdaClient = invoker
+	retentionLister = testRetentionLister{
+		{LogType: "AWS.VPCFlow", RetentionDays: 30},
+		{LogType: "AWS.CloudTrail"},
+	}
+	require.NoError(t, HandleRetentionEvent(context.Background(), &RetentionEvent{TraceID: "trace"}))
+	// The log table and its Parquet table are expired
+	require.Len(t, invoker.events, 2)
+	for i, tableName := range []string{"aws_vpcflow", "aws_vpcflow_parquet"} {
+		event := invoker.events[i].ExpireTableEvent
+		require.NotNil(t, event)
+		require.Equal(t, "trace", event.TraceID)
+		require.Equal(t, "AWS.VPCFlow", event.LogType)
+		require.Equal(t, 30, event.RetentionDays)
+		require.Equal(t, awsglue.LogProcessingDatabaseName, event.DatabaseName)
+		require.Equal(t, tableName, event.TableName)
+		require.WithinDuration(t, time.Now().Add(-30*24*time.Hour), event.Before, time.Minute)
+	}
+}
+
+func TestRetentionAuditKey(t *testing.T) {
+	tm := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.Equal(t, "retention_audit/year=2020/month=01/day=02/aws_vpcflow-20200102T030405Z.json", retentionAuditKey("aws_vpcflow", tm))
+}
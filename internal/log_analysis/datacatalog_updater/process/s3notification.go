@@ -80,10 +80,16 @@ func HandleS3Notification(notification S3Notification) error {
 		}
 
 		// attempt to create the partition
-		_, err = gluePartition.GetGlueTableMetadata().CreateJSONPartition(glueClient, gluePartition.GetTime())
+		gm := gluePartition.GetGlueTableMetadata()
+		_, err = gm.CreateDataPartition(glueClient, gluePartition.GetTime())
 		if err != nil {
 			return errors.Wrapf(err, "failed to create partition %#v", notification)
 		}
+		// the log processor stores Parquet files at the same time as JSON files but only notifies for the latter
+		_, err = gm.CreateParquetPartition(glueClient, gluePartition.GetTime())
+		if err != nil {
+			return errors.Wrapf(err, "failed to create Parquet partition %#v", notification)
+		}
 
 		// remember in cache
 		partitionPrefixCache[gluePartition.GetPartitionLocation()] = struct{}{}
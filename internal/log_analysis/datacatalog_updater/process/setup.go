@@ -30,8 +30,11 @@ import (
 	"github.com/aws/aws-sdk-go/service/glue/glueiface"
 	"github.com/aws/aws-sdk-go/service/lambda"
 	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/kelseyhightower/envconfig"
 
+	"github.com/panther-labs/panther/internal/core/logtypesapi"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
 	"github.com/panther-labs/panther/pkg/awsretry"
@@ -39,6 +42,8 @@ import (
 
 const (
 	maxRetries = 20 // setting Max Retries to a higher number - we'd like to retry VERY hard before failing.
+
+	logTypesAPIFunctionName = "panther-logtypes-api"
 )
 
 var (
@@ -50,6 +55,8 @@ var (
 	glueClient            glueiface.GlueAPI
 	lambdaClient          lambdaiface.LambdaAPI
 	athenaClient          athenaiface.AthenaAPI
+	s3Client              s3iface.S3API
+	retentionLister       logtypesapi.RetentionLister
 	logtypesResolver      logtypes.Resolver
 	listAvailableLogTypes func(ctx context.Context) ([]string, error)
 )
@@ -61,9 +68,24 @@ func Setup() {
 	glueClient = glue.New(awsSession)
 	lambdaClient = lambda.New(awsSession)
 	athenaClient = athena.New(awsSession)
+	s3Client = s3.New(awsSession)
 
-	logtypesResolver = registry.NativeLogTypesResolver()
-	listAvailableLogTypes = func(_ context.Context) ([]string, error) {
-		return registry.AvailableLogTypes(), nil
+	logtypesAPI := &logtypesapi.LogTypesAPILambdaClient{
+		LambdaName: logTypesAPIFunctionName,
+		LambdaAPI:  lambdaClient,
+	}
+	retentionLister = logtypesAPI
+	logtypesResolver = logtypes.ChainResolvers(
+		registry.NativeLogTypesResolver(),
+		&logtypesapi.CustomLogsResolver{
+			API: logtypesAPI,
+		},
+	)
+	listAvailableLogTypes = func(ctx context.Context) ([]string, error) {
+		reply, err := logtypesAPI.ListAvailableLogTypes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return reply.LogTypes, nil
 	}
 }
@@ -54,16 +54,27 @@ func HandleSyncEvent(ctx context.Context, event *SyncEvent) error {
 		// The rest can only have partitions in the range TableCreateTime <= PartitionTime < now
 		afterTableCreateTime := dbName != awsglue.LogProcessingDatabaseName
 		for _, logType := range event.LogTypes {
-			tblName := awsglue.GetTableName(logType)
-			tableEvents = append(tableEvents, &SyncTableEvent{
-				TraceID: event.TraceID,
-				SyncTablePartitions: gluetasks.SyncTablePartitions{
-					DryRun:               event.DryRun,
-					TableName:            tblName,
-					DatabaseName:         dbName,
-					AfterTableCreateTime: afterTableCreateTime,
-				},
-			})
+			tblNames := []string{awsglue.GetTableName(logType)}
+			if dbName == awsglue.LogProcessingDatabaseName {
+				parquetTable, err := resolveParquetTable(ctx, logType)
+				if err != nil {
+					log.Error("failed to resolve log type", zap.String("logType", logType), zap.Error(err))
+				}
+				if parquetTable != nil {
+					tblNames = append(tblNames, parquetTable.TableName())
+				}
+			}
+			for _, tblName := range tblNames {
+				tableEvents = append(tableEvents, &SyncTableEvent{
+					TraceID: event.TraceID,
+					SyncTablePartitions: gluetasks.SyncTablePartitions{
+						DryRun:               event.DryRun,
+						TableName:            tblName,
+						DatabaseName:         dbName,
+						AfterTableCreateTime: afterTableCreateTime,
+					},
+				})
+			}
 		}
 	}
 	numTasks := 0
@@ -82,3 +93,15 @@ func HandleSyncEvent(ctx context.Context, event *SyncEvent) error {
 	log.Info("database sync started", zap.Int("numTables", len(tableEvents)), zap.Int("numTasks", numTasks))
 	return nil
 }
+
+// resolveParquetTable returns the table holding the Parquet copy of a log type's data or nil if there is none
+func resolveParquetTable(ctx context.Context, logType string) (*awsglue.GlueTableMetadata, error) {
+	if logtypesResolver == nil {
+		return nil, nil
+	}
+	entry, err := logtypesResolver.Resolve(ctx, logType)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	return entry.GlueTableMeta().ParquetTable(), nil
+}
@@ -23,6 +23,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/glue"
@@ -101,6 +102,9 @@ func ExpandLogTables(tables ...*awsglue.GlueTableMetadata) (expanded []*awsglue.
 			continue
 		}
 		expanded = append(expanded, table, table.RuleTable(), table.RuleErrorTable())
+		if parquetTable := table.ParquetTable(); parquetTable != nil {
+			expanded = append(expanded, parquetTable)
+		}
 	}
 	return
 }
@@ -122,10 +126,57 @@ func BuildSignature(tables ...*awsglue.GlueTableMetadata) (string, error) {
 	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
+// DiffDeployedTables compares the schemas of deployed tables to the schemas defined in the table metadata.
+// Tables that are not deployed are skipped.
+func DiffDeployedTables(glueClient glueiface.GlueAPI, tables ...*awsglue.GlueTableMetadata) ([]*awsglue.SchemaDiff, error) {
+	diffs := make([]*awsglue.SchemaDiff, 0, len(tables))
+	for _, table := range tables {
+		output, err := awsglue.GetTable(glueClient, table.DatabaseName(), table.TableName())
+		if err != nil {
+			if awsutils.IsAnyError(err, glue.ErrCodeEntityNotFoundException) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failure checking existence of %s.%s",
+				table.DatabaseName(), table.TableName())
+		}
+		diff, err := table.DiffTable(output.Table)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// ValidateSchemaChanges returns an error reporting all tables whose schema changes would break reading existing data
+func ValidateSchemaChanges(glueClient glueiface.GlueAPI, tables ...*awsglue.GlueTableMetadata) error {
+	diffs, err := DiffDeployedTables(glueClient, tables...)
+	if err != nil {
+		return err
+	}
+	return CheckSchemaDiffs(diffs...)
+}
+
+// CheckSchemaDiffs returns an error with a report of all incompatible schema changes
+func CheckSchemaDiffs(diffs ...*awsglue.SchemaDiff) error {
+	var reports []string
+	for _, diff := range diffs {
+		if !diff.IsSafe() {
+			reports = append(reports, diff.Report())
+		}
+	}
+	if len(reports) > 0 {
+		return errors.Errorf("incompatible schema changes in %d table(s):\n%s", len(reports), strings.Join(reports, "\n"))
+	}
+	return nil
+}
+
 type TablesForLogType struct {
 	LogTable       *awsglue.GlueTableMetadata
 	RuleTable      *awsglue.GlueTableMetadata
 	RuleErrorTable *awsglue.GlueTableMetadata
+	// ParquetTable is nil unless the log type is also stored as Parquet
+	ParquetTable *awsglue.GlueTableMetadata
 }
 
 // CreateOrUpdateGlueTables, given a log meta data table, creates all tables related to this log table in the glue catalog.
@@ -155,9 +206,20 @@ func CreateOrUpdateGlueTables(glueClient glueiface.GlueAPI, bucket string,
 			ruleErrorTable.DatabaseName(), ruleErrorTable.TableName())
 	}
 
+	// the Parquet copy of the log data is kept in its own table so that each table has a single SerDe
+	parquetTable := logTable.ParquetTable()
+	if parquetTable != nil {
+		err = parquetTable.CreateOrUpdateTable(glueClient, bucket)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not create glue log table for %s.%s",
+				parquetTable.DatabaseName(), parquetTable.TableName())
+		}
+	}
+
 	return &TablesForLogType{
 		LogTable:       logTable,
 		RuleTable:      ruleTable,
 		RuleErrorTable: ruleErrorTable,
+		ParquetTable:   parquetTable,
 	}, nil
 }
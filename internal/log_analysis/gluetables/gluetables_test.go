@@ -23,11 +23,14 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/glue"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/panther-labs/panther/api/lambda/core/log_analysis/log_processor/models"
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
@@ -100,3 +103,37 @@ func TestDeployedTablesSignature(t *testing.T) {
 
 	mockGlueClient.AssertExpectations(t)
 }
+
+func TestValidateSchemaChanges(t *testing.T) {
+	type event struct {
+		Foo string `json:"foo" description:"foo"`
+	}
+	table := awsglue.NewGlueTableMetadata(models.LogData, "Foo.Bar", "foo", awsglue.GlueTableHourly, &event{})
+	tableOutput := func(colType string) *glue.GetTableOutput {
+		return &glue.GetTableOutput{
+			Table: &glue.TableData{
+				StorageDescriptor: &glue.StorageDescriptor{
+					Columns: []*glue.Column{
+						{Name: aws.String("foo"), Type: aws.String(colType)},
+					},
+				},
+			},
+		}
+	}
+
+	mockGlueClient := &testutils.GlueMock{}
+	mockGlueClient.On("GetTable", mock.Anything).Return(tableOutput("string"), nil).Once()
+	require.NoError(t, ValidateSchemaChanges(mockGlueClient, table))
+
+	mockGlueClient.On("GetTable", mock.Anything).Return(tableOutput("struct<foo:string>"), nil).Once()
+	err := ValidateSchemaChanges(mockGlueClient, table)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "incompatible change foo struct<foo:string> -> string")
+
+	// tables that are not deployed are skipped
+	notFound := awserr.New(glue.ErrCodeEntityNotFoundException, "not found", nil)
+	mockGlueClient.On("GetTable", mock.Anything).Return(&glue.GetTableOutput{}, notFound).Once()
+	require.NoError(t, ValidateSchemaChanges(mockGlueClient, table))
+
+	mockGlueClient.AssertExpectations(t)
+}
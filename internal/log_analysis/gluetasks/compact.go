@@ -0,0 +1,880 @@
+package gluetasks
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+	"github.com/panther-labs/panther/internal/log_analysis/parquet"
+)
+
+const (
+	// DefaultCompactTargetSize is the default size of the objects produced by compaction
+	DefaultCompactTargetSize = 128 * 1024 * 1024
+	// DefaultCompactMinAge is the default time that needs to pass after a partition ends before it is compacted
+	DefaultCompactMinAge = 24 * time.Hour
+
+	// S3 DeleteObjects accepts up to 1000 keys per request
+	maxDeleteKeys = 1000
+	// Layout of the timestamp prefix of processed data object keys
+	objectTimestampLayout = "20060102T150405Z"
+)
+
+// CompactDatabaseTables merges small objects in the closed partitions of all tables in a Glue database
+type CompactDatabaseTables struct {
+	// DatabaseName scans this Glue database for partitions to compact
+	DatabaseName string
+	// MatchPrefix will match tables whose name begins with this prefix
+	MatchPrefix string
+	// Start sets the start of the scan range
+	Start time.Time
+	// End sets the end of the scan range
+	End time.Time
+	// TargetSize sets the size in bytes of compacted objects
+	TargetSize int64
+	// MinAge sets the time that needs to pass after a partition ends for it to be considered closed
+	MinAge time.Duration
+	// NumWorkers sets the number of parallel compactions to run on each table
+	NumWorkers int
+	// DryRun is a flag to not modify any objects
+	DryRun bool
+	// Stats holds the stats for all tables compacted
+	Stats CompactStats
+}
+
+// Run executes the compaction
+func (c *CompactDatabaseTables) Run(ctx context.Context, glueAPI glueiface.GlueAPI, s3API s3iface.S3API, log *zap.Logger) error {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	log = log.Named("CompactDatabase").With(
+		zap.String("database", c.DatabaseName),
+	)
+	group, ctx := errgroup.WithContext(ctx)
+	tables := make(chan []*glue.TableData)
+	group.Go(func() error {
+		defer close(tables)
+		log.Info("scanning for tables")
+		input := glue.GetTablesInput{
+			DatabaseName: &c.DatabaseName,
+		}
+		if c.MatchPrefix != "" {
+			expr := c.MatchPrefix + "*"
+			input.Expression = &expr
+		}
+		err := glueAPI.GetTablesPagesWithContext(ctx, &input, func(page *glue.GetTablesOutput, _ bool) bool {
+			select {
+			case tables <- page.TableList:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+		if err != nil {
+			log.Error("failed to scan tables", zap.Error(err))
+		}
+		return err
+	})
+	group.Go(func() error {
+		for page := range tables {
+			tasks := make([]*CompactTablePartitions, len(page))
+			childGroup, ctx := errgroup.WithContext(ctx)
+			for i, tbl := range page {
+				tbl := tbl
+				task := &CompactTablePartitions{
+					DatabaseName: c.DatabaseName,
+					TableName:    aws.StringValue(tbl.Name),
+					Start:        c.Start,
+					End:          c.End,
+					TargetSize:   c.TargetSize,
+					MinAge:       c.MinAge,
+					NumWorkers:   c.NumWorkers,
+					DryRun:       c.DryRun,
+				}
+				tasks[i] = task
+				childGroup.Go(func() error {
+					log := log.With(zap.String("table", task.TableName))
+					return task.compactTable(ctx, glueAPI, s3API, log, tbl)
+				})
+			}
+			err := childGroup.Wait()
+			for _, task := range tasks {
+				c.Stats.merge(task.Stats)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return group.Wait()
+}
+
+// CompactTablePartitions merges small objects in the closed partitions of a table.
+//
+// Objects smaller than TargetSize are merged into objects of up to TargetSize bytes.
+// If a log table has a Parquet table, the Parquet copies of the merged objects are replaced along with them,
+// Parquet tables are not compacted on their own.
+// Each merge is recorded in a manifest in the partition before the merged object is staged under a key starting
+// with `_`, that queries skip. The objects it replaces are removed with a single DeleteObjects request before the
+// staged object is copied to its visible key, so queries never read the same rows twice. Rows of a merge
+// are missing from queries for the time between the removal and the copy.
+// Compacting a partition first completes or reverts the merges of pending manifests so that an interrupted
+// compaction can be safely run again.
+// Rows with a `p_row_id` that is already stored in the partition are not written to merged objects.
+type CompactTablePartitions struct {
+	DatabaseName string
+	TableName    string
+	NumWorkers   int
+	DryRun       bool
+	TargetSize   int64
+	MinAge       time.Duration
+	Start        time.Time
+	End          time.Time
+	Stats        CompactStats
+}
+
+func (c *CompactTablePartitions) Run(ctx context.Context, glueAPI glueiface.GlueAPI, s3API s3iface.S3API, log *zap.Logger) error {
+	tbl, err := findTable(ctx, glueAPI, c.DatabaseName, c.TableName)
+	if log == nil {
+		log = zap.NewNop()
+	}
+	log = log.Named("CompactTablePartitions").With(
+		zap.String("database", c.DatabaseName),
+		zap.String("table", c.TableName),
+	)
+	if err != nil {
+		log.Error("table not found", zap.Error(err))
+		return err
+	}
+	return c.compactTable(ctx, glueAPI, s3API, log, tbl)
+}
+
+func (c *CompactTablePartitions) compactTable(ctx context.Context, glueAPI glueiface.GlueAPI, s3API s3iface.S3API,
+	log *zap.Logger, tbl *glue.TableData) (err error) {
+
+	if desc := tbl.StorageDescriptor; desc != nil && desc.SerdeInfo != nil && awsglue.IsParquetPartition(desc) {
+		log.Info("skipping Parquet table, it is compacted along with its log table")
+		return nil
+	}
+	parquetTbl, err := findParquetTable(ctx, glueAPI, tbl)
+	if err != nil {
+		return err
+	}
+	var parquetColumns []*glue.Column
+	if parquetTbl != nil {
+		parquetColumns = parquetTbl.StorageDescriptor.Columns
+	}
+	start, end, err := buildRecoverRange(tbl, c.Start, c.End)
+	if err != nil {
+		return err
+	}
+	minAge := c.MinAge
+	if minAge <= 0 {
+		minAge = DefaultCompactMinAge
+	}
+	// Only partitions that ended before the cutoff are closed
+	cutoff := time.Now().Add(-minAge)
+	if end.After(cutoff) {
+		end = hourly.Truncate(cutoff.UTC())
+	}
+	if !start.Before(end) {
+		log.Info("no closed partitions in range", zap.Stringer("start", start), zap.Stringer("end", end))
+		return nil
+	}
+	log.Info("starting compaction", zap.Stringer("start", start), zap.Stringer("end", end))
+	defer func(since time.Time) {
+		delta := time.Since(since)
+		if err != nil {
+			log.Error("compaction failed", zap.Error(err), zap.Duration("duration", delta), zap.Any("stats", &c.Stats))
+		} else {
+			log.Info("compaction finished", zap.Duration("duration", delta), zap.Any("stats", &c.Stats))
+		}
+	}(time.Now())
+
+	targetSize := c.TargetSize
+	if targetSize <= 0 {
+		targetSize = DefaultCompactTargetSize
+	}
+	group, ctx := errgroup.WithContext(ctx)
+	partitions := make(chan *glue.Partition)
+	group.Go(func() error {
+		defer close(partitions)
+		expr := hourly.PartitionsBetween(start, end)
+		input := glue.GetPartitionsInput{
+			CatalogId:    tbl.CatalogId,
+			DatabaseName: tbl.DatabaseName,
+			TableName:    tbl.Name,
+			Expression:   &expr,
+		}
+		log.Info("scanning for partitions")
+		err := glueAPI.GetPartitionsPagesWithContext(ctx, &input, func(page *glue.GetPartitionsOutput, _ bool) bool {
+			for _, p := range page.Partitions {
+				select {
+				case partitions <- p:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil {
+			log.Error("partition scan failed", zap.Error(err))
+		}
+		return err
+	})
+	numWorkers := c.NumWorkers
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+	workers := make([]compactWorker, numWorkers)
+	for i := range workers {
+		w := &workers[i]
+		*w = compactWorker{
+			s3:             s3API,
+			log:            log,
+			dryRun:         c.DryRun,
+			targetSize:     targetSize,
+			table:          tbl,
+			parquetColumns: parquetColumns,
+		}
+		group.Go(func() error {
+			for p := range partitions {
+				if err := w.compactPartition(ctx, p); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	err = group.Wait()
+	for i := range workers {
+		c.Stats.merge(workers[i].stats)
+	}
+	return err
+}
+
+type compactWorker struct {
+	s3         s3iface.S3API
+	log        *zap.Logger
+	dryRun     bool
+	targetSize int64
+	table      *glue.TableData
+	// parquetColumns are the columns of the Parquet table of a log table or nil if there is none
+	parquetColumns []*glue.Column
+	stats          CompactStats
+}
+
+// compactObject is a data object in a partition.
+// ParquetKey is the key of the copy of the object in the Parquet table of a log table.
+type compactObject struct {
+	Key        string
+	ParquetKey string
+	Size       int64
+}
+
+func (w *compactWorker) compactPartition(ctx context.Context, p *glue.Partition) error {
+	tm, err := awsglue.PartitionTimeFromValues(p.Values)
+	if err != nil {
+		return err
+	}
+	log := w.log.With(zap.String("time", tm.Format("2006-01-02 15:04")))
+	desc := p.StorageDescriptor
+	if desc == nil || desc.Location == nil {
+		log.Warn("partition has no location")
+		return nil
+	}
+	bucket, prefix, err := awsglue.ParseS3URL(aws.StringValue(desc.Location))
+	if err != nil {
+		return errors.WithMessagef(err, "failed to parse S3 path for partition at %s", tm)
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	hasParquet := w.parquetColumns != nil
+	var listed []*s3.Object
+	listInput := s3.ListObjectsV2Input{
+		Bucket: &bucket,
+		Prefix: &prefix,
+	}
+	err = w.s3.ListObjectsV2PagesWithContext(ctx, &listInput, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		listed = append(listed, page.Contents...)
+		return true
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list objects for partition at %s", tm)
+	}
+	w.stats.NumPartitions++
+	listed, err = w.recoverMerges(ctx, bucket, listed)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to recover compaction of partition at %s", tm)
+	}
+	objects := partitionDataObjects(listed, hasParquet)
+	maxObjects := maxDeleteKeys
+	if hasParquet {
+		// Each merged Parquet object removes two keys
+		maxObjects /= 2
+	}
+	groups := planCompaction(objects, w.targetSize, maxObjects)
+	if len(groups) == 0 {
+		log.Debug("nothing to compact", zap.Int("numObjects", len(objects)))
+		return nil
+	}
+	w.stats.NumCompacted++
+	if w.dryRun {
+		for _, group := range groups {
+			w.stats.NumMerged++
+			w.stats.NumObjectsMerged += len(group)
+			w.stats.NumBytesMerged += totalSize(group)
+			log.Info("dryrun, skipping merge", zap.Int("numObjects", len(group)), zap.Int64("size", totalSize(group)))
+		}
+		return nil
+	}
+	// Row ids are tracked across all objects of the partition, including the ones that are not merged
+	rowIDs := make(map[string]struct{})
+	for _, obj := range unmergedObjects(objects, groups) {
+		if err := w.readRowIDs(ctx, bucket, obj.Key, rowIDs); err != nil {
+			return errors.WithMessagef(err, "failed to compact partition at %s", tm)
+		}
+	}
+	for _, group := range groups {
+		if err := w.mergeObjects(ctx, bucket, group, w.parquetColumns, rowIDs); err != nil {
+			w.stats.NumFailed++
+			return errors.WithMessagef(err, "failed to compact partition at %s", tm)
+		}
+	}
+	return nil
+}
+
+func (w *compactWorker) mergeObjects(ctx context.Context, bucket string, objects []compactObject, columns []*glue.Column,
+	rowIDs map[string]struct{}) error {
+
+	var out bytes.Buffer
+	gz := gzip.NewWriter(&out)
+	var pw *parquet.Writer
+	var pout bytes.Buffer
+	if columns != nil {
+		schema, err := parquet.NewSchema(glueColumns(columns))
+		if err != nil {
+			return err
+		}
+		pw = parquet.NewWriter(&pout, schema)
+	}
+	numRows := 0
+	for i := range objects {
+		obj := &objects[i]
+		reply, err := w.s3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+			Bucket: &bucket,
+			Key:    &obj.Key,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %q", obj.Key)
+		}
+		n, err := w.copyRows(gz, pw, reply.Body, rowIDs)
+		_ = reply.Body.Close()
+		if err != nil {
+			return errors.WithMessagef(err, "failed to read rows of %q", obj.Key)
+		}
+		numRows += n
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if pw != nil {
+		if err := pw.Close(); err != nil {
+			return err
+		}
+	}
+
+	prefix := path.Dir(objects[0].Key)
+	name := fmt.Sprintf("%s-%s", objectTimestamp(objects[0].Key), uuid.New())
+	manifest := compactManifest{
+		Key: path.Join(prefix, name+".json.gz"),
+	}
+	if pw != nil {
+		manifest.ParquetKey = parquetObjectKey(manifest.Key)
+	}
+	for i := range objects {
+		obj := &objects[i]
+		manifest.Merged = append(manifest.Merged, obj.Key)
+		if obj.ParquetKey != "" {
+			manifest.Merged = append(manifest.Merged, obj.ParquetKey)
+		}
+	}
+	manifestData, err := jsoniter.Marshal(&manifest)
+	if err != nil {
+		return err
+	}
+	manifestKey := compactManifestKey(manifest.Key)
+	if err := w.putObject(ctx, bucket, manifestKey, manifestData); err != nil {
+		return err
+	}
+	if pw != nil {
+		// Store the Parquet file first the same way the log processor does
+		if err := w.putObject(ctx, bucket, stagedObjectKey(manifest.ParquetKey), pout.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := w.putObject(ctx, bucket, stagedObjectKey(manifest.Key), out.Bytes()); err != nil {
+		return err
+	}
+	if err := w.publishMerge(ctx, bucket, &manifest, manifestKey); err != nil {
+		return err
+	}
+	w.stats.NumMerged++
+	w.stats.NumObjectsMerged += len(objects)
+	w.stats.NumBytesMerged += totalSize(objects)
+	w.stats.NumBytesWritten += int64(out.Len() + pout.Len())
+	w.log.Debug("merged objects",
+		zap.String("key", manifest.Key),
+		zap.Int("numObjects", len(objects)),
+		zap.Int("numRows", numRows),
+	)
+	return nil
+}
+
+// publishMerge replaces the objects merged by a manifest with its staged objects and removes the manifest.
+// The staged objects are copied to their visible keys only once all merged objects are removed.
+func (w *compactWorker) publishMerge(ctx context.Context, bucket string, manifest *compactManifest, manifestKey string) error {
+	// Remove all merged objects with a single request
+	if err := w.deleteObjects(ctx, bucket, manifest.Merged); err != nil {
+		return err
+	}
+	remove := []string{manifestKey}
+	for _, key := range []string{manifest.ParquetKey, manifest.Key} {
+		if key == "" {
+			continue
+		}
+		staged := stagedObjectKey(key)
+		if err := w.copyObject(ctx, bucket, staged, key); err != nil {
+			return err
+		}
+		remove = append(remove, staged)
+	}
+	return w.deleteObjects(ctx, bucket, remove)
+}
+
+// copyRows copies JSON lines from a gzip stream skipping rows with an already seen `p_row_id`
+func (w *compactWorker) copyRows(dst io.Writer, pw *parquet.Writer, src io.Reader, rowIDs map[string]struct{}) (int, error) {
+	gz, err := gzip.NewReader(src)
+	if err != nil {
+		return 0, err
+	}
+	numRows := 0
+	r := bufio.NewReader(gz)
+	for {
+		line, err := r.ReadBytes('\n')
+		if row := bytes.TrimSpace(line); len(row) > 0 && w.isNewRow(row, rowIDs) {
+			if _, err := dst.Write(row); err != nil {
+				return numRows, err
+			}
+			if _, err := dst.Write([]byte{'\n'}); err != nil {
+				return numRows, err
+			}
+			if pw != nil {
+				if err := pw.WriteJSON(row); err != nil {
+					return numRows, err
+				}
+			}
+			numRows++
+		}
+		if err == io.EOF {
+			return numRows, nil
+		}
+		if err != nil {
+			return numRows, err
+		}
+	}
+}
+
+// isNewRow checks if the `p_row_id` of a row was not seen before
+func (w *compactWorker) isNewRow(row []byte, rowIDs map[string]struct{}) bool {
+	rowID := jsoniter.Get(row, "p_row_id").ToString()
+	if rowID == "" {
+		return true
+	}
+	if _, duplicate := rowIDs[rowID]; duplicate {
+		w.stats.NumDuplicates++
+		return false
+	}
+	rowIDs[rowID] = struct{}{}
+	return true
+}
+
+// recoverMerges completes or reverts the merges of the manifests left in a partition by an interrupted compaction.
+// If the merged object of a manifest was staged the merge is published, otherwise the staged Parquet copy is removed.
+// It returns the listed objects that were not removed along with the published objects.
+func (w *compactWorker) recoverMerges(ctx context.Context, bucket string, listed []*s3.Object) ([]*s3.Object, error) {
+	objects := make(map[string]*s3.Object, len(listed))
+	for _, obj := range listed {
+		objects[aws.StringValue(obj.Key)] = obj
+	}
+	removed := make(map[string]bool)
+	var published []*s3.Object
+	for _, obj := range listed {
+		key := aws.StringValue(obj.Key)
+		if !isCompactManifestKey(key) {
+			continue
+		}
+		manifest, err := w.readManifest(ctx, bucket, key)
+		if err != nil {
+			return nil, err
+		}
+		staged := objects[stagedObjectKey(manifest.Key)]
+		if w.dryRun {
+			w.log.Info("dryrun, skipping recovery of interrupted merge", zap.String("manifest", key), zap.Bool("staged", staged != nil))
+			continue
+		}
+		if staged != nil {
+			if err := w.publishMerge(ctx, bucket, manifest, key); err != nil {
+				return nil, err
+			}
+			for _, k := range manifest.Merged {
+				removed[k] = true
+			}
+			removed[aws.StringValue(staged.Key)] = true
+			published = append(published, &s3.Object{
+				Key:  aws.String(manifest.Key),
+				Size: staged.Size,
+			})
+		} else {
+			// The merge was either published or not staged at all, only the staged Parquet copy can be left
+			remove := []string{key}
+			if manifest.ParquetKey != "" {
+				remove = append(remove, stagedObjectKey(manifest.ParquetKey))
+			}
+			if err := w.deleteObjects(ctx, bucket, remove); err != nil {
+				return nil, err
+			}
+		}
+		w.stats.NumRecovered++
+		w.log.Info("recovered interrupted merge", zap.String("manifest", key), zap.Bool("published", staged != nil))
+	}
+	if len(removed) == 0 {
+		return listed, nil
+	}
+	remaining := make([]*s3.Object, 0, len(listed)+len(published))
+	for _, obj := range listed {
+		if !removed[aws.StringValue(obj.Key)] {
+			remaining = append(remaining, obj)
+		}
+	}
+	return append(remaining, published...), nil
+}
+
+func (w *compactWorker) readManifest(ctx context.Context, bucket, key string) (*compactManifest, error) {
+	reply, err := w.s3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %q", key)
+	}
+	defer reply.Body.Close()
+	manifest := compactManifest{}
+	if err := jsoniter.NewDecoder(reply.Body).Decode(&manifest); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode manifest %q", key)
+	}
+	if manifest.Key == "" {
+		return nil, errors.Errorf("invalid manifest %q", key)
+	}
+	return &manifest, nil
+}
+
+// readRowIDs adds the `p_row_id` of all rows of an object to rowIDs
+func (w *compactWorker) readRowIDs(ctx context.Context, bucket, key string, rowIDs map[string]struct{}) error {
+	reply, err := w.s3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %q", key)
+	}
+	defer reply.Body.Close()
+	gz, err := gzip.NewReader(reply.Body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %q", key)
+	}
+	r := bufio.NewReader(gz)
+	for {
+		line, err := r.ReadBytes('\n')
+		if rowID := jsoniter.Get(line, "p_row_id").ToString(); rowID != "" {
+			rowIDs[rowID] = struct{}{}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read rows of %q", key)
+		}
+	}
+}
+
+// deleteObjects removes objects with a single request
+func (w *compactWorker) deleteObjects(ctx context.Context, bucket string, keys []string) error {
+	deleteInput := s3.DeleteObjectsInput{
+		Bucket: &bucket,
+		Delete: &s3.Delete{
+			Quiet: aws.Bool(true),
+		},
+	}
+	for _, key := range keys {
+		deleteInput.Delete.Objects = append(deleteInput.Delete.Objects, &s3.ObjectIdentifier{Key: aws.String(key)})
+	}
+	reply, err := w.s3.DeleteObjectsWithContext(ctx, &deleteInput)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete %d objects", len(keys))
+	}
+	for _, e := range reply.Errors {
+		err = multierr.Append(err, errors.Errorf("failed to delete %q: %s", aws.StringValue(e.Key), aws.StringValue(e.Message)))
+	}
+	return err
+}
+
+// copyObject copies an object within a bucket
+func (w *compactWorker) copyObject(ctx context.Context, bucket, src, dst string) error {
+	_, err := w.s3.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+		Bucket:     &bucket,
+		Key:        &dst,
+		CopySource: aws.String(url.PathEscape(bucket + "/" + src)),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to copy %q to %q", src, dst)
+	}
+	return nil
+}
+
+func (w *compactWorker) putObject(ctx context.Context, bucket, key string, body []byte) error {
+	_, err := w.s3.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+		Body:   bytes.NewReader(body),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to write %q", key)
+	}
+	return nil
+}
+
+// compactManifest records a merge before any object is written so that an interrupted compaction can be recovered.
+// Manifests are stored in the partition with a name starting with `_` so that queries skip them.
+type compactManifest struct {
+	// Key is the key of the merged object, it is staged under the key returned by stagedObjectKey
+	Key string `json:"key"`
+	// ParquetKey is the key of the Parquet copy of the merged object if the log table has a Parquet table
+	ParquetKey string `json:"parquetKey,omitempty"`
+	// Merged are the keys of the objects replaced by the merged object, including their Parquet copies
+	Merged []string `json:"merged"`
+}
+
+const compactManifestPrefix = "_compaction-"
+
+// compactManifestKey returns the key of the manifest for a merged object
+func compactManifestKey(key string) string {
+	dir, name := path.Split(key)
+	return dir + compactManifestPrefix + strings.TrimSuffix(name, ".json.gz") + ".json"
+}
+
+func isCompactManifestKey(key string) bool {
+	name := path.Base(key)
+	return strings.HasPrefix(name, compactManifestPrefix) && strings.HasSuffix(name, ".json")
+}
+
+const compactStagedPrefix = "_staged-"
+
+// stagedObjectKey returns the key a merged object is written to before it replaces the objects it merged.
+// Queries skip objects with a name starting with `_`.
+func stagedObjectKey(key string) string {
+	dir, name := path.Split(key)
+	return dir + compactStagedPrefix + name
+}
+
+// partitionDataObjects selects the visible JSON objects of a partition.
+// If the log table has a Parquet table, the key of the Parquet copy of each object is also set.
+func partitionDataObjects(listed []*s3.Object, hasParquet bool) (objects []compactObject) {
+	for _, obj := range listed {
+		key := aws.StringValue(obj.Key)
+		if name := path.Base(key); strings.HasPrefix(name, "_") || !strings.HasSuffix(name, ".json.gz") {
+			continue
+		}
+		object := compactObject{
+			Key:  key,
+			Size: aws.Int64Value(obj.Size),
+		}
+		if hasParquet {
+			object.ParquetKey = parquetObjectKey(key)
+		}
+		objects = append(objects, object)
+	}
+	return objects
+}
+
+// parquetObjectKey returns the key of the Parquet copy of a JSON object of a log table.
+// The Parquet copy has the same name and partition in the Parquet table (see awsglue.GlueTableMetadata.ParquetTable).
+func parquetObjectKey(key string) string {
+	parts := strings.SplitN(key, "/", 3)
+	if len(parts) != 3 {
+		return ""
+	}
+	parts[1] += awsglue.ParquetTableSuffix
+	return strings.TrimSuffix(strings.Join(parts, "/"), ".json.gz") + ".parquet"
+}
+
+// findParquetTable returns the Parquet table of a log table or nil if there is none
+func findParquetTable(ctx context.Context, api glueiface.GlueAPI, tbl *glue.TableData) (*glue.TableData, error) {
+	if aws.StringValue(tbl.DatabaseName) != awsglue.LogProcessingDatabaseName {
+		return nil, nil
+	}
+	parquetTbl, err := findTable(ctx, api, awsglue.LogProcessingDatabaseName, aws.StringValue(tbl.Name)+awsglue.ParquetTableSuffix)
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == glue.ErrCodeEntityNotFoundException {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parquetTbl, nil
+}
+
+// planCompaction groups objects smaller than targetSize so that each group adds up to at most targetSize bytes.
+// Groups with a single object are dropped since there is nothing to merge.
+func planCompaction(objects []compactObject, targetSize int64, maxObjects int) (groups [][]compactObject) {
+	small := make([]compactObject, 0, len(objects))
+	for _, obj := range objects {
+		if obj.Size < targetSize {
+			small = append(small, obj)
+		}
+	}
+	sort.Slice(small, func(i, j int) bool {
+		return small[i].Key < small[j].Key
+	})
+	var group []compactObject
+	var size int64
+	for _, obj := range small {
+		if len(group) > 0 && (size+obj.Size > targetSize || len(group) == maxObjects) {
+			if len(group) > 1 {
+				groups = append(groups, group)
+			}
+			group, size = nil, 0
+		}
+		group = append(group, obj)
+		size += obj.Size
+	}
+	if len(group) > 1 {
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// unmergedObjects returns the objects that are not in any of the merge groups
+func unmergedObjects(objects []compactObject, groups [][]compactObject) (unmerged []compactObject) {
+	merged := make(map[string]bool)
+	for _, group := range groups {
+		for _, obj := range group {
+			merged[obj.Key] = true
+		}
+	}
+	for _, obj := range objects {
+		if !merged[obj.Key] {
+			unmerged = append(unmerged, obj)
+		}
+	}
+	return unmerged
+}
+
+func totalSize(objects []compactObject) (size int64) {
+	for i := range objects {
+		size += objects[i].Size
+	}
+	return size
+}
+
+// objectTimestamp returns the timestamp prefix of an object key so merged objects sort along with the objects they replace
+func objectTimestamp(key string) string {
+	name := path.Base(key)
+	if i := strings.IndexByte(name, '-'); i != -1 {
+		name = name[:i]
+	}
+	if _, err := time.Parse(objectTimestampLayout, name); err != nil {
+		return time.Now().UTC().Format(objectTimestampLayout)
+	}
+	return name
+}
+
+func glueColumns(columns []*glue.Column) []awsglue.Column {
+	out := make([]awsglue.Column, len(columns))
+	for i, col := range columns {
+		out[i] = awsglue.Column{
+			Name:    aws.StringValue(col.Name),
+			Type:    aws.StringValue(col.Type),
+			Comment: aws.StringValue(col.Comment),
+		}
+	}
+	return out
+}
+
+type CompactStats struct {
+	NumPartitions    int
+	NumCompacted     int
+	NumMerged        int
+	NumObjectsMerged int
+	NumBytesMerged   int64
+	NumBytesWritten  int64
+	NumDuplicates    int
+	NumRecovered     int
+	NumFailed        int
+}
+
+func (s *CompactStats) merge(others ...CompactStats) {
+	for _, other := range others {
+		s.NumPartitions += other.NumPartitions
+		s.NumCompacted += other.NumCompacted
+		s.NumMerged += other.NumMerged
+		s.NumObjectsMerged += other.NumObjectsMerged
+		s.NumBytesMerged += other.NumBytesMerged
+		s.NumBytesWritten += other.NumBytesWritten
+		s.NumDuplicates += other.NumDuplicates
+		s.NumRecovered += other.NumRecovered
+		s.NumFailed += other.NumFailed
+	}
+}
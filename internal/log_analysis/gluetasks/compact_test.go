@@ -0,0 +1,340 @@
+package gluetasks
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPlanCompaction(t *testing.T) {
+	objects := []compactObject{
+		{Key: "d", Size: 40},
+		{Key: "a", Size: 40},
+		{Key: "big", Size: 100},
+		{Key: "c", Size: 40},
+		{Key: "b", Size: 40},
+		{Key: "e", Size: 10},
+	}
+	groups := planCompaction(objects, 100, maxDeleteKeys)
+	require.Equal(t, [][]compactObject{
+		{{Key: "a", Size: 40}, {Key: "b", Size: 40}},
+		{{Key: "c", Size: 40}, {Key: "d", Size: 40}, {Key: "e", Size: 10}},
+	}, groups)
+
+	groups = planCompaction(objects, 100, 2)
+	require.Equal(t, [][]compactObject{
+		{{Key: "a", Size: 40}, {Key: "b", Size: 40}},
+		{{Key: "c", Size: 40}, {Key: "d", Size: 40}},
+	}, groups)
+
+	require.Empty(t, planCompaction(objects[1:3], 100, maxDeleteKeys))
+}
+
+func TestPartitionDataObjects(t *testing.T) {
+	listed := []*s3.Object{
+		{Key: aws.String("logs/foo/hour=00/20200101T000000Z-1.json.gz"), Size: aws.Int64(10)},
+		{Key: aws.String("logs/foo/hour=00/_20200101T000000Z-2.json.gz"), Size: aws.Int64(20)},
+		{Key: aws.String("logs/foo/hour=00/20200101T000000Z-3.parquet"), Size: aws.Int64(15)},
+	}
+	require.Equal(t, []compactObject{
+		{Key: "logs/foo/hour=00/20200101T000000Z-1.json.gz", Size: 10},
+	}, partitionDataObjects(listed, false))
+	require.Equal(t, []compactObject{
+		{
+			Key:        "logs/foo/hour=00/20200101T000000Z-1.json.gz",
+			ParquetKey: "logs/foo_parquet/hour=00/20200101T000000Z-1.parquet",
+			Size:       10,
+		},
+	}, partitionDataObjects(listed, true))
+}
+
+func TestCompactPartition(t *testing.T) {
+	fake := &fakeS3{
+		objects: map[string][]byte{
+			"logs/foo/year=2020/month=01/day=01/hour=00/20200101T000000Z-1.json.gz": gzipLines(`{"p_row_id":"a"}`, `{"p_row_id":"b"}`),
+			"logs/foo/year=2020/month=01/day=01/hour=00/20200101T000000Z-2.json.gz": gzipLines(`{"p_row_id":"b"}`, `{"p_row_id":"c"}`),
+			"logs/foo/year=2020/month=01/day=01/hour=00/20200101T000000Z-3.json.gz": gzipLines(`{"p_row_id":"d"}`),
+		},
+	}
+	w := compactWorker{
+		s3:         fake,
+		log:        zap.NewNop(),
+		targetSize: DefaultCompactTargetSize,
+		table:      &glue.TableData{},
+	}
+	p := testCompactPartition()
+	require.NoError(t, w.compactPartition(context.Background(), p))
+	require.Equal(t, CompactStats{
+		NumPartitions:    1,
+		NumCompacted:     1,
+		NumMerged:        1,
+		NumObjectsMerged: 3,
+		NumBytesMerged:   w.stats.NumBytesMerged,
+		NumBytesWritten:  w.stats.NumBytesWritten,
+		NumDuplicates:    1,
+	}, w.stats)
+	require.Len(t, fake.objects, 1)
+	for key, data := range fake.objects {
+		require.True(t, strings.HasPrefix(key, "logs/foo/year=2020/month=01/day=01/hour=00/20200101T000000Z-"), key)
+		require.Equal(t, []string{`{"p_row_id":"a"}`, `{"p_row_id":"b"}`, `{"p_row_id":"c"}`, `{"p_row_id":"d"}`}, gunzipLines(t, data))
+	}
+
+	// Nothing to do in a dry run after the partition is compacted
+	w.dryRun = true
+	w.stats = CompactStats{}
+	require.NoError(t, w.compactPartition(context.Background(), p))
+	require.Equal(t, CompactStats{NumPartitions: 1}, w.stats)
+}
+
+func TestCompactPartitionParquet(t *testing.T) {
+	fake := &fakeS3{
+		objects: map[string][]byte{
+			"logs/foo/year=2020/month=01/day=01/hour=00/20200101T000000Z-1.json.gz":         gzipLines(`{"p_row_id":"a"}`),
+			"logs/foo/year=2020/month=01/day=01/hour=00/20200101T000000Z-2.json.gz":         gzipLines(`{"p_row_id":"b"}`),
+			"logs/foo_parquet/year=2020/month=01/day=01/hour=00/20200101T000000Z-1.parquet": []byte("PAR1"),
+			"logs/foo_parquet/year=2020/month=01/day=01/hour=00/20200101T000000Z-2.parquet": []byte("PAR1"),
+		},
+	}
+	w := compactWorker{
+		s3:         fake,
+		log:        zap.NewNop(),
+		targetSize: DefaultCompactTargetSize,
+		table:      &glue.TableData{},
+		parquetColumns: []*glue.Column{
+			{Name: aws.String("p_row_id"), Type: aws.String("string")},
+		},
+	}
+	require.NoError(t, w.compactPartition(context.Background(), testCompactPartition()))
+	require.Len(t, fake.objects, 2)
+	var keys []string
+	for key := range fake.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	// The Parquet copy of the merged object replaces the copies of the objects it merged
+	require.Equal(t, parquetObjectKey(keys[0]), keys[1])
+	require.Equal(t, []string{`{"p_row_id":"a"}`, `{"p_row_id":"b"}`}, gunzipLines(t, fake.objects[keys[0]]))
+	require.Equal(t, "PAR1", string(fake.objects[keys[1]][:4]))
+}
+
+func TestCompactPartitionRowIDs(t *testing.T) {
+	lines := []string{`{"p_row_id":"a"}`}
+	for i := 0; i < 100; i++ {
+		lines = append(lines, fmt.Sprintf(`{"p_row_id":"%d"}`, i))
+	}
+	big := gzipLines(lines...)
+	fake := &fakeS3{
+		objects: map[string][]byte{
+			"logs/foo/year=2020/month=01/day=01/hour=00/20200101T000000Z-0.json.gz": big,
+			"logs/foo/year=2020/month=01/day=01/hour=00/20200101T000000Z-1.json.gz": gzipLines(`{"p_row_id":"a"}`),
+			"logs/foo/year=2020/month=01/day=01/hour=00/20200101T000000Z-2.json.gz": gzipLines(`{"p_row_id":"b"}`),
+		},
+	}
+	w := compactWorker{
+		s3:         fake,
+		log:        zap.NewNop(),
+		targetSize: int64(len(big)),
+		table:      &glue.TableData{},
+	}
+	require.NoError(t, w.compactPartition(context.Background(), testCompactPartition()))
+	require.Equal(t, 1, w.stats.NumDuplicates)
+	require.Len(t, fake.objects, 2)
+	for key, data := range fake.objects {
+		if strings.HasSuffix(key, "-0.json.gz") {
+			continue
+		}
+		// Rows stored in objects that are not merged are dropped
+		require.Equal(t, []string{`{"p_row_id":"b"}`}, gunzipLines(t, data))
+	}
+}
+
+func TestCompactPartitionRecover(t *testing.T) {
+	const prefix = "logs/foo/year=2020/month=01/day=01/hour=00/"
+	const parquetPrefix = "logs/foo_parquet/year=2020/month=01/day=01/hour=00/"
+	stagedManifest := testManifest(prefix, "20200101T000000Z-m", "20200101T000000Z-1", "20200101T000000Z-2")
+	fake := &fakeS3{
+		objects: map[string][]byte{
+			// A merge that was interrupted after the merged object was staged
+			prefix + "20200101T000000Z-1.json.gz":                gzipLines(`{"p_row_id":"a"}`),
+			prefix + "20200101T000000Z-2.json.gz":                gzipLines(`{"p_row_id":"b"}`),
+			prefix + "_staged-20200101T000000Z-m.json.gz":        gzipLines(`{"p_row_id":"a"}`, `{"p_row_id":"b"}`),
+			prefix + "_compaction-20200101T000000Z-m.json":       stagedManifest,
+			parquetPrefix + "20200101T000000Z-1.parquet":         []byte("PAR1"),
+			parquetPrefix + "20200101T000000Z-2.parquet":         []byte("PAR1"),
+			parquetPrefix + "_staged-20200101T000000Z-m.parquet": []byte("PAR1"),
+			// A merge that was interrupted before the merged object was staged
+			prefix + "20200101T010000Z-3.json.gz":                gzipLines(`{"p_row_id":"c"}`),
+			prefix + "_compaction-20200101T010000Z-n.json":       testManifest(prefix, "20200101T010000Z-n", "20200101T010000Z-3"),
+			parquetPrefix + "20200101T010000Z-3.parquet":         []byte("PAR1"),
+			parquetPrefix + "_staged-20200101T010000Z-n.parquet": []byte("PAR1"),
+		},
+	}
+	w := compactWorker{
+		s3:         fake,
+		log:        zap.NewNop(),
+		targetSize: DefaultCompactTargetSize,
+		table:      &glue.TableData{},
+		parquetColumns: []*glue.Column{
+			{Name: aws.String("p_row_id"), Type: aws.String("string")},
+		},
+		dryRun: true,
+	}
+	// Nothing is removed in a dry run
+	require.NoError(t, w.compactPartition(context.Background(), testCompactPartition()))
+	require.Len(t, fake.objects, 11)
+
+	w.dryRun = false
+	w.stats = CompactStats{}
+	require.NoError(t, w.compactPartition(context.Background(), testCompactPartition()))
+	require.Equal(t, 2, w.stats.NumRecovered)
+	require.Equal(t, 1, w.stats.NumMerged)
+	require.Equal(t, 0, w.stats.NumDuplicates)
+	var keys []string
+	for key := range fake.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	// The published merged object is merged with the object of the reverted merge
+	require.Len(t, keys, 2)
+	require.Equal(t, parquetObjectKey(keys[0]), keys[1])
+	require.Equal(t, []string{`{"p_row_id":"a"}`, `{"p_row_id":"b"}`, `{"p_row_id":"c"}`}, gunzipLines(t, fake.objects[keys[0]]))
+}
+
+func testManifest(prefix, name string, merged ...string) []byte {
+	manifest := compactManifest{
+		Key: prefix + name + ".json.gz",
+	}
+	manifest.ParquetKey = parquetObjectKey(manifest.Key)
+	for _, m := range merged {
+		key := prefix + m + ".json.gz"
+		manifest.Merged = append(manifest.Merged, key, parquetObjectKey(key))
+	}
+	data, _ := jsoniter.Marshal(&manifest)
+	return data
+}
+
+func testCompactPartition() *glue.Partition {
+	return &glue.Partition{
+		Values: aws.StringSlice([]string{"2020", "01", "01", "00"}),
+		StorageDescriptor: &glue.StorageDescriptor{
+			Location: aws.String("s3://bucket/logs/foo/year=2020/month=01/day=01/hour=00/"),
+			SerdeInfo: &glue.SerDeInfo{
+				SerializationLibrary: aws.String("org.openx.data.jsonserde.JsonSerDe"),
+			},
+		},
+	}
+}
+
+type fakeS3 struct {
+	s3iface.S3API
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ListObjectsV2PagesWithContext(_ aws.Context, input *s3.ListObjectsV2Input,
+	fn func(*s3.ListObjectsV2Output, bool) bool, _ ...request.Option) error {
+
+	var keys []string
+	for key := range f.objects {
+		if strings.HasPrefix(key, aws.StringValue(input.Prefix)) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	page := s3.ListObjectsV2Output{}
+	for _, key := range keys {
+		page.Contents = append(page.Contents, &s3.Object{
+			Key:  aws.String(key),
+			Size: aws.Int64(int64(len(f.objects[key]))),
+		})
+	}
+	fn(&page, true)
+	return nil
+}
+
+func (f *fakeS3) GetObjectWithContext(_ aws.Context, input *s3.GetObjectInput, _ ...request.Option) (*s3.GetObjectOutput, error) {
+	return &s3.GetObjectOutput{
+		Body: ioutil.NopCloser(bytes.NewReader(f.objects[aws.StringValue(input.Key)])),
+	}, nil
+}
+
+func (f *fakeS3) PutObjectWithContext(_ aws.Context, input *s3.PutObjectInput, _ ...request.Option) (*s3.PutObjectOutput, error) {
+	data, err := ioutil.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.objects[aws.StringValue(input.Key)] = data
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeS3) CopyObjectWithContext(_ aws.Context, input *s3.CopyObjectInput, _ ...request.Option) (*s3.CopyObjectOutput, error) {
+	src, err := url.PathUnescape(aws.StringValue(input.CopySource))
+	if err != nil {
+		return nil, err
+	}
+	src = strings.TrimPrefix(src, aws.StringValue(input.Bucket)+"/")
+	data, ok := f.objects[src]
+	if !ok {
+		return nil, awserr.New(s3.ErrCodeNoSuchKey, "not found", nil)
+	}
+	f.objects[aws.StringValue(input.Key)] = data
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func (f *fakeS3) DeleteObjectsWithContext(_ aws.Context, input *s3.DeleteObjectsInput,
+	_ ...request.Option) (*s3.DeleteObjectsOutput, error) {
+
+	for _, obj := range input.Delete.Objects {
+		delete(f.objects, aws.StringValue(obj.Key))
+	}
+	return &s3.DeleteObjectsOutput{}, nil
+}
+
+func gzipLines(lines ...string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, _ = gz.Write([]byte(strings.Join(lines, "\n") + "\n"))
+	_ = gz.Close()
+	return buf.Bytes()
+}
+
+func gunzipLines(t *testing.T, data []byte) []string {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	out, err := ioutil.ReadAll(gz)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(out)), "\n")
+}
@@ -0,0 +1,233 @@
+package gluetasks
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+)
+
+// Glue BatchDeletePartition accepts up to 25 partitions per request
+const maxBatchDeletePartitions = 25
+
+// ExpireTablePartitions deletes all partitions of a table before a point in time from both S3 and Glue.
+//
+// The data of a partition are deleted from S3 before the partition is deleted from Glue.
+// This way a partition that failed to be removed will be retried in the next run and
+// no data are left in S3 without a partition pointing to them.
+type ExpireTablePartitions struct {
+	DatabaseName string
+	TableName    string
+	// Before sets the time before which all partitions are deleted
+	Before time.Time
+	// DryRun is a flag to not delete any data
+	DryRun bool
+	// Expired holds a record for each partition deleted
+	Expired []ExpiredPartition
+	Stats   ExpireStats
+}
+
+// ExpiredPartition is the record of a partition deleted by ExpireTablePartitions
+type ExpiredPartition struct {
+	Time       time.Time `json:"time"`
+	Location   string    `json:"location"`
+	NumObjects int       `json:"numObjects"`
+	NumBytes   int64     `json:"numBytes"`
+}
+
+type ExpireStats struct {
+	NumPartitions int
+	NumObjects    int
+	NumBytes      int64
+	NumFailed     int
+}
+
+func (e *ExpireTablePartitions) Run(ctx context.Context, glueAPI glueiface.GlueAPI, s3API s3iface.S3API, log *zap.Logger) (err error) {
+	if log == nil {
+		log = zap.NewNop()
+	}
+	log = log.Named("ExpireTablePartitions").With(
+		zap.String("database", e.DatabaseName),
+		zap.String("table", e.TableName),
+		zap.Time("before", e.Before),
+		zap.Bool("dryRun", e.DryRun),
+	)
+	tbl, err := findTable(ctx, glueAPI, e.DatabaseName, e.TableName)
+	if err != nil {
+		log.Error("table not found", zap.Error(err))
+		return err
+	}
+	bin, err := awsglue.TimebinFromTable(tbl)
+	if err != nil {
+		return err
+	}
+	defer func(since time.Time) {
+		delta := time.Since(since)
+		if err != nil {
+			log.Error("expire failed", zap.Error(err), zap.Duration("duration", delta), zap.Any("stats", &e.Stats))
+		} else {
+			log.Info("expire finished", zap.Duration("duration", delta), zap.Any("stats", &e.Stats))
+		}
+	}(time.Now())
+
+	// Partitions are collected before deleting any of them so that deletes do not interfere with paging
+	var partitions []*glue.Partition
+	expr := bin.PartitionsBefore(e.Before)
+	input := glue.GetPartitionsInput{
+		CatalogId:    tbl.CatalogId,
+		DatabaseName: tbl.DatabaseName,
+		TableName:    tbl.Name,
+		Expression:   &expr,
+	}
+	log.Info("scanning for expired partitions")
+	err = glueAPI.GetPartitionsPagesWithContext(ctx, &input, func(page *glue.GetPartitionsOutput, _ bool) bool {
+		partitions = append(partitions, page.Partitions...)
+		return true
+	})
+	if err != nil {
+		return errors.Wrap(err, "partition scan failed")
+	}
+	log.Info("partitions scanned", zap.Int("numPartitions", len(partitions)))
+
+	for len(partitions) > 0 {
+		batch := partitions
+		if len(batch) > maxBatchDeletePartitions {
+			batch = batch[:maxBatchDeletePartitions]
+		}
+		partitions = partitions[len(batch):]
+		if err := e.expirePartitions(ctx, glueAPI, s3API, tbl, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *ExpireTablePartitions) expirePartitions(ctx context.Context, glueAPI glueiface.GlueAPI, s3API s3iface.S3API,
+	tbl *glue.TableData, partitions []*glue.Partition) error {
+
+	batch := glue.BatchDeletePartitionInput{
+		CatalogId:    tbl.CatalogId,
+		DatabaseName: tbl.DatabaseName,
+		TableName:    tbl.Name,
+	}
+	for _, p := range partitions {
+		tm, err := awsglue.PartitionTimeFromValues(p.Values)
+		if err != nil {
+			return err
+		}
+		expired := ExpiredPartition{
+			Time: tm,
+		}
+		if desc := p.StorageDescriptor; desc != nil {
+			expired.Location = aws.StringValue(desc.Location)
+		}
+		if expired.Location != "" {
+			if err := e.deletePartitionData(ctx, s3API, &expired); err != nil {
+				e.Stats.NumFailed++
+				return err
+			}
+		}
+		e.Expired = append(e.Expired, expired)
+		e.Stats.NumPartitions++
+		e.Stats.NumObjects += expired.NumObjects
+		e.Stats.NumBytes += expired.NumBytes
+		batch.PartitionsToDelete = append(batch.PartitionsToDelete, &glue.PartitionValueList{
+			Values: p.Values,
+		})
+	}
+	if e.DryRun || len(batch.PartitionsToDelete) == 0 {
+		return nil
+	}
+	reply, err := glueAPI.BatchDeletePartitionWithContext(ctx, &batch)
+	if err != nil {
+		e.Stats.NumFailed += len(batch.PartitionsToDelete)
+		return errors.Wrapf(err, "failed to delete %d partitions", len(batch.PartitionsToDelete))
+	}
+	for _, pe := range reply.Errors {
+		if pe == nil || pe.ErrorDetail == nil {
+			continue
+		}
+		if aws.StringValue(pe.ErrorDetail.ErrorCode) == glue.ErrCodeEntityNotFoundException {
+			continue
+		}
+		e.Stats.NumFailed++
+		tm, _ := awsglue.PartitionTimeFromValues(pe.PartitionValues)
+		err = multierr.Append(err, errors.Errorf("failed to delete Glue partition at %s: %s",
+			tm, aws.StringValue(pe.ErrorDetail.ErrorMessage)))
+	}
+	return err
+}
+
+func (e *ExpireTablePartitions) deletePartitionData(ctx context.Context, s3API s3iface.S3API, expired *ExpiredPartition) error {
+	bucket, prefix, err := awsglue.ParseS3URL(expired.Location)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to parse S3 path for partition at %s", expired.Time)
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	listInput := s3.ListObjectsV2Input{
+		Bucket: &bucket,
+		Prefix: &prefix,
+	}
+	var deleteErr error
+	err = s3API.ListObjectsV2PagesWithContext(ctx, &listInput, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		// List pages hold up to 1000 keys which is the max number of keys for DeleteObjects
+		input := s3.DeleteObjectsInput{
+			Bucket: &bucket,
+			Delete: &s3.Delete{
+				Quiet: aws.Bool(true),
+			},
+		}
+		for _, obj := range page.Contents {
+			expired.NumObjects++
+			expired.NumBytes += aws.Int64Value(obj.Size)
+			input.Delete.Objects = append(input.Delete.Objects, &s3.ObjectIdentifier{Key: obj.Key})
+		}
+		if e.DryRun || len(input.Delete.Objects) == 0 {
+			return true
+		}
+		reply, err := s3API.DeleteObjectsWithContext(ctx, &input)
+		if err != nil {
+			deleteErr = errors.Wrapf(err, "failed to delete objects in %q", expired.Location)
+			return false
+		}
+		for _, oe := range reply.Errors {
+			deleteErr = multierr.Append(deleteErr, errors.Errorf("failed to delete %q: %s",
+				aws.StringValue(oe.Key), aws.StringValue(oe.Message)))
+		}
+		return deleteErr == nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to list objects in %q", expired.Location)
+	}
+	return deleteErr
+}
@@ -0,0 +1,129 @@
+package gluetasks
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/glue"
+	"github.com/aws/aws-sdk-go/service/glue/glueiface"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+)
+
+func TestExpireTablePartitions(t *testing.T) {
+	s3API := &fakeS3{
+		objects: map[string][]byte{
+			"logs/foo/year=2020/month=01/day=01/hour=00/a.json.gz": []byte("aaaa"),
+			"logs/foo/year=2020/month=01/day=01/hour=00/b.json.gz": []byte("bb"),
+			"logs/foo/year=2020/month=01/day=02/hour=00/c.json.gz": []byte("c"),
+		},
+	}
+	glueAPI := &fakeGlue{
+		table: &glue.TableData{
+			Name:         aws.String("foo"),
+			DatabaseName: aws.String(awsglue.LogProcessingDatabaseName),
+			PartitionKeys: []*glue.Column{
+				{Name: aws.String("year")},
+				{Name: aws.String("month")},
+				{Name: aws.String("day")},
+				{Name: aws.String("hour")},
+			},
+		},
+		partitions: []*glue.Partition{
+			testPartition("2020", "01", "01", "00"),
+		},
+	}
+	task := ExpireTablePartitions{
+		DatabaseName: awsglue.LogProcessingDatabaseName,
+		TableName:    "foo",
+		Before:       time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+		DryRun:       true,
+	}
+	require.NoError(t, task.Run(context.Background(), glueAPI, s3API, nil))
+	require.Equal(t, hourly.PartitionsBefore(task.Before), glueAPI.expr)
+	expired := []ExpiredPartition{
+		{
+			Time:       time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Location:   "s3://bucket/logs/foo/year=2020/month=01/day=01/hour=00/",
+			NumObjects: 2,
+			NumBytes:   6,
+		},
+	}
+	require.Equal(t, expired, task.Expired)
+	require.Equal(t, ExpireStats{NumPartitions: 1, NumObjects: 2, NumBytes: 6}, task.Stats)
+	require.Len(t, s3API.objects, 3)
+	require.Len(t, glueAPI.partitions, 1)
+
+	task.DryRun = false
+	task.Expired, task.Stats = nil, ExpireStats{}
+	require.NoError(t, task.Run(context.Background(), glueAPI, s3API, nil))
+	require.Equal(t, expired, task.Expired)
+	require.Len(t, s3API.objects, 1)
+	require.Empty(t, glueAPI.partitions)
+}
+
+func testPartition(values ...string) *glue.Partition {
+	return &glue.Partition{
+		Values: aws.StringSlice(values),
+		StorageDescriptor: &glue.StorageDescriptor{
+			Location: aws.String("s3://bucket/logs/foo/year=" + values[0] + "/month=" + values[1] +
+				"/day=" + values[2] + "/hour=" + values[3] + "/"),
+		},
+	}
+}
+
+type fakeGlue struct {
+	glueiface.GlueAPI
+	table      *glue.TableData
+	partitions []*glue.Partition
+	expr       string
+}
+
+func (f *fakeGlue) GetTableWithContext(_ aws.Context, _ *glue.GetTableInput, _ ...request.Option) (*glue.GetTableOutput, error) {
+	return &glue.GetTableOutput{Table: f.table}, nil
+}
+
+func (f *fakeGlue) GetPartitionsPagesWithContext(_ aws.Context, input *glue.GetPartitionsInput,
+	fn func(*glue.GetPartitionsOutput, bool) bool, _ ...request.Option) error {
+
+	f.expr = aws.StringValue(input.Expression)
+	fn(&glue.GetPartitionsOutput{Partitions: f.partitions}, true)
+	return nil
+}
+
+func (f *fakeGlue) BatchDeletePartitionWithContext(_ aws.Context, input *glue.BatchDeletePartitionInput,
+	_ ...request.Option) (*glue.BatchDeletePartitionOutput, error) {
+
+	for _, values := range input.PartitionsToDelete {
+		for i, p := range f.partitions {
+			if reflect.DeepEqual(p.Values, values.Values) {
+				f.partitions = append(f.partitions[:i], f.partitions[i+1:]...)
+				break
+			}
+		}
+	}
+	return &glue.BatchDeletePartitionOutput{}, nil
+}
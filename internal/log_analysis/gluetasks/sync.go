@@ -191,6 +191,15 @@ func (s *SyncTablePartitions) syncTable(ctx context.Context, api glueiface.GlueA
 					continue
 				}
 				s.Stats.NumDiff++
+				if safe, err := isSafeToSync(tbl, p); !safe {
+					s.Stats.NumUnsafe++
+					log.Warn("skipping partition update",
+						zap.String("reason", "incompatibleSchema"),
+						zap.String("partition", tm.Format(time.RFC3339)),
+						zap.Error(err),
+					)
+					continue
+				}
 				if s.DryRun {
 					log.Debug("skipping partition update", zap.String("reason", "dryRun"), zap.String("partition", tm.Format(time.RFC3339)))
 					continue
@@ -244,6 +253,20 @@ func isSynced(tbl *glue.TableData, p *glue.Partition) bool {
 	return reflect.DeepEqual(want, have)
 }
 
+// isSafeToSync checks that data stored in a partition can be read using the table schema
+func isSafeToSync(tbl *glue.TableData, p *glue.Partition) (bool, error) {
+	changes, err := awsglue.DiffColumns(p.StorageDescriptor.Columns, tbl.StorageDescriptor.Columns)
+	if err != nil {
+		return false, err
+	}
+	for _, change := range changes {
+		if change.Kind == awsglue.ColumnIncompatible {
+			return false, errors.New(change.String())
+		}
+	}
+	return true, nil
+}
+
 type partitionUpdate struct {
 	Partition *glue.Partition
 	Table     *glue.TableData
@@ -314,6 +337,7 @@ type SyncStats struct {
 	NumPartitions    int
 	NumDiff          int
 	NumSynced        int
+	NumUnsafe        int
 	MinTime, MaxTime time.Time
 }
 
@@ -323,6 +347,7 @@ func (s *SyncStats) merge(other *SyncStats) {
 	s.NumPartitions += other.NumPartitions
 	s.NumPartitions += other.NumPartitions
 	s.NumDiff += other.NumDiff
+	s.NumUnsafe += other.NumUnsafe
 	s.observeMinTime(other.MinTime)
 	s.observeMaxTime(other.MaxTime)
 }
@@ -49,6 +49,15 @@ type ClassifierResult struct {
 	Matched bool
 	// NumMiss counts the number for failed classification attempts
 	NumMiss int
+	// ParserErrors contains the errors of all parsers that failed to parse the log entry
+	// It is only set if the classifier did not match the log entry
+	ParserErrors []ParserError
+}
+
+// ParserError is the error of a parser that failed to parse a log entry
+type ParserError struct {
+	LogType string
+	Err     error
 }
 
 // NewClassifier returns a new instance of a ClassifierAPI implementation
@@ -139,10 +148,15 @@ func (c *Classifier) Classify(log string) (*ClassifierResult, error) {
 			currentItem.penalty++
 			// Increment the number of misses in the result
 			result.NumMiss++
+			result.ParserErrors = append(result.ParserErrors, ParserError{
+				LogType: logType,
+				Err:     err,
+			})
 			// record failure
 			continue
 		}
 		result.Matched = true
+		result.ParserErrors = nil
 
 		// Since the parsing was successful, remove all penalty from the parser
 		// The parser will be higher priority in the queue
@@ -187,6 +201,7 @@ type ClassifierStats struct {
 	EventCount                  uint64 // output records
 	SuccessfullyClassifiedCount uint64
 	ClassificationFailureCount  uint64
+	DuplicateEventCount         uint64 // events dropped as duplicates
 }
 
 func (s *ClassifierStats) Add(other *ClassifierStats) {
@@ -196,6 +211,7 @@ func (s *ClassifierStats) Add(other *ClassifierStats) {
 	s.SuccessfullyClassifiedCount += other.EventCount
 	s.LogLineCount += other.LogLineCount
 	s.ClassificationFailureCount += other.ClassificationFailureCount
+	s.DuplicateEventCount += other.DuplicateEventCount
 }
 
 // per parser stats
@@ -205,6 +221,7 @@ type ParserStats struct {
 	LogLineCount           uint64 // input records
 	EventCount             uint64 // output records
 	CombinedLatency        uint64 // sum of latency of events
+	DuplicateEventCount    uint64 // events dropped as duplicates
 	LogType                string
 }
 
@@ -214,6 +231,7 @@ func (s *ParserStats) Add(other *ParserStats) {
 	s.EventCount += other.EventCount
 	s.LogLineCount += other.LogLineCount
 	s.CombinedLatency += other.CombinedLatency
+	s.DuplicateEventCount += other.DuplicateEventCount
 }
 
 func MergeParserStats(dst map[string]*ParserStats, src map[string]*ParserStats) {
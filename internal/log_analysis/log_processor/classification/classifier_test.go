@@ -135,7 +135,10 @@ func TestClassifyNoMatch(t *testing.T) {
 	expectedStats.ClassifyTimeMicroseconds = classifier.Stats().ClassifyTimeMicroseconds
 	require.Equal(t, expectedStats, classifier.Stats())
 
-	require.Equal(t, &ClassifierResult{NumMiss: 1}, result)
+	require.Len(t, result.ParserErrors, 1)
+	require.Equal(t, "failure", result.ParserErrors[0].LogType)
+	require.EqualError(t, result.ParserErrors[0].Err, "fail")
+	require.Equal(t, &ClassifierResult{NumMiss: 1, ParserErrors: result.ParserErrors}, result)
 	failingParser.AssertNumberOfCalls(t, "Parse", 1)
 	require.Nil(t, classifier.ParserStats()["failure"])
 }
@@ -172,7 +175,9 @@ func TestClassifyParserPanic(t *testing.T) {
 	expectedStats.ClassifyTimeMicroseconds = classifier.Stats().ClassifyTimeMicroseconds
 	require.Equal(t, expectedStats, classifier.Stats())
 
-	require.Equal(t, &ClassifierResult{NumMiss: 1}, result)
+	require.Len(t, result.ParserErrors, 1)
+	require.EqualError(t, result.ParserErrors[0].Err, `parser "panic" panic: test parser panic`)
+	require.Equal(t, &ClassifierResult{NumMiss: 1, ParserErrors: result.ParserErrors}, result)
 	panicParser.AssertNumberOfCalls(t, "Parse", 1)
 }
 
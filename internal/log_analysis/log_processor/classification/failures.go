@@ -0,0 +1,101 @@
+package classification
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/panther-labs/panther/api/lambda/core/log_analysis/log_processor/models"
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// FailureLogType is the log type of results holding log entries that could not be classified.
+// It is not a registered log type, results with this log type are stored in the classification failures database.
+const FailureLogType = "Classification.Failures"
+
+// Failure is a log entry that could not be classified by any parser.
+// It holds enough information to locate the original data and replay it once the parsers are fixed.
+type Failure struct {
+	SourceID    string         `json:"sourceId" description:"The id of the source the log entry came from"`
+	SourceLabel string         `json:"sourceLabel" description:"The label of the source the log entry came from"`
+	S3Bucket    string         `json:"s3Bucket,omitempty" description:"The S3 bucket of the object the log entry was read from"`
+	S3ObjectKey string         `json:"s3ObjectKey,omitempty" description:"The key of the S3 object the log entry was read from"`
+	LineNumber  uint64         `json:"lineNumber" description:"The line number of the log entry in the input"`
+	Line        string         `json:"line" description:"The raw log entry, omitted for sources with field transforms"`
+	Errors      []FailureError `json:"errors" description:"The errors of each parser that tried to parse the log entry"`
+}
+
+// FailureError is the error of a parser that failed to parse a log entry
+type FailureError struct {
+	LogType string `json:"logType" description:"The log type of the parser"`
+	Error   string `json:"error" description:"The parser error message"`
+}
+
+// NewFailureErrors converts parser errors to failure errors
+func NewFailureErrors(parserErrors []ParserError) []FailureError {
+	errs := make([]FailureError, len(parserErrors))
+	for i, parserErr := range parserErrors {
+		errs[i] = FailureError{
+			LogType: parserErr.LogType,
+		}
+		if parserErr.Err != nil {
+			errs[i].Error = parserErr.Err.Error()
+		}
+	}
+	return errs
+}
+
+// QuarantineResult builds a failure result for an event that was quarantined by the lateness policy of its log type.
+// The failure holds the JSON of the event as its log entry so it can be inspected and replayed.
+func QuarantineResult(result *pantherlog.Result, eventJSON []byte) *pantherlog.Result {
+	return &pantherlog.Result{
+		CoreFields: pantherlog.CoreFields{
+			PantherLogType:     FailureLogType,
+			PantherRowID:       result.PantherRowID,
+			PantherParseTime:   result.PantherParseTime,
+			PantherSourceID:    result.PantherSourceID,
+			PantherSourceLabel: result.PantherSourceLabel,
+		},
+		EnvelopeFields: result.EnvelopeFields,
+		Event: &Failure{
+			SourceID:    result.PantherSourceID,
+			SourceLabel: result.PantherSourceLabel,
+			Line:        string(eventJSON),
+			Errors: []FailureError{
+				{
+					LogType: result.PantherLogType,
+					Error: fmt.Sprintf("event time %s is ahead of parse time %s",
+						result.PantherEventTime.Format(time.RFC3339Nano), result.PantherParseTime.Format(time.RFC3339Nano)),
+				},
+			},
+		},
+	}
+}
+
+// FailuresTable returns the metadata of the Glue table holding classification failures
+func FailuresTable() *awsglue.GlueTableMetadata {
+	schema, err := pantherlog.BuildEventSchema(&Failure{})
+	if err != nil {
+		panic(err)
+	}
+	const description = "Log entries that could not be classified by any parser"
+	return awsglue.NewGlueTableMetadata(models.ClassificationFailures, FailureLogType, description, awsglue.GlueTableHourly, schema)
+}
@@ -0,0 +1,55 @@
+package classification
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+)
+
+func TestFailuresTable(t *testing.T) {
+	table := FailuresTable()
+	require.Equal(t, awsglue.ClassificationFailuresDatabaseName, table.DatabaseName())
+	require.Equal(t, "classification_failures", table.TableName())
+	require.Equal(t, "classification_failures/classification_failures/", table.Prefix())
+
+	columns := map[string]string{}
+	cols, _ := awsglue.InferJSONColumns(table.EventStruct(), awsglue.GlueMappings...)
+	for _, col := range cols {
+		columns[col.Name] = col.Type
+	}
+	require.Equal(t, "string", columns["line"])
+	require.Equal(t, "bigint", columns["lineNumber"])
+	require.Equal(t, "array<struct<logType:string,error:string>>", columns["errors"])
+	require.Equal(t, "timestamp", columns["p_parse_time"])
+}
+
+func TestNewFailureErrors(t *testing.T) {
+	require.Equal(t, []FailureError{
+		{LogType: "Foo", Error: "foo"},
+		{LogType: "Bar"},
+	}, NewFailureErrors([]ParserError{
+		{LogType: "Foo", Err: errors.New("foo")},
+		{LogType: "Bar"},
+	}))
+}
@@ -62,6 +62,14 @@ type EnvConfig struct {
 	SqsQueueURL                 string `required:"true" split_words:"true"`
 	SqsDelaySec                 int64  `required:"true" split_words:"true"`
 	SnsTopicARN                 string `required:"true" split_words:"true"`
+	// Directory with GeoIP databases and threat intel lists, relative paths are resolved from the Lambda task root
+	EnrichmentDataPath string `default:"enrichment" split_words:"true"`
+	// Table storing the fingerprints of processed events
+	DedupTableName string `split_words:"true"`
+	// How long to drop duplicate events for, 0 disables event deduplication
+	DedupWindowMinutes int `split_words:"true"`
+	// Log types with event deduplication enabled
+	DedupLogTypes []string `split_words:"true"`
 }
 
 func Setup() {
@@ -40,5 +40,9 @@ var (
 			Name: "CombinedLatency",
 			Unit: metrics.UnitMilliseconds,
 		},
+		{
+			Name: "DuplicateEvents",
+			Unit: metrics.UnitCount,
+		},
 	})
 )
@@ -0,0 +1,235 @@
+package customlogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/tcodec"
+)
+
+var (
+	typString     = reflect.TypeOf(pantherlog.String{})
+	typBool       = reflect.TypeOf(pantherlog.Bool{})
+	typInt32      = reflect.TypeOf(pantherlog.Int32{})
+	typInt16      = reflect.TypeOf(pantherlog.Int16{})
+	typInt64      = reflect.TypeOf(pantherlog.Int64{})
+	typFloat64    = reflect.TypeOf(pantherlog.Float64{})
+	typTime       = reflect.TypeOf(pantherlog.Time{})
+	typRawMessage = reflect.TypeOf(pantherlog.RawMessage{})
+
+	// Field names must be valid column names in Glue that can be used in queries without quoting
+	validFieldName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Compile compiles the fields of a spec to a struct type that can be used with pantherlog.
+func Compile(fields []FieldSchema) (reflect.Type, error) {
+	return compileObject(fields, "")
+}
+
+func compileObject(fields []FieldSchema, path string) (reflect.Type, error) {
+	if len(fields) == 0 {
+		return nil, errors.Errorf("object %q has no fields", path)
+	}
+	structFields := make([]reflect.StructField, 0, len(fields))
+	names := make(map[string]bool, len(fields))
+	goNames := make(map[string]bool, len(fields))
+	for i := range fields {
+		field := &fields[i]
+		fieldPath := joinPath(path, field.Name)
+		if !validFieldName.MatchString(field.Name) {
+			return nil, errors.Errorf("invalid field name %q", fieldPath)
+		}
+		if strings.HasPrefix(field.Name, pantherlog.FieldPrefixJSON) {
+			return nil, errors.Errorf("field name %q uses reserved prefix %q", fieldPath, pantherlog.FieldPrefixJSON)
+		}
+		if names[field.Name] {
+			return nil, errors.Errorf("duplicate field %q", fieldPath)
+		}
+		names[field.Name] = true
+		typ, err := compileValue(&field.ValueSchema, fieldPath)
+		if err != nil {
+			return nil, err
+		}
+		tag, err := buildTag(field)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid field %q", fieldPath)
+		}
+		structFields = append(structFields, reflect.StructField{
+			Name: goFieldName(field.Name, goNames),
+			Type: typ,
+			Tag:  tag,
+		})
+	}
+	return reflect.StructOf(structFields), nil
+}
+
+func compileValue(value *ValueSchema, path string) (reflect.Type, error) {
+	if len(value.Indicators) > 0 && value.Type != TypeString {
+		return nil, errors.Errorf("indicators are only allowed on string values (%q)", path)
+	}
+	if value.IsEventTime && value.Type != TypeTimestamp {
+		return nil, errors.Errorf("only timestamp values can be used as event time (%q)", path)
+	}
+	switch value.Type {
+	case TypeObject:
+		typ, err := compileObject(value.Fields, path)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.PtrTo(typ), nil
+	case TypeArray:
+		if value.Element == nil {
+			return nil, errors.Errorf("array %q has no element type", path)
+		}
+		typ, err := compileElement(value.Element, path+"[]")
+		if err != nil {
+			return nil, err
+		}
+		return reflect.SliceOf(typ), nil
+	case TypeTimestamp:
+		if value.TimeFormat == "" {
+			return nil, errors.Errorf("timestamp %q has no time format", path)
+		}
+		return typTime, nil
+	case TypeString:
+		for _, name := range value.Indicators {
+			if scanner, _ := pantherlog.LookupScanner(name); scanner == nil {
+				return nil, errors.Errorf("unknown indicator %q for field %q", name, path)
+			}
+		}
+		return typString, nil
+	case TypeBoolean:
+		return typBool, nil
+	case TypeInt:
+		return typInt32, nil
+	case TypeSmallInt:
+		return typInt16, nil
+	case TypeBigInt:
+		return typInt64, nil
+	case TypeFloat:
+		return typFloat64, nil
+	case TypeJSON:
+		return reflect.PtrTo(typRawMessage), nil
+	default:
+		return nil, errors.Errorf("invalid type %q for field %q", value.Type, path)
+	}
+}
+
+// compileElement compiles the type of array elements.
+// Struct tags cannot be applied to array elements so we use plain Go types for scalar values.
+func compileElement(value *ValueSchema, path string) (reflect.Type, error) {
+	if len(value.Indicators) > 0 {
+		return nil, errors.Errorf("indicators are not supported on array elements (%q)", path)
+	}
+	if value.IsEventTime {
+		return nil, errors.Errorf("array elements cannot be used as event time (%q)", path)
+	}
+	switch value.Type {
+	case TypeObject, TypeArray, TypeJSON:
+		return compileValue(value, path)
+	case TypeString:
+		return reflect.TypeOf(""), nil
+	case TypeBoolean:
+		return reflect.TypeOf(false), nil
+	case TypeInt:
+		return reflect.TypeOf(int32(0)), nil
+	case TypeSmallInt:
+		return reflect.TypeOf(int16(0)), nil
+	case TypeBigInt:
+		return reflect.TypeOf(int64(0)), nil
+	case TypeFloat:
+		return reflect.TypeOf(float64(0)), nil
+	default:
+		return nil, errors.Errorf("invalid array element type %q for field %q", value.Type, path)
+	}
+}
+
+func buildTag(field *FieldSchema) (reflect.StructTag, error) {
+	tags := []string{
+		tag("json", field.Name+",omitempty"),
+	}
+	if field.Required {
+		tags = append(tags, tag("validate", "required"))
+	}
+	if field.Type == TypeTimestamp {
+		codec, err := timeCodecTag(field.TimeFormat)
+		if err != nil {
+			return "", err
+		}
+		tags = append(tags, tag(tcodec.DefaultTagName, codec))
+		if field.IsEventTime {
+			tags = append(tags, tag(pantherlog.TagNameEventTime, "true"))
+		}
+	}
+	if len(field.Indicators) > 0 {
+		tags = append(tags, tag(pantherlog.TagNameIndicator, strings.Join(field.Indicators, ",")))
+	}
+	description := field.Description
+	if description == "" {
+		description = field.Name
+	}
+	tags = append(tags, tag("description", description))
+	return reflect.StructTag(strings.Join(tags, " ")), nil
+}
+
+func timeCodecTag(format string) (string, error) {
+	if tcodec.Lookup(format) != nil {
+		return format, nil
+	}
+	if strings.Contains(format, "%") {
+		return "strftime=" + format, nil
+	}
+	return "", errors.Errorf("invalid time format %q", format)
+}
+
+func tag(key, value string) string {
+	return key + ":" + strconv.Quote(value)
+}
+
+// goFieldName derives a unique exported Go field name from a JSON field name
+func goFieldName(name string, seen map[string]bool) string {
+	fieldName := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, name)
+	fieldName = "Field_" + fieldName
+	unique := fieldName
+	for i := 1; seen[unique]; i++ {
+		unique = fieldName + strconv.Itoa(i)
+	}
+	seen[unique] = true
+	return unique
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
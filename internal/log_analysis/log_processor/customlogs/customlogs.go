@@ -0,0 +1,90 @@
+package customlogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+// LogTypePrefix is the required prefix for all user-defined log types.
+// It ensures user-defined log types never clash with native log types.
+const LogTypePrefix = "Custom."
+
+// Build compiles a user-defined log type spec to a log type config.
+func Build(spec *Spec) (*logtypes.Config, error) {
+	if spec == nil {
+		return nil, errors.New("nil log type spec")
+	}
+	if !strings.HasPrefix(spec.Name, LogTypePrefix) {
+		return nil, errors.Errorf("user-defined log type %q must have a %q prefix", spec.Name, LogTypePrefix)
+	}
+	desc := logtypes.Desc{
+		Name:         spec.Name,
+		Description:  spec.Description,
+		ReferenceURL: spec.ReferenceURL,
+	}
+	desc.Fill()
+	if err := desc.Validate(); err != nil {
+		return nil, err
+	}
+
+	eventType, err := Compile(spec.Fields)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to compile log type %q", spec.Name)
+	}
+	newEvent := func() interface{} {
+		return reflect.New(eventType).Interface()
+	}
+	schema, err := pantherlog.BuildEventSchema(newEvent())
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to build schema for log type %q", spec.Name)
+	}
+	return &logtypes.Config{
+		Name:         desc.Name,
+		Description:  desc.Description,
+		ReferenceURL: desc.ReferenceURL,
+		Schema:       schema,
+		NewParser: &parsers.JSONParserFactory{
+			LogType:  desc.Name,
+			NewEvent: newEvent,
+		},
+	}, nil
+}
+
+// Register compiles and registers user-defined log types to a registry.
+// It returns the first error it encounters.
+func Register(r *logtypes.Registry, specs ...*Spec) error {
+	for _, spec := range specs {
+		config, err := Build(spec)
+		if err != nil {
+			return err
+		}
+		if _, err := r.Register(config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package customlogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
+)
+
+const testSpecYAML = `
+name: Custom.MyApp
+description: MyApp access logs
+referenceURL: https://example.com/myapp
+fields:
+  - name: time
+    type: timestamp
+    timeFormat: rfc3339
+    isEventTime: true
+    required: true
+  - name: remote_ip
+    description: The client address
+    type: string
+    indicators: [ip]
+  - name: status
+    type: smallint
+  - name: request
+    type: object
+    fields:
+      - name: url
+        type: string
+        indicators: [url]
+      - name: headers
+        type: json
+  - name: tags
+    type: array
+    element:
+      type: string
+`
+
+func TestBuild(t *testing.T) {
+	assert := require.New(t)
+	spec, err := ParseSpec([]byte(testSpecYAML))
+	assert.NoError(err)
+	r := &logtypes.Registry{}
+	assert.NoError(Register(r, spec))
+
+	entry := r.Get("Custom.MyApp")
+	assert.NotNil(entry)
+	assert.Equal(logtypes.Desc{
+		Name:         "Custom.MyApp",
+		Description:  "MyApp access logs",
+		ReferenceURL: "https://example.com/myapp",
+	}, entry.Describe())
+
+	columns := map[string]string{}
+	cols, _ := awsglue.InferJSONColumns(entry.GlueTableMeta().EventStruct(), awsglue.GlueMappings...)
+	for _, col := range cols {
+		columns[col.Name] = col.Type
+	}
+	assert.Equal("timestamp", columns["time"])
+	assert.Equal("string", columns["remote_ip"])
+	assert.Equal("smallint", columns["status"])
+	assert.Equal("struct<url:string,headers:string>", columns["request"])
+	assert.Equal("array<string>", columns["tags"])
+	assert.Equal("array<string>", columns["p_any_ip_addresses"])
+	assert.Equal("array<string>", columns["p_any_domain_names"])
+	assert.Equal("array<string>", columns["p_any_urls"])
+
+	input := `{"time":"2020-10-01T12:00:00Z","remote_ip":"192.168.1.1","status":200,"request":{"url":"https://example.com/foo","headers":{"foo":"bar"}},"tags":["a","b"]}`
+	expect := `{
+		"time":"2020-10-01T12:00:00Z",
+		"remote_ip":"192.168.1.1",
+		"status":200,
+		"request":{"url":"https://example.com/foo","headers":{"foo":"bar"}},
+		"tags":["a","b"],
+		"p_log_type":"Custom.MyApp",
+		"p_event_time":"2020-10-01T12:00:00Z",
+		"p_any_ip_addresses":["192.168.1.1"],
+		"p_any_domain_names":["example.com"],
+		"p_any_urls":["https://example.com/foo"]
+	}`
+	logtesting.TestRegisteredParser(t, r, "Custom.MyApp", input, expect)
+
+	// Required fields are validated
+	parser, err := entry.NewParser(nil)
+	assert.NoError(err)
+	_, err = parser.ParseLog(`{"remote_ip":"192.168.1.1"}`)
+	assert.Error(err)
+}
+
+func TestBuildErrors(t *testing.T) {
+	for name, spec := range map[string]string{
+		"no prefix":          "name: MyApp\nfields: [{name: foo, type: string}]",
+		"no fields":          "name: Custom.MyApp\nfields: []",
+		"invalid type":       "name: Custom.MyApp\nfields: [{name: foo, type: uuid}]",
+		"duplicate field":    "name: Custom.MyApp\nfields: [{name: foo, type: string}, {name: foo, type: int}]",
+		"reserved prefix":    "name: Custom.MyApp\nfields: [{name: p_foo, type: string}]",
+		"dotted field name":  "name: Custom.MyApp\nfields: [{name: foo.bar, type: string}]",
+		"dashed field name":  "name: Custom.MyApp\nfields: [{name: foo-bar, type: string}]",
+		"digit field name":   "name: Custom.MyApp\nfields: [{name: 1foo, type: string}]",
+		"unknown indicator":  "name: Custom.MyApp\nfields: [{name: foo, type: string, indicators: [foo]}]",
+		"indicator type":     "name: Custom.MyApp\nfields: [{name: foo, type: int, indicators: [ip]}]",
+		"no time format":     "name: Custom.MyApp\nfields: [{name: foo, type: timestamp}]",
+		"invalid event time": "name: Custom.MyApp\nfields: [{name: foo, type: string, isEventTime: true}]",
+		"no element":         "name: Custom.MyApp\nfields: [{name: foo, type: array}]",
+		"timestamp element":  "name: Custom.MyApp\nfields: [{name: foo, type: array, element: {type: timestamp, timeFormat: unix}}]",
+	} {
+		spec := spec
+		t.Run(name, func(t *testing.T) {
+			s, err := ParseSpec([]byte(spec))
+			require.NoError(t, err)
+			_, err = Build(s)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseSpecJSON(t *testing.T) {
+	spec, err := ParseSpec([]byte(`{"name":"Custom.Foo","fields":[{"name":"ts","type":"timestamp","timeFormat":"%Y-%m-%d %H:%M:%S","isEventTime":true}]}`))
+	require.NoError(t, err)
+	require.Equal(t, &Spec{
+		Name: "Custom.Foo",
+		Fields: []FieldSchema{
+			{
+				Name: "ts",
+				ValueSchema: ValueSchema{
+					Type:        TypeTimestamp,
+					TimeFormat:  "%Y-%m-%d %H:%M:%S",
+					IsEventTime: true,
+				},
+			},
+		},
+	}, spec)
+	_, err = Build(spec)
+	require.NoError(t, err)
+
+	_, err = ParseSpec([]byte(`{"name":"Custom.Foo","unknown":true}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package customlogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Spec describes a user-defined log type.
+//
+// Specs are written in YAML or JSON:
+// ```
+// name: Custom.MyApp
+// description: Access logs of MyApp
+// referenceURL: https://example.com/myapp/logs
+// fields:
+//   - name: time
+//     type: timestamp
+//     timeFormat: rfc3339
+//     isEventTime: true
+//     required: true
+//   - name: remote_ip
+//     type: string
+//     indicators: [ip]
+//   - name: request
+//     type: object
+//     fields:
+//       - name: host
+//         type: string
+//         indicators: [domain]
+// ```
+type Spec struct {
+	Name         string        `json:"name" yaml:"name"`
+	Description  string        `json:"description,omitempty" yaml:"description,omitempty"`
+	ReferenceURL string        `json:"referenceURL,omitempty" yaml:"referenceURL,omitempty"`
+	Fields       []FieldSchema `json:"fields" yaml:"fields"`
+}
+
+// FieldSchema describes a field of an object value
+type FieldSchema struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool   `json:"required,omitempty" yaml:"required,omitempty"`
+	ValueSchema `yaml:",inline"`
+}
+
+// ValueSchema describes the type of a value
+type ValueSchema struct {
+	Type ValueType `json:"type" yaml:"type"`
+	// Fields of an object value
+	Fields []FieldSchema `json:"fields,omitempty" yaml:"fields,omitempty"`
+	// Element type of an array value
+	Element *ValueSchema `json:"element,omitempty" yaml:"element,omitempty"`
+	// Indicator scanners to use on string values (ie `ip`, `domain`, `url`)
+	Indicators []string `json:"indicators,omitempty" yaml:"indicators,omitempty"`
+	// The time format of a timestamp value.
+	// It can be the name of a registered time codec (ie `rfc3339`, `unix`, `unix_ms`) or a strftime format.
+	TimeFormat string `json:"timeFormat,omitempty" yaml:"timeFormat,omitempty"`
+	// Use the value of this timestamp as the event time
+	IsEventTime bool `json:"isEventTime,omitempty" yaml:"isEventTime,omitempty"`
+}
+
+// ValueType is the type of a value in a schema
+type ValueType string
+
+// Value types supported in a schema
+const (
+	TypeObject    ValueType = "object"
+	TypeArray     ValueType = "array"
+	TypeTimestamp ValueType = "timestamp"
+	TypeString    ValueType = "string"
+	TypeBoolean   ValueType = "boolean"
+	TypeInt       ValueType = "int"
+	TypeSmallInt  ValueType = "smallint"
+	TypeBigInt    ValueType = "bigint"
+	TypeFloat     ValueType = "float"
+	TypeJSON      ValueType = "json"
+)
+
+// ParseSpec parses a YAML or JSON document describing a user-defined log type.
+func ParseSpec(data []byte) (*Spec, error) {
+	spec := Spec{}
+	// YAML is a superset of JSON so we can use a single decoder for both formats
+	if err := yaml.UnmarshalStrict(data, &spec); err != nil {
+		return nil, errors.Wrap(err, "invalid log type spec")
+	}
+	return &spec, nil
+}
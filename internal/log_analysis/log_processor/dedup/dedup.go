@@ -0,0 +1,92 @@
+// Package dedup detects duplicate log events delivered more than once to the log processor.
+package dedup
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Store records event fingerprints for a time window.
+type Store interface {
+	// Seen returns the fingerprints that were recorded within the window.
+	Seen(fingerprints []string) (seen map[string]bool, err error)
+	// Record stores the fingerprints for the duration of the window.
+	// It should only be called once the events have been delivered, so that retries do not drop events.
+	Record(fingerprints []string) error
+}
+
+// DefaultKeys are the JSON paths of the fields that uniquely identify the events of a log type.
+var DefaultKeys = map[string]string{
+	"AWS.CloudTrail":        "eventID",
+	"AWS.CloudTrailInsight": "eventID",
+	"AWS.GuardDuty":         "id",
+}
+
+// Fingerprinter computes the fingerprints used to detect duplicate events.
+type Fingerprinter struct {
+	// Keys are the dot-separated JSON paths of the unique id field for each log type.
+	// Events of log types without a key are identified by a hash of their source, log line and position in the line.
+	Keys map[string]string
+}
+
+// Fingerprint returns the fingerprint of the event at index of the events parsed from a log line.
+func (f *Fingerprinter) Fingerprint(logType, sourceID, line string, index, numEvents int) string {
+	h := sha256.New()
+	h.Write([]byte(logType))
+	h.Write([]byte{0})
+	// Keys can only be used if a single event was parsed from the line.
+	if key, ok := f.Keys[logType]; ok && numEvents == 1 {
+		if id := lookupKey(line, key); id != "" {
+			// Events with an id are the same across all sources (ie a trail delivering to multiple buckets)
+			h.Write([]byte(id))
+			return fingerprint(h.Sum(nil))
+		}
+	}
+	h.Write([]byte(sourceID))
+	h.Write([]byte{0})
+	h.Write([]byte(strings.TrimSpace(line)))
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(index))
+	h.Write(buf[:])
+	return fingerprint(h.Sum(nil))
+}
+
+func lookupKey(line, key string) string {
+	path := strings.Split(key, ".")
+	keys := make([]interface{}, len(path))
+	for i, p := range path {
+		keys[i] = p
+	}
+	any := jsoniter.Get([]byte(line), keys...)
+	if any.ValueType() != jsoniter.StringValue {
+		return ""
+	}
+	return any.ToString()
+}
+
+// fingerprint uses the first 128 bits of the hash to reduce storage
+func fingerprint(sum []byte) string {
+	return hex.EncodeToString(sum[:16])
+}
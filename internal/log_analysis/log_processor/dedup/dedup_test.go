@@ -0,0 +1,150 @@
+package dedup
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	f := Fingerprinter{Keys: DefaultKeys}
+	line := `{"eventID":"abc","eventName":"GetObject"}`
+	fp := f.Fingerprint("AWS.CloudTrail", "src1", line, 0, 1)
+	assert.Len(t, fp, 32)
+	// Events with an id have the same fingerprint regardless of source and formatting
+	assert.Equal(t, fp, f.Fingerprint("AWS.CloudTrail", "src2", `{"eventName":"GetObject", "eventID":"abc"}`, 0, 1))
+	assert.NotEqual(t, fp, f.Fingerprint("AWS.CloudTrail", "src1", `{"eventID":"abd"}`, 0, 1))
+	assert.NotEqual(t, fp, f.Fingerprint("AWS.CloudTrailInsight", "src1", line, 0, 1))
+
+	// Lines with multiple events use the line hash
+	assert.NotEqual(t, f.Fingerprint("AWS.CloudTrail", "src1", line, 0, 2), f.Fingerprint("AWS.CloudTrail", "src1", line, 1, 2))
+	// Events without a key are scoped to the source
+	assert.Equal(t, f.Fingerprint("Test.Log", "src1", "foo\n", 0, 1), f.Fingerprint("Test.Log", "src1", "foo", 0, 1))
+	assert.NotEqual(t, f.Fingerprint("Test.Log", "src1", "foo", 0, 1), f.Fingerprint("Test.Log", "src2", "foo", 0, 1))
+	// Missing id falls back to the line hash
+	assert.NotEqual(t, f.Fingerprint("AWS.CloudTrail", "src1", `{"eventID":1}`, 0, 1), f.Fingerprint("AWS.CloudTrail", "src1", `{"eventID":2}`, 0, 1))
+}
+
+type mockDynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+	mu         sync.Mutex
+	items      map[string]int64
+	getCalls   int
+	writeCalls int
+	// number of calls that leave the first key unprocessed
+	unprocessedCalls int
+}
+
+func (m *mockDynamoDB) BatchGetItem(input *dynamodb.BatchGetItemInput) (*dynamodb.BatchGetItemOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.getCalls++
+	output := &dynamodb.BatchGetItemOutput{
+		Responses: map[string][]map[string]*dynamodb.AttributeValue{},
+	}
+	for table, request := range input.RequestItems {
+		keys := request.Keys
+		if m.unprocessedCalls > 0 {
+			m.unprocessedCalls--
+			output.UnprocessedKeys = map[string]*dynamodb.KeysAndAttributes{
+				table: {Keys: keys[:1]},
+			}
+			keys = keys[1:]
+		}
+		for _, key := range keys {
+			fp := aws.StringValue(key[AttrFingerprint].S)
+			if expiresAt, ok := m.items[fp]; ok {
+				output.Responses[table] = append(output.Responses[table], map[string]*dynamodb.AttributeValue{
+					AttrFingerprint: {S: aws.String(fp)},
+					AttrExpiresAt:   {N: aws.String(strconv.FormatInt(expiresAt, 10))},
+				})
+			}
+		}
+	}
+	return output, nil
+}
+
+func (m *mockDynamoDB) BatchWriteItem(input *dynamodb.BatchWriteItemInput) (*dynamodb.BatchWriteItemOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writeCalls++
+	for _, requests := range input.RequestItems {
+		for _, r := range requests {
+			expiresAt, _ := strconv.ParseInt(aws.StringValue(r.PutRequest.Item[AttrExpiresAt].N), 10, 64)
+			m.items[aws.StringValue(r.PutRequest.Item[AttrFingerprint].S)] = expiresAt
+		}
+	}
+	return &dynamodb.BatchWriteItemOutput{}, nil
+}
+
+func TestDynamoDBStore(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := &mockDynamoDB{
+		items: map[string]int64{
+			"expired": now.Unix() - 1,
+		},
+	}
+	store := DynamoDBStore{
+		Client:        client,
+		TableName:     "dedup",
+		Window:        time.Hour,
+		RetryInterval: time.Millisecond,
+		Now:           func() time.Time { return now },
+	}
+
+	var fingerprints []string
+	for i := 0; i < 120; i++ {
+		fingerprints = append(fingerprints, strconv.Itoa(i))
+	}
+	require.NoError(t, store.Record(fingerprints[:60]))
+	assert.Equal(t, 3, client.writeCalls)
+	assert.Equal(t, now.Add(time.Hour).Unix(), client.items["0"])
+
+	seen, err := store.Seen(append(fingerprints, "expired", "0"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, client.getCalls)
+	assert.Len(t, seen, 60)
+	assert.True(t, seen["59"])
+	assert.False(t, seen["60"])
+	assert.False(t, seen["expired"])
+
+	// Unprocessed keys are retried
+	client.unprocessedCalls = 2
+	client.getCalls = 0
+	seen, err = store.Seen(fingerprints[:20])
+	require.NoError(t, err)
+	assert.Equal(t, 3, client.getCalls)
+	assert.Len(t, seen, 20)
+
+	// Keys that are never processed fail
+	client.unprocessedCalls = maxBatchRetries + 1
+	client.getCalls = 0
+	_, err = store.Seen(fingerprints[:20])
+	require.Error(t, err)
+	assert.Equal(t, maxBatchRetries+1, client.getCalls)
+}
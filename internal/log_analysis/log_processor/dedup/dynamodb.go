@@ -0,0 +1,209 @@
+package dedup
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// AttrFingerprint is the hash key of the dedup table
+	AttrFingerprint = "fingerprint"
+	// AttrExpiresAt is the TTL attribute of the dedup table (epoch seconds)
+	AttrExpiresAt = "expiresAt"
+
+	// Max number of keys in a single BatchGetItem request
+	maxBatchGetKeys = 100
+	// Max number of items in a single BatchWriteItem request
+	maxBatchWriteItems = 25
+	// Max number of retries of the unprocessed keys and items of a batch request
+	maxBatchRetries = 5
+	// Default wait before the first retry of a batch request, it grows exponentially on each retry
+	defaultRetryInterval = 100 * time.Millisecond
+	// Max number of BatchWriteItem requests in flight
+	maxConcurrentWrites = 4
+)
+
+// DynamoDBStore stores fingerprints in a DynamoDB table with a TTL.
+//
+// Fingerprints are recorded after the events are delivered so concurrent deliveries of the same events
+// will not be detected as duplicates.
+type DynamoDBStore struct {
+	Client    dynamodbiface.DynamoDBAPI
+	TableName string
+	// How long to keep fingerprints
+	Window time.Duration
+	// Wait before the first retry of unprocessed keys and items, defaults to defaultRetryInterval
+	RetryInterval time.Duration
+	// Override for tests
+	Now func() time.Time
+}
+
+var _ Store = (*DynamoDBStore)(nil)
+
+// Seen implements Store interface
+func (s *DynamoDBStore) Seen(fingerprints []string) (map[string]bool, error) {
+	now := s.now()
+	fingerprints = distinct(fingerprints)
+	seen := make(map[string]bool)
+	for start := 0; start < len(fingerprints); start += maxBatchGetKeys {
+		end := start + maxBatchGetKeys
+		if end > len(fingerprints) {
+			end = len(fingerprints)
+		}
+		if err := s.findSeen(fingerprints[start:end], now, seen); err != nil {
+			return nil, err
+		}
+	}
+	return seen, nil
+}
+
+// Record implements Store interface
+func (s *DynamoDBStore) Record(fingerprints []string) error {
+	fingerprints = distinct(fingerprints)
+	expiresAt := strconv.FormatInt(s.now().Add(s.Window).Unix(), 10)
+	requests := make([]*dynamodb.WriteRequest, len(fingerprints))
+	for i, fp := range fingerprints {
+		requests[i] = &dynamodb.WriteRequest{
+			PutRequest: &dynamodb.PutRequest{
+				Item: map[string]*dynamodb.AttributeValue{
+					AttrFingerprint: {S: aws.String(fp)},
+					AttrExpiresAt:   {N: aws.String(expiresAt)},
+				},
+			},
+		}
+	}
+	var group errgroup.Group
+	inFlight := make(chan struct{}, maxConcurrentWrites)
+	for start := 0; start < len(requests); start += maxBatchWriteItems {
+		end := start + maxBatchWriteItems
+		if end > len(requests) {
+			end = len(requests)
+		}
+		batch := requests[start:end]
+		inFlight <- struct{}{}
+		group.Go(func() error {
+			defer func() { <-inFlight }()
+			return s.write(batch)
+		})
+	}
+	return group.Wait()
+}
+
+func (s *DynamoDBStore) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+// backoff returns the backoff policy for retries of unprocessed keys and items.
+// DynamoDB leaves keys and items unprocessed when the table is throttled, retrying them immediately would fail again.
+func (s *DynamoDBStore) backoff() backoff.BackOff {
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = defaultRetryInterval
+	if s.RetryInterval > 0 {
+		b.InitialInterval = s.RetryInterval
+	}
+	return backoff.WithMaxRetries(b, maxBatchRetries)
+}
+
+func (s *DynamoDBStore) findSeen(fingerprints []string, now time.Time, seen map[string]bool) error {
+	keys := make([]map[string]*dynamodb.AttributeValue, len(fingerprints))
+	for i, fp := range fingerprints {
+		keys[i] = map[string]*dynamodb.AttributeValue{
+			AttrFingerprint: {S: aws.String(fp)},
+		}
+	}
+	request := map[string]*dynamodb.KeysAndAttributes{
+		s.TableName: {
+			Keys:                 keys,
+			ProjectionExpression: aws.String("#fp, #exp"),
+			ExpressionAttributeNames: map[string]*string{
+				"#fp":  aws.String(AttrFingerprint),
+				"#exp": aws.String(AttrExpiresAt),
+			},
+		},
+	}
+	return backoff.Retry(func() error {
+		output, err := s.Client.BatchGetItem(&dynamodb.BatchGetItemInput{
+			RequestItems: request,
+		})
+		if err != nil {
+			// The client already retries throttling and server errors
+			return backoff.Permanent(errors.Wrapf(err, "failed to get fingerprints from %s", s.TableName))
+		}
+		for _, item := range output.Responses[s.TableName] {
+			fp := aws.StringValue(item[AttrFingerprint].S)
+			// Expired items are deleted by DynamoDB with a delay so they need to be filtered out
+			if exp := item[AttrExpiresAt]; exp != nil {
+				expiresAt, _ := strconv.ParseInt(aws.StringValue(exp.N), 10, 64)
+				if expiresAt <= now.Unix() {
+					continue
+				}
+			}
+			seen[fp] = true
+		}
+		request = output.UnprocessedKeys
+		if len(request) > 0 {
+			return errors.Errorf("failed to get all fingerprints from %s", s.TableName)
+		}
+		return nil
+	}, s.backoff())
+}
+
+func (s *DynamoDBStore) write(requests []*dynamodb.WriteRequest) error {
+	request := map[string][]*dynamodb.WriteRequest{
+		s.TableName: requests,
+	}
+	return backoff.Retry(func() error {
+		output, err := s.Client.BatchWriteItem(&dynamodb.BatchWriteItemInput{
+			RequestItems: request,
+		})
+		if err != nil {
+			return backoff.Permanent(errors.Wrapf(err, "failed to write fingerprints to %s", s.TableName))
+		}
+		request = output.UnprocessedItems
+		if len(request) > 0 {
+			return errors.Errorf("failed to write all fingerprints to %s", s.TableName)
+		}
+		return nil
+	}, s.backoff())
+}
+
+func distinct(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := values[:0:0]
+	for _, v := range values {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
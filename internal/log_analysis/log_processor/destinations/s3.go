@@ -19,9 +19,13 @@ package destinations
  */
 
 import (
+	"bufio"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"fmt"
+	"io"
+	"math"
 	"path"
 	"runtime"
 	"sync"
@@ -40,8 +44,11 @@ import (
 	"github.com/panther-labs/panther/api/lambda/core/log_analysis/log_processor/models"
 	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
 	"github.com/panther-labs/panther/internal/log_analysis/datacatalog_updater/process"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/parquet"
 )
 
 const (
@@ -74,11 +81,14 @@ func init() {
 	memUsedAtStartupMB = (int)(memStats.Sys/(bytesPerMB)) + 1
 }
 
-func CreateS3Destination(jsonAPI jsoniter.API) Destination {
+// CreateS3Destination creates a destination that stores events to S3.
+// The resolver is used to look up the storage format of each log type, if it is nil all data are stored as JSON.
+func CreateS3Destination(resolver logtypes.Resolver, jsonAPI jsoniter.API) Destination {
 	if jsonAPI == nil {
 		jsonAPI = jsoniter.ConfigDefault
 	}
 	return &S3Destination{
+		resolver:            resolver,
 		s3Uploader:          common.S3Uploader,
 		snsClient:           common.SnsClient,
 		s3Bucket:            common.Config.ProcessedDataBucket,
@@ -89,25 +99,45 @@ func CreateS3Destination(jsonAPI jsoniter.API) Destination {
 	}
 }
 
+// CreateIdempotentS3Destination creates a destination that stores events to S3 using deterministic object keys.
+// Object keys are derived from objectID so storing the events of the same input again overwrites previous output
+// instead of adding duplicate files to the partitions.
+func CreateIdempotentS3Destination(resolver logtypes.Resolver, s3Uploader s3manageriface.UploaderAPI, snsClient snsiface.SNSAPI,
+	s3Bucket, snsTopicArn, objectID string, maxBufferedMemBytes uint64, jsonAPI jsoniter.API) *S3Destination {
+
+	if jsonAPI == nil {
+		jsonAPI = jsoniter.ConfigDefault
+	}
+	return &S3Destination{
+		resolver:            resolver,
+		s3Uploader:          s3Uploader,
+		snsClient:           snsClient,
+		s3Bucket:            s3Bucket,
+		snsTopicArn:         snsTopicArn,
+		maxBufferedMemBytes: maxBufferedMemBytes,
+		// buffers must not be flushed based on time so that the same input always results in the same files
+		maxDuration: math.MaxInt64,
+		jsonAPI:     jsonAPI,
+		objectID:    objectID,
+		objectSeq:   make(map[string]int),
+	}
+}
+
 // the largest we let total size of compressed output buffers get before calling sendData() to write to S3 in bytes
 // NOTE: this presumes processing 1 file at a time
 func maxS3BufferMemUsageBytes(lambdaSizeMB int) uint64 {
 	const (
 		/*
-					NOTE:
-					  "More specifically CloudTrail will collect logs for 5 mins or until the max file size of 45MB has been reached.
-					  An important thing worth noting is that these logs get compressed before being sent to S3, once the file size
-					  limit is met or the time limit has been exceeded"
-				    Because CT files are "document" JSON and all on 1 line we currently need to read ALL the uncompressed data into memory.
-			        FIXME: we should switch to streaming JSON reader
-					Below we set the lower bound on memory to be 45MB * 4 (because we convert all the records and parse) plus some for overhead
+			NOTE:
+			  "Document" JSON files (ie CloudTrail) that have all records on 1 line are read using a streaming JSON iterator
+			  (see processor.Splitter), so only a single log entry needs to be in memory at any time.
+			  Below we reserve some memory for the largest log entry we expect to process (and its parsed events)
+			  plus some for overhead.
 		*/
-		largestAllInMemFileMB     = 45
-		processingExpansionFactor = 4
-		memoryFootprint           = largestAllInMemFileMB * processingExpansionFactor
-		minimumScratchMemMB       = 5 // how much overhead is needed to process
+		largestLogEntryMB   = 5
+		minimumScratchMemMB = 5 // how much overhead is needed to process
 	)
-	maxBufferUsageMB := lambdaSizeMB - memUsedAtStartupMB - memoryFootprint - minimumScratchMemMB
+	maxBufferUsageMB := lambdaSizeMB - memUsedAtStartupMB - largestLogEntryMB - minimumScratchMemMB
 	if maxBufferUsageMB < 5 {
 		panic(fmt.Sprintf("available memory too small for log processing, increase lambda size from %dMB", lambdaSizeMB))
 	}
@@ -116,7 +146,13 @@ func maxS3BufferMemUsageBytes(lambdaSizeMB int) uint64 {
 }
 
 // S3Destination sends normalized events to S3
+//
+// Events are always stored as gzipped JSON files, these are the files referenced in SNS notifications.
+// For log types also stored as Parquet, the events are written to a Parquet file in the separate Parquet table
+// of the log type (see awsglue.GlueTableMetadata.ParquetTable) before the JSON file is stored.
 type S3Destination struct {
+	// resolver resolves the storage format of log types
+	resolver   logtypes.Resolver
 	s3Uploader s3manageriface.UploaderAPI
 	snsClient  snsiface.SNSAPI
 	// s3Bucket is the s3Bucket where the data will be stored
@@ -128,6 +164,10 @@ type S3Destination struct {
 	maxBufferedMemBytes uint64 // max will hold in buffers before ejection
 	maxDuration         time.Duration
 	jsonAPI             jsoniter.API
+	// objectID is used instead of a random id in object keys if set (see CreateIdempotentS3Destination)
+	objectID string
+	// objectSeq counts the files stored per partition prefix when objectID is set
+	objectSeq map[string]int
 }
 
 // SendEvents stores events in S3.
@@ -235,7 +275,7 @@ func (destination *S3Destination) sendData(buffer *s3EventBuffer, errChan chan e
 			zap.String("key", key))
 	}()
 
-	key = getS3ObjectKey(buffer.logType, buffer.hour)
+	table, err := destination.parquetTable(buffer)
 	if err != nil {
 		errChan <- err
 		return
@@ -249,6 +289,17 @@ func (destination *S3Destination) sendData(buffer *s3EventBuffer, errChan chan e
 
 	contentLength = int64(len(payload)) // for logging above
 
+	partitionPrefix, name := destination.s3ObjectName(buffer)
+	if table != nil {
+		// Store the Parquet file first so the data are available in Athena once the partition is created
+		parquetKey := path.Join(table.GetPartitionPrefix(buffer.hour), name+".parquet")
+		if err := destination.uploadParquet(table, parquetKey, payload); err != nil {
+			errChan <- errors.WithMessagef(err, "failed to store %s events as Parquet", buffer.logType)
+			return
+		}
+	}
+
+	key = path.Join(partitionPrefix, name+".json.gz")
 	if _, err := destination.s3Uploader.Upload(&s3manager.UploadInput{
 		Bucket: &destination.s3Bucket,
 		Key:    &key,
@@ -264,6 +315,70 @@ func (destination *S3Destination) sendData(buffer *s3EventBuffer, errChan chan e
 	}
 }
 
+// parquetTable returns the Parquet table of the buffer log type if it is also stored as Parquet or nil otherwise.
+func (destination *S3Destination) parquetTable(buffer *s3EventBuffer) (*awsglue.GlueTableMetadata, error) {
+	if destination.resolver == nil || buffer.dataType != models.LogData {
+		return nil, nil
+	}
+	entry, err := destination.resolver.Resolve(context.TODO(), buffer.logType)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to resolve log type %q", buffer.logType)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	return entry.GlueTableMeta().ParquetTable(), nil
+}
+
+// uploadParquet converts gzipped JSON lines to a Parquet file and streams it to S3.
+// Only a row group of the Parquet file is held in memory at any time.
+func (destination *S3Destination) uploadParquet(table *awsglue.GlueTableMetadata, key string, payload []byte) error {
+	schema, err := parquet.SchemaFromTable(table)
+	if err != nil {
+		return err
+	}
+	r, w := io.Pipe()
+	go func() {
+		w.CloseWithError(writeParquet(w, schema, payload))
+	}()
+	_, err = destination.s3Uploader.Upload(&s3manager.UploadInput{
+		Bucket: &destination.s3Bucket,
+		Key:    &key,
+		Body:   r,
+	})
+	// Unblock the writer if the upload failed before reading all of the file
+	r.CloseWithError(err)
+	if err != nil {
+		return errors.Wrap(err, "S3Upload")
+	}
+	return nil
+}
+
+// writeParquet writes gzipped JSON lines to out as a Parquet file
+func writeParquet(out io.Writer, schema *parquet.Schema, payload []byte) error {
+	gz, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	w := parquet.NewWriter(out, schema)
+	r := bufio.NewReader(gz)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if err := w.WriteJSON(line); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
 func (destination *S3Destination) sendSNSNotification(key string, buffer *s3EventBuffer) error {
 	var err error
 	operation := common.OpLogManager.Start("sendSNSNotification", common.OpLogSNSServiceDim)
@@ -286,7 +401,7 @@ func (destination *S3Destination) sendSNSNotification(key string, buffer *s3Even
 		Message:  aws.String(marshalledNotification),
 		MessageAttributes: map[string]*sns.MessageAttributeValue{
 			logDataTypeAttributeName: {
-				StringValue: aws.String(models.LogData.String()),
+				StringValue: aws.String(buffer.dataType.String()),
 				DataType:    aws.String(messageAttributeDataType),
 			},
 			logTypeAttributeName: {
@@ -303,16 +418,30 @@ func (destination *S3Destination) sendSNSNotification(key string, buffer *s3Even
 	return err
 }
 
-// getS3ObjectKey builds the S3 object key for storing a partition file of processed logs.
-func getS3ObjectKey(logType string, timestamp time.Time) string {
-	dbPrefix := awsglue.GetDataPrefix(awsglue.LogProcessingDatabaseName)
-	tblName := awsglue.GetTableName(logType)
-	partitionPrefix := awsglue.GlueTableHourly.PartitionPathS3(timestamp)
-	filename := fmt.Sprintf("%s-%s.json.gz",
-		timestamp.Format(S3ObjectTimestampLayout),
-		uuid.New(),
+// s3ObjectName returns the partition prefix and the file name without extension to store a buffer.
+// NOTE: this is only called from the single sendData() go routine so objectSeq needs no locking
+func (destination *S3Destination) s3ObjectName(buffer *s3EventBuffer) (partitionPrefix, name string) {
+	partitionPrefix = awsglue.GetPartitionPrefix(buffer.dataType, buffer.logType, awsglue.GlueTableHourly, buffer.hour)
+	if destination.objectID == "" {
+		return partitionPrefix, fmt.Sprintf("%s-%s", buffer.hour.Format(S3ObjectTimestampLayout), uuid.New())
+	}
+	seq := destination.objectSeq[partitionPrefix]
+	destination.objectSeq[partitionPrefix] = seq + 1
+	name = fmt.Sprintf("%s-%s-%d",
+		buffer.hour.Format(S3ObjectTimestampLayout),
+		destination.objectID,
+		seq,
 	)
-	return path.Join(dbPrefix, tblName, partitionPrefix, filename)
+	return partitionPrefix, name
+}
+
+// getDataType returns the type of data stored for a log type.
+// Log entries that failed classification are stored apart from log data so they are not processed by rules.
+func getDataType(logType string) models.DataType {
+	if logType == classification.FailureLogType {
+		return models.ClassificationFailures
+	}
+	return models.LogData
 }
 
 // s3BufferSet is a group of buffers associated with hour time bins, pointing to maps logtype->s3EventBuffer
@@ -344,6 +473,16 @@ func (bs *s3EventBufferSet) writeEvent(event *parsers.Result, maxBufferSize, max
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to serialize event to JSON")
 	}
+	// Events quarantined by the lateness policy of their log type are stored with the classification failures
+	if event.IsQuarantined() {
+		event = classification.QuarantineResult(event, stream.Buffer())
+		stream.Reset(nil)
+		stream.WriteVal(event)
+		err, stream.Error = stream.Error, nil
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to serialize quarantined event to JSON")
+		}
+	}
 	// Just in case something was amiss elsewhere `getBuffer` checks again and uses PantherParseTime and Time.Now() as fallbacks.
 	buf = bs.getBuffer(event)
 	if buf == nil {
@@ -375,13 +514,11 @@ func (bs *s3EventBufferSet) writeEvent(event *parsers.Result, maxBufferSize, max
 
 func (bs *s3EventBufferSet) getBuffer(event *parsers.Result) *s3EventBuffer {
 	// Make sure we have a valid time to set the event partition
-	// If the event had no event time we use PantherParseTime and time.Now as fallbacks
-	eventTime := event.PantherEventTime
+	// If the event had no event time we use PantherParseTime as fallback.
+	// The lateness policy of the log type can also choose the parse time for late events.
+	eventTime := event.PartitionTime()
 	if eventTime.IsZero() {
-		eventTime = event.PantherParseTime
-		if eventTime.IsZero() {
-			return nil
-		}
+		return nil
 	}
 	// bin by hour (this is our partition size)
 	// We convert to UTC here so truncation does not affect the partition in the weird half-hour timezones if for
@@ -397,7 +534,7 @@ func (bs *s3EventBufferSet) getBuffer(event *parsers.Result) *s3EventBuffer {
 	logType := event.PantherLogType
 	buffer, ok := logTypeToBuffer[logType]
 	if !ok {
-		buffer = newS3EventBuffer(logType, hour)
+		buffer = newS3EventBuffer(getDataType(logType), logType, hour)
 		logTypeToBuffer[logType] = buffer
 	}
 
@@ -440,6 +577,7 @@ func (bs *s3EventBufferSet) apply(f func(buffer *s3EventBuffer) error) error {
 // s3EventBuffer is a group of events of the same type
 // that will be stored in the same S3 object
 type s3EventBuffer struct {
+	dataType   models.DataType
 	logType    string
 	buffer     *bytes.Buffer
 	writer     *gzip.Writer
@@ -449,10 +587,11 @@ type s3EventBuffer struct {
 	createTime time.Time // used to expire buffer
 }
 
-func newS3EventBuffer(logType string, hour time.Time) *s3EventBuffer {
+func newS3EventBuffer(dataType models.DataType, logType string, hour time.Time) *s3EventBuffer {
 	buffer := &bytes.Buffer{}
 	writer := gzip.NewWriter(buffer)
 	return &s3EventBuffer{
+		dataType:   dataType,
 		logType:    logType,
 		buffer:     buffer,
 		writer:     writer,
@@ -39,8 +39,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/panther-labs/panther/api/lambda/core/log_analysis/log_processor/models"
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
 	"github.com/panther-labs/panther/internal/log_analysis/datacatalog_updater/process"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/null"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
@@ -243,6 +246,35 @@ func TestSendDataToS3BeforeTerminating(t *testing.T) {
 	assert.Equal(t, expectedSnsPublishInput, publishInput)
 }
 
+func TestSendClassificationFailuresToS3(t *testing.T) {
+	initTest()
+
+	destination := newS3Destination()
+	eventChannel := make(chan *parsers.Result, 1)
+
+	failure, _ := resultBuilder.BuildResult(classification.FailureLogType, &classification.Failure{
+		SourceID: "source-id",
+		Line:     "foo",
+	})
+	eventChannel <- failure
+
+	destination.mockS3Uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Once()
+	destination.mockSns.On("Publish", mock.Anything).Return(&sns.PublishOutput{}, nil).Once()
+
+	runSendEvents(t, destination, eventChannel, false)
+
+	destination.mockS3Uploader.AssertExpectations(t)
+	destination.mockSns.AssertExpectations(t)
+
+	uploadInput := destination.mockS3Uploader.Calls[0].Arguments.Get(0).(*s3manager.UploadInput)
+	expectedPrefix := "classification_failures/classification_failures/year="
+	assert.True(t, strings.HasPrefix(*uploadInput.Key, expectedPrefix), *uploadInput.Key)
+
+	// classification failures must not be sent to the rules engine
+	publishInput := destination.mockSns.Calls[0].Arguments.Get(0).(*sns.PublishInput)
+	assert.Equal(t, models.ClassificationFailures.String(), aws.StringValue(publishInput.MessageAttributes["type"].StringValue))
+}
+
 func TestSendDataIfTotalMemSizeLimitHasBeenReached(t *testing.T) {
 	initTest()
 
@@ -278,6 +310,91 @@ func TestSendDataIfTotalMemSizeLimitHasBeenReached(t *testing.T) {
 	require.Equal(t, expectedS3Prefix, aws.StringValue(key2)[:len(expectedS3Prefix)])
 }
 
+func TestSendDataWithIdempotentKeys(t *testing.T) {
+	initTest()
+
+	mockSns := &mockSns{}
+	mockS3Uploader := &mockS3ManagerUploader{}
+	destination := CreateIdempotentS3Destination(nil, mockS3Uploader, mockSns, "testbucket",
+		"arn:aws:sns:us-west-2:123456789012:test", "objectid", 0, common.BuildJSON())
+	eventChannel := make(chan *parsers.Result, 2)
+
+	// maxBufferedMemBytes is zero so each event will trigger a send
+	eventChannel <- newTestResult(nil)
+	eventChannel <- newSimpleTestEvent().Result()
+	close(eventChannel)
+
+	mockS3Uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Twice()
+	mockSns.On("Publish", mock.Anything).Return(&sns.PublishOutput{}, nil).Twice()
+
+	errChan := make(chan error, 10)
+	destination.SendEvents(eventChannel, errChan)
+	require.Len(t, errChan, 0)
+
+	mockS3Uploader.AssertExpectations(t)
+	mockSns.AssertExpectations(t)
+
+	key1 := mockS3Uploader.Calls[0].Arguments.Get(0).(*s3manager.UploadInput).Key
+	require.Equal(t, expectedS3Prefix+"-objectid-0.json.gz", aws.StringValue(key1))
+	key2 := mockS3Uploader.Calls[1].Arguments.Get(0).(*s3manager.UploadInput).Key
+	require.Equal(t, expectedS3Prefix+"-objectid-1.json.gz", aws.StringValue(key2))
+}
+
+func TestSendDataParquet(t *testing.T) {
+	initTest()
+
+	schema, err := pantherlog.BuildEventSchema(&fooEvent{})
+	require.NoError(t, err)
+	resolver := &logtypes.Registry{}
+	_, err = resolver.Register(logtypes.Config{
+		Name:         testLogType,
+		Description:  "Test log type",
+		ReferenceURL: "-",
+		Schema:       schema,
+		NewParser: parsers.FactoryFunc(func(_ interface{}) (parsers.Interface, error) {
+			return nil, nil
+		}),
+		DataFormat: awsglue.DataFormatParquet,
+	})
+	require.NoError(t, err)
+
+	destination := newS3Destination()
+	destination.resolver = resolver
+	eventChannel := make(chan *parsers.Result, 1)
+	eventChannel <- newTestResult(nil)
+
+	// The Parquet file is streamed so it has to be read during the upload
+	var bodies [][]byte
+	destination.mockS3Uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Twice().
+		Run(func(args mock.Arguments) {
+			body, err := ioutil.ReadAll(args.Get(0).(*s3manager.UploadInput).Body)
+			require.NoError(t, err)
+			bodies = append(bodies, body)
+		})
+	destination.mockSns.On("Publish", mock.Anything).Return(&sns.PublishOutput{}, nil).Once()
+
+	runSendEvents(t, destination, eventChannel, false)
+
+	destination.mockS3Uploader.AssertExpectations(t)
+	destination.mockSns.AssertExpectations(t)
+
+	// The Parquet file is stored first in the Parquet table
+	parquetKey := aws.StringValue(destination.mockS3Uploader.Calls[0].Arguments.Get(0).(*s3manager.UploadInput).Key)
+	expectParquetPrefix := "logs/testlogtype_parquet/year=2020/month=01/day=01/hour=00/20200101T000000Z"
+	require.True(t, strings.HasPrefix(parquetKey, expectParquetPrefix), parquetKey)
+	require.True(t, strings.HasSuffix(parquetKey, ".parquet"), parquetKey)
+	require.Equal(t, "PAR1", string(bodies[0][:4]))
+	require.Equal(t, "PAR1", string(bodies[0][len(bodies[0])-4:]))
+
+	// The JSON file is stored in the log table with the same name and is used in the notification
+	jsonKey := aws.StringValue(destination.mockS3Uploader.Calls[1].Arguments.Get(0).(*s3manager.UploadInput).Key)
+	name := strings.TrimSuffix(strings.TrimPrefix(parquetKey, "logs/testlogtype_parquet/"), ".parquet")
+	expectJSONKey := "logs/testlogtype/" + name + ".json.gz"
+	require.Equal(t, expectJSONKey, jsonKey)
+	publish := destination.mockSns.Calls[0].Arguments.Get(0).(*sns.PublishInput)
+	require.Contains(t, aws.StringValue(publish.Message), jsonKey)
+}
+
 func TestSendDataIfBufferSizeLimitHasBeenReached(t *testing.T) {
 	initTest()
 
@@ -488,6 +605,31 @@ func TestBufferSetLargest(t *testing.T) {
 	require.Same(t, bs.largestBuffer(), expectedLargest)
 }
 
+func TestBufferSetLateness(t *testing.T) {
+	bs := newS3EventBufferSet(common.BuildJSON())
+	parseHour := refParseTime.UTC().Truncate(time.Hour)
+
+	// late events are stored in the partition of their parse time
+	result := newTestResult(nil)
+	result.ApplyLateness(&pantherlog.LatenessPolicy{MaxDelay: time.Hour})
+	_, err := bs.writeEvent(result, defaultMaxS3BufferSizeBytes, defaultMaxS3BufferSizeBytes)
+	require.NoError(t, err)
+	require.NotNil(t, bs.set[parseHour][testLogType])
+	require.Nil(t, bs.set[time.Time(refTime).Truncate(time.Hour)])
+
+	// future events are stored with the classification failures
+	result = newTestResult(&fooEvent{
+		Time: refParseTime.Add(24 * time.Hour),
+		Foo:  null.FromString("future"),
+	})
+	result.ApplyLateness(&pantherlog.LatenessPolicy{FutureEvents: pantherlog.FutureEventsQuarantine})
+	_, err = bs.writeEvent(result, defaultMaxS3BufferSizeBytes, defaultMaxS3BufferSizeBytes)
+	require.NoError(t, err)
+	buf := bs.set[parseHour][classification.FailureLogType]
+	require.NotNil(t, buf)
+	require.Equal(t, 1, buf.events)
+}
+
 func runSendEvents(t *testing.T, destination Destination, eventChannel chan *parsers.Result, expectErr bool) {
 	runSendEventsSignaled(t, destination, eventChannel, expectErr, nil)
 }
@@ -0,0 +1,163 @@
+// Package enrichment adds geolocation and threat intel data to the indicator fields of log events.
+package enrichment
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+const (
+	// GeoIPDatabaseExt is the file extension of GeoIP databases in an enrichment data directory
+	GeoIPDatabaseExt = ".mmdb"
+	// ThreatIntelListExt is the file extension of threat intel lists in an enrichment data directory
+	ThreatIntelListExt = ".csv"
+)
+
+// Fields matched against threat intel lists
+var threatIntelFields = []pantherlog.FieldID{
+	pantherlog.FieldIPAddress,
+	pantherlog.FieldDomainName,
+	pantherlog.FieldMD5Hash,
+	pantherlog.FieldSHA1Hash,
+	pantherlog.FieldSHA256Hash,
+	pantherlog.FieldJA3Hash,
+}
+
+// Enricher adds GeoIP data for all IP address values and matches indicator values against a threat intel list.
+type Enricher struct {
+	// GeoIP databases are looked up in order, with earlier databases taking precedence for each field.
+	GeoIP       []*GeoIPDatabase
+	ThreatIntel *ThreatIntelList
+}
+
+var _ pantherlog.Enricher = (*Enricher)(nil)
+
+// Enrich implements pantherlog.Enricher interface
+func (e *Enricher) Enrich(fields *pantherlog.EnrichmentFields, values *pantherlog.ValueBuffer) {
+	if values == nil {
+		return
+	}
+	if len(e.GeoIP) > 0 {
+		for _, value := range values.Get(pantherlog.FieldIPAddress) {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				continue
+			}
+			geo, found := pantherlog.GeoIP{}, false
+			for _, db := range e.GeoIP {
+				ok, err := db.Lookup(ip, &geo)
+				if err != nil {
+					// Enrichment is best effort, a corrupt record should not fail processing
+					zap.L().Debug("GeoIP lookup failed", zap.String("databaseType", db.DatabaseType()), zap.Error(err))
+				}
+				found = found || ok
+			}
+			if !found {
+				continue
+			}
+			if fields.PantherEnrichment == nil {
+				fields.PantherEnrichment = &pantherlog.Enrichment{}
+			}
+			fields.PantherEnrichment.SetGeoIP(value, geo)
+		}
+	}
+	if e.ThreatIntel.Len() > 0 {
+		for _, id := range threatIntelFields {
+			for _, value := range values.Get(id) {
+				entry, ok := e.ThreatIntel.Match(id, value)
+				if !ok {
+					continue
+				}
+				fields.PantherThreatIntelMatches = append(fields.PantherThreatIntelMatches, pantherlog.ThreatIntelMatch{
+					Indicator:   value,
+					Field:       pantherlog.FieldNameJSON(id),
+					Source:      entry.Source,
+					Description: entry.Description,
+				})
+			}
+		}
+	}
+}
+
+// IsEmpty checks if there is no enrichment data
+func (e *Enricher) IsEmpty() bool {
+	return len(e.GeoIP) == 0 && e.ThreatIntel.Len() == 0
+}
+
+// Load loads all GeoIP databases (*.mmdb) and threat intel lists (*.csv) found in a directory.
+// GeoIP databases are used in lexical order of their file names.
+// The source of threat intel matches is the name of the list file without the extension.
+// It returns nil if the directory does not exist or has no enrichment data.
+func Load(dir string) (*Enricher, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read enrichment data directory %q", dir)
+	}
+	e := Enricher{
+		ThreatIntel: &ThreatIntelList{},
+	}
+	// ReadDir returns files sorted by name
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		filename := filepath.Join(dir, file.Name())
+		switch ext := filepath.Ext(file.Name()); strings.ToLower(ext) {
+		case GeoIPDatabaseExt:
+			db, err := OpenGeoIPDatabase(filename)
+			if err != nil {
+				return nil, err
+			}
+			e.GeoIP = append(e.GeoIP, db)
+		case ThreatIntelListExt:
+			if err := loadThreatIntelList(e.ThreatIntel, filename, strings.TrimSuffix(file.Name(), ext)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if e.IsEmpty() {
+		return nil, nil
+	}
+	return &e, nil
+}
+
+func loadThreatIntelList(l *ThreatIntelList, filename, source string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open threat intel list %q", filename)
+	}
+	defer f.Close()
+	if err := l.ReadCSV(f, source); err != nil {
+		return errors.Wrapf(err, "failed to read threat intel list %q", filename)
+	}
+	return nil
+}
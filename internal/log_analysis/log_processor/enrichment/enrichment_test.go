@@ -0,0 +1,86 @@
+package enrichment
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "enrichment")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	e, err := Load(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	require.Nil(t, e)
+	e, err = Load(dir)
+	require.NoError(t, err)
+	require.Nil(t, e)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a-city.mmdb"), testCityDatabase(t), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "b-asn.mmdb"), testASNDatabase(t), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "blocklist.csv"), []byte("2.2.2.2,scanner\nevil.com\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README"), []byte("ignored"), 0600))
+	e, err = Load(dir)
+	require.NoError(t, err)
+	require.NotNil(t, e)
+	require.Len(t, e.GeoIP, 2)
+	assert.Equal(t, "GeoLite2-City", e.GeoIP[0].DatabaseType())
+	assert.Equal(t, 2, e.ThreatIntel.Len())
+
+	values := pantherlog.ValueBuffer{}
+	values.WriteValues(pantherlog.FieldIPAddress, "1.1.1.1", "2.2.2.2", "3.3.3.3")
+	values.WriteValues(pantherlog.FieldDomainName, "www.evil.com", "example.com")
+	fields := pantherlog.EnrichmentFields{}
+	e.Enrich(&fields, &values)
+	assert.Equal(t, &pantherlog.Enrichment{
+		GeoIP: map[string]pantherlog.GeoIP{
+			"1.1.1.1": {
+				CountryCode:     "GR",
+				Country:         "Greece",
+				City:            "Athens",
+				Latitude:        37.9842,
+				Longitude:       23.7353,
+				ASN:             13335,
+				ASNOrganization: "CLOUDFLARENET",
+			},
+			"2.2.2.2": {
+				CountryCode: "FR",
+				Country:     "France",
+			},
+		},
+	}, fields.PantherEnrichment)
+	assert.Equal(t, []pantherlog.ThreatIntelMatch{
+		{Indicator: "2.2.2.2", Field: "p_any_ip_addresses", Source: "blocklist", Description: "scanner"},
+		{Indicator: "www.evil.com", Field: "p_any_domain_names", Source: "blocklist"},
+	}, fields.PantherThreatIntelMatches)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "corrupt.mmdb"), []byte("foo"), 0600))
+	_, err = Load(dir)
+	require.Error(t, err)
+}
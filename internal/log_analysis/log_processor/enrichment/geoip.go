@@ -0,0 +1,155 @@
+package enrichment
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"io/ioutil"
+	"net"
+	"sync"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// Max number of records to keep decoded in memory for each database
+const geoIPCacheSize = 10000
+
+// GeoIPDatabase looks up geolocation and network information for IP addresses in a MaxMind DB file.
+// It reads the record layout of the GeoIP2/GeoLite2 City, Country and ASN databases.
+type GeoIPDatabase struct {
+	db    *maxminddb.Reader
+	mu    sync.Mutex
+	cache map[uintptr]pantherlog.GeoIP
+}
+
+// OpenGeoIPDatabase reads a MaxMind DB file into memory.
+func OpenGeoIPDatabase(filename string) (*GeoIPDatabase, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read GeoIP database %q", filename)
+	}
+	db, err := NewGeoIPDatabase(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open GeoIP database %q", filename)
+	}
+	return db, nil
+}
+
+// NewGeoIPDatabase creates a database from the contents of a MaxMind DB file.
+func NewGeoIPDatabase(data []byte) (*GeoIPDatabase, error) {
+	db, err := maxminddb.FromBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	return &GeoIPDatabase{
+		db:    db,
+		cache: make(map[uintptr]pantherlog.GeoIP),
+	}, nil
+}
+
+// DatabaseType returns the type of the database as stored in the file metadata (ie 'GeoLite2-City')
+func (g *GeoIPDatabase) DatabaseType() string {
+	return g.db.Metadata.DatabaseType
+}
+
+// Lookup sets the empty fields of geo with the information found for ip.
+// It returns false if there is no record for ip in the database.
+func (g *GeoIPDatabase) Lookup(ip net.IP, geo *pantherlog.GeoIP) (bool, error) {
+	offset, err := g.db.LookupOffset(ip)
+	if err != nil || offset == maxminddb.NotFound {
+		return false, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	record, ok := g.cache[offset]
+	if !ok {
+		value := geoIPRecord{}
+		if err := g.db.Decode(offset, &value); err != nil {
+			return false, err
+		}
+		record = value.GeoIP()
+		if len(g.cache) >= geoIPCacheSize {
+			g.cache = make(map[uintptr]pantherlog.GeoIP)
+		}
+		g.cache[offset] = record
+	}
+	mergeGeoIP(geo, &record)
+	return true, nil
+}
+
+// geoIPRecord is the subset of the GeoIP2/GeoLite2 record fields stored in pantherlog.GeoIP
+type geoIPRecord struct {
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Country           geoIPCountry `maxminddb:"country"`
+	RegisteredCountry geoIPCountry `maxminddb:"registered_country"`
+	Location          struct {
+		Latitude  float64 `maxminddb:"latitude"`
+		Longitude float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+	ASN             uint32 `maxminddb:"autonomous_system_number"`
+	ASNOrganization string `maxminddb:"autonomous_system_organization"`
+}
+
+type geoIPCountry struct {
+	ISOCode string            `maxminddb:"iso_code"`
+	Names   map[string]string `maxminddb:"names"`
+}
+
+func (r *geoIPRecord) GeoIP() pantherlog.GeoIP {
+	country := r.Country
+	if country.ISOCode == "" && len(country.Names) == 0 {
+		country = r.RegisteredCountry
+	}
+	return pantherlog.GeoIP{
+		CountryCode:     country.ISOCode,
+		Country:         country.Names["en"],
+		City:            r.City.Names["en"],
+		Latitude:        r.Location.Latitude,
+		Longitude:       r.Location.Longitude,
+		ASN:             r.ASN,
+		ASNOrganization: r.ASNOrganization,
+	}
+}
+
+// mergeGeoIP sets all empty fields of dst from src
+func mergeGeoIP(dst, src *pantherlog.GeoIP) {
+	if dst.CountryCode == "" {
+		dst.CountryCode = src.CountryCode
+	}
+	if dst.Country == "" {
+		dst.Country = src.Country
+	}
+	if dst.City == "" {
+		dst.City = src.City
+	}
+	if dst.Latitude == 0 && dst.Longitude == 0 {
+		dst.Latitude, dst.Longitude = src.Latitude, src.Longitude
+	}
+	if dst.ASN == 0 {
+		dst.ASN = src.ASN
+	}
+	if dst.ASNOrganization == "" {
+		dst.ASNOrganization = src.ASNOrganization
+	}
+}
@@ -0,0 +1,113 @@
+package enrichment
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+func testCityDatabase(t *testing.T) []byte {
+	w := testMMDB{}
+	w.insert(t, "1.1.1.0/24", map[string]interface{}{
+		"city": map[string]interface{}{
+			"names": map[string]interface{}{"en": "Athens", "de": "Athen"},
+		},
+		"country": map[string]interface{}{
+			"iso_code": "GR",
+			"names":    map[string]interface{}{"en": "Greece", "de": "Griechenland"},
+		},
+		"location": map[string]interface{}{
+			"latitude":  37.9842,
+			"longitude": 23.7353,
+		},
+	})
+	w.insert(t, "2.2.0.0/16", map[string]interface{}{
+		"registered_country": map[string]interface{}{
+			"iso_code": "FR",
+			"names":    map[string]interface{}{"en": "France"},
+		},
+	})
+	return w.bytes("GeoLite2-City")
+}
+
+func testASNDatabase(t *testing.T) []byte {
+	w := testMMDB{}
+	w.insert(t, "1.1.0.0/16", map[string]interface{}{
+		"autonomous_system_number":       uint32(13335),
+		"autonomous_system_organization": "CLOUDFLARENET",
+	})
+	return w.bytes("GeoLite2-ASN")
+}
+
+func TestGeoIPDatabase(t *testing.T) {
+	city, err := NewGeoIPDatabase(testCityDatabase(t))
+	require.NoError(t, err)
+	assert.Equal(t, "GeoLite2-City", city.DatabaseType())
+	asn, err := NewGeoIPDatabase(testASNDatabase(t))
+	require.NoError(t, err)
+
+	geo := pantherlog.GeoIP{}
+	ok, err := city.Lookup(net.ParseIP("1.1.1.1"), &geo)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = asn.Lookup(net.ParseIP("1.1.1.1"), &geo)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, pantherlog.GeoIP{
+		CountryCode:     "GR",
+		Country:         "Greece",
+		City:            "Athens",
+		Latitude:        37.9842,
+		Longitude:       23.7353,
+		ASN:             13335,
+		ASNOrganization: "CLOUDFLARENET",
+	}, geo)
+
+	// cached records are returned
+	geo = pantherlog.GeoIP{}
+	ok, err = city.Lookup(net.ParseIP("1.1.1.2"), &geo)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "Athens", geo.City)
+	assert.Len(t, city.cache, 1)
+
+	// registered country is used if there is no country
+	geo = pantherlog.GeoIP{}
+	ok, err = city.Lookup(net.ParseIP("2.2.2.2"), &geo)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, pantherlog.GeoIP{CountryCode: "FR", Country: "France"}, geo)
+
+	geo = pantherlog.GeoIP{}
+	ok, err = asn.Lookup(net.ParseIP("2.2.2.2"), &geo)
+	require.NoError(t, err)
+	require.False(t, ok)
+	assert.Equal(t, pantherlog.GeoIP{}, geo)
+}
+
+func TestGeoIPDatabaseInvalid(t *testing.T) {
+	_, err := NewGeoIPDatabase([]byte("not a database"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,194 @@
+package enrichment
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Data section field types used in test databases
+const (
+	mmdbPointer = 1
+	mmdbString  = 2
+	mmdbDouble  = 3
+	mmdbUint16  = 5
+	mmdbUint32  = 6
+	mmdbMap     = 7
+	mmdbArray   = 11
+	mmdbBool    = 14
+)
+
+// mmdbMetadataStart marks the start of the metadata section at the end of MaxMind DB files
+var mmdbMetadataStart = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// testMMDB builds a MaxMind DB file with 24 bit records for tests
+// See https://maxmind.github.io/MaxMind-DB/
+type testMMDB struct {
+	root    testNode
+	data    bytes.Buffer
+	strings map[string]int
+}
+
+type testNode struct {
+	children [2]*testNode
+	records  [2]int // data offset + 1, 0 if empty
+}
+
+// insert adds a network with a record, IPv4 networks are stored under ::/96
+func (w *testMMDB) insert(t *testing.T, cidr string, record map[string]interface{}) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	require.NoError(t, err)
+	ip := ipNet.IP.To16()
+	ones, _ := ipNet.Mask.Size()
+	if ipNet.IP.To4() != nil {
+		ip = append(make(net.IP, 12), ipNet.IP.To4()...)
+		ones += 96
+	}
+	offset := w.data.Len()
+	w.encode(record)
+	node := &w.root
+	for i := 0; i < ones; i++ {
+		bit := ip[i>>3] >> (7 - (i & 7)) & 1
+		if i == ones-1 {
+			node.records[bit] = offset + 1
+			break
+		}
+		if node.children[bit] == nil {
+			node.children[bit] = &testNode{}
+		}
+		node = node.children[bit]
+	}
+}
+
+func (w *testMMDB) bytes(databaseType string) []byte {
+	var nodes []*testNode
+	ids := map[*testNode]int{}
+	queue := []*testNode{&w.root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		ids[node] = len(nodes)
+		nodes = append(nodes, node)
+		for _, child := range node.children {
+			if child != nil {
+				queue = append(queue, child)
+			}
+		}
+	}
+	nodeCount := len(nodes)
+	var buf bytes.Buffer
+	for _, node := range nodes {
+		for i, child := range node.children {
+			record := nodeCount
+			switch {
+			case child != nil:
+				record = ids[child]
+			case node.records[i] != 0:
+				record = nodeCount + 16 + node.records[i] - 1
+			}
+			buf.Write([]byte{byte(record >> 16), byte(record >> 8), byte(record)})
+		}
+	}
+	buf.Write(make([]byte, 16))
+	buf.Write(w.data.Bytes())
+	buf.Write(mmdbMetadataStart)
+	meta := testMMDB{}
+	meta.encode(map[string]interface{}{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 uint16(24),
+		"ip_version":                  uint16(6),
+		"database_type":               databaseType,
+	})
+	buf.Write(meta.data.Bytes())
+	return buf.Bytes()
+}
+
+func (w *testMMDB) control(typ, size int) {
+	var ext []byte
+	if size >= 29 {
+		// sizes up to 284 are stored in an extra byte
+		ext = []byte{byte(size - 29)}
+		size = 29
+	}
+	if typ < 8 {
+		w.data.WriteByte(byte(typ<<5 | size))
+	} else {
+		w.data.Write([]byte{byte(size), byte(typ - 7)})
+	}
+	w.data.Write(ext)
+}
+
+func (w *testMMDB) encode(value interface{}) {
+	switch v := value.(type) {
+	case string:
+		// Repeated strings are written as pointers
+		if offset, ok := w.strings[v]; ok {
+			w.data.Write([]byte{byte(mmdbPointer<<5 | offset>>8), byte(offset)})
+			return
+		}
+		if w.strings == nil {
+			w.strings = map[string]int{}
+		}
+		w.strings[v] = w.data.Len()
+		w.control(mmdbString, len(v))
+		w.data.WriteString(v)
+	case float64:
+		w.control(mmdbDouble, 8)
+		_ = binary.Write(&w.data, binary.BigEndian, math.Float64bits(v))
+	case uint16:
+		w.control(mmdbUint16, 2)
+		_ = binary.Write(&w.data, binary.BigEndian, v)
+	case uint32:
+		w.control(mmdbUint32, 4)
+		_ = binary.Write(&w.data, binary.BigEndian, v)
+	case bool:
+		b := 0
+		if v {
+			b = 1
+		}
+		w.control(mmdbBool, b)
+	case []interface{}:
+		w.control(mmdbArray, len(v))
+		for _, el := range v {
+			w.encode(el)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		w.control(mmdbMap, len(v))
+		for _, key := range keys {
+			w.encode(key)
+			w.encode(v[key])
+		}
+	default:
+		panic("unsupported value")
+	}
+}
@@ -0,0 +1,150 @@
+package enrichment
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"encoding/csv"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// ThreatIntelList matches indicator values against a list of known malicious indicators (IOCs).
+//
+// Entries can be IP addresses, CIDR ranges, domain names, file hashes or JA3 fingerprints.
+// A domain name entry also matches all of its subdomains.
+type ThreatIntelList struct {
+	entries  map[string]ThreatIntelEntry
+	networks []threatIntelNetwork
+}
+
+// ThreatIntelEntry is the information about an indicator in a threat intel list.
+type ThreatIntelEntry struct {
+	Source      string
+	Description string
+}
+
+type threatIntelNetwork struct {
+	*net.IPNet
+	ThreatIntelEntry
+}
+
+// Add adds an indicator to the list, overriding any previous entry for the same indicator.
+func (l *ThreatIntelList) Add(indicator string, entry ThreatIntelEntry) error {
+	indicator = strings.ToLower(strings.TrimSpace(indicator))
+	if indicator == "" {
+		return errors.New("empty indicator")
+	}
+	if strings.Contains(indicator, "/") {
+		_, ipNet, err := net.ParseCIDR(indicator)
+		if err != nil {
+			return errors.Errorf("invalid network indicator %q", indicator)
+		}
+		l.networks = append(l.networks, threatIntelNetwork{
+			IPNet:            ipNet,
+			ThreatIntelEntry: entry,
+		})
+		return nil
+	}
+	if ip := net.ParseIP(indicator); ip != nil {
+		indicator = ip.String()
+	}
+	if l.entries == nil {
+		l.entries = make(map[string]ThreatIntelEntry)
+	}
+	l.entries[indicator] = entry
+	return nil
+}
+
+// ReadCSV adds the indicators of a CSV file to the list.
+//
+// Each record has the indicator in the first column and an optional description in the second.
+// Empty lines and lines starting with '#' are ignored.
+func (l *ThreatIntelList) ReadCSV(r io.Reader, source string) error {
+	rd := csv.NewReader(r)
+	rd.Comment = '#'
+	rd.FieldsPerRecord = -1
+	rd.TrimLeadingSpace = true
+	for n := 1; ; n++ {
+		record, err := rd.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		entry := ThreatIntelEntry{
+			Source: source,
+		}
+		if len(record) > 1 {
+			entry.Description = record[1]
+		}
+		if err := l.Add(record[0], entry); err != nil {
+			return errors.Wrapf(err, "invalid record %d", n)
+		}
+	}
+}
+
+// Len returns the number of indicators in the list.
+func (l *ThreatIntelList) Len() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.entries) + len(l.networks)
+}
+
+// Match checks if the value of an indicator field is in the list.
+func (l *ThreatIntelList) Match(id pantherlog.FieldID, value string) (ThreatIntelEntry, bool) {
+	switch id {
+	case pantherlog.FieldIPAddress:
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return ThreatIntelEntry{}, false
+		}
+		if entry, ok := l.entries[ip.String()]; ok {
+			return entry, true
+		}
+		for i := range l.networks {
+			if network := &l.networks[i]; network.Contains(ip) {
+				return network.ThreatIntelEntry, true
+			}
+		}
+	case pantherlog.FieldDomainName:
+		domain := strings.TrimSuffix(strings.ToLower(value), ".")
+		for domain != "" {
+			if entry, ok := l.entries[domain]; ok {
+				return entry, true
+			}
+			// Check the parent domain
+			pos := strings.IndexByte(domain, '.')
+			if pos == -1 {
+				break
+			}
+			domain = domain[pos+1:]
+		}
+	case pantherlog.FieldMD5Hash, pantherlog.FieldSHA1Hash, pantherlog.FieldSHA256Hash, pantherlog.FieldJA3Hash:
+		entry, ok := l.entries[strings.ToLower(value)]
+		return entry, ok
+	}
+	return ThreatIntelEntry{}, false
+}
@@ -0,0 +1,73 @@
+package enrichment
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+func TestThreatIntelList(t *testing.T) {
+	l := ThreatIntelList{}
+	input := `# indicator,description
+1.1.1.1,known bad
+10.0.0.0/8
+Evil.com,phishing
+"d41d8cd98f00b204e9800998ecf8427e",empty file
+`
+	require.NoError(t, l.ReadCSV(strings.NewReader(input), "test"))
+	assert.Equal(t, 4, l.Len())
+
+	for _, tc := range []struct {
+		ID    pantherlog.FieldID
+		Value string
+		Match bool
+		Entry ThreatIntelEntry
+	}{
+		{pantherlog.FieldIPAddress, "1.1.1.1", true, ThreatIntelEntry{Source: "test", Description: "known bad"}},
+		{pantherlog.FieldIPAddress, "10.1.2.3", true, ThreatIntelEntry{Source: "test"}},
+		{pantherlog.FieldIPAddress, "1.1.1.2", false, ThreatIntelEntry{}},
+		{pantherlog.FieldIPAddress, "evil.com", false, ThreatIntelEntry{}},
+		{pantherlog.FieldDomainName, "evil.com", true, ThreatIntelEntry{Source: "test", Description: "phishing"}},
+		{pantherlog.FieldDomainName, "www.EVIL.com.", true, ThreatIntelEntry{Source: "test", Description: "phishing"}},
+		{pantherlog.FieldDomainName, "notevil.com", false, ThreatIntelEntry{}},
+		{pantherlog.FieldDomainName, "com", false, ThreatIntelEntry{}},
+		{pantherlog.FieldMD5Hash, "D41D8CD98F00B204E9800998ECF8427E", true, ThreatIntelEntry{Source: "test", Description: "empty file"}},
+		{pantherlog.FieldSHA1Hash, "1.1.1.1", true, ThreatIntelEntry{Source: "test", Description: "known bad"}},
+		{pantherlog.FieldJA3Hash, "d41d8cd98f00b204e9800998ecf8427e", true, ThreatIntelEntry{Source: "test", Description: "empty file"}},
+		{pantherlog.FieldTraceID, "1.1.1.1", false, ThreatIntelEntry{}},
+	} {
+		entry, ok := l.Match(tc.ID, tc.Value)
+		assert.Equal(t, tc.Match, ok, tc.Value)
+		assert.Equal(t, tc.Entry, entry, tc.Value)
+	}
+}
+
+func TestThreatIntelListInvalid(t *testing.T) {
+	l := ThreatIntelList{}
+	err := l.ReadCSV(strings.NewReader("1.1.1.1\n10.0.0.0/33\n"), "test")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid record 2")
+	assert.Error(t, l.Add(" ", ThreatIntelEntry{}))
+}
@@ -158,10 +158,12 @@ func TestResult(t *testing.T, expect string, actual *pantherlog.Result, indicato
 	require.Equal(t, actual.PantherEventTime.UTC().Format(time.RFC3339Nano), actualAny["p_event_time"], "Invalid JSON event time")
 	require.Equal(t, actual.PantherParseTime.UTC().Format(time.RFC3339Nano), actualAny["p_parse_time"], "Invalid JSON parse time")
 	require.Equal(t, actual.PantherRowID, actualAny["p_row_id"], "Invalid JSON row id")
+	require.Equal(t, float64(actual.PantherIngestDelaySeconds), actualAny["p_ingest_delay_seconds"], "Invalid JSON ingest delay")
 	// Since these values are checked to be valid we assign them to expect to check the rest of the JSON values
 	expectAny["p_event_time"] = actualAny["p_event_time"]
 	expectAny["p_parse_time"] = actualAny["p_parse_time"]
 	expectAny["p_row_id"] = actualAny["p_row_id"]
+	expectAny["p_ingest_delay_seconds"] = actualAny["p_ingest_delay_seconds"]
 	// By now expect JSON and actual JSON must be equal
 	expectJSON, err := jsoniter.MarshalToString(expectAny)
 	require.NoError(t, err)
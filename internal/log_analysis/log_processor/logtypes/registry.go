@@ -30,6 +30,7 @@ import (
 	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/parquet"
 )
 
 // Registry is a collection of log type entries.
@@ -168,6 +169,9 @@ type Config struct {
 	ReferenceURL string
 	Schema       interface{}
 	NewParser    parsers.Factory
+	// DataFormat is the storage format for processed logs of this type (defaults to JSON).
+	// Logs are always stored as JSON for the rules engine, DataFormatParquet also stores them in a Parquet table.
+	DataFormat awsglue.DataFormat
 }
 
 func (config *Config) Describe() Desc {
@@ -193,6 +197,17 @@ func (config *Config) Validate() error {
 	if config.NewParser == nil {
 		return errors.New("nil parser factory")
 	}
+	switch config.DataFormat {
+	case awsglue.DataFormatJSON:
+	case awsglue.DataFormatParquet:
+		// Verify all columns can be stored in Parquet files
+		columns, _ := awsglue.InferJSONColumns(config.Schema, awsglue.GlueMappings...)
+		if _, err := parquet.NewSchema(columns); err != nil {
+			return errors.WithMessagef(err, "invalid Parquet schema for log type %q", desc.Name)
+		}
+	default:
+		return errors.Errorf("invalid data format %s for log type %q", config.DataFormat, desc.Name)
+	}
 	return nil
 }
 
@@ -200,7 +215,7 @@ func (config Config) Build() (Entry, error) {
 	if err := config.Validate(); err != nil {
 		return nil, err
 	}
-	entry := newEntry(config.Describe(), config.Schema, config.NewParser)
+	entry := newEntry(config.Describe(), config.Schema, config.NewParser, config.DataFormat)
 	return entry, nil
 }
 
@@ -251,12 +266,16 @@ type entry struct {
 	glueTableMeta *awsglue.GlueTableMetadata
 }
 
-func newEntry(desc Desc, schema interface{}, fac parsers.Factory) *entry {
+func newEntry(desc Desc, schema interface{}, fac parsers.Factory, format awsglue.DataFormat) *entry {
+	glueTableMeta := awsglue.NewGlueTableMetadata(models.LogData, desc.Name, desc.Description, awsglue.GlueTableHourly, schema)
+	if format == awsglue.DataFormatParquet {
+		glueTableMeta = glueTableMeta.WithParquetTable()
+	}
 	return &entry{
 		Desc:          desc,
 		schema:        schema,
 		newParser:     fac.NewParser,
-		glueTableMeta: awsglue.NewGlueTableMetadata(models.LogData, desc.Name, desc.Description, awsglue.GlueTableHourly, schema),
+		glueTableMeta: glueTableMeta,
 	}
 }
 
@@ -62,6 +62,25 @@ func TestRegistry(t *testing.T) {
 		api.GlueTableMeta(),
 	)
 
+	// Ensure log types stored as Parquet have a Parquet table
+	configParquet := logTypeConfig
+	configParquet.Name = "Foo.Parquet"
+	configParquet.DataFormat = awsglue.DataFormatParquet
+	parquetEntry, err := r.Register(configParquet)
+	require.NoError(t, err)
+	require.Equal(t, awsglue.DataFormatJSON, parquetEntry.GlueTableMeta().DataFormat())
+	require.NotNil(t, parquetEntry.GlueTableMeta().ParquetTable())
+	require.Equal(t, "foo_parquet_parquet", parquetEntry.GlueTableMeta().ParquetTable().TableName())
+	require.Nil(t, api.GlueTableMeta().ParquetTable())
+	require.True(t, r.Del(configParquet.Name))
+
+	// Ensure invalid data formats don't pass
+	configInvalidFormat := logTypeConfig
+	configInvalidFormat.DataFormat = -1
+	nilEntry0, err := r.Register(configInvalidFormat)
+	require.Error(t, err)
+	require.Nil(t, nilEntry0)
+
 	// Ensure invalid schemas don't pass
 	configEmpty := logTypeConfig
 	configEmpty.Schema = struct{}{}
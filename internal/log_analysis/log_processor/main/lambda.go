@@ -20,21 +20,65 @@ package main
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"go.uber.org/zap"
 
+	"github.com/panther-labs/panther/internal/core/logtypesapi"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/dedup"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/enrichment"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
 	"github.com/panther-labs/panther/pkg/lambdalogger"
 )
 
+const (
+	logTypesAPIFunctionName = "panther-logtypes-api"
+	// Log types are resolved for every file processed so user-defined log types are cached longer than the default
+	customLogsMaxAge = 5 * time.Minute
+)
+
+var logTypesResolver logtypes.Resolver
+
 func main() {
 	common.Setup()
+	logTypesResolver = logtypes.ChainResolvers(
+		registry.NativeLogTypesResolver(),
+		&logtypesapi.CustomLogsResolver{
+			API: &logtypesapi.LogTypesAPILambdaClient{
+				LambdaName: logTypesAPIFunctionName,
+				LambdaAPI:  common.LambdaClient,
+			},
+			MaxAge: customLogsMaxAge,
+		},
+	)
+	enricher, err := enrichment.Load(common.Config.EnrichmentDataPath)
+	if err != nil {
+		panic(err)
+	}
+	if enricher != nil {
+		processor.Enricher = enricher
+	}
+	if common.Config.DedupWindowMinutes > 0 {
+		processor.DedupLogTypes = make(map[string]bool)
+		for _, logType := range common.Config.DedupLogTypes {
+			if logType = strings.TrimSpace(logType); logType != "" {
+				processor.DedupLogTypes[logType] = true
+			}
+		}
+		processor.DedupStore = &dedup.DynamoDBStore{
+			Client:    dynamodb.New(common.Session),
+			TableName: common.Config.DedupTableName,
+			Window:    time.Duration(common.Config.DedupWindowMinutes) * time.Minute,
+		}
+	}
 	lambda.Start(handle)
 }
 
@@ -53,7 +97,6 @@ func process(lc *lambdacontext.LambdaContext, deadline time.Time, event events.S
 		operation.Stop().Log(err, zap.Int("sqsMessageCount", sqsMessageCount))
 	}()
 
-	logTypesResolver := registry.NativeLogTypesResolver()
 	sqsMessageCount, err = processor.StreamEvents(common.SqsClient, logTypesResolver, deadline, event)
 	return err
 }
@@ -0,0 +1,200 @@
+// Package multiline frames consecutive lines of a log stream into multi-line events.
+package multiline
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+)
+
+const (
+	// DefaultMaxLines is the maximum number of lines in an event if no limit is configured
+	DefaultMaxLines = 1000
+	// DefaultMaxBytes is the maximum size of an event if no limit is configured
+	DefaultMaxBytes = 1024 * 1024
+)
+
+// Splitter joins the lines of a stream into multi-line events.
+type Splitter struct {
+	eventStart *regexp.Regexp
+	indented   bool
+	maxLines   int
+	maxBytes   int
+	timeout    time.Duration
+}
+
+// New creates a splitter for the multi-line config of a source.
+func New(config *models.MultilineConfig) (*Splitter, error) {
+	if config == nil {
+		return nil, errors.New("nil multiline config")
+	}
+	if config.EventStartPattern == "" && !config.IndentedContinuation {
+		return nil, errors.New("either an event start pattern or indented continuation is required")
+	}
+	s := Splitter{
+		indented: config.IndentedContinuation,
+		maxLines: config.MaxLines,
+		maxBytes: config.MaxBytes,
+		timeout:  time.Duration(config.TimeoutSeconds) * time.Second,
+	}
+	if pattern := config.EventStartPattern; pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid event start pattern %q", pattern)
+		}
+		s.eventStart = re
+	}
+	if s.maxLines <= 0 {
+		s.maxLines = DefaultMaxLines
+	}
+	if s.maxBytes <= 0 {
+		s.maxBytes = DefaultMaxBytes
+	}
+	return &s, nil
+}
+
+// Split reads lines from r until EOF calling emit for each multi-line event.
+// It implements processor.Splitter interface.
+func (s *Splitter) Split(r *bufio.Reader, emit func(entry string)) error {
+	event := frame{splitter: s}
+	if s.timeout <= 0 {
+		for {
+			line, err := r.ReadString(common.EventDelimiter)
+			event.add(line, emit)
+			if err != nil {
+				event.flush(emit)
+				if err == io.EOF {
+					return nil
+				}
+				return errors.Wrap(err, "failed to ReadString()")
+			}
+		}
+	}
+
+	// Lines are read in a separate goroutine so that the current event can be emitted if reading the next line takes
+	// longer than the timeout. The events are still emitted from the calling goroutine.
+	type readResult struct {
+		line string
+		err  error
+	}
+	lines := make(chan readResult)
+	go func() {
+		defer close(lines)
+		for {
+			line, err := r.ReadString(common.EventDelimiter)
+			lines <- readResult{line: line, err: err}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case result := <-lines:
+			event.add(result.line, emit)
+			if err := result.err; err != nil {
+				event.flush(emit)
+				if err == io.EOF {
+					return nil
+				}
+				return errors.Wrap(err, "failed to ReadString()")
+			}
+		case <-timer.C:
+			event.flush(emit)
+		}
+		// Stop and drain the timer before resetting it, see time.Timer.Reset
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(s.timeout)
+	}
+}
+
+// Frame returns functions that join entries read by another splitter (ie the messages of an envelope) into
+// multi-line events. add adds an entry to the current event, emitting the previous event if the entry starts a new one,
+// and flush emits the current event. Entries without a trailing newline are joined with one.
+func (s *Splitter) Frame(emit func(entry string)) (add func(entry string), flush func()) {
+	event := frame{splitter: s}
+	add = func(entry string) {
+		event.add(entry, emit)
+	}
+	flush = func() {
+		event.flush(emit)
+	}
+	return add, flush
+}
+
+// isContinuation checks if a line continues the current event
+func (s *Splitter) isContinuation(line string) bool {
+	if s.indented && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+		return true
+	}
+	if s.eventStart != nil {
+		return !s.eventStart.MatchString(line)
+	}
+	return false
+}
+
+// frame is the event being framed
+type frame struct {
+	splitter *Splitter
+	buffer   strings.Builder
+	numLines int
+}
+
+func (f *frame) add(line string, emit func(string)) {
+	if line == "" {
+		return
+	}
+	s := f.splitter
+	if f.numLines > 0 {
+		// Lines that do not continue the event or that would exceed the limits start a new event
+		if !s.isContinuation(line) || f.numLines >= s.maxLines || f.buffer.Len()+len(line) > s.maxBytes {
+			f.flush(emit)
+		}
+	}
+	if f.numLines > 0 && !strings.HasSuffix(f.buffer.String(), "\n") {
+		f.buffer.WriteByte(common.EventDelimiter)
+	}
+	f.buffer.WriteString(line)
+	f.numLines++
+}
+
+func (f *frame) flush(emit func(string)) {
+	if f.numLines == 0 {
+		return
+	}
+	emit(f.buffer.String())
+	f.buffer.Reset()
+	f.numLines = 0
+}
@@ -0,0 +1,143 @@
+package multiline
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+)
+
+func split(t *testing.T, config models.MultilineConfig, input string) []string {
+	s, err := New(&config)
+	require.NoError(t, err)
+	var entries []string
+	err = s.Split(bufio.NewReader(strings.NewReader(input)), func(entry string) {
+		entries = append(entries, entry)
+	})
+	require.NoError(t, err)
+	return entries
+}
+
+func TestSplitEventStart(t *testing.T) {
+	input := `2020-01-01 10:00:00 ERROR failed
+java.lang.NullPointerException: null
+	at com.example.Foo.bar(Foo.java:42)
+	at com.example.Main.main(Main.java:10)
+2020-01-01 10:00:01 INFO ok
+2020-01-01 10:00:02 INFO done`
+	entries := split(t, models.MultilineConfig{EventStartPattern: `^\d{4}-\d{2}-\d{2} `}, input)
+	assert.Equal(t, []string{
+		"2020-01-01 10:00:00 ERROR failed\njava.lang.NullPointerException: null\n" +
+			"\tat com.example.Foo.bar(Foo.java:42)\n\tat com.example.Main.main(Main.java:10)\n",
+		"2020-01-01 10:00:01 INFO ok\n",
+		"2020-01-01 10:00:02 INFO done",
+	}, entries)
+}
+
+func TestFrame(t *testing.T) {
+	s, err := New(&models.MultilineConfig{EventStartPattern: `^\d{4}-\d{2}-\d{2} `})
+	require.NoError(t, err)
+	var entries []string
+	add, flush := s.Frame(func(entry string) {
+		entries = append(entries, entry)
+	})
+	add("2020-01-01 10:00:00 ERROR failed")
+	add("\tat com.example.Foo.bar(Foo.java:42)")
+	add("2020-01-01 10:00:01 INFO ok")
+	flush()
+	assert.Equal(t, []string{
+		"2020-01-01 10:00:00 ERROR failed\n\tat com.example.Foo.bar(Foo.java:42)",
+		"2020-01-01 10:00:01 INFO ok",
+	}, entries)
+}
+
+func TestSplitIndented(t *testing.T) {
+	input := "<13>Jan  1 10:00:00 host app: first\n  continued\n<13>Jan  1 10:00:01 host app: second\n\n"
+	entries := split(t, models.MultilineConfig{IndentedContinuation: true}, input)
+	assert.Equal(t, []string{
+		"<13>Jan  1 10:00:00 host app: first\n  continued\n",
+		"<13>Jan  1 10:00:01 host app: second\n",
+		"\n",
+	}, entries)
+}
+
+func TestSplitPrettyJSON(t *testing.T) {
+	input := "{\n  \"a\": 1\n}\n{\n  \"b\": {\n    \"c\": 2\n  }\n}\n"
+	entries := split(t, models.MultilineConfig{EventStartPattern: `^\{`}, input)
+	assert.Equal(t, []string{
+		"{\n  \"a\": 1\n}\n",
+		"{\n  \"b\": {\n    \"c\": 2\n  }\n}\n",
+	}, entries)
+}
+
+func TestSplitLimits(t *testing.T) {
+	input := "start\na\nb\nc\nstart\nabcdefghij\n"
+	entries := split(t, models.MultilineConfig{EventStartPattern: `^start`, MaxLines: 2}, input)
+	assert.Equal(t, []string{"start\na\n", "b\nc\n", "start\nabcdefghij\n"}, entries)
+
+	entries = split(t, models.MultilineConfig{EventStartPattern: `^start`, MaxBytes: 10}, input)
+	assert.Equal(t, []string{"start\na\nb\n", "c\n", "start\n", "abcdefghij\n"}, entries)
+}
+
+func TestSplitTimeout(t *testing.T) {
+	s, err := New(&models.MultilineConfig{IndentedContinuation: true, TimeoutSeconds: 1})
+	require.NoError(t, err)
+	s.timeout = 10 * time.Millisecond
+
+	r, w := io.Pipe()
+	entries := make(chan string, 10)
+	done := make(chan error)
+	go func() {
+		done <- s.Split(bufio.NewReader(r), func(entry string) {
+			entries <- entry
+		})
+	}()
+	_, err = w.Write([]byte("first\n  continued\n"))
+	require.NoError(t, err)
+	// The event is emitted while waiting for the next line
+	select {
+	case entry := <-entries:
+		assert.Equal(t, "first\n  continued\n", entry)
+	case <-time.After(time.Second):
+		t.Fatal("event was not emitted after timeout")
+	}
+	_, err = w.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, <-done)
+	assert.Equal(t, "second\n", <-entries)
+	assert.Empty(t, entries)
+}
+
+func TestNewInvalid(t *testing.T) {
+	_, err := New(nil)
+	assert.Error(t, err)
+	_, err = New(&models.MultilineConfig{MaxLines: 10})
+	assert.Error(t, err)
+	_, err = New(&models.MultilineConfig{EventStartPattern: `^(`})
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package pantherlog
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"reflect"
+)
+
+const (
+	FieldEnrichmentJSON         = FieldPrefixJSON + "enrichment"
+	FieldThreatIntelMatchesJSON = FieldPrefixJSON + "threat_intel_matches"
+)
+
+// Enricher adds enrichment fields to a result based on the indicator values collected from the event.
+// Implementations must be safe for concurrent use.
+type Enricher interface {
+	Enrich(fields *EnrichmentFields, values *ValueBuffer)
+}
+
+// EnrichmentFields are the fields Panther adds to events when an Enricher is set on a Result.
+// These fields are part of the schema of all log types so that enriched events can be stored in any table.
+// nolint(lll)
+type EnrichmentFields struct {
+	PantherEnrichment         *Enrichment        `json:"p_enrichment,omitempty" description:"Panther added field with enrichment data for the indicator values of the row"`
+	PantherThreatIntelMatches []ThreatIntelMatch `json:"p_threat_intel_matches,omitempty" description:"Panther added field with indicator values of the row that matched threat intelligence"`
+}
+
+// IsEmpty checks if no enrichment fields were set
+func (f *EnrichmentFields) IsEmpty() bool {
+	return f.PantherEnrichment.IsEmpty() && len(f.PantherThreatIntelMatches) == 0
+}
+
+// Enrichment holds enrichment data for indicator values.
+type Enrichment struct {
+	GeoIP map[string]GeoIP `json:"geoip,omitempty" description:"Geolocation and network information for IP addresses keyed by IP address"`
+}
+
+// IsEmpty checks if there is no enrichment data
+func (e *Enrichment) IsEmpty() bool {
+	return e == nil || len(e.GeoIP) == 0
+}
+
+// SetGeoIP sets the geolocation data for an IP address
+func (e *Enrichment) SetGeoIP(ip string, geo GeoIP) {
+	if e.GeoIP == nil {
+		e.GeoIP = make(map[string]GeoIP)
+	}
+	e.GeoIP[ip] = geo
+}
+
+// GeoIP is the geolocation and network information for an IP address.
+type GeoIP struct {
+	CountryCode     string  `json:"country_code,omitempty" description:"ISO 3166-1 alpha-2 code of the country"`
+	Country         string  `json:"country,omitempty" description:"Country name"`
+	City            string  `json:"city,omitempty" description:"City name"`
+	Latitude        float64 `json:"latitude,omitempty" description:"Approximate latitude of the IP address"`
+	Longitude       float64 `json:"longitude,omitempty" description:"Approximate longitude of the IP address"`
+	ASN             uint32  `json:"asn,omitempty" description:"Autonomous system number"`
+	ASNOrganization string  `json:"asn_organization,omitempty" description:"Organization of the autonomous system"`
+}
+
+// ThreatIntelMatch is an indicator value that matched an entry in a threat intelligence list.
+type ThreatIntelMatch struct {
+	Indicator   string `json:"indicator" description:"The indicator value that matched"`
+	Field       string `json:"field" description:"The Panther field containing the indicator"`
+	Source      string `json:"source,omitempty" description:"The threat intelligence source of the match"`
+	Description string `json:"description,omitempty" description:"Description of the threat intelligence entry"`
+}
+
+var typEnrichmentFields = reflect.TypeOf(EnrichmentFields{})
@@ -0,0 +1,61 @@
+package pantherlog
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"reflect"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	FieldCloudWatchLogGroupJSON  = FieldPrefixJSON + "cloudwatch_log_group"
+	FieldCloudWatchLogStreamJSON = FieldPrefixJSON + "cloudwatch_log_stream"
+)
+
+// EnvelopeFields are the fields Panther adds to events that were unwrapped from a delivery envelope.
+// These fields are part of the schema of all log types since any log type can be delivered in an envelope.
+// nolint(lll)
+type EnvelopeFields struct {
+	PantherCloudWatchLogGroup  string `json:"p_cloudwatch_log_group,omitempty" description:"Panther added field with the CloudWatch log group of events delivered by a CloudWatch Logs subscription"`
+	PantherCloudWatchLogStream string `json:"p_cloudwatch_log_stream,omitempty" description:"Panther added field with the CloudWatch log stream of events delivered by a CloudWatch Logs subscription"`
+}
+
+// IsEmpty checks if no envelope fields were set
+func (f *EnvelopeFields) IsEmpty() bool {
+	return f.PantherCloudWatchLogGroup == "" && f.PantherCloudWatchLogStream == ""
+}
+
+var typEnvelopeFields = reflect.TypeOf(EnvelopeFields{})
+
+func writeEnvelopeFields(fields *EnvelopeFields, stream *jsoniter.Stream) {
+	more := false
+	if fields.PantherCloudWatchLogGroup != "" {
+		stream.WriteObjectField(FieldCloudWatchLogGroupJSON)
+		stream.WriteString(fields.PantherCloudWatchLogGroup)
+		more = true
+	}
+	if fields.PantherCloudWatchLogStream != "" {
+		if more {
+			stream.WriteMore()
+		}
+		stream.WriteObjectField(FieldCloudWatchLogStreamJSON)
+		stream.WriteString(fields.PantherCloudWatchLogStream)
+	}
+}
@@ -0,0 +1,41 @@
+package pantherlog
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// resolveEventTime returns the event time that is set on a result when its event is encoded.
+// The event is encoded once so that eventTimeEncoder resolves the time of the first non-zero `event_time:"true"` field,
+// the output is discarded.
+func resolveEventTime(event interface{}) time.Time {
+	// Indicator values are also collected while encoding, they are discarded along with the result
+	result := Result{
+		values: BlankValueBuffer(),
+	}
+	defer result.values.Recycle()
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+	stream.Attachment = &result
+	stream.WriteVal(event)
+	return result.PantherEventTime
+}
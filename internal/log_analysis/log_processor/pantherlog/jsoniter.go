@@ -83,6 +83,18 @@ func (e *resultEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
 	// TODO: Remove this once all parsers are ported to not use parsers.PantherLog
 	if result.EventIncludesPantherFields {
 		stream.WriteVal(result.Event)
+		if extendJSON(stream.Buffer()) {
+			stream.WriteObjectField(FieldIngestDelaySecondsJSON)
+			stream.WriteInt64(result.PantherIngestDelaySeconds)
+			stream.WriteObjectEnd()
+		}
+		if result.Enricher != nil {
+			e.writeLegacyEnrichmentFields(result, stream)
+		}
+		if !result.EnvelopeFields.IsEmpty() && extendJSON(stream.Buffer()) {
+			writeEnvelopeFields(&result.EnvelopeFields, stream)
+			stream.WriteObjectEnd()
+		}
 		return
 	}
 
@@ -132,6 +144,10 @@ func (*resultEncoder) writePantherFields(r *Result, stream *jsoniter.Stream) {
 
 	stream.WriteObjectField(FieldParseTimeJSON)
 	stream.WriteVal(r.PantherParseTime)
+	stream.WriteMore()
+
+	stream.WriteObjectField(FieldIngestDelaySecondsJSON)
+	stream.WriteInt64(r.PantherIngestDelaySeconds)
 
 	for id, values := range r.values.index {
 		if len(values) == 0 || id.IsCore() {
@@ -154,9 +170,64 @@ func (*resultEncoder) writePantherFields(r *Result, stream *jsoniter.Stream) {
 		stream.WriteArrayEnd()
 	}
 
+	if !r.EnvelopeFields.IsEmpty() {
+		stream.WriteMore()
+		writeEnvelopeFields(&r.EnvelopeFields, stream)
+	}
+
+	if r.Enricher != nil {
+		fields := EnrichmentFields{}
+		r.Enricher.Enrich(&fields, r.values)
+		if !fields.IsEmpty() {
+			stream.WriteMore()
+			writeEnrichmentFields(&fields, stream)
+		}
+	}
+
+	stream.WriteObjectEnd()
+}
+
+// legacyValueWriterTo is implemented by events that embed parsers.PantherLog to expose the values of their p_any fields.
+// TODO: Remove this once all parsers are ported to not use parsers.PantherLog
+type legacyValueWriterTo interface {
+	WriteAnyValuesTo(w ValueWriter)
+}
+
+// writeLegacyEnrichmentFields extends the JSON object of events that embed parsers.PantherLog with enrichment fields.
+func (*resultEncoder) writeLegacyEnrichmentFields(r *Result, stream *jsoniter.Stream) {
+	event, ok := r.Event.(legacyValueWriterTo)
+	if !ok {
+		return
+	}
+	values := BlankValueBuffer()
+	defer values.Recycle()
+	event.WriteAnyValuesTo(values)
+
+	fields := EnrichmentFields{}
+	r.Enricher.Enrich(&fields, values)
+	if fields.IsEmpty() || !extendJSON(stream.Buffer()) {
+		return
+	}
+	writeEnrichmentFields(&fields, stream)
 	stream.WriteObjectEnd()
 }
 
+func writeEnrichmentFields(fields *EnrichmentFields, stream *jsoniter.Stream) {
+	more := false
+	if !fields.PantherEnrichment.IsEmpty() {
+		stream.WriteObjectField(FieldEnrichmentJSON)
+		stream.WriteVal(fields.PantherEnrichment)
+		more = true
+	}
+	if len(fields.PantherThreatIntelMatches) > 0 {
+		if more {
+			stream.WriteMore()
+		}
+		stream.WriteObjectField(FieldThreatIntelMatchesJSON)
+		stream.WriteVal(fields.PantherThreatIntelMatches)
+	}
+}
+
 func extendJSON(data []byte) bool {
 	// Swap JSON object closing brace ('}') with comma (',') to extend the object
 	if n := len(data) - 1; 0 <= n && n < len(data) && data[n] == '}' {
@@ -322,6 +393,11 @@ func (*pantherExt) decorateIndicatorField(b *jsoniter.Binding, scanners ...Value
 			parent:  b.Encoder,
 			scanner: scanner,
 		}
+	case typ.ConvertibleTo(typStringSlice):
+		b.Encoder = &scanStringSliceEncoder{
+			parent:  b.Encoder,
+			scanner: scanner,
+		}
 	case reflect.PtrTo(typ).Implements(typStringer):
 		b.Encoder = &scanStringerEncoder{
 			parent:  b.Encoder,
@@ -445,3 +521,30 @@ func (enc *scanStringEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream
 		enc.scanner.ScanValues(values, input)
 	}
 }
+
+type scanStringSliceEncoder struct {
+	parent  jsoniter.ValEncoder
+	scanner ValueScanner
+}
+
+// IsEmpty implements jsoniter.ValEncoder interface
+func (enc *scanStringSliceEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return enc.parent.IsEmpty(ptr)
+}
+
+// Encode implements jsoniter.ValEncoder interface
+func (enc *scanStringSliceEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	enc.parent.Encode(ptr, stream)
+	if stream.Error != nil {
+		return
+	}
+	values, ok := stream.Attachment.(ValueWriter)
+	if !ok {
+		return
+	}
+	for _, input := range *((*[]string)(ptr)) {
+		if input != "" {
+			enc.scanner.ScanValues(values, input)
+		}
+	}
+}
@@ -92,6 +92,7 @@ func TestPantherExt_DecorateEncoder(t *testing.T) {
 		Baz  string        `json:"baz" panther:"baz"`
 		Qux  *string       `json:"qux" panther:"qux"`
 		Quux null.String   `json:"quux" panther:"quux"`
+		List []string      `json:"list" panther:"baz"`
 	}
 
 	v := T{
@@ -104,6 +105,7 @@ func TestPantherExt_DecorateEncoder(t *testing.T) {
 		Baz:  "ok",
 		Qux:  box.String("ok"),
 		Quux: null.FromString("ok"),
+		List: []string{"ok", "", "list"},
 	}
 
 	result := Result{
@@ -114,11 +116,11 @@ func TestPantherExt_DecorateEncoder(t *testing.T) {
 	stream.WriteVal(&v)
 	require.Equal(t, []string{"ok"}, result.values.Get(kindFoo), "foo")
 	require.Equal(t, []string{"ok"}, result.values.Get(kindBar), "bar")
-	require.Equal(t, []string{"ok"}, result.values.Get(kindBaz), "baz")
+	require.Equal(t, []string{"list", "ok"}, result.values.Get(kindBaz), "baz")
 	require.Equal(t, []string{"ok"}, result.values.Get(kindQux), "qux")
 	require.Equal(t, []string{"ok"}, result.values.Get(kindQuux), "quux")
 	actual := string(stream.Buffer())
-	require.Equal(t, `{"foo":"ok","bar":"ok","baz":"ok","qux":"ok","quux":"ok"}`, actual)
+	require.Equal(t, `{"foo":"ok","bar":"ok","baz":"ok","qux":"ok","quux":"ok","list":["ok","","list"]}`, actual)
 }
 
 func TestResultEncoder(t *testing.T) {
@@ -145,6 +147,7 @@ func TestResultEncoder(t *testing.T) {
 		},
 		Event: &event,
 	}
+	result.ApplyLateness(nil)
 	actual, err := jsoniter.MarshalToString(&result)
 	assert.NoError(err)
 	expect := fmt.Sprintf(`{
@@ -154,8 +157,107 @@ func TestResultEncoder(t *testing.T) {
 		"p_row_id": "id",
 		"p_event_time": "%s",
 		"p_parse_time": "%s",
+		"p_ingest_delay_seconds": 60,
 		"p_any_ip_addresses": ["1.1.1.1", "2.2.2.2"],
 		"p_log_type": "Foo.Bar"
 	}`, tm.In(loc).Format(time.RFC3339Nano), tm.UTC().Format(time.RFC3339Nano), now.UTC().Format(time.RFC3339Nano))
 	assert.JSONEq(expect, actual)
 }
+
+type testEnricher struct{}
+
+func (testEnricher) Enrich(fields *EnrichmentFields, values *ValueBuffer) {
+	for _, ip := range values.Get(FieldIPAddress) {
+		if ip == "1.1.1.1" {
+			fields.PantherThreatIntelMatches = append(fields.PantherThreatIntelMatches, ThreatIntelMatch{
+				Indicator: ip,
+				Field:     FieldNameJSON(FieldIPAddress),
+				Source:    "test",
+			})
+			continue
+		}
+		if fields.PantherEnrichment == nil {
+			fields.PantherEnrichment = &Enrichment{}
+		}
+		fields.PantherEnrichment.SetGeoIP(ip, GeoIP{CountryCode: "GR", ASN: 42})
+	}
+}
+
+func TestResultEncoderEnricher(t *testing.T) {
+	now := time.Now().UTC()
+	type T struct {
+		RemoteIP string `json:"remote_ip" panther:"ip"`
+		LocalIP  string `json:"local_ip" panther:"ip"`
+	}
+	result := Result{
+		CoreFields: CoreFields{
+			PantherLogType:   "Foo.Bar",
+			PantherRowID:     "id",
+			PantherParseTime: now,
+		},
+		Event: &T{
+			RemoteIP: "2.2.2.2",
+			LocalIP:  "1.1.1.1",
+		},
+		Enricher: testEnricher{},
+	}
+	assert := require.New(t)
+	actual, err := jsoniter.MarshalToString(&result)
+	assert.NoError(err)
+	expect := fmt.Sprintf(`{
+		"remote_ip":"2.2.2.2",
+		"local_ip":"1.1.1.1",
+		"p_row_id": "id",
+		"p_event_time": "%s",
+		"p_parse_time": "%s",
+		"p_ingest_delay_seconds": 0,
+		"p_any_ip_addresses": ["1.1.1.1", "2.2.2.2"],
+		"p_log_type": "Foo.Bar",
+		"p_enrichment": {"geoip": {"2.2.2.2": {"country_code": "GR", "asn": 42}}},
+		"p_threat_intel_matches": [{"indicator": "1.1.1.1", "field": "p_any_ip_addresses", "source": "test"}]
+	}`, now.Format(time.RFC3339Nano), now.Format(time.RFC3339Nano))
+	assert.JSONEq(expect, actual)
+
+	// No enrichment fields are written if there are no values to enrich
+	result.Event = &T{}
+	actual, err = jsoniter.MarshalToString(&result)
+	assert.NoError(err)
+	assert.NotContains(actual, FieldEnrichmentJSON)
+	assert.NotContains(actual, FieldThreatIntelMatchesJSON)
+}
+
+func TestResultEncoderEnvelopeFields(t *testing.T) {
+	now := time.Now().UTC()
+	type T struct {
+		RemoteIP string `json:"remote_ip" panther:"ip"`
+	}
+	result := Result{
+		CoreFields: CoreFields{
+			PantherLogType:   "Foo.Bar",
+			PantherRowID:     "id",
+			PantherParseTime: now,
+		},
+		EnvelopeFields: EnvelopeFields{
+			PantherCloudWatchLogGroup:  "group",
+			PantherCloudWatchLogStream: "stream",
+		},
+		Event: &T{
+			RemoteIP: "2.2.2.2",
+		},
+	}
+	assert := require.New(t)
+	actual, err := jsoniter.MarshalToString(&result)
+	assert.NoError(err)
+	expect := fmt.Sprintf(`{
+		"remote_ip":"2.2.2.2",
+		"p_row_id": "id",
+		"p_event_time": "%s",
+		"p_parse_time": "%s",
+		"p_ingest_delay_seconds": 0,
+		"p_any_ip_addresses": ["2.2.2.2"],
+		"p_log_type": "Foo.Bar",
+		"p_cloudwatch_log_group": "group",
+		"p_cloudwatch_log_stream": "stream"
+	}`, now.Format(time.RFC3339Nano), now.Format(time.RFC3339Nano))
+	assert.JSONEq(expect, actual)
+}
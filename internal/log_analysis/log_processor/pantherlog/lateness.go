@@ -0,0 +1,108 @@
+package pantherlog
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"time"
+)
+
+// FutureEventsAction decides what happens to events with timestamps too far ahead of their parse time
+type FutureEventsAction int
+
+const (
+	// FutureEventsAllow keeps the event time of future events
+	FutureEventsAllow FutureEventsAction = iota
+	// FutureEventsClamp sets the event time of future events to their parse time
+	FutureEventsClamp
+	// FutureEventsQuarantine marks future events so that they are stored apart from the log data
+	FutureEventsQuarantine
+)
+
+// LatenessPolicy decides the partition time of results that arrive late or have timestamps in the future.
+// It is applied once to each result before the result is encoded (see Result.ApplyLateness).
+type LatenessPolicy struct {
+	// PartitionByParseTime stores all events in the partition of their parse time
+	PartitionByParseTime bool
+	// MaxDelay is the maximum delay of an event to be stored in the partition of its event time.
+	// Events arriving later are stored in the partition of their parse time. Zero disables the limit.
+	MaxDelay time.Duration
+	// MaxFutureSkew is the tolerance for event times ahead of the parse time
+	MaxFutureSkew time.Duration
+	// FutureEvents is the action for events with event times ahead of the parse time by more than MaxFutureSkew
+	FutureEvents FutureEventsAction
+}
+
+// legacyEventTimeSetter is implemented by events that embed parsers.PantherLog to update their p_event_time field.
+// TODO: Remove this once all parsers are ported to not use parsers.PantherLog
+type legacyEventTimeSetter interface {
+	SetPantherEventTime(tm time.Time)
+}
+
+// ApplyLateness resolves the event time of a result and sets its ingest delay using a lateness policy.
+// Future events are clamped or quarantined according to the policy, a nil policy keeps the event time.
+// It must be called before the result is encoded since the encoder only writes the resulting fields.
+func (r *Result) ApplyLateness(p *LatenessPolicy) {
+	r.Lateness = p
+	if r.PantherEventTime.IsZero() {
+		r.PantherEventTime = resolveEventTime(r.Event)
+	}
+	if r.PantherEventTime.IsZero() {
+		r.PantherEventTime = r.PantherParseTime
+	}
+	// The delay is computed before clamping so that the original skew is recorded
+	delay := r.PantherParseTime.Sub(r.PantherEventTime)
+	r.PantherIngestDelaySeconds = int64(delay / time.Second)
+	r.quarantined = false
+	if p == nil || -delay <= p.MaxFutureSkew {
+		return
+	}
+	switch p.FutureEvents {
+	case FutureEventsClamp:
+		r.PantherEventTime = r.PantherParseTime
+		if event, ok := r.Event.(legacyEventTimeSetter); ok && r.EventIncludesPantherFields {
+			event.SetPantherEventTime(r.PantherEventTime)
+		}
+	case FutureEventsQuarantine:
+		r.quarantined = true
+	}
+}
+
+// PartitionTime returns the time used to decide the partition of the result
+func (r *Result) PartitionTime() time.Time {
+	eventTime := r.PantherEventTime
+	if eventTime.IsZero() {
+		return r.PantherParseTime
+	}
+	p := r.Lateness
+	if p == nil {
+		return eventTime
+	}
+	if p.PartitionByParseTime {
+		return r.PantherParseTime
+	}
+	if p.MaxDelay > 0 && r.PantherParseTime.Sub(eventTime) > p.MaxDelay {
+		return r.PantherParseTime
+	}
+	return eventTime
+}
+
+// IsQuarantined checks if the result was quarantined by its lateness policy
+func (r *Result) IsQuarantined() bool {
+	return r.quarantined
+}
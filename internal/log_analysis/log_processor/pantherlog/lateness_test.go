@@ -0,0 +1,128 @@
+package pantherlog
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatenessPolicy(t *testing.T) {
+	type T struct {
+		Time time.Time `json:"tm" event_time:"true"`
+	}
+	parseTime := time.Date(2020, 6, 5, 12, 0, 0, 0, time.UTC)
+	newResult := func(eventTime time.Time, policy *LatenessPolicy) *Result {
+		r := &Result{
+			CoreFields: CoreFields{
+				PantherLogType:   "Foo.Bar",
+				PantherRowID:     "id",
+				PantherParseTime: parseTime,
+			},
+			Event: &T{Time: eventTime},
+		}
+		r.ApplyLateness(policy)
+		return r
+	}
+
+	// Without a policy events are partitioned by event time
+	lastWeek := parseTime.Add(-7 * 24 * time.Hour)
+	r := newResult(lastWeek, nil)
+	require.Equal(t, lastWeek, r.PartitionTime())
+	require.Equal(t, int64(7*24*3600), r.PantherIngestDelaySeconds)
+	require.False(t, r.IsQuarantined())
+
+	// Events delayed more than MaxDelay are partitioned by parse time
+	r = newResult(lastWeek, &LatenessPolicy{MaxDelay: 24 * time.Hour})
+	require.Equal(t, parseTime, r.PartitionTime())
+	require.Equal(t, lastWeek, r.PantherEventTime)
+	r = newResult(parseTime.Add(-time.Hour), &LatenessPolicy{MaxDelay: 24 * time.Hour})
+	require.Equal(t, parseTime.Add(-time.Hour), r.PartitionTime())
+
+	// All events are partitioned by parse time
+	r = newResult(parseTime.Add(-time.Hour), &LatenessPolicy{PartitionByParseTime: true})
+	require.Equal(t, parseTime, r.PartitionTime())
+
+	// Future events within the allowed skew keep their event time
+	nextHour := parseTime.Add(time.Hour)
+	r = newResult(nextHour, &LatenessPolicy{MaxFutureSkew: time.Hour, FutureEvents: FutureEventsClamp})
+	require.Equal(t, nextHour, r.PantherEventTime)
+	require.Equal(t, int64(-3600), r.PantherIngestDelaySeconds)
+
+	// Future events are clamped to the parse time, the original skew is kept in the ingest delay
+	r = newResult(nextHour, &LatenessPolicy{FutureEvents: FutureEventsClamp})
+	require.Equal(t, parseTime, r.PantherEventTime)
+	require.Equal(t, parseTime, r.PartitionTime())
+	require.Equal(t, int64(-3600), r.PantherIngestDelaySeconds)
+	require.False(t, r.IsQuarantined())
+
+	// Future events are quarantined
+	r = newResult(nextHour, &LatenessPolicy{FutureEvents: FutureEventsQuarantine})
+	require.Equal(t, nextHour, r.PantherEventTime)
+	require.True(t, r.IsQuarantined())
+
+	// Encoding writes the fields set by the policy without changing the result
+	data, err := jsoniter.Marshal(r)
+	require.NoError(t, err)
+	require.Equal(t, int64(-3600), jsoniter.Get(data, FieldIngestDelaySecondsJSON).ToInt64())
+	require.Equal(t, nextHour, r.PantherEventTime)
+	require.True(t, r.IsQuarantined())
+	r = newResult(nextHour, &LatenessPolicy{FutureEvents: FutureEventsClamp})
+	data, err = jsoniter.Marshal(r)
+	require.NoError(t, err)
+	require.Equal(t, parseTime.Format(time.RFC3339Nano), jsoniter.Get(data, FieldEventTimeJSON).ToString())
+
+	// Events without event time are partitioned by parse time
+	r = newResult(time.Time{}, nil)
+	require.Equal(t, parseTime, r.PartitionTime())
+	require.Equal(t, int64(0), r.PantherIngestDelaySeconds)
+}
+
+func TestResolveEventTime(t *testing.T) {
+	type Inner struct {
+		Time time.Time `json:"tm" event_time:"true"`
+	}
+	type T struct {
+		Skipped time.Time        `json:"skipped"`
+		First   *Inner           `json:"first"`
+		Second  Inner            `json:"second"`
+		Ignored *time.Time       `json:"-" event_time:"true"`
+		List    []Inner          `json:"list"`
+		Map     map[string]Inner `json:"map"`
+	}
+	tm := time.Date(2020, 6, 5, 12, 0, 0, 0, time.UTC)
+	later := tm.Add(time.Hour)
+	require.Equal(t, tm, resolveEventTime(&T{Skipped: later, Second: Inner{Time: tm}}))
+	require.Equal(t, later, resolveEventTime(&T{First: &Inner{Time: later}, Second: Inner{Time: tm}}))
+	require.True(t, resolveEventTime(&T{Ignored: &tm}).IsZero())
+	require.True(t, resolveEventTime(&T{}).IsZero())
+	require.True(t, resolveEventTime(nil).IsZero())
+	// Fields in slices and maps are resolved the same way they are encoded
+	require.Equal(t, later, resolveEventTime(&T{List: []Inner{{}, {Time: later}}, Map: map[string]Inner{"a": {Time: tm}}}))
+	require.Equal(t, tm, resolveEventTime(&T{Map: map[string]Inner{"a": {Time: tm}}}))
+	// Encoding the event sets the same event time on its result
+	event := &T{List: []Inner{{Time: later}}, Second: Inner{Time: tm}}
+	r := &Result{Event: event}
+	_, err := jsoniter.Marshal(r)
+	require.NoError(t, err)
+	require.Equal(t, r.PantherEventTime, resolveEventTime(event))
+}
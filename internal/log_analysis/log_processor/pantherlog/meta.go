@@ -44,6 +44,7 @@ const (
 	CoreFieldRowID
 	CoreFieldSourceID
 	CoreFieldSourceLabel
+	CoreFieldIngestDelaySeconds
 )
 
 func coreField(id FieldID) reflect.StructField {
@@ -70,6 +71,11 @@ const (
 	FieldAWSInstanceID
 	FieldAWSARN
 	FieldAWSTag
+	FieldJA3Hash
+	FieldUsername
+	FieldEmail
+	FieldURL
+	FieldMACAddress
 )
 
 // ScanValues implements ValueScanner interface
@@ -80,22 +86,24 @@ func (id FieldID) ScanValues(w ValueWriter, input string) {
 // CoreFields are the 'core' fields Panther adds to each log.
 // External modules cannot add core fields.
 type CoreFields struct {
-	PantherEventTime   time.Time `json:"p_event_time" validate:"required" description:"Panther added standardized event time (UTC)"`
-	PantherParseTime   time.Time `json:"p_parse_time" validate:"required" description:"Panther added standardized log parse time (UTC)"`
-	PantherLogType     string    `json:"p_log_type" validate:"required" description:"Panther added field with type of log"`
-	PantherRowID       string    `json:"p_row_id" validate:"required" description:"Panther added field with unique id (within table)"`
-	PantherSourceID    string    `json:"p_source_id,omitempty" description:"Panther added field with the source id"`
-	PantherSourceLabel string    `json:"p_source_label,omitempty" description:"Panther added field with the source label"`
+	PantherEventTime          time.Time `json:"p_event_time" validate:"required" description:"Panther added standardized event time (UTC)"`
+	PantherParseTime          time.Time `json:"p_parse_time" validate:"required" description:"Panther added standardized log parse time (UTC)"`
+	PantherLogType            string    `json:"p_log_type" validate:"required" description:"Panther added field with type of log"`
+	PantherRowID              string    `json:"p_row_id" validate:"required" description:"Panther added field with unique id (within table)"`
+	PantherSourceID           string    `json:"p_source_id,omitempty" description:"Panther added field with the source id"`
+	PantherSourceLabel        string    `json:"p_source_label,omitempty" description:"Panther added field with the source label"`
+	PantherIngestDelaySeconds int64     `json:"p_ingest_delay_seconds" description:"Panther added field with the ingest delay in seconds"`
 }
 
 const (
 	// FieldPrefixJSON is the prefix for field names injected by panther to log events.
-	FieldPrefixJSON    = "p_"
-	FieldPrefix        = "Panther"
-	FieldLogTypeJSON   = FieldPrefixJSON + "log_type"
-	FieldRowIDJSON     = FieldPrefixJSON + "row_id"
-	FieldEventTimeJSON = FieldPrefixJSON + "event_time"
-	FieldParseTimeJSON = FieldPrefixJSON + "parse_time"
+	FieldPrefixJSON             = "p_"
+	FieldPrefix                 = "Panther"
+	FieldLogTypeJSON            = FieldPrefixJSON + "log_type"
+	FieldRowIDJSON              = FieldPrefixJSON + "row_id"
+	FieldEventTimeJSON          = FieldPrefixJSON + "event_time"
+	FieldParseTimeJSON          = FieldPrefixJSON + "parse_time"
+	FieldIngestDelaySecondsJSON = FieldPrefixJSON + "ingest_delay_seconds"
 )
 
 var (
@@ -104,12 +112,13 @@ var (
 	// Registered fields holds the distinct index of field ids to struct fields
 	registeredFields = map[FieldID]reflect.StructField{
 		// Reserve ids for core fields
-		CoreFieldEventTime:   coreField(CoreFieldEventTime),
-		CoreFieldParseTime:   coreField(CoreFieldParseTime),
-		CoreFieldRowID:       coreField(CoreFieldRowID),
-		CoreFieldLogType:     coreField(CoreFieldLogType),
-		CoreFieldSourceID:    coreField(CoreFieldSourceID),
-		CoreFieldSourceLabel: coreField(CoreFieldSourceLabel),
+		CoreFieldEventTime:          coreField(CoreFieldEventTime),
+		CoreFieldParseTime:          coreField(CoreFieldParseTime),
+		CoreFieldRowID:              coreField(CoreFieldRowID),
+		CoreFieldLogType:            coreField(CoreFieldLogType),
+		CoreFieldSourceID:           coreField(CoreFieldSourceID),
+		CoreFieldSourceLabel:        coreField(CoreFieldSourceLabel),
+		CoreFieldIngestDelaySeconds: coreField(CoreFieldIngestDelaySeconds),
 	}
 	// registeredFieldNamesJSON stores the JSON field names of registered field ids.
 	registeredFieldNamesJSON = map[FieldID]string{}
@@ -126,6 +135,14 @@ var (
 		"PantherLogType":   FieldNone,
 		FieldRowIDJSON:     FieldNone,
 		"PantherRowID":     FieldNone,
+		// Reserve field names for the ingest delay
+		FieldIngestDelaySecondsJSON: FieldNone,
+		"PantherIngestDelaySeconds": FieldNone,
+		// Reserve all field names for enrichment fields
+		FieldEnrichmentJSON:         FieldNone,
+		"PantherEnrichment":         FieldNone,
+		FieldThreatIntelMatchesJSON: FieldNone,
+		"PantherThreatIntelMatches": FieldNone,
 	}
 )
 
@@ -196,13 +213,43 @@ func init() {
 		NameJSON:    "p_any_aws_tags",
 		Description: "Panther added field with collection of AWS Tags associated with the row",
 	})
+	MustRegisterIndicator(FieldJA3Hash, FieldMeta{
+		Name:        "PantherAnyJA3Hashes",
+		NameJSON:    "p_any_ja3_hashes",
+		Description: "Panther added field with collection of JA3 and JA3S TLS fingerprints associated with the row",
+	})
+	MustRegisterIndicator(FieldUsername, FieldMeta{
+		Name:        "PantherAnyUsernames",
+		NameJSON:    "p_any_usernames",
+		Description: "Panther added field with collection of usernames associated with the row",
+	})
+	MustRegisterIndicator(FieldEmail, FieldMeta{
+		Name:        "PantherAnyEmails",
+		NameJSON:    "p_any_emails",
+		Description: "Panther added field with collection of email addresses associated with the row",
+	})
+	MustRegisterIndicator(FieldURL, FieldMeta{
+		Name:        "PantherAnyURLs",
+		NameJSON:    "p_any_urls",
+		Description: "Panther added field with collection of URLs associated with the row",
+	})
+	MustRegisterIndicator(FieldMACAddress, FieldMeta{
+		Name:        "PantherAnyMACAddresses",
+		NameJSON:    "p_any_mac_addresses",
+		Description: "Panther added field with collection of MAC addresses associated with the row",
+	})
 	MustRegisterScanner("ip", ValueScannerFunc(ScanIPAddress), FieldIPAddress)
 	MustRegisterScanner("domain", FieldDomainName, FieldDomainName)
 	MustRegisterScanner("md5", FieldMD5Hash, FieldMD5Hash)
 	MustRegisterScanner("sha1", FieldSHA1Hash, FieldSHA1Hash)
 	MustRegisterScanner("sha256", FieldSHA256Hash, FieldSHA256Hash)
+	MustRegisterScanner("ja3", FieldJA3Hash, FieldJA3Hash)
+	MustRegisterScanner("username", FieldUsername, FieldUsername)
+	MustRegisterScanner("email", ValueScannerFunc(ScanEmail), FieldEmail)
+	MustRegisterScanner("mac", ValueScannerFunc(ScanMACAddress), FieldMACAddress)
+	MustRegisterScanner("hash", ValueScannerFunc(ScanHash), FieldMD5Hash, FieldSHA1Hash, FieldSHA256Hash)
 	MustRegisterScanner("hostname", ValueScannerFunc(ScanHostname), FieldDomainName, FieldIPAddress)
-	MustRegisterScanner("url", ValueScannerFunc(ScanURL), FieldDomainName, FieldIPAddress)
+	MustRegisterScanner("url", ValueScannerFunc(ScanURL), FieldURL, FieldDomainName, FieldIPAddress)
 	MustRegisterScanner("trace_id", FieldTraceID, FieldTraceID)
 	MustRegisterScanner("net_addr", ValueScannerFunc(ScanNetworkAddress), FieldIPAddress, FieldDomainName)
 }
@@ -340,6 +387,10 @@ func BuildEventTypeSchema(eventType reflect.Type, indicators ...FieldID) (reflec
 		fields = append(fields, field)
 	}
 
+	// Enrichment and envelope fields go last so they can be added to existing tables
+	fields, _ = extendStructFields(fields, typEnrichmentFields)
+	fields, _ = extendStructFields(fields, typEnvelopeFields)
+
 	if err := checkDistinctNames(fields); err != nil {
 		return nil, err
 	}
@@ -35,7 +35,10 @@ func TestMetaEventStruct(t *testing.T) {
 	eventStruct := pantherlog.MustBuildEventSchema(&testEventMeta{}, pantherlog.FieldIPAddress)
 
 	columns, names := awsglue.InferJSONColumns(eventStruct, awsglue.GlueMappings...)
-	require.Equal(t, []string{}, names)
+	require.Equal(t, []string{
+		"asn", "asn_organization", "city", "country", "country_code", "description",
+		"field", "geoip", "indicator", "latitude", "longitude", "source",
+	}, names)
 	// nolint: lll,govet
 	require.Equal(t, []awsglue.Column{
 		{"foo", "string", "foo", false},
@@ -47,7 +50,12 @@ func TestMetaEventStruct(t *testing.T) {
 		{"p_row_id", "string", "Panther added field with unique id (within table)", true},
 		{"p_source_id", "string", "Panther added field with the source id", false},
 		{"p_source_label", "string", "Panther added field with the source label", false},
+		{"p_ingest_delay_seconds", "bigint", "Panther added field with the ingest delay in seconds", false},
 		{"p_any_ip_addresses", "array<string>", "Panther added field with collection of ip addresses associated with the row", false},
+		{"p_enrichment", "struct<geoip:map<string,struct<country_code:string,country:string,city:string,latitude:double,longitude:double,asn:bigint,asn_organization:string>>>", "Panther added field with enrichment data for the indicator values of the row", false},
+		{"p_threat_intel_matches", "array<struct<indicator:string,field:string,source:string,description:string>>", "Panther added field with indicator values of the row that matched threat intelligence", false},
+		{"p_cloudwatch_log_group", "string", "Panther added field with the CloudWatch log group of events delivered by a CloudWatch Logs subscription", false},
+		{"p_cloudwatch_log_stream", "string", "Panther added field with the CloudWatch log stream of events delivered by a CloudWatch Logs subscription", false},
 	}, columns)
 }
 
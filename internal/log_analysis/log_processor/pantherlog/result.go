@@ -29,6 +29,8 @@ import (
 type Result struct {
 	// Result extends all core panther fields
 	CoreFields
+	// Fields of the envelope the event was delivered in
+	EnvelopeFields
 	// The underlying event
 	Event interface{}
 	// Used for log events that embed parsers.PantherLog. This is a low-overhead, temporary work-around
@@ -39,6 +41,14 @@ type Result struct {
 	// This field is normally nil throughout the lifetime of results.
 	// It is populated temporarily by the custom jsoniter encoder for *Result to collect all indicator field values.
 	values *ValueBuffer
+	// Enricher adds enrichment fields based on the indicator values of the event when the result is encoded.
+	// If it is nil no enrichment fields are added.
+	Enricher Enricher
+	// Lateness decides the partition of the result, it is set by ApplyLateness.
+	// If it is nil results are partitioned by event time.
+	Lateness *LatenessPolicy
+	// quarantined is set by ApplyLateness if the lateness policy quarantines future events
+	quarantined bool
 }
 
 // WriteValues implements ValueWriter interface
@@ -92,12 +92,14 @@ func TestNewResultBuilder(t *testing.T) {
 	// Ensure event time is zero time
 	require.Equal(t, time.Time{}, result.PantherEventTime)
 	require.Equal(t, rowID, result.PantherRowID)
+	result.ApplyLateness(nil)
 	expect := fmt.Sprintf(`{
 		"p_row_id": "id",
 		"p_log_type": "TestEvent",
 		"p_event_time": "%s",
 		"ts": %d,
 		"p_parse_time": "%s",
+		"p_ingest_delay_seconds": 3600,
 		"@name": "event",
 		"ip": "1.1.1.1",
 		"hostname": "2.1.1.1",
@@ -140,12 +142,14 @@ func TestOldResults(t *testing.T) {
 	// Ensure event time is zero time
 	//require.Equal(t, time.Time{}, result.PantherEventTime)
 	require.Equal(t, rowID, result.PantherRowID)
+	result.ApplyLateness(nil)
 	expect := fmt.Sprintf(`{
 		"p_row_id": "id",
 		"p_log_type": "Foo",
 		"p_event_time": "%s",
 		"ts": "%s",
 		"p_parse_time": "%s",
+		"p_ingest_delay_seconds": 3600,
 		"@name": "event",
 		"ip": "1.1.1.1",
 		"hostname": "2.1.1.1",
@@ -160,6 +164,86 @@ func TestOldResults(t *testing.T) {
 	require.JSONEq(t, expect, string(actual))
 }
 
+type testEnricher struct{}
+
+func (testEnricher) Enrich(fields *pantherlog.EnrichmentFields, values *pantherlog.ValueBuffer) {
+	for _, ip := range values.Get(pantherlog.FieldIPAddress) {
+		fields.PantherThreatIntelMatches = append(fields.PantherThreatIntelMatches, pantherlog.ThreatIntelMatch{
+			Indicator: ip,
+			Field:     pantherlog.FieldNameJSON(pantherlog.FieldIPAddress),
+		})
+	}
+}
+
+func TestOldResultsEnricher(t *testing.T) {
+	now := time.Now().UTC()
+	event := oldEvent{
+		Name: box.String("event"),
+		IP:   box.String("1.1.1.1"),
+		PantherLog: parsers.PantherLog{
+			PantherLogType:        box.String("Foo"),
+			PantherRowID:          box.String("id"),
+			PantherEventTime:      (*timestamp.RFC3339)(&now),
+			PantherParseTime:      (*timestamp.RFC3339)(&now),
+			PantherAnyIPAddresses: parsers.NewPantherAnyString(),
+		},
+	}
+	event.SetEvent(&event)
+	parsers.AppendAnyString(event.PantherAnyIPAddresses, "1.1.1.1")
+
+	result := event.Result()
+	result.Enricher = testEnricher{}
+	expect := fmt.Sprintf(`{
+		"p_row_id": "id",
+		"p_log_type": "Foo",
+		"p_event_time": "%s",
+		"p_parse_time": "%s",
+		"p_ingest_delay_seconds": 0,
+		"@name": "event",
+		"ip": "1.1.1.1",
+		"p_any_ip_addresses": ["1.1.1.1"],
+		"p_threat_intel_matches": [{"indicator": "1.1.1.1", "field": "p_any_ip_addresses"}]
+	}`,
+		now.Format(awsglue.TimestampLayout),
+		now.Format(awsglue.TimestampLayout),
+	)
+	actual, err := buildAPI().Marshal(result)
+	require.NoError(t, err)
+	require.JSONEq(t, expect, string(actual))
+}
+
+func TestOldResultsEnvelopeFields(t *testing.T) {
+	now := time.Now().UTC()
+	event := oldEvent{
+		Name: box.String("event"),
+		PantherLog: parsers.PantherLog{
+			PantherLogType:   box.String("Foo"),
+			PantherRowID:     box.String("id"),
+			PantherEventTime: (*timestamp.RFC3339)(&now),
+			PantherParseTime: (*timestamp.RFC3339)(&now),
+		},
+	}
+	event.SetEvent(&event)
+
+	result := event.Result()
+	result.PantherCloudWatchLogGroup = "group"
+	expect := fmt.Sprintf(`{
+		"p_row_id": "id",
+		"p_log_type": "Foo",
+		"p_event_time": "%s",
+		"p_parse_time": "%s",
+		"p_ingest_delay_seconds": 0,
+		"@name": "event",
+		"p_cloudwatch_log_group": "group"
+	}`,
+		now.Format(awsglue.TimestampLayout),
+		now.Format(awsglue.TimestampLayout),
+	)
+	actual, err := buildAPI().Marshal(result)
+	require.NoError(t, err)
+	require.JSONEq(t, expect, string(actual))
+}
+
 func buildAPI() jsoniter.API {
 	api := jsoniter.Config{}.Froze()
 	api.RegisterExtension(&tcodec.Extension{})
@@ -19,7 +19,11 @@ package pantherlog
  */
 
 import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
 	"net"
+	"net/mail"
 	"net/url"
 	"strings"
 
@@ -116,7 +120,8 @@ func LookupScanner(name string) (scanner ValueScanner, fields []FieldID) {
 	return
 }
 
-// ScanURL scans a URL string for domain or ip address
+// ScanURL scans a URL string for domain or ip address.
+// Absolute URLs are also written as URL values.
 func ScanURL(dest ValueWriter, input string) {
 	if input == "" {
 		return
@@ -125,6 +130,9 @@ func ScanURL(dest ValueWriter, input string) {
 	if err != nil {
 		return
 	}
+	if u.Scheme != "" && u.Host != "" {
+		dest.WriteValues(FieldURL, input)
+	}
 	ScanHostname(dest, u.Hostname())
 }
 
@@ -148,6 +156,57 @@ func ScanIPAddress(w ValueWriter, input string) {
 	}
 }
 
+// ScanEmail scans `input` for an email address value.
+// Addresses with a display name (ie `Jane Doe <jane@example.com>`) are also accepted.
+func ScanEmail(w ValueWriter, input string) {
+	input = strings.TrimSpace(input)
+	if strings.IndexByte(input, '@') == -1 {
+		return
+	}
+	if addr, err := mail.ParseAddress(input); err == nil {
+		w.WriteValues(FieldEmail, addr.Address)
+	}
+}
+
+// ScanMACAddress scans `input` for a MAC address value.
+// Values are normalized to lower case hex digits separated by colons (ie `00:1a:2b:3c:4d:5e`).
+func ScanMACAddress(w ValueWriter, input string) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return
+	}
+	if addr, err := net.ParseMAC(input); err == nil {
+		w.WriteValues(FieldMACAddress, addr.String())
+	}
+}
+
+// ScanHash scans `input` for an MD5, SHA1 or SHA256 hex digest, detecting the algorithm by the length of the digest.
+func ScanHash(w ValueWriter, input string) {
+	input = strings.TrimSpace(input)
+	if !isHex(input) {
+		return
+	}
+	switch len(input) {
+	case 2 * md5.Size:
+		w.WriteValues(FieldMD5Hash, input)
+	case 2 * sha1.Size:
+		w.WriteValues(FieldSHA1Hash, input)
+	case 2 * sha256.Size:
+		w.WriteValues(FieldSHA256Hash, input)
+	}
+}
+
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case '0' <= c && c <= '9', 'a' <= c && c <= 'f', 'A' <= c && c <= 'F':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // checkIPAddress checks if an IP address is valid
 // TODO: [performance] Use a simpler method to check ip addresses than net.ParseIP to avoid allocations.
 func checkIPAddress(addr string) bool {
@@ -17,3 +17,64 @@ package pantherlog
  * You should have received a copy of the GNU Affero General Public License
  * along with this program.  If not, see <https://www.gnu.org/licenses/>.
  */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanHash(t *testing.T) {
+	const (
+		md5Hex    = "d41d8cd98f00b204e9800998ecf8427e"
+		sha1Hex   = "da39a3ee5e6b4b0d3255bfef95601890afd80709"
+		sha256Hex = "E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855"
+	)
+	b := ValueBuffer{}
+	ScanHash(&b, md5Hex)
+	ScanHash(&b, sha1Hex)
+	ScanHash(&b, sha256Hex)
+	ScanHash(&b, "not a hash")
+	ScanHash(&b, "zz1d8cd98f00b204e9800998ecf8427e")
+	require.Equal(t, map[FieldID][]string{
+		FieldMD5Hash:    {md5Hex},
+		FieldSHA1Hash:   {sha1Hex},
+		FieldSHA256Hash: {sha256Hex},
+	}, b.Inspect())
+}
+
+func TestScanEmail(t *testing.T) {
+	b := ValueBuffer{}
+	ScanEmail(&b, "jane@example.com")
+	ScanEmail(&b, " Joe Doe <joe@example.com> ")
+	ScanEmail(&b, "jane")
+	ScanEmail(&b, "@example.com")
+	ScanEmail(&b, "")
+	require.Equal(t, map[FieldID][]string{
+		FieldEmail: {"jane@example.com", "joe@example.com"},
+	}, b.Inspect())
+}
+
+func TestScanURL(t *testing.T) {
+	b := ValueBuffer{}
+	ScanURL(&b, "https://example.com/foo?bar=baz")
+	ScanURL(&b, "http://1.1.1.1:8080/")
+	ScanURL(&b, "/relative/path")
+	ScanURL(&b, "")
+	require.Equal(t, map[FieldID][]string{
+		FieldURL:        {"http://1.1.1.1:8080/", "https://example.com/foo?bar=baz"},
+		FieldDomainName: {"example.com"},
+		FieldIPAddress:  {"1.1.1.1"},
+	}, b.Inspect())
+}
+
+func TestScanMACAddress(t *testing.T) {
+	b := ValueBuffer{}
+	ScanMACAddress(&b, "00:1A:2B:3C:4D:5E")
+	ScanMACAddress(&b, "00-1a-2b-3c-4d-5f")
+	ScanMACAddress(&b, "001a.2b3c.4d60")
+	ScanMACAddress(&b, "not a mac")
+	require.Equal(t, map[FieldID][]string{
+		FieldMACAddress: {"00:1a:2b:3c:4d:5e", "00:1a:2b:3c:4d:5f", "00:1a:2b:3c:4d:60"},
+	}, b.Inspect())
+}
@@ -78,6 +78,8 @@ func (log *AccessCombined) updatePantherFields(p *parsers.PantherLog) {
 		// Handle cases where apache config has resolved addresses enabled
 		p.AppendAnyDomainNamePtrs(log.RemoteHostIPAddress)
 	}
+	p.AppendAnyUsernamePtrs(log.UserID)
+	p.AppendAnyURLPtrs(log.Referer)
 }
 
 type AccessCombinedParser struct{}
@@ -52,5 +52,8 @@ func TestAccessCombinedParser(t *testing.T) {
 	event.PantherLogType = aws.String(TypeAccessCombined)
 	event.SetEvent(&event)
 	event.AppendAnyIPAddress("127.0.0.1")
+	event.AppendAnyUsernames("frank")
+	event.AppendAnyURLs("http://www.example.com/start.html")
+	event.AppendAnyDomainNames("www.example.com")
 	testutil.CheckPantherParser(t, log, NewAccessCombinedParser(), &event.PantherLog)
 }
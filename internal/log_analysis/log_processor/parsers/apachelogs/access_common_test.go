@@ -49,6 +49,7 @@ func TestAccessCommonParser(t *testing.T) {
 	event.PantherLogType = aws.String(TypeAccessCommon)
 	event.SetEvent(&event)
 	event.AppendAnyIPAddress("127.0.0.1")
+	event.AppendAnyUsernames("frank")
 	testutil.CheckPantherParser(t, log, NewAccessCommonParser(), &event.PantherLog)
 }
 
@@ -72,5 +73,6 @@ func TestAccessCommonParseEmptySizeDash(t *testing.T) {
 	event.PantherLogType = aws.String(TypeAccessCommon)
 	event.SetEvent(&event)
 	event.AppendAnyIPAddress("127.0.0.1")
+	event.AppendAnyUsernames("frank")
 	testutil.CheckPantherParser(t, log, NewAccessCommonParser(), &event.PantherLog)
 }
@@ -19,7 +19,6 @@ package awslogs
  */
 
 import (
-	"errors"
 	"strings"
 
 	jsoniter "github.com/json-iterator/go"
@@ -123,8 +122,9 @@ func (p *CloudTrailParser) Parse(log string) (results []*parsers.PantherLog, err
 	// Use strings.Reader to avoid duplicate allocation of `log` as bytes
 	const bufferSize = 8192
 	iter := jsoniter.Parse(jsoniter.ConfigDefault, strings.NewReader(log), bufferSize)
-	// CloudTrail has all events in a single line inside an array at key `Records`
-	// Seek to Records key
+	// CloudTrail S3 objects have all events in a single line inside an array at key `Records`.
+	// When the object is split by the log processor each record is passed individually.
+	// Seek to Records key, it is not necessarily the first key of the object
 	const fieldNameRecords = `Records`
 	for key := iter.ReadObject(); key != ""; key = iter.ReadObject() {
 		if key != fieldNameRecords {
@@ -152,7 +152,20 @@ func (p *CloudTrailParser) Parse(log string) (results []*parsers.PantherLog, err
 	if err := iter.Error; err != nil {
 		return nil, err
 	}
-	return nil, errors.New(`missing 'Records' field`)
+	// A single record does not contain a `Records` key
+	return p.parseRecord(log)
+}
+
+func (p *CloudTrailParser) parseRecord(log string) ([]*parsers.PantherLog, error) {
+	event := CloudTrail{}
+	if err := jsoniter.UnmarshalFromString(log, &event); err != nil {
+		return nil, err
+	}
+	event.updatePantherFields(p)
+	if err := parsers.ValidateStruct(&event); err != nil {
+		return nil, err
+	}
+	return []*parsers.PantherLog{event.Log()}, nil
 }
 
 // LogType returns the log type supported by this parser
@@ -174,11 +187,13 @@ func (event *CloudTrail) updatePantherFields(p *CloudTrailParser) {
 	if event.UserIdentity != nil {
 		event.AppendAnyAWSAccountIdPtrs(event.UserIdentity.AccountID)
 		event.AppendAnyAWSARNPtrs(event.UserIdentity.ARN)
+		event.AppendAnyUsernamePtrs(event.UserIdentity.Username)
 
 		if event.UserIdentity.SessionContext != nil {
 			if event.UserIdentity.SessionContext.SessionIssuer != nil {
 				event.AppendAnyAWSAccountIdPtrs(event.UserIdentity.SessionContext.SessionIssuer.AccountID)
 				event.AppendAnyAWSARNPtrs(event.UserIdentity.SessionContext.SessionIssuer.Arn)
+				event.AppendAnyUsernamePtrs(event.UserIdentity.SessionContext.SessionIssuer.Username)
 			}
 		}
 	}
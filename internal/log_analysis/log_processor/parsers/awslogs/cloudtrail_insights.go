@@ -84,6 +84,10 @@ func (p *CloudTrailInsightParser) New() parsers.LogParser {
 
 // Parse returns the parsed events or nil if parsing failed
 func (p *CloudTrailInsightParser) Parse(log string) ([]*parsers.PantherLog, error) {
+	// When the S3 object is split by the log processor each record is passed individually
+	if jsoniter.Get([]byte(log), "Records").ValueType() == jsoniter.InvalidValue {
+		return p.parseRecord(log)
+	}
 	cloudTrailInsightRecords := &CloudTrailInsightRecords{}
 	err := jsoniter.UnmarshalFromString(log, cloudTrailInsightRecords)
 	if err != nil {
@@ -94,7 +98,7 @@ func (p *CloudTrailInsightParser) Parse(log string) ([]*parsers.PantherLog, erro
 		event.updatePantherFields(p)
 	}
 
-	if err := parsers.Validator.Struct(cloudTrailInsightRecords); err != nil {
+	if err := parsers.ValidateStruct(cloudTrailInsightRecords); err != nil {
 		return nil, err
 	}
 	result := make([]*parsers.PantherLog, len(cloudTrailInsightRecords.Records))
@@ -104,6 +108,18 @@ func (p *CloudTrailInsightParser) Parse(log string) ([]*parsers.PantherLog, erro
 	return result, nil
 }
 
+func (p *CloudTrailInsightParser) parseRecord(log string) ([]*parsers.PantherLog, error) {
+	event := CloudTrailInsight{}
+	if err := jsoniter.UnmarshalFromString(log, &event); err != nil {
+		return nil, err
+	}
+	event.updatePantherFields(p)
+	if err := parsers.ValidateStruct(&event); err != nil {
+		return nil, err
+	}
+	return []*parsers.PantherLog{event.Log()}, nil
+}
+
 // LogType returns the log type supported by this parser
 func (p *CloudTrailInsightParser) LogType() string {
 	return TypeCloudTrailInsight
@@ -19,6 +19,7 @@ package awslogs
  */
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -73,6 +74,14 @@ func TestCloudTrailLogGenerateDataKey(t *testing.T) {
 	expectedEvent.AppendAnyAWSAccountIds("888888888888", "777777777777")
 
 	checkCloudTrailLog(t, log, expectedEvent)
+
+	// Records split from an S3 object by the log processor are parsed individually
+	record := strings.TrimSuffix(strings.TrimPrefix(log, `{"Records": [`), `]}`)
+	checkCloudTrailLog(t, record, expectedEvent)
+
+	// The `Records` key is not necessarily the first key of the S3 object
+	leadingKey := `{"eventType":"AwsApiCall","Records": [` + record + `]}`
+	checkCloudTrailLog(t, leadingKey, expectedEvent)
 }
 
 func TestCloudTrailLogDecrypt(t *testing.T) {
@@ -134,6 +143,7 @@ func TestCloudTrailLogDecrypt(t *testing.T) {
 		"arn:aws:lambda:us-east-1:888888888888:function:panther-log-processor")
 	expectedEvent.AppendAnyAWSAccountIds("888888888888")
 	expectedEvent.AppendAnyIPAddress("1.2.3.4")
+	expectedEvent.AppendAnyUsernames("panther-app-LogProcessor-XXXXXXXXXXXX-FunctionRole-XXXXXXXXXX")
 
 	checkCloudTrailLog(t, log, expectedEvent)
 }
@@ -47,19 +47,11 @@ func (e *AWSExtractor) Extract(key, value gjson.Result) {
 		    arn:partition:service:region:account-id:resource-type/resource-id
 		    arn:partition:service:region:account-id:resource-type:resource-id
 		*/
-		parsedARN, err := arn.Parse(value.Str)
-		if err == nil {
+		if accountID, instanceID, ok := parseARN(value.Str); ok {
 			e.pl.AppendAnyAWSARNs(value.Str)
-			e.pl.AppendAnyAWSAccountIds(parsedARN.AccountID)
-			// instanceId: https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/iam-policy-structure.html#EC2_ARN_Format
-			if strings.HasPrefix(parsedARN.Resource, "instance/") {
-				slashIndex := strings.LastIndex(parsedARN.Resource, "/")
-				if slashIndex < len(parsedARN.Resource)-2 { // not if ends in "/"
-					instanceID := parsedARN.Resource[slashIndex+1:]
-					if strings.HasPrefix(instanceID, "i-") {
-						e.pl.AppendAnyAWSInstanceIds(instanceID)
-					}
-				}
+			e.pl.AppendAnyAWSAccountIds(accountID)
+			if instanceID != "" {
+				e.pl.AppendAnyAWSInstanceIds(instanceID)
 			}
 		}
 		return
@@ -113,3 +105,21 @@ func (e *AWSExtractor) Extract(key, value gjson.Result) {
 		e.pl.AppendAnyDomainNames(value.Str)
 	}
 }
+
+// parseARN returns the account id and the instance id (if any) embedded in an ARN
+func parseARN(value string) (accountID, instanceID string, ok bool) {
+	parsedARN, err := arn.Parse(value)
+	if err != nil {
+		return "", "", false
+	}
+	// instanceId: https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/iam-policy-structure.html#EC2_ARN_Format
+	if strings.HasPrefix(parsedARN.Resource, "instance/") {
+		slashIndex := strings.LastIndex(parsedARN.Resource, "/")
+		if slashIndex < len(parsedARN.Resource)-2 { // not if ends in "/"
+			if id := parsedARN.Resource[slashIndex+1:]; strings.HasPrefix(id, "i-") {
+				instanceID = id
+			}
+		}
+	}
+	return parsedARN.AccountID, instanceID, true
+}
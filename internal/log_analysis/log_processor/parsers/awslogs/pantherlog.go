@@ -103,3 +103,20 @@ func (pl *AWSPantherLog) AppendAnyAWSTags(values ...string) {
 	}
 	parsers.AppendAnyString(pl.PantherAnyAWSTags, values...)
 }
+
+// RemoveAny removes values from all the p_any_* fields, used when field values are redacted after parsing.
+// The account ids and instance ids of redacted ARNs are also removed, even if other fields contain them.
+func (pl *AWSPantherLog) RemoveAny(values ...string) {
+	derived := make([]string, 0, len(values))
+	for _, value := range values {
+		if accountID, instanceID, ok := parseARN(value); ok {
+			derived = append(derived, accountID, instanceID)
+		}
+	}
+	values = append(derived, values...)
+	pl.PantherLog.RemoveAny(values...)
+	parsers.RemoveAnyString(pl.PantherAnyAWSAccountIds, values...)
+	parsers.RemoveAnyString(pl.PantherAnyAWSInstanceIds, values...)
+	parsers.RemoveAnyString(pl.PantherAnyAWSARNs, values...)
+	parsers.RemoveAnyString(pl.PantherAnyAWSTags, values...)
+}
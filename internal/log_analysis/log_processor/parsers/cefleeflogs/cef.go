@@ -0,0 +1,231 @@
+package cefleeflogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/sysloglogs"
+)
+
+const cefPrefix = "CEF:"
+
+// CEF is an event in ArcSight Common Event Format.
+// Extension keys defined by the CEF standard map to columns named after the key, custom and vendor specific keys are
+// stored in the `extensions` column.
+// nolint:lll,maligned
+type CEF struct {
+	Version            pantherlog.Int64  `json:"cef_version" validate:"required" description:"Version of the CEF format."`
+	DeviceVendor       pantherlog.String `json:"device_vendor" validate:"required" description:"The vendor of the sending device."`
+	DeviceProduct      pantherlog.String `json:"device_product" validate:"required" description:"The product name of the sending device."`
+	DeviceVersion      pantherlog.String `json:"device_version" description:"The version of the sending device."`
+	DeviceEventClassID pantherlog.String `json:"device_event_class_id" validate:"required" description:"A unique identifier per event type (also known as Signature ID)."`
+	Name               pantherlog.String `json:"name" description:"A human-readable description of the event."`
+	Severity           pantherlog.String `json:"severity" description:"The importance of the event, either an integer 0-10 or one of Unknown, Low, Medium, High, Very-High."`
+
+	ReceiptTime                  pantherlog.Time   `json:"rt" tcodec:"cef" event_time:"true" description:"The time at which the event related to the activity was received."`
+	StartTime                    pantherlog.Time   `json:"start" tcodec:"cef" description:"The time when the activity the event referred to started."`
+	EndTime                      pantherlog.Time   `json:"end" tcodec:"cef" description:"The time at which the activity related to the event ended."`
+	DeviceAction                 pantherlog.String `json:"act" description:"Action taken by the device."`
+	ApplicationProtocol          pantherlog.String `json:"app" description:"Application level protocol."`
+	DeviceEventCategory          pantherlog.String `json:"cat" description:"Category assigned by the originating device."`
+	BaseEventCount               pantherlog.Int64  `json:"cnt" description:"A count associated with this event."`
+	DeviceDirection              pantherlog.String `json:"deviceDirection" description:"Direction of the observed communication (0 for inbound, 1 for outbound)."`
+	DeviceExternalID             pantherlog.String `json:"deviceExternalId" description:"A name that uniquely identifies the device generating this event."`
+	DeviceFacility               pantherlog.String `json:"deviceFacility" description:"The facility generating this event."`
+	DeviceInboundInterface       pantherlog.String `json:"deviceInboundInterface" description:"Interface on which the packet or data entered the device."`
+	DeviceOutboundInterface      pantherlog.String `json:"deviceOutboundInterface" description:"Interface on which the packet or data left the device."`
+	DeviceProcessName            pantherlog.String `json:"deviceProcessName" description:"Process name associated with the event."`
+	DeviceAddress                pantherlog.String `json:"dvc" panther:"ip" description:"IP address of the device generating the event."`
+	DeviceHostName               pantherlog.String `json:"dvchost" panther:"hostname" description:"Hostname of the device generating the event."`
+	DeviceMACAddress             pantherlog.String `json:"dvcmac" panther:"mac" description:"MAC address of the device generating the event."`
+	DeviceProcessID              pantherlog.Int64  `json:"dvcpid" description:"Process ID of the process on the device generating the event."`
+	DeviceTimeZone               pantherlog.String `json:"dtz" description:"The timezone for the device generating the event."`
+	SourceAddress                pantherlog.String `json:"src" panther:"ip" description:"IP address of the source of the event."`
+	SourceHostName               pantherlog.String `json:"shost" panther:"hostname" description:"Hostname of the source of the event."`
+	SourceMACAddress             pantherlog.String `json:"smac" panther:"mac" description:"MAC address of the source of the event."`
+	SourceNtDomain               pantherlog.String `json:"sntdom" description:"Windows domain name of the source address."`
+	SourcePort                   pantherlog.Uint16 `json:"spt" description:"Source port."`
+	SourceProcessID              pantherlog.Int64  `json:"spid" description:"Process ID of the source process."`
+	SourceProcessName            pantherlog.String `json:"sproc" description:"Name of the source process."`
+	SourceUserID                 pantherlog.String `json:"suid" description:"User ID associated with the source."`
+	SourceUserName               pantherlog.String `json:"suser" panther:"username" description:"User name associated with the source."`
+	SourceUserPrivileges         pantherlog.String `json:"spriv" description:"Typical values are Administrator, User, and Guest."`
+	SourceTranslatedAddress      pantherlog.String `json:"sourceTranslatedAddress" panther:"ip" description:"The translated source address (ie NAT)."`
+	SourceTranslatedPort         pantherlog.Uint16 `json:"sourceTranslatedPort" description:"The translated source port (ie NAT)."`
+	DestinationAddress           pantherlog.String `json:"dst" panther:"ip" description:"IP address of the destination."`
+	DestinationHostName          pantherlog.String `json:"dhost" panther:"hostname" description:"Hostname of the destination."`
+	DestinationMACAddress        pantherlog.String `json:"dmac" panther:"mac" description:"MAC address of the destination."`
+	DestinationNtDomain          pantherlog.String `json:"dntdom" description:"Windows domain name of the destination address."`
+	DestinationPort              pantherlog.Uint16 `json:"dpt" description:"Destination port."`
+	DestinationProcessID         pantherlog.Int64  `json:"dpid" description:"Process ID of the destination process."`
+	DestinationProcessName       pantherlog.String `json:"dproc" description:"Name of the destination process."`
+	DestinationUserID            pantherlog.String `json:"duid" description:"User ID associated with the destination."`
+	DestinationUserName          pantherlog.String `json:"duser" panther:"username" description:"User name associated with the destination."`
+	DestinationUserPrivileges    pantherlog.String `json:"dpriv" description:"Typical values are Administrator, User, and Guest."`
+	DestinationTranslatedAddress pantherlog.String `json:"destinationTranslatedAddress" panther:"ip" description:"The translated destination address (ie NAT)."`
+	DestinationTranslatedPort    pantherlog.Uint16 `json:"destinationTranslatedPort" description:"The translated destination port (ie NAT)."`
+	ExternalID                   pantherlog.String `json:"externalId" description:"The ID used by the originating device."`
+	FileHash                     pantherlog.String `json:"fileHash" panther:"hash" description:"Hash of a file."`
+	FileID                       pantherlog.String `json:"fileId" description:"An ID associated with a file."`
+	FileName                     pantherlog.String `json:"fname" description:"Name of the file only (without its path)."`
+	FilePath                     pantherlog.String `json:"filePath" description:"Full path to the file, including file name itself."`
+	FileSize                     pantherlog.Int64  `json:"fsize" description:"Size of the file."`
+	FileType                     pantherlog.String `json:"fileType" description:"Type of file (pipe, socket, etc.)"`
+	OldFileHash                  pantherlog.String `json:"oldFileHash" panther:"hash" description:"Hash of the old file."`
+	OldFileName                  pantherlog.String `json:"oldFileName" description:"Name of the old file."`
+	BytesIn                      pantherlog.Int64  `json:"in" description:"Number of bytes transferred inbound."`
+	BytesOut                     pantherlog.Int64  `json:"out" description:"Number of bytes transferred outbound."`
+	Message                      pantherlog.String `json:"msg" description:"An arbitrary message giving more details about the event."`
+	EventOutcome                 pantherlog.String `json:"outcome" description:"Displays the outcome, usually 'success' or 'failure'."`
+	TransportProtocol            pantherlog.String `json:"proto" description:"Identifies the Layer-4 protocol used."`
+	Reason                       pantherlog.String `json:"reason" description:"The reason an audit event was generated."`
+	RequestURL                   pantherlog.String `json:"request" panther:"url" description:"In the case of an HTTP request, this field contains the URL accessed."`
+	RequestClientApplication     pantherlog.String `json:"requestClientApplication" description:"The User-Agent associated with the request."`
+	RequestContext               pantherlog.String `json:"requestContext" description:"Description of the content from which the request originated (for example, HTTP Referrer)."`
+	RequestCookies               pantherlog.String `json:"requestCookies" description:"Cookies associated with the request."`
+	RequestMethod                pantherlog.String `json:"requestMethod" description:"The HTTP method used to access a URL."`
+
+	Extensions map[string]string  `json:"extensions" description:"Extension fields that are not part of the CEF standard dictionary (ie custom strings like cs1 or vendor specific keys)."`
+	Syslog     *sysloglogs.Header `json:"syslog" description:"The syslog header, if the event was sent over syslog."`
+}
+
+func (event *CEF) setSyslogHeader(header *sysloglogs.Header) {
+	event.Syslog = header
+}
+
+// parseCEF parses a CEF message (ie `CEF:Version|Device Vendor|Device Product|Device Version|Device Event Class ID|Name|Severity|Extension`)
+func parseCEF(msg string) (map[string]interface{}, map[string]string, error) {
+	const numHeaderFields = 7
+	header, extension := splitCEFHeader(msg, numHeaderFields)
+	if len(header) != numHeaderFields {
+		return nil, nil, errors.New("invalid CEF header")
+	}
+	fields := map[string]interface{}{
+		"cef_version":           strings.TrimPrefix(header[0], cefPrefix),
+		"device_vendor":         header[1],
+		"device_product":        header[2],
+		"device_version":        header[3],
+		"device_event_class_id": header[4],
+		"name":                  header[5],
+		"severity":              header[6],
+	}
+	return fields, parseCEFExtension(extension), nil
+}
+
+// splitCEFHeader splits the `|` separated header fields handling `\|` and `\\` escape sequences.
+// The rest of the message after the header fields is returned as the extension.
+func splitCEFHeader(msg string, n int) (header []string, extension string) {
+	var field strings.Builder
+	for i := 0; i < len(msg); i++ {
+		switch c := msg[i]; c {
+		case '\\':
+			if i+1 < len(msg) && (msg[i+1] == '|' || msg[i+1] == '\\') {
+				i++
+				field.WriteByte(msg[i])
+				continue
+			}
+			field.WriteByte(c)
+		case '|':
+			header = append(header, field.String())
+			field.Reset()
+			if len(header) == n {
+				return header, msg[i+1:]
+			}
+		default:
+			field.WriteByte(c)
+		}
+	}
+	// Allow the extension to be omitted along with the last separator
+	return append(header, field.String()), ""
+}
+
+// parseCEFExtension parses the space separated `key=value` pairs of a CEF extension.
+// Values can contain unescaped spaces so the end of a value is found by looking ahead for the next key.
+// A `=` is only considered the start of a new value if it is preceded by a space and a valid key, this way
+// values with unescaped `=` (ie URLs with query strings) are read as a whole.
+func parseCEFExtension(ext string) map[string]string {
+	fields := make(map[string]string)
+	key := ""
+	valueStart := 0
+	for i := 0; i < len(ext); i++ {
+		switch ext[i] {
+		case '\\':
+			// Skip the escaped character
+			i++
+		case '=':
+			keyStart := strings.LastIndexByte(ext[valueStart:i], ' ') + 1 + valueStart
+			if key != "" && keyStart == valueStart {
+				continue
+			}
+			if !isCEFKey(ext[keyStart:i]) {
+				continue
+			}
+			if key != "" {
+				fields[key] = unescapeCEFValue(strings.TrimRight(ext[valueStart:keyStart], " "))
+			}
+			key = ext[keyStart:i]
+			valueStart = i + 1
+		}
+	}
+	if key != "" {
+		fields[key] = unescapeCEFValue(strings.TrimRight(ext[valueStart:], " "))
+	}
+	return fields
+}
+
+func isCEFKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		switch c := key[i]; {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '_', c == '.', c == '-', c == '[', c == ']':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// unescapeCEFValue replaces the escape sequences allowed in extension values (`\=`, `\\`, `\n` and `\r`)
+func unescapeCEFValue(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c == '\\' && i+1 < len(value) {
+			i++
+			switch c = value[i]; c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,165 @@
+// Package cefleeflogs provides parsers for ArcSight CEF and IBM QRadar LEEF events.
+package cefleeflogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"reflect"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/tcodec"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/sysloglogs"
+)
+
+const (
+	TypeCEF  = "CEF.Event"
+	TypeLEEF = "LEEF.Event"
+
+	// Name of the column holding extension fields that are not mapped to a column
+	extensionsField = "extensions"
+	// Name of the column holding the syslog header of events sent over syslog
+	syslogField = "syslog"
+)
+
+func init() {
+	tcodec.MustRegister("cef", tcodec.Join(tcodec.TimeDecoderFunc(decodeTime), tcodec.LayoutCodec(rfc3339Nano)))
+
+	logtypes.MustRegister(
+		logtypes.Config{
+			Name:         TypeCEF,
+			Description:  `ArcSight Common Event Format (CEF) events, optionally sent over syslog`,
+			ReferenceURL: `https://www.microfocus.com/documentation/arcsight/arcsight-smartconnectors-8.3/cef-implementation-standard/`,
+			Schema:       pantherlog.MustBuildEventSchema(&CEF{}),
+			NewParser:    newParserFactory(TypeCEF, cefPrefix, func() syslogEvent { return &CEF{} }, parseCEF),
+		},
+		logtypes.Config{
+			Name:         TypeLEEF,
+			Description:  `IBM QRadar Log Event Extended Format (LEEF) events, optionally sent over syslog`,
+			ReferenceURL: `https://www.ibm.com/docs/en/dsm?topic=leef-overview`,
+			Schema:       pantherlog.MustBuildEventSchema(&LEEF{}),
+			NewParser:    newParserFactory(TypeLEEF, leefPrefix, func() syslogEvent { return &LEEF{} }, parseLEEF),
+		},
+	)
+}
+
+// syslogEvent is implemented by events that can be sent over syslog
+type syslogEvent interface {
+	setSyslogHeader(header *sysloglogs.Header)
+}
+
+// parseFunc parses a CEF or LEEF message into the header fields and the key/value pairs of the extension
+type parseFunc func(msg string) (header map[string]interface{}, extension map[string]string, err error)
+
+func newParserFactory(logType, prefix string, newEvent func() syslogEvent, parse parseFunc) parsers.Factory {
+	// Extension keys that map to a column of the event, all others are stored in the extensions map
+	columns := make(map[string]bool)
+	typ := reflect.TypeOf(newEvent()).Elem()
+	for i := 0; i < typ.NumField(); i++ {
+		name := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+		columns[name] = true
+	}
+	delete(columns, extensionsField)
+	delete(columns, syslogField)
+	return parsers.FactoryFunc(func(_ interface{}) (parsers.Interface, error) {
+		return &parser{
+			logType:  logType,
+			prefix:   prefix,
+			newEvent: newEvent,
+			parse:    parse,
+			columns:  columns,
+			json:     common.BuildJSON(),
+		}, nil
+	})
+}
+
+// parser handles the parts common to CEF and LEEF events.
+// Events are read either as is or prefixed by a syslog header.
+// The parsed fields are converted to a JSON object so that the event struct is decoded
+// with the same field types and timestamp codecs as any other JSON log type.
+type parser struct {
+	logType  string
+	prefix   string
+	newEvent func() syslogEvent
+	parse    parseFunc
+	columns  map[string]bool
+	json     jsoniter.API
+	builder  pantherlog.ResultBuilder
+}
+
+var _ parsers.Interface = (*parser)(nil)
+
+// ParseLog implements parsers.Interface
+func (p *parser) ParseLog(log string) ([]*parsers.Result, error) {
+	log = strings.TrimSpace(log)
+	pos := strings.Index(log, p.prefix)
+	if pos == -1 {
+		return nil, errors.Errorf("missing %q header", p.prefix)
+	}
+	var syslogHeader *sysloglogs.Header
+	if pos > 0 {
+		header, err := sysloglogs.ParseHeader(log[:pos])
+		if err != nil {
+			return nil, err
+		}
+		syslogHeader = header
+	}
+
+	fields, extension, err := p.parse(log[pos:])
+	if err != nil {
+		return nil, err
+	}
+	extensions := make(map[string]string)
+	for key, value := range extension {
+		if _, isHeader := fields[key]; !isHeader && p.columns[key] {
+			fields[key] = value
+			continue
+		}
+		extensions[key] = value
+	}
+	if len(extensions) > 0 {
+		fields[extensionsField] = extensions
+	}
+
+	data, err := p.json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	event := p.newEvent()
+	if err := p.json.Unmarshal(data, event); err != nil {
+		return nil, err
+	}
+	if syslogHeader != nil {
+		event.setSyslogHeader(syslogHeader)
+	}
+	if err := parsers.ValidateStruct(event); err != nil {
+		return nil, err
+	}
+	result, err := p.builder.BuildResult(p.logType, event)
+	if err != nil {
+		return nil, err
+	}
+	return []*parsers.Result{result}, nil
+}
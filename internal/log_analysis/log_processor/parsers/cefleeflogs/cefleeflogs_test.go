@@ -0,0 +1,97 @@
+package cefleeflogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
+)
+
+func TestCEFLEEFLogParsers(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, logtypes.DefaultRegistry(), "./testdata/cefleef_tests.yml")
+}
+
+func TestParseCEFExtension(t *testing.T) {
+	for _, tc := range []struct {
+		Name      string
+		Extension string
+		Expect    map[string]string
+	}{
+		{"empty", "", map[string]string{}},
+		{"spaces in values", "msg=foo bar baz src=10.0.0.1", map[string]string{"msg": "foo bar baz", "src": "10.0.0.1"}},
+		{"escapes", `msg=a\=b\\c\nd cs1=x\\`, map[string]string{"msg": "a=b\\c\nd", "cs1": `x\`}},
+		{"unescaped equals", "request=http://example.com/?a=b&c=d dpt=80", map[string]string{"request": "http://example.com/?a=b&c=d", "dpt": "80"}},
+		{"empty value", "msg= dpt=80", map[string]string{"msg": "", "dpt": "80"}},
+		{"trailing spaces", "msg=foo  ", map[string]string{"msg": "foo"}},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			require.Equal(t, tc.Expect, parseCEFExtension(tc.Extension))
+		})
+	}
+}
+
+func TestParseCEFHeader(t *testing.T) {
+	_, _, err := parseCEF("CEF:0|Security|threatmanager")
+	require.Error(t, err)
+	fields, ext, err := parseCEF(`CEF:0|Sec\\urity|threat\|manager|1.0|100|name|10`)
+	require.NoError(t, err)
+	require.Empty(t, ext)
+	require.Equal(t, `Sec\urity`, fields["device_vendor"])
+	require.Equal(t, `threat|manager`, fields["device_product"])
+	require.Equal(t, `10`, fields["severity"])
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for input, expect := range map[string]time.Time{
+		"1591367999305":            time.Date(2020, 6, 5, 14, 39, 59, 305*int(time.Millisecond), time.UTC),
+		"Jun 05 2020 14:39:59":     time.Date(2020, 6, 5, 14, 39, 59, 0, time.UTC),
+		"Jun 05 2020 14:39:59.305": time.Date(2020, 6, 5, 14, 39, 59, 305*int(time.Millisecond), time.UTC),
+		"Jun 5 14:39:59 UTC":       time.Date(2020, 6, 5, 14, 39, 59, 0, time.UTC),
+		"2020-06-05T14:39:59Z":     time.Date(2020, 6, 5, 14, 39, 59, 0, time.UTC),
+	} {
+		tm, err := parseTime(input, now)
+		require.NoError(t, err, input)
+		require.Equal(t, expect, tm, input)
+	}
+	_, err := parseTime("yesterday", now)
+	require.Error(t, err)
+}
+
+func TestParseLEEFDelimiter(t *testing.T) {
+	for input, expect := range map[string]string{
+		"":     "\t",
+		"^":    "^",
+		"x09":  "\t",
+		"0x09": "\t",
+		`\x7c`: "|",
+	} {
+		d, err := parseLEEFDelimiter(input)
+		require.NoError(t, err, input)
+		require.Equal(t, expect, d, input)
+	}
+	_, err := parseLEEFDelimiter("foo")
+	require.Error(t, err)
+}
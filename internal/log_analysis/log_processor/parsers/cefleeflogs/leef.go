@@ -0,0 +1,156 @@
+package cefleeflogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/sysloglogs"
+)
+
+const leefPrefix = "LEEF:"
+
+// LEEF is an event in IBM QRadar Log Event Extended Format.
+// Predefined LEEF attributes map to columns named after the attribute, custom keys are stored in the `extensions` column.
+// nolint:lll,maligned
+type LEEF struct {
+	Version        pantherlog.String `json:"leef_version" validate:"required" description:"Version of the LEEF format."`
+	Vendor         pantherlog.String `json:"vendor" validate:"required" description:"The vendor of the sending device."`
+	Product        pantherlog.String `json:"product" validate:"required" description:"The product name of the sending device."`
+	ProductVersion pantherlog.String `json:"product_version" description:"The version of the sending device."`
+	EventID        pantherlog.String `json:"event_id" validate:"required" description:"A unique identifier for the event type."`
+
+	DeviceTime             pantherlog.Time   `json:"devTime" tcodec:"cef" event_time:"true" description:"The time the event occurred on the device."`
+	DeviceTimeFormat       pantherlog.String `json:"devTimeFormat" description:"The Java SimpleDateFormat pattern of devTime."`
+	Category               pantherlog.String `json:"cat" description:"The category of the event."`
+	Severity               pantherlog.String `json:"sev" description:"The severity of the event, an integer between 1 (lowest) and 10 (highest)."`
+	Protocol               pantherlog.String `json:"proto" description:"Transport protocol of the event."`
+	SourceAddress          pantherlog.String `json:"src" panther:"ip" description:"Source IP address."`
+	DestinationAddress     pantherlog.String `json:"dst" panther:"ip" description:"Destination IP address."`
+	SourcePort             pantherlog.Uint16 `json:"srcPort" description:"Source port."`
+	DestinationPort        pantherlog.Uint16 `json:"dstPort" description:"Destination port."`
+	SourcePreNAT           pantherlog.String `json:"srcPreNAT" panther:"ip" description:"Source IP address of the message before Network Address Translation (NAT) occurred."`
+	DestinationPreNAT      pantherlog.String `json:"dstPreNAT" panther:"ip" description:"Destination IP address of the message before Network Address Translation (NAT) occurred."`
+	SourcePostNAT          pantherlog.String `json:"srcPostNAT" panther:"ip" description:"Source IP address of the message after Network Address Translation (NAT) occurred."`
+	DestinationPostNAT     pantherlog.String `json:"dstPostNAT" panther:"ip" description:"Destination IP address of the message after Network Address Translation (NAT) occurred."`
+	SourcePreNATPort       pantherlog.Uint16 `json:"srcPreNATPort" description:"Source port before Network Address Translation (NAT) occurred."`
+	DestinationPreNATPort  pantherlog.Uint16 `json:"dstPreNATPort" description:"Destination port before Network Address Translation (NAT) occurred."`
+	SourcePostNATPort      pantherlog.Uint16 `json:"srcPostNATPort" description:"Source port after Network Address Translation (NAT) occurred."`
+	DestinationPostNATPort pantherlog.Uint16 `json:"dstPostNATPort" description:"Destination port after Network Address Translation (NAT) occurred."`
+	SourceMAC              pantherlog.String `json:"srcMAC" panther:"mac" description:"Source MAC address."`
+	DestinationMAC         pantherlog.String `json:"dstMAC" panther:"mac" description:"Destination MAC address."`
+	SourceBytes            pantherlog.Int64  `json:"srcBytes" description:"Number of bytes sent from the source."`
+	DestinationBytes       pantherlog.Int64  `json:"dstBytes" description:"Number of bytes sent from the destination."`
+	TotalBytes             pantherlog.Int64  `json:"totalBytes" description:"Total number of bytes transferred."`
+	SourcePackets          pantherlog.Int64  `json:"srcPackets" description:"Number of packets sent from the source."`
+	DestinationPackets     pantherlog.Int64  `json:"dstPackets" description:"Number of packets sent from the destination."`
+	TotalPackets           pantherlog.Int64  `json:"totalPackets" description:"Total number of packets transferred."`
+	UserName               pantherlog.String `json:"usrName" panther:"username" description:"User name associated with the event."`
+	AccountName            pantherlog.String `json:"accountName" description:"The account name associated with the event."`
+	GroupName              pantherlog.String `json:"identGrpName" description:"Group name associated with the identity."`
+	IdentitySource         pantherlog.String `json:"identSrc" panther:"ip" description:"The source IP address of the identity event."`
+	IdentityHostName       pantherlog.String `json:"identHostName" panther:"hostname" description:"The host name associated with the identity event."`
+	IdentityNetBIOS        pantherlog.String `json:"identNetBios" description:"The NetBIOS name associated with the identity event."`
+	IdentityMAC            pantherlog.String `json:"identMAC" panther:"mac" description:"The MAC address associated with the identity event."`
+	Domain                 pantherlog.String `json:"domain" description:"The Windows domain or realm associated with the event."`
+	Realm                  pantherlog.String `json:"realm" description:"The realm associated with the event."`
+	Role                   pantherlog.String `json:"role" description:"The role associated with the event."`
+	Policy                 pantherlog.String `json:"policy" description:"The policy associated with the event."`
+	Resource               pantherlog.String `json:"resource" description:"The resource associated with the event."`
+	URL                    pantherlog.String `json:"url" panther:"url" description:"The URL associated with the event."`
+	VirtualSource          pantherlog.String `json:"vSrc" panther:"ip" description:"The virtual source IP address of the event."`
+	VirtualSourceName      pantherlog.String `json:"vSrcName" description:"The virtual source name of the event."`
+
+	Extensions map[string]string  `json:"extensions" description:"Custom event attributes that are not part of the predefined LEEF attributes."`
+	Syslog     *sysloglogs.Header `json:"syslog" description:"The syslog header, if the event was sent over syslog."`
+}
+
+func (event *LEEF) setSyslogHeader(header *sysloglogs.Header) {
+	event.Syslog = header
+}
+
+// parseLEEF parses a LEEF 1.0 (ie `LEEF:1.0|Vendor|Product|Version|EventID|Extension`) or
+// a LEEF 2.0 (ie `LEEF:2.0|Vendor|Product|Version|EventID|Delimiter|Extension`) message.
+func parseLEEF(msg string) (map[string]interface{}, map[string]string, error) {
+	parts := strings.SplitN(msg, "|", 6)
+	if len(parts) < 5 {
+		return nil, nil, errors.New("invalid LEEF header")
+	}
+	version := strings.TrimPrefix(parts[0], leefPrefix)
+	fields := map[string]interface{}{
+		"leef_version":    version,
+		"vendor":          parts[1],
+		"product":         parts[2],
+		"product_version": parts[3],
+		"event_id":        parts[4],
+	}
+	if len(parts) == 5 {
+		return fields, nil, nil
+	}
+	delimiter, extension := "\t", parts[5]
+	if strings.HasPrefix(version, "2") {
+		pos := strings.IndexByte(extension, '|')
+		if pos == -1 {
+			return nil, nil, errors.New("missing LEEF 2.0 delimiter")
+		}
+		d, err := parseLEEFDelimiter(extension[:pos])
+		if err != nil {
+			return nil, nil, err
+		}
+		delimiter, extension = d, extension[pos+1:]
+	}
+
+	attributes := make(map[string]string)
+	for _, attr := range strings.Split(extension, delimiter) {
+		pos := strings.IndexByte(attr, '=')
+		if pos <= 0 {
+			continue
+		}
+		attributes[strings.TrimSpace(attr[:pos])] = attr[pos+1:]
+	}
+	// Convert timestamps in a custom format so that they can be decoded along with the default formats
+	if format := attributes["devTimeFormat"]; format != "" && attributes["devTime"] != "" {
+		tm, err := parseJavaTime(format, attributes["devTime"])
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid LEEF devTime")
+		}
+		attributes["devTime"] = tm.Format(rfc3339Nano)
+	}
+	return fields, attributes, nil
+}
+
+// parseLEEFDelimiter reads the LEEF 2.0 delimiter that is either a single character or a hex value (ie `x09`, `0x09` or `\x09`)
+func parseLEEFDelimiter(s string) (string, error) {
+	switch {
+	case s == "":
+		return "\t", nil
+	case len(s) == 1:
+		return s, nil
+	}
+	hex := strings.TrimPrefix(strings.TrimLeft(strings.ToLower(s), `\0`), "x")
+	n, err := strconv.ParseUint(hex, 16, 8)
+	if err != nil || len(hex) == len(s) {
+		return "", errors.Errorf("invalid LEEF delimiter %q", s)
+	}
+	return string([]byte{byte(n)}), nil
+}
@@ -0,0 +1,115 @@
+package cefleeflogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const rfc3339Nano = time.RFC3339Nano
+
+// timeLayouts are the timestamp formats allowed by the CEF standard for `rt`, `start` and `end`.
+// LEEF uses the same formats for `devTime` when no `devTimeFormat` is set.
+// RFC3339 is also accepted as it is used for `devTime` values converted using `devTimeFormat`.
+var timeLayouts = []string{
+	rfc3339Nano,
+	"Jan 2 2006 15:04:05.000 MST",
+	"Jan 2 2006 15:04:05.000",
+	"Jan 2 2006 15:04:05 MST",
+	"Jan 2 2006 15:04:05",
+	"Jan 2 15:04:05.000 MST",
+	"Jan 2 15:04:05.000",
+	"Jan 2 15:04:05 MST",
+	"Jan 2 15:04:05",
+}
+
+// decodeTime decodes CEF/LEEF timestamps that are either milliseconds since UNIX epoch or one of `timeLayouts`
+func decodeTime(iter *jsoniter.Iterator) time.Time {
+	const opName = "ParseCEFTimestamp"
+	switch iter.WhatIsNext() {
+	case jsoniter.StringValue:
+		s := strings.TrimSpace(iter.ReadString())
+		if s == "" {
+			return time.Time{}
+		}
+		tm, err := parseTime(s, time.Now())
+		if err != nil {
+			iter.ReportError(opName, err.Error())
+			return time.Time{}
+		}
+		return tm
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		return time.Time{}
+	default:
+		iter.Skip()
+		iter.ReportError(opName, "invalid timestamp value")
+		return time.Time{}
+	}
+}
+
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if msec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(0, msec*int64(time.Millisecond)).UTC(), nil
+	}
+	var err error
+	for _, layout := range timeLayouts {
+		var tm time.Time
+		if tm, err = time.Parse(layout, s); err == nil {
+			// Layouts without a year assume the current year, same as RFC3164 syslog timestamps
+			if tm.Year() == 0 {
+				tm = tm.AddDate(now.Year(), 0, 0)
+			}
+			return tm.UTC(), nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// javaTimeFormat converts the most common Java SimpleDateFormat patterns used in LEEF `devTimeFormat` to a Go layout
+var javaTimeFormat = strings.NewReplacer(
+	"yyyy", "2006",
+	"yy", "06",
+	"MMM", "Jan",
+	"MM", "01",
+	"dd", "02",
+	"HH", "15",
+	"hh", "03",
+	"mm", "04",
+	"ss", "05",
+	"SSS", "000",
+	"a", "PM",
+	"XXX", "Z07:00",
+	"Z", "-0700",
+	"z", "MST",
+	"'", "",
+)
+
+// parseJavaTime parses a timestamp using a Java SimpleDateFormat pattern
+func parseJavaTime(format, value string) (time.Time, error) {
+	tm, err := time.Parse(javaTimeFormat.Replace(format), value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return tm.UTC(), nil
+}
@@ -0,0 +1,90 @@
+package duologs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// Authentication is a Duo authentication log event.
+// nolint:lll
+type Authentication struct {
+	TxID                  pantherlog.String `json:"txid" validate:"required" panther:"trace_id" description:"The transaction ID of the event."`
+	Timestamp             pantherlog.Time   `json:"timestamp" tcodec:"unix" description:"Unix timestamp of the event."`
+	ISOTimestamp          pantherlog.Time   `json:"isotimestamp" validate:"required" tcodec:"rfc3339" event_time:"true" description:"ISO8601 timestamp of the event."`
+	EventType             pantherlog.String `json:"event_type" validate:"required" description:"The type of activity logged (authentication or enrollment)."`
+	Result                pantherlog.String `json:"result" description:"The result of the authentication attempt (success, denied, failure, error or fraud)."`
+	Reason                pantherlog.String `json:"reason" description:"The reason for the authentication attempt result."`
+	Factor                pantherlog.String `json:"factor" description:"The authentication factor (ie duo_push, phone_call, passcode)."`
+	User                  *User             `json:"user" description:"Information about the authenticating user."`
+	Alias                 pantherlog.String `json:"alias" panther:"username" description:"The username alias used to log in. No value if the user logged in with their username instead of a username alias."`
+	Email                 pantherlog.String `json:"email" panther:"email" description:"The email address of the user, if known to Duo, otherwise none."`
+	Application           *Application      `json:"application" description:"Information about the application accessed."`
+	AccessDevice          *AccessDevice     `json:"access_device" description:"Browser, plugin, and operating system information for the endpoint used to access the Duo-protected resource."`
+	AuthDevice            *AuthDevice       `json:"auth_device" description:"Information about the device used to approve or deny authentication."`
+	OODSoftware           pantherlog.String `json:"ood_software" description:"If authentication was denied due to out-of-date software, shows the name of the software."`
+	TrustedEndpointStatus pantherlog.String `json:"trusted_endpoint_status" description:"Status of Trusted Endpoint (trusted, not trusted or unknown)."`
+}
+
+// User is the user of an authentication event.
+type User struct {
+	Key    pantherlog.String `json:"key" description:"The user's ID."`
+	Name   pantherlog.String `json:"name" panther:"username" description:"The user's username."`
+	Groups []string          `json:"groups" description:"Duo group membership information for the user."`
+}
+
+// Application is the application accessed.
+type Application struct {
+	Key  pantherlog.String `json:"key" description:"The application's integration_key."`
+	Name pantherlog.String `json:"name" description:"The application's name."`
+}
+
+// AccessDevice is the endpoint used to access a Duo-protected resource.
+// nolint:lll
+type AccessDevice struct {
+	Browser             pantherlog.String      `json:"browser" description:"The browser used to access the Duo-protected resource."`
+	BrowserVersion      pantherlog.String      `json:"browser_version" description:"The browser version."`
+	EPKey               pantherlog.String      `json:"epkey" description:"The unique identifier of the endpoint."`
+	FlashVersion        pantherlog.String      `json:"flash_version" description:"The Flash plugin version used, if present, otherwise uninstalled."`
+	Hostname            pantherlog.String      `json:"hostname" panther:"hostname" description:"The hostname, if present."`
+	IP                  pantherlog.String      `json:"ip" panther:"ip" description:"The access device's IP address."`
+	IsEncryptionEnabled pantherlog.String      `json:"is_encryption_enabled" description:"Reports the disk encryption state as detected by the Duo Device Health app (true, false or unknown)."`
+	IsFirewallEnabled   pantherlog.String      `json:"is_firewall_enabled" description:"Reports the firewall state as detected by the Duo Device Health app (true, false or unknown)."`
+	IsPasswordSet       pantherlog.String      `json:"is_password_set" description:"Reports the system password state as detected by the Duo Device Health app (true, false or unknown)."`
+	JavaVersion         pantherlog.String      `json:"java_version" description:"The Java plugin version used, if present, otherwise uninstalled."`
+	Location            *Location              `json:"location" description:"The GeoIP location of the access device."`
+	OS                  pantherlog.String      `json:"os" description:"The device operating system name."`
+	OSVersion           pantherlog.String      `json:"os_version" description:"The device operating system version."`
+	SecurityAgents      *pantherlog.RawMessage `json:"security_agents" description:"Reports the security agents present on the endpoint as detected by the Duo Device Health app."`
+}
+
+// AuthDevice is the device used to approve or deny an authentication.
+type AuthDevice struct {
+	IP       pantherlog.String `json:"ip" panther:"ip" description:"The IP address of the authentication device."`
+	Key      pantherlog.String `json:"key" description:"The Duo identifier of the authentication device."`
+	Location *Location         `json:"location" description:"The GeoIP location of the authentication device."`
+	Name     pantherlog.String `json:"name" description:"The name of the authentication device."`
+}
+
+// Location is a GeoIP location.
+type Location struct {
+	City    pantherlog.String `json:"city" description:"The city name."`
+	State   pantherlog.String `json:"state" description:"The state, county, province, or prefecture."`
+	Country pantherlog.String `json:"country" description:"The country."`
+}
@@ -0,0 +1,47 @@
+package duologs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+const (
+	// LogTypePrefix is the prefix of log types for the Duo Admin API logs
+	LogTypePrefix = "Duo"
+	// TypeAuthentication is the log type of Duo authentication logs
+	TypeAuthentication = LogTypePrefix + ".Authentication"
+)
+
+func init() {
+	logtypes.MustRegister(logtypes.Config{
+		Name:         TypeAuthentication,
+		Description:  `Duo authentication and enrollment events, as returned by version 2 of the Admin API authentication logs endpoint.`,
+		ReferenceURL: `https://duo.com/docs/adminapi#authentication-logs`,
+		Schema:       pantherlog.MustBuildEventSchema(&Authentication{}),
+		NewParser: &parsers.JSONParserFactory{
+			LogType: TypeAuthentication,
+			NewEvent: func() interface{} {
+				return &Authentication{}
+			},
+		},
+	})
+}
@@ -97,6 +97,7 @@ func TestGitLabAPI(t *testing.T) {
 	// panther fields
 	expectedEvent.PantherLogType = aws.String("GitLab.API")
 	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.RemoteIP)
+	expectedEvent.AppendAnyUsernames("root", "testuser")
 	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
 	checkGitLabAPI(t, log, expectedEvent)
 }
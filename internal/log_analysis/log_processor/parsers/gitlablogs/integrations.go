@@ -74,4 +74,5 @@ func (p *IntegrationsParser) LogType() string {
 
 func (event *Integrations) updatePantherFields(p *IntegrationsParser) {
 	event.SetCoreFields(p.LogType(), event.Time, event)
+	event.AppendAnyURLPtrs(event.ClientURL)
 }
@@ -56,6 +56,8 @@ func TestIntegrationsError(t *testing.T) {
 	// panther fields
 	expectedEvent.PantherLogType = aws.String("GitLab.Integrations")
 	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyURLs("http://jira.gitlap.com:8080")
+	expectedEvent.AppendAnyDomainNames("jira.gitlap.com")
 	checkIntegrations(t, log, expectedEvent)
 }
 func TestIntegrations(t *testing.T) {
@@ -84,6 +86,8 @@ func TestIntegrations(t *testing.T) {
 	// panther fields
 	expectedEvent.PantherLogType = aws.String("GitLab.Integrations")
 	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
+	expectedEvent.AppendAnyURLs("http://jira.example.com")
+	expectedEvent.AppendAnyDomainNames("jira.example.com")
 	checkIntegrations(t, log, expectedEvent)
 }
 func TestGitLabIntegrationsType(t *testing.T) {
@@ -104,4 +104,6 @@ func (p *ProductionParser) LogType() string {
 func (event *Production) updatePantherFields(p *ProductionParser) {
 	event.SetCoreFields(p.LogType(), event.Time, event)
 	event.AppendAnyIPAddressPtr(event.RemoteIP)
+	event.AppendAnyUsernamePtrs(event.UserName)
+	event.AppendAnyURLPtrs(event.Location)
 }
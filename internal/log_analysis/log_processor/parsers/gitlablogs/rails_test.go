@@ -91,6 +91,7 @@ func TestGitLabProduction(t *testing.T) {
 	// panther fields
 	expectedEvent.PantherLogType = box.String("GitLab.Production")
 	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.RemoteIP)
+	expectedEvent.AppendAnyUsernamePtrs(expectedEvent.UserName)
 	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
 	checkGitLabProduction(t, log, expectedEvent)
 }
@@ -162,6 +163,7 @@ func TestGitLabProductionException(t *testing.T) {
 	// panther fields
 	expectedEvent.PantherLogType = box.String("GitLab.Production")
 	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.RemoteIP)
+	expectedEvent.AppendAnyUsernamePtrs(expectedEvent.UserName)
 	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
 	checkGitLabProduction(t, log, expectedEvent)
 }
@@ -225,6 +227,8 @@ func TestGitLabProductionRedirect(t *testing.T) {
 	// panther fields
 	expectedEvent.PantherLogType = box.String("GitLab.Production")
 	expectedEvent.AppendAnyIPAddressPtr(expectedEvent.RemoteIP)
+	expectedEvent.AppendAnyUsernamePtrs(expectedEvent.UserName)
+	expectedEvent.AppendAnyURLs("http://34.222.254.254/users/sign_in")
 	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
 	checkGitLabProduction(t, log, expectedEvent)
 }
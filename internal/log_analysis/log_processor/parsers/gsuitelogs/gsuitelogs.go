@@ -0,0 +1,48 @@
+package gsuitelogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+const (
+	// LogTypePrefix is the prefix of log types for G Suite (Google Workspace) APIs
+	LogTypePrefix = "GSuite"
+	// TypeReports is the log type of G Suite (Google Workspace) Reports API activities
+	TypeReports = LogTypePrefix + ".Reports"
+)
+
+func init() {
+	logtypes.MustRegister(logtypes.Config{
+		Name: TypeReports,
+		Description: `G Suite (Google Workspace) Reports API activity records, including Admin console and Login audit activities.
+All applications of the Reports API share the same record format, the application is stored in 'id.applicationName'.`,
+		ReferenceURL: `https://developers.google.com/admin-sdk/reports/v1/reference/activities`,
+		Schema:       pantherlog.MustBuildEventSchema(&Reports{}),
+		NewParser: &parsers.JSONParserFactory{
+			LogType: TypeReports,
+			NewEvent: func() interface{} {
+				return &Reports{}
+			},
+		},
+	})
+}
@@ -0,0 +1,106 @@
+// Package kuberneteslogs provides parsers for Kubernetes logs
+package kuberneteslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+const LogTypePrefix = "Kubernetes"
+
+// TypeAudit registers and exports the logtype entry for Kubernetes.Audit logs
+var TypeAudit = logtypes.MustRegisterJSON(logtypes.Desc{
+	Name:         LogTypePrefix + ".Audit",
+	Description:  `Kubernetes API server audit events (audit.k8s.io/v1), including EKS control plane audit logs.`,
+	ReferenceURL: `https://kubernetes.io/docs/reference/config-api/apiserver-audit.v1/#audit-k8s-io-v1-Event`,
+}, func() interface{} {
+	return &Audit{}
+})
+
+// Audit is an audit event generated by the Kubernetes API server.
+// An event is generated for each stage of a request, the `auditID` is the same for all stages of the same request.
+// The amount of detail in an event depends on the audit `level` of the policy rule that matched the request.
+// nolint:lll,maligned
+type Audit struct {
+	Kind       pantherlog.String `json:"kind" description:"The kind of the object (always 'Event')."`
+	APIVersion pantherlog.String `json:"apiVersion" description:"The versioned schema of the event (ie 'audit.k8s.io/v1')."`
+
+	// The audit level determines which fields are set:
+	//
+	//   * Metadata - Log request metadata (requesting user, timestamp, resource, verb, etc.) but not request or response body.
+	//   * Request - Log event metadata and request body but not response body.
+	//   * RequestResponse - Log event metadata, request and response bodies.
+	Level pantherlog.String `json:"level" validate:"required,oneof=None Metadata Request RequestResponse" description:"AuditLevel at which event was generated (None, Metadata, Request or RequestResponse)."`
+	// The stage of the request handling when this event instance was generated:
+	//
+	//   * RequestReceived - The stage for events generated as soon as the audit handler receives the request.
+	//   * ResponseStarted - Once the response headers are sent, but before the response body is sent (only for long-running requests ie watch).
+	//   * ResponseComplete - Once the response body has been completed.
+	//   * Panic - Events generated when a panic occurred.
+	Stage                    pantherlog.String      `json:"stage" validate:"required,oneof=RequestReceived ResponseStarted ResponseComplete Panic" description:"Stage of the request handling when this event instance was generated."`
+	AuditID                  pantherlog.String      `json:"auditID" validate:"required" panther:"trace_id" description:"Unique audit ID, generated for each request."`
+	RequestURI               pantherlog.String      `json:"requestURI" validate:"required" description:"RequestURI is the request URI as sent by the client to a server."`
+	Verb                     pantherlog.String      `json:"verb" validate:"required" description:"Verb is the kubernetes verb associated with the request (ie get, list, watch, create, update, patch, delete, deletecollection). For non-resource requests, this is the lower-cased HTTP method."`
+	User                     *UserInfo              `json:"user" validate:"required" description:"Authenticated user information."`
+	ImpersonatedUser         *UserInfo              `json:"impersonatedUser" description:"Impersonated user information."`
+	SourceIPs                []string               `json:"sourceIPs" panther:"ip" description:"Source IPs, from where the request originated and intermediate proxies."`
+	UserAgent                pantherlog.String      `json:"userAgent" description:"UserAgent records the user agent string reported by the client."`
+	ObjectRef                *ObjectReference       `json:"objectRef" description:"Object reference this request is targeted at. Does not apply for List-type requests, or non-resource requests."`
+	ResponseStatus           *ResponseStatus        `json:"responseStatus" description:"The response status, populated even when the ResponseObject is not a Status type."`
+	RequestObject            *pantherlog.RawMessage `json:"requestObject" description:"API object from the request, in JSON format. Only set at Request level and higher."`
+	ResponseObject           *pantherlog.RawMessage `json:"responseObject" description:"API object returned in the response, in JSON. Only set at RequestResponse level."`
+	RequestReceivedTimestamp pantherlog.Time        `json:"requestReceivedTimestamp" tcodec:"rfc3339" event_time:"true" validate:"required" description:"Time the request reached the apiserver."`
+	StageTimestamp           pantherlog.Time        `json:"stageTimestamp" tcodec:"rfc3339" description:"Time the request reached current audit stage."`
+	Annotations              map[string]string      `json:"annotations" description:"An unstructured key value map stored with an audit event (ie authorization decision and reason)."`
+}
+
+// UserInfo holds the information about the user needed to implement the user.Info interface.
+// nolint:lll
+type UserInfo struct {
+	Username pantherlog.String                `json:"username" panther:"username" description:"The name that uniquely identifies this user among all active users."`
+	UID      pantherlog.String                `json:"uid" description:"A unique value that identifies this user across time."`
+	Groups   []string                         `json:"groups" description:"The names of groups this user is a part of."`
+	Extra    map[string]pantherlog.RawMessage `json:"extra" description:"Any additional information provided by the authenticator (ie the IAM ARN of the user on EKS)."`
+}
+
+// ObjectReference contains enough information to let you inspect or modify the referred object.
+// nolint:lll
+type ObjectReference struct {
+	Resource        pantherlog.String `json:"resource" description:"The resource type of the object (ie pods, secrets, configmaps)."`
+	Namespace       pantherlog.String `json:"namespace" description:"The namespace of the object."`
+	Name            pantherlog.String `json:"name" description:"The name of the object."`
+	UID             pantherlog.String `json:"uid" panther:"trace_id" description:"The unique id of the object."`
+	APIGroup        pantherlog.String `json:"apiGroup" description:"The name of the API group that contains the referred object. The empty string represents the core API group."`
+	APIVersion      pantherlog.String `json:"apiVersion" description:"The version of the API group that contains the referred object."`
+	ResourceVersion pantherlog.String `json:"resourceVersion" description:"The resource version of the object."`
+	Subresource     pantherlog.String `json:"subresource" description:"The subresource of the object (ie exec, log, status)."`
+}
+
+// ResponseStatus is the status returned by the API server
+// nolint:lll
+type ResponseStatus struct {
+	Code     pantherlog.Int32       `json:"code" description:"Suggested HTTP return code for this status, 0 if not set."`
+	Status   pantherlog.String      `json:"status" description:"Status of the operation. One of: Success or Failure."`
+	Message  pantherlog.String      `json:"message" description:"A human-readable description of the status of this operation."`
+	Reason   pantherlog.String      `json:"reason" description:"A machine-readable description of why this operation is in the Failure status."`
+	Details  *pantherlog.RawMessage `json:"details" description:"Extended data associated with the reason."`
+	Metadata *pantherlog.RawMessage `json:"metadata" description:"Standard list metadata."`
+}
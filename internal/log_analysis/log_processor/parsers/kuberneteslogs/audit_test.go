@@ -0,0 +1,39 @@
+package kuberneteslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
+)
+
+func TestAudit(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, logtypes.DefaultRegistry(), "./testdata/audit_tests.yml")
+}
+
+func TestAuditInvalidStage(t *testing.T) {
+	p, err := TypeAudit.NewParser(nil)
+	require.NoError(t, err)
+	_, err = p.ParseLog(`{"level":"Metadata","auditID":"1","stage":"Done","requestURI":"/api","verb":"get","user":{"username":"admin"},"requestReceivedTimestamp":"2020-10-20T17:08:32.284915Z"}`)
+	require.Error(t, err)
+}
@@ -49,6 +49,8 @@ func TestAccessLog(t *testing.T) {
 	expectedEvent.PantherLogType = aws.String("Nginx.Access")
 	expectedEvent.PantherEventTime = (*timestamp.RFC3339)(&expectedTime)
 	expectedEvent.AppendAnyIPAddress("180.76.15.143")
+	expectedEvent.AppendAnyURLs("https://domain1.com/?p=1")
+	expectedEvent.AppendAnyDomainNames("domain1.com")
 
 	checkAccessLog(t, log, expectedEvent)
 }
@@ -0,0 +1,47 @@
+package oktalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+const (
+	// LogTypePrefix groups the Okta log types under a common name
+	LogTypePrefix = "Okta"
+	// TypeSystemLog is the log type of Okta System Log events
+	TypeSystemLog = LogTypePrefix + ".SystemLog"
+)
+
+func init() {
+	logtypes.MustRegister(logtypes.Config{
+		Name:         TypeSystemLog,
+		Description:  `Okta System Log events for authentication, user lifecycle and administrative activity in an Okta organization.`,
+		ReferenceURL: `https://developer.okta.com/docs/reference/api/system-log/#logevent-object`,
+		Schema:       pantherlog.MustBuildEventSchema(&SystemLog{}),
+		NewParser: &parsers.JSONParserFactory{
+			LogType: TypeSystemLog,
+			NewEvent: func() interface{} {
+				return &SystemLog{}
+			},
+		},
+	})
+}
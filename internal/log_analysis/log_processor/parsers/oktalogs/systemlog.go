@@ -0,0 +1,150 @@
+package oktalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// SystemLog is an event of the Okta System Log API.
+// nolint:lll
+type SystemLog struct {
+	UUID                  pantherlog.String      `json:"uuid" validate:"required" panther:"trace_id" description:"Unique identifier for an individual event"`
+	Published             pantherlog.Time        `json:"published" validate:"required" tcodec:"rfc3339" event_time:"true" description:"Timestamp when the event is published"`
+	EventType             pantherlog.String      `json:"eventType" validate:"required" description:"Type of event that is published"`
+	Version               pantherlog.String      `json:"version" description:"Versioning indicator"`
+	Severity              pantherlog.String      `json:"severity" description:"Indicates how severe the event is: DEBUG, INFO, WARN, ERROR"`
+	LegacyEventType       pantherlog.String      `json:"legacyEventType" description:"Associated Events API Action objectType attribute value"`
+	DisplayMessage        pantherlog.String      `json:"displayMessage" description:"The display message for an event"`
+	Actor                 *Actor                 `json:"actor" description:"Describes the entity that performed an action"`
+	Client                *Client                `json:"client" description:"The client that requested an action"`
+	Request               *Request               `json:"request" description:"The request that initiated an action"`
+	Outcome               *Outcome               `json:"outcome" description:"The outcome of an action"`
+	Target                []Actor                `json:"target" description:"Zero or more targets of an action"`
+	Transaction           *Transaction           `json:"transaction" description:"The transaction details of an action"`
+	DebugContext          *DebugContext          `json:"debugContext" description:"The debug request data of an action"`
+	AuthenticationContext *AuthenticationContext `json:"authenticationContext" description:"The authentication data of an action"`
+	SecurityContext       *SecurityContext       `json:"securityContext" description:"The security data of an action"`
+}
+
+// Actor describes an entity (ie a user or an app) that performed an action or is the target of an action.
+// nolint:lll
+type Actor struct {
+	ID          pantherlog.String                `json:"id" description:"ID of actor"`
+	Type        pantherlog.String                `json:"type" description:"Type of actor"`
+	AlternateID pantherlog.String                `json:"alternateId" panther:"username,email" description:"Alternative ID of actor (ie the login of a user)"`
+	DisplayName pantherlog.String                `json:"displayName" description:"Display name of actor"`
+	DetailEntry map[string]pantherlog.RawMessage `json:"detailEntry" description:"Details about actor"`
+}
+
+// Client is the client that requested an action.
+// nolint:lll
+type Client struct {
+	ID                  pantherlog.String    `json:"id" description:"For OAuth requests this is the id of the OAuth client making the request. For SSWS token requests, this is the id of the agent making the request."`
+	UserAgent           *UserAgent           `json:"userAgent" description:"The user agent used by an actor to perform an action"`
+	GeographicalContext *GeographicalContext `json:"geographicalContext" description:"The physical location where the client made its request from"`
+	Zone                pantherlog.String    `json:"zone" description:"The name of the Zone that the client's location is mapped to"`
+	IPAddress           pantherlog.String    `json:"ipAddress" panther:"ip" description:"IP address that the client made its request from"`
+	Device              pantherlog.String    `json:"device" description:"Type of device that the client operated from (ie Computer)"`
+}
+
+// UserAgent is the user agent used by an actor to perform an action.
+// nolint:lll
+type UserAgent struct {
+	Browser      pantherlog.String `json:"browser" description:"If the client is a web browser, this field identifies the type of web browser (ie CHROME, FIREFOX)"`
+	OS           pantherlog.String `json:"os" description:"The Operating System the client runs on (ie Windows 10)"`
+	RawUserAgent pantherlog.String `json:"rawUserAgent" description:"A raw string representation of the user agent, formatted according to section 5.5.3 of HTTP/1.1 Semantics and Content"`
+}
+
+// GeographicalContext is the physical location of a client.
+// nolint:lll
+type GeographicalContext struct {
+	City        pantherlog.String `json:"city" description:"The city encompassing the area containing the geolocation coordinates, if available (ie Seattle, San Francisco)"`
+	State       pantherlog.String `json:"state" description:"Full name of the state/province encompassing the area containing the geolocation coordinates (ie Montana, Incheon)"`
+	Country     pantherlog.String `json:"country" description:"Full name of the country encompassing the area containing the geolocation coordinates (ie France, Uganda)"`
+	PostalCode  pantherlog.String `json:"postalCode" description:"Postal code of the area encompassing the geolocation coordinates"`
+	Geolocation *Geolocation      `json:"geolocation" description:"Contains the geolocation coordinates (latitude, longitude)"`
+}
+
+// Geolocation contains geolocation coordinates
+type Geolocation struct {
+	Latitude  pantherlog.Float64 `json:"lat" description:"Latitude"`
+	Longitude pantherlog.Float64 `json:"lon" description:"Longitude"`
+}
+
+// Request is the request that initiated an action.
+type Request struct {
+	IPChain []IPAddress `json:"ipChain" description:"If the incoming request passes through any proxies, the IP addresses of those proxies are stored here in the format (clientIp, proxy1, proxy2, ...)."`
+}
+
+// IPAddress describes an IP address used in a request.
+// nolint:lll
+type IPAddress struct {
+	IP                  pantherlog.String    `json:"ip" panther:"ip" description:"IP address"`
+	GeographicalContext *GeographicalContext `json:"geographicalContext" description:"Geographical context of the IP address"`
+	Version             pantherlog.String    `json:"version" description:"IP address version (V4 or V6)"`
+	Source              pantherlog.String    `json:"source" description:"Details regarding the source"`
+}
+
+// Outcome is the outcome of an action.
+// nolint:lll
+type Outcome struct {
+	Result pantherlog.String `json:"result" description:"Result of the action: SUCCESS, FAILURE, SKIPPED, ALLOW, DENY, CHALLENGE, UNKNOWN"`
+	Reason pantherlog.String `json:"reason" description:"Reason for the result, for example INVALID_CREDENTIALS"`
+}
+
+// Transaction describes the transaction details of an action.
+type Transaction struct {
+	ID     pantherlog.String                `json:"id" panther:"trace_id" description:"Unique identifier for this transaction"`
+	Type   pantherlog.String                `json:"type" description:"Describes the kind of transaction: WEB or JOB"`
+	Detail map[string]pantherlog.RawMessage `json:"detail" description:"Details for this transaction"`
+}
+
+// DebugContext holds additional information for debugging or troubleshooting an event.
+type DebugContext struct {
+	DebugData map[string]pantherlog.RawMessage `json:"debugData" description:"Dynamic field containing miscellaneous information dependent on the event type"`
+}
+
+// AuthenticationContext holds authentication data of an action.
+// nolint:lll
+type AuthenticationContext struct {
+	AuthenticationProvider pantherlog.String `json:"authenticationProvider" description:"The system that proves the identity of an actor using the credentials provided to it"`
+	AuthenticationStep     pantherlog.Int32  `json:"authenticationStep" description:"The zero-based step number in the authentication pipeline. Currently unused and always set to 0."`
+	CredentialProvider     pantherlog.String `json:"credentialProvider" description:"A credential provider is a software service that manages identities and their associated credentials"`
+	CredentialType         pantherlog.String `json:"credentialType" description:"The underlying technology/scheme used in the credential"`
+	ExternalSessionID      pantherlog.String `json:"externalSessionId" panther:"trace_id" description:"A proxy for the actor's session ID"`
+	Interface              pantherlog.String `json:"interface" description:"The third party user interface that the actor authenticates through, if any"`
+	Issuer                 *Issuer           `json:"issuer" description:"The specific software entity that created and issued the credential"`
+}
+
+// Issuer is the software entity that created and issued a credential.
+type Issuer struct {
+	ID   pantherlog.String `json:"id" description:"Varies depending on the type of authentication"`
+	Type pantherlog.String `json:"type" description:"The type of the issuer"`
+}
+
+// SecurityContext holds security data of an action.
+// nolint:lll
+type SecurityContext struct {
+	AsNumber pantherlog.Int64  `json:"asNumber" description:"Autonomous system number associated with the autonomous system that the event request was sourced to"`
+	AsOrg    pantherlog.String `json:"asOrg" description:"Organization associated with the autonomous system that the event request was sourced to"`
+	ISP      pantherlog.String `json:"isp" description:"Internet service provider used to send the event's request"`
+	Domain   pantherlog.String `json:"domain" panther:"domain" description:"The domain name associated with the IP address of the inbound event request"`
+	IsProxy  pantherlog.Bool   `json:"isProxy" description:"Specifies whether an event's request is from a known proxy"`
+}
@@ -65,6 +65,20 @@ type PantherLog struct {
 	PantherAnySHA1Hashes   *PantherAnyString `json:"p_any_sha1_hashes,omitempty" description:"Panther added field with collection of SHA1 hashes associated with the row"`
 	PantherAnyMD5Hashes    *PantherAnyString `json:"p_any_md5_hashes,omitempty" description:"Panther added field with collection of MD5 hashes associated with the row"`
 	PantherAnySHA256Hashes *PantherAnyString `json:"p_any_sha256_hashes,omitempty" description:"Panther added field with collection of SHA256 hashes of any algorithm associated with the row"`
+
+	// enrichment (set when the result is encoded)
+	pantherlog.EnrichmentFields
+	// envelope (set when the result is encoded)
+	pantherlog.EnvelopeFields
+
+	// optional (any)
+	PantherAnyUsernames    *PantherAnyString `json:"p_any_usernames,omitempty" description:"Panther added field with collection of usernames associated with the row"`
+	PantherAnyEmails       *PantherAnyString `json:"p_any_emails,omitempty" description:"Panther added field with collection of email addresses associated with the row"`
+	PantherAnyURLs         *PantherAnyString `json:"p_any_urls,omitempty" description:"Panther added field with collection of URLs associated with the row"`
+	PantherAnyMACAddresses *PantherAnyString `json:"p_any_mac_addresses,omitempty" description:"Panther added field with collection of MAC addresses associated with the row"`
+
+	// ingest delay (set when the result is encoded)
+	PantherIngestDelaySeconds *int64 `json:"p_ingest_delay_seconds,omitempty" description:"Panther added field with the ingest delay in seconds"`
 }
 
 type PantherAnyString struct { // needed to declare as struct (rather than map) for CF generation
@@ -154,6 +168,11 @@ func (pl *PantherLog) SetPantherSource(id, label string) {
 	pl.PantherSourceID = box.NonEmpty(id)
 }
 
+// SetPantherEventTime updates the event time, used when the lateness policy of a log type clamps the event time
+func (pl *PantherLog) SetPantherEventTime(tm time.Time) {
+	pl.PantherEventTime = (*timestamp.RFC3339)(&tm)
+}
+
 // AppendAnyIPAddressPtr returns true if the IP address was successfully appended,
 // otherwise false if the value was not an IP
 func (pl *PantherLog) AppendAnyIPAddressPtr(value *string) bool {
@@ -257,6 +276,102 @@ func (pl *PantherLog) AppendAnySHA256HashesPtr(values ...*string) {
 	}
 }
 
+func (pl *PantherLog) AppendAnyUsernamePtrs(values ...*string) {
+	for _, value := range values {
+		if value != nil {
+			pl.AppendAnyUsernames(*value)
+		}
+	}
+}
+
+func (pl *PantherLog) AppendAnyUsernames(values ...string) {
+	if pl.PantherAnyUsernames == nil { // lazy create
+		pl.PantherAnyUsernames = NewPantherAnyString()
+	}
+	AppendAnyString(pl.PantherAnyUsernames, values...)
+}
+
+func (pl *PantherLog) AppendAnyEmailPtrs(values ...*string) {
+	for _, value := range values {
+		if value != nil {
+			pl.AppendAnyEmails(*value)
+		}
+	}
+}
+
+// AppendAnyEmails appends the values that are valid email addresses
+func (pl *PantherLog) AppendAnyEmails(values ...string) {
+	for _, value := range values {
+		pantherlog.ScanEmail(pl, value)
+	}
+}
+
+func (pl *PantherLog) AppendAnyURLPtrs(values ...*string) {
+	for _, value := range values {
+		if value != nil {
+			pl.AppendAnyURLs(*value)
+		}
+	}
+}
+
+// AppendAnyURLs appends the values that are absolute URLs along with the domain name or IP address of each URL
+func (pl *PantherLog) AppendAnyURLs(values ...string) {
+	for _, value := range values {
+		pantherlog.ScanURL(pl, value)
+	}
+}
+
+func (pl *PantherLog) AppendAnyMACAddressPtrs(values ...*string) {
+	for _, value := range values {
+		if value != nil {
+			pl.AppendAnyMACAddresses(*value)
+		}
+	}
+}
+
+// AppendAnyMACAddresses appends the values that are valid MAC addresses
+func (pl *PantherLog) AppendAnyMACAddresses(values ...string) {
+	for _, value := range values {
+		pantherlog.ScanMACAddress(pl, value)
+	}
+}
+
+var _ pantherlog.ValueWriter = (*PantherLog)(nil)
+
+// WriteValues implements pantherlog.ValueWriter interface so that pantherlog scanners can append values to the p_any_* fields
+func (pl *PantherLog) WriteValues(id pantherlog.FieldID, values ...string) {
+	var any **PantherAnyString
+	switch id {
+	case pantherlog.FieldIPAddress:
+		for _, value := range values {
+			pl.AppendAnyIPAddress(value)
+		}
+		return
+	case pantherlog.FieldDomainName:
+		any = &pl.PantherAnyDomainNames
+	case pantherlog.FieldSHA1Hash:
+		any = &pl.PantherAnySHA1Hashes
+	case pantherlog.FieldMD5Hash:
+		any = &pl.PantherAnyMD5Hashes
+	case pantherlog.FieldSHA256Hash:
+		any = &pl.PantherAnySHA256Hashes
+	case pantherlog.FieldUsername:
+		any = &pl.PantherAnyUsernames
+	case pantherlog.FieldEmail:
+		any = &pl.PantherAnyEmails
+	case pantherlog.FieldURL:
+		any = &pl.PantherAnyURLs
+	case pantherlog.FieldMACAddress:
+		any = &pl.PantherAnyMACAddresses
+	default:
+		return
+	}
+	if *any == nil { // lazy create
+		*any = NewPantherAnyString()
+	}
+	AppendAnyString(*any, values...)
+}
+
 func AppendAnyString(any *PantherAnyString, values ...string) {
 	// add new if not present
 	for _, v := range values {
@@ -270,6 +385,70 @@ func AppendAnyString(any *PantherAnyString, values ...string) {
 	}
 }
 
+// RemoveAny removes values from all the p_any_* fields, used when field values are redacted after parsing.
+// The domain names and ip addresses of redacted URLs are also removed, even if other fields contain them.
+func (pl *PantherLog) RemoveAny(values ...string) {
+	values = ScanAnyValues(pantherlog.ScanURL, values...)
+	RemoveAnyString(pl.PantherAnyIPAddresses, values...)
+	RemoveAnyString(pl.PantherAnyDomainNames, values...)
+	RemoveAnyString(pl.PantherAnySHA1Hashes, values...)
+	RemoveAnyString(pl.PantherAnyMD5Hashes, values...)
+	RemoveAnyString(pl.PantherAnySHA256Hashes, values...)
+	RemoveAnyString(pl.PantherAnyUsernames, values...)
+	RemoveAnyString(pl.PantherAnyEmails, values...)
+	RemoveAnyString(pl.PantherAnyURLs, values...)
+	RemoveAnyString(pl.PantherAnyMACAddresses, values...)
+}
+
+func RemoveAnyString(any *PantherAnyString, values ...string) {
+	if any == nil {
+		return
+	}
+	for _, v := range values {
+		delete(any.set, v)
+	}
+}
+
+// ScanAnyValues returns values along with the values that scan derives from them (ie the domain name of a URL)
+func ScanAnyValues(scan pantherlog.ValueScannerFunc, values ...string) []string {
+	w := append(anyValues(nil), values...)
+	for _, value := range values {
+		scan(&w, value)
+	}
+	return w
+}
+
+type anyValues []string
+
+func (v *anyValues) WriteValues(_ pantherlog.FieldID, values ...string) {
+	*v = append(*v, values...)
+}
+
+// WriteAnyValuesTo writes the values of all the p_any_* fields to w, used to enrich the event when it is encoded
+func (pl *PantherLog) WriteAnyValuesTo(w pantherlog.ValueWriter) {
+	for _, any := range []struct {
+		id     pantherlog.FieldID
+		values *PantherAnyString
+	}{
+		{pantherlog.FieldIPAddress, pl.PantherAnyIPAddresses},
+		{pantherlog.FieldDomainName, pl.PantherAnyDomainNames},
+		{pantherlog.FieldSHA1Hash, pl.PantherAnySHA1Hashes},
+		{pantherlog.FieldMD5Hash, pl.PantherAnyMD5Hashes},
+		{pantherlog.FieldSHA256Hash, pl.PantherAnySHA256Hashes},
+		{pantherlog.FieldUsername, pl.PantherAnyUsernames},
+		{pantherlog.FieldEmail, pl.PantherAnyEmails},
+		{pantherlog.FieldURL, pl.PantherAnyURLs},
+		{pantherlog.FieldMACAddress, pl.PantherAnyMACAddresses},
+	} {
+		if any.values == nil {
+			continue
+		}
+		for value := range any.values.set {
+			w.WriteValues(any.id, value)
+		}
+	}
+}
+
 // Result converts a PantherLog to Result
 func (pl *PantherLog) Result() *Result {
 	event := pl.Event()
@@ -249,3 +249,69 @@ func TestAppendAnyMD5Hashes(t *testing.T) {
 	event.AppendAnyMD5HashPtrs(&value)
 	require.Equal(t, expectedAny, event.PantherAnyMD5Hashes)
 }
+
+func TestAppendAnyUsernames(t *testing.T) {
+	event := PantherLog{}
+	value := "a"
+	expectedAny := &PantherAnyString{
+		set: map[string]struct{}{
+			value: {},
+		},
+	}
+	event.AppendAnyUsernames(value)
+	require.Equal(t, expectedAny, event.PantherAnyUsernames)
+
+	event = PantherLog{}
+	event.AppendAnyUsernamePtrs(&value)
+	require.Equal(t, expectedAny, event.PantherAnyUsernames)
+}
+
+func TestAppendAnyEmails(t *testing.T) {
+	event := PantherLog{}
+	value := "jane@example.com"
+	event.AppendAnyEmails(value, "jane")
+	require.Equal(t, &PantherAnyString{
+		set: map[string]struct{}{
+			value: {},
+		},
+	}, event.PantherAnyEmails)
+
+	event = PantherLog{}
+	event.AppendAnyEmailPtrs(nil)
+	require.Nil(t, event.PantherAnyEmails)
+}
+
+func TestAppendAnyURLs(t *testing.T) {
+	event := PantherLog{}
+	value := "https://example.com/foo"
+	event.AppendAnyURLPtrs(&value, nil)
+	event.AppendAnyURLs("/foo", "http://1.1.1.1/")
+	require.Equal(t, &PantherAnyString{
+		set: map[string]struct{}{
+			value:             {},
+			"http://1.1.1.1/": {},
+		},
+	}, event.PantherAnyURLs)
+	require.Equal(t, &PantherAnyString{
+		set: map[string]struct{}{
+			"example.com": {},
+		},
+	}, event.PantherAnyDomainNames)
+	require.Equal(t, &PantherAnyString{
+		set: map[string]struct{}{
+			"1.1.1.1": {},
+		},
+	}, event.PantherAnyIPAddresses)
+}
+
+func TestAppendAnyMACAddresses(t *testing.T) {
+	event := PantherLog{}
+	value := "00-1A-2B-3C-4D-5E"
+	event.AppendAnyMACAddressPtrs(&value)
+	event.AppendAnyMACAddresses("not a mac")
+	require.Equal(t, &PantherAnyString{
+		set: map[string]struct{}{
+			"00:1a:2b:3c:4d:5e": {},
+		},
+	}, event.PantherAnyMACAddresses)
+}
@@ -0,0 +1,116 @@
+package sysloglogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"time"
+
+	"github.com/influxdata/go-syslog/v3/rfc3164"
+	"github.com/influxdata/go-syslog/v3/rfc5424"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/null"
+)
+
+// Header is the syslog envelope of a message that carries another log format (ie CEF or LEEF over syslog).
+// nolint:lll
+type Header struct {
+	Priority  pantherlog.Uint8  `json:"priority" validate:"required" description:"Priority is calculated by (Facility * 8 + Severity). The lower this value, the higher importance of the log message."`
+	Facility  pantherlog.Uint8  `json:"facility" description:"Facility value helps determine which process created the message. Eg: 0 = kernel messages, 3 = system daemons."`
+	Severity  pantherlog.Uint8  `json:"severity" description:"Severity indicates how severe the message is. Eg: 0=Emergency to 7=Debug."`
+	Version   pantherlog.Uint16 `json:"version" description:"Version of the syslog message protocol. Only set for RFC5424 messages."`
+	Timestamp pantherlog.Time   `json:"timestamp" tcodec:"rfc3339" event_time:"true" description:"Timestamp of the syslog message in UTC."`
+	Hostname  pantherlog.String `json:"hostname" panther:"hostname" description:"Hostname identifies the machine that originally sent the syslog message."`
+	Appname   pantherlog.String `json:"appname" description:"Appname identifies the device or application that originated the syslog message."`
+	ProcID    pantherlog.String `json:"procid" description:"ProcID is often the process ID, but can be any value used to enable log analyzers to detect discontinuities in syslog reporting."`
+	MsgID     pantherlog.String `json:"msgid" description:"MsgID identifies the type of message. For example, a firewall might use the MsgID 'TCPIN' for incoming TCP traffic."`
+}
+
+// ParseHeader parses the syslog header that precedes a message.
+// The header can be in either RFC5424 or RFC3164 format, the message itself is not part of the input.
+func ParseHeader(header string) (*Header, error) {
+	header = strings.TrimSpace(header)
+	if !strings.HasPrefix(header, "<") {
+		return nil, errors.New("syslog header must start with a priority value")
+	}
+	// The header is followed by a space separated message in both formats
+	input := []byte(header + " ")
+	if msg, err := rfc5424.NewParser(rfc5424.WithBestEffort()).Parse(input); err == nil {
+		m := msg.(*rfc5424.SyslogMessage)
+		return &Header{
+			Priority:  fromUint8Ptr(m.Priority),
+			Facility:  fromUint8Ptr(m.Facility),
+			Severity:  fromUint8Ptr(m.Severity),
+			Version:   null.FromUint16(m.Version),
+			Timestamp: fromTimePtr(m.Timestamp),
+			Hostname:  fromStringPtr(m.Hostname),
+			Appname:   fromStringPtr(m.Appname),
+			ProcID:    fromStringPtr(m.ProcID),
+			MsgID:     fromStringPtr(m.MsgID),
+		}, nil
+	}
+	// The RFC3164 parser reports an error for headers without a tag (ie `<13>Dec  2 16:31:03 host CEF:0|...`),
+	// in best effort mode it still returns the fields that were parsed successfully.
+	msg, _ := rfc3164.NewParser(
+		rfc3164.WithBestEffort(),
+		rfc3164.WithTimezone(time.UTC),
+		rfc3164.WithYear(rfc3164.CurrentYear{}),
+		rfc3164.WithRFC3339(),
+	).Parse(input)
+	if msg == nil {
+		return nil, errors.New("invalid syslog header")
+	}
+	m := msg.(*rfc3164.SyslogMessage)
+	if m.Priority == nil || m.Timestamp == nil {
+		return nil, errors.New("invalid syslog header")
+	}
+	return &Header{
+		Priority:  fromUint8Ptr(m.Priority),
+		Facility:  fromUint8Ptr(m.Facility),
+		Severity:  fromUint8Ptr(m.Severity),
+		Timestamp: fromTimePtr(m.Timestamp),
+		Hostname:  fromStringPtr(m.Hostname),
+		Appname:   fromStringPtr(m.Appname),
+		ProcID:    fromStringPtr(m.ProcID),
+		MsgID:     fromStringPtr(m.MsgID),
+	}, nil
+}
+
+func fromUint8Ptr(n *uint8) pantherlog.Uint8 {
+	if n == nil {
+		return pantherlog.Uint8{}
+	}
+	return null.FromUint8(*n)
+}
+
+func fromStringPtr(s *string) pantherlog.String {
+	if s == nil {
+		return pantherlog.String{}
+	}
+	return null.FromString(*s)
+}
+
+func fromTimePtr(tm *time.Time) pantherlog.Time {
+	if tm == nil {
+		return pantherlog.Time{}
+	}
+	return tm.UTC()
+}
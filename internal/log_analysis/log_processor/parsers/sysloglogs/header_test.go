@@ -0,0 +1,56 @@
+package sysloglogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/null"
+)
+
+func TestParseHeader(t *testing.T) {
+	header, err := ParseHeader(`<134>1 2020-06-05T14:39:59Z fw01.example.com asa 42 - - `)
+	require.NoError(t, err)
+	require.Equal(t, &Header{
+		Priority:  null.FromUint8(134),
+		Facility:  null.FromUint8(16),
+		Severity:  null.FromUint8(6),
+		Version:   null.FromUint16(1),
+		Timestamp: time.Date(2020, 6, 5, 14, 39, 59, 0, time.UTC),
+		Hostname:  null.FromString("fw01.example.com"),
+		Appname:   null.FromString("asa"),
+		ProcID:    null.FromString("42"),
+	}, header)
+
+	header, err = ParseHeader(`<13>Dec  2 16:31:03 host `)
+	require.NoError(t, err)
+	require.Equal(t, &Header{
+		Priority:  null.FromUint8(13),
+		Facility:  null.FromUint8(1),
+		Severity:  null.FromUint8(5),
+		Timestamp: time.Date(time.Now().UTC().Year(), 12, 2, 16, 31, 3, 0, time.UTC),
+		Hostname:  null.FromString("host"),
+	}, header)
+
+	_, err = ParseHeader(`Dec  2 16:31:03 host `)
+	require.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// DHCP is a Zeek dhcp.log entry summarizing a DHCP lease transaction
+// nolint:lll,maligned
+type DHCP struct {
+	TS            pantherlog.Time    `json:"ts" tcodec:"unix" event_time:"true" validate:"required" description:"The earliest time at which a DHCP message over the associated connection is observed."`
+	UIDs          []string           `json:"uids" validate:"required" panther:"trace_id" description:"A series of unique identifiers of the connections over which DHCP is occurring."`
+	ClientAddr    pantherlog.String  `json:"client_addr" panther:"ip" description:"IP address of the client."`
+	ServerAddr    pantherlog.String  `json:"server_addr" panther:"ip" description:"IP address of the server handing out the lease."`
+	ClientPort    pantherlog.Uint16  `json:"client_port" description:"Client port number seen at time of server handing out IP."`
+	ServerPort    pantherlog.Uint16  `json:"server_port" description:"Server port number seen at time of server handing out IP."`
+	MAC           pantherlog.String  `json:"mac" panther:"mac" description:"Client's hardware address."`
+	HostName      pantherlog.String  `json:"host_name" panther:"hostname" description:"Name given by client in Hostname option 12."`
+	ClientFQDN    pantherlog.String  `json:"client_fqdn" panther:"domain" description:"FQDN given by client in Client FQDN option 81."`
+	Domain        pantherlog.String  `json:"domain" panther:"domain" description:"Domain given by the server in option 15."`
+	RequestedAddr pantherlog.String  `json:"requested_addr" panther:"ip" description:"IP address requested by the client."`
+	AssignedAddr  pantherlog.String  `json:"assigned_addr" panther:"ip" description:"IP address assigned by the server."`
+	LeaseTime     pantherlog.Float64 `json:"lease_time" description:"IP address lease interval in seconds."`
+	ClientMessage pantherlog.String  `json:"client_message" description:"Message typically accompanied with a DHCP_DECLINE so the client can tell the server why it rejected an address."`
+	ServerMessage pantherlog.String  `json:"server_message" description:"Message typically accompanied with a DHCP_NAK to let the client know why it rejected the request."`
+	MsgTypes      []string           `json:"msg_types" validate:"required" description:"The DHCP message types seen by this DHCP transaction."`
+	Duration      pantherlog.Float64 `json:"duration" description:"Duration of the DHCP session in seconds representing the time from the first message to the last."`
+}
@@ -0,0 +1,53 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// Files is a Zeek files.log entry for a file observed by the file analysis framework
+// nolint:lll,maligned
+type Files struct {
+	TS              pantherlog.Time    `json:"ts" tcodec:"unix" event_time:"true" validate:"required" description:"The time when the file was first seen."`
+	FUID            pantherlog.String  `json:"fuid" validate:"required" description:"An identifier associated with a single file."`
+	TxHosts         []string           `json:"tx_hosts" panther:"ip" description:"If this file was transferred over a network connection this should show the host or hosts that the data sourced from."`
+	RxHosts         []string           `json:"rx_hosts" panther:"ip" description:"If this file was transferred over a network connection this should show the host or hosts that the data traveled to."`
+	ConnUIDs        []string           `json:"conn_uids" panther:"trace_id" description:"Connection UIDs over which the file was transferred."`
+	Source          pantherlog.String  `json:"source" description:"An identification of the source of the file data."`
+	Depth           pantherlog.Uint64  `json:"depth" description:"A value to represent the depth of this file in relation to its source."`
+	Analyzers       []string           `json:"analyzers" description:"A set of analysis types done during the file analysis."`
+	MIMEType        pantherlog.String  `json:"mime_type" description:"A mime type provided by the strongest file magic signature match against the bof_buffer field."`
+	Filename        pantherlog.String  `json:"filename" description:"A filename for the file if one is available from the source for the file."`
+	Duration        pantherlog.Float64 `json:"duration" description:"The duration the file was analyzed for in seconds."`
+	LocalOrig       pantherlog.Bool    `json:"local_orig" description:"If the source of this file is a network connection, this field indicates if the data originated from the local network or not."`
+	IsOrig          pantherlog.Bool    `json:"is_orig" description:"If the source of this file is a network connection, this field indicates if the file is being sent by the originator of the connection or the responder."`
+	SeenBytes       pantherlog.Uint64  `json:"seen_bytes" description:"Number of bytes provided to the file analysis engine for the file."`
+	TotalBytes      pantherlog.Uint64  `json:"total_bytes" description:"Total number of bytes that are supposed to comprise the full file."`
+	MissingBytes    pantherlog.Uint64  `json:"missing_bytes" description:"The number of bytes in the file stream that were completely missed during the process of analysis."`
+	OverflowBytes   pantherlog.Uint64  `json:"overflow_bytes" description:"The number of bytes in the file stream that were not delivered to stream file analyzers."`
+	TimedOut        pantherlog.Bool    `json:"timedout" description:"Whether the file analysis timed out at least once for the file."`
+	ParentFUID      pantherlog.String  `json:"parent_fuid" description:"Identifier associated with a container file from which this one was extracted as part of the file analysis."`
+	MD5             pantherlog.String  `json:"md5" panther:"md5" description:"An MD5 digest of the file contents."`
+	SHA1            pantherlog.String  `json:"sha1" panther:"sha1" description:"A SHA1 digest of the file contents."`
+	SHA256          pantherlog.String  `json:"sha256" panther:"sha256" description:"A SHA256 digest of the file contents."`
+	Extracted       pantherlog.String  `json:"extracted" description:"Local filename of extracted file."`
+	ExtractedCutoff pantherlog.Bool    `json:"extracted_cutoff" description:"Set to true if the file being extracted was cut off so the whole file was not logged."`
+	ExtractedSize   pantherlog.Uint64  `json:"extracted_size" description:"The number of bytes extracted to disk."`
+}
@@ -0,0 +1,58 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// HTTP is a Zeek http.log entry for a single HTTP request/response pair
+// nolint:lll,maligned
+type HTTP struct {
+	TS              pantherlog.Time   `json:"ts" tcodec:"unix" event_time:"true" validate:"required" description:"Timestamp for when the request happened."`
+	UID             pantherlog.String `json:"uid" validate:"required" panther:"trace_id" description:"A unique identifier of the connection."`
+	IDOrigH         pantherlog.String `json:"id.orig_h" validate:"required" panther:"ip" description:"The originator's IP address."`
+	IDOrigP         pantherlog.Uint16 `json:"id.orig_p" description:"The originator's port number."`
+	IDRespH         pantherlog.String `json:"id.resp_h" validate:"required" panther:"ip" description:"The responder's IP address."`
+	IDRespP         pantherlog.Uint16 `json:"id.resp_p" description:"The responder's port number."`
+	TransDepth      pantherlog.Uint64 `json:"trans_depth" validate:"required" description:"Represents the pipelined depth into the connection of this request/response transaction."`
+	Method          pantherlog.String `json:"method" description:"Verb used in the HTTP request (GET, POST, HEAD, etc.)."`
+	Host            pantherlog.String `json:"host" panther:"hostname" description:"Value of the HOST header."`
+	URI             pantherlog.String `json:"uri" description:"URI used in the request."`
+	Referrer        pantherlog.String `json:"referrer" panther:"url" description:"Value of the Referer header."`
+	Version         pantherlog.String `json:"version" description:"Value of the version portion of the request."`
+	UserAgent       pantherlog.String `json:"user_agent" description:"Value of the User-Agent header from the client."`
+	Origin          pantherlog.String `json:"origin" panther:"url" description:"Value of the Origin header from the client."`
+	RequestBodyLen  pantherlog.Uint64 `json:"request_body_len" description:"Actual uncompressed content size of the data transferred from the client."`
+	ResponseBodyLen pantherlog.Uint64 `json:"response_body_len" description:"Actual uncompressed content size of the data transferred from the server."`
+	StatusCode      pantherlog.Uint64 `json:"status_code" description:"Status code returned by the server."`
+	StatusMsg       pantherlog.String `json:"status_msg" description:"Status message returned by the server."`
+	InfoCode        pantherlog.Uint64 `json:"info_code" description:"Last seen 1xx informational reply code returned by the server."`
+	InfoMsg         pantherlog.String `json:"info_msg" description:"Last seen 1xx informational reply message returned by the server."`
+	Tags            []string          `json:"tags" description:"A set of indicators of various attributes discovered and related to a particular request/response pair."`
+	Username        pantherlog.String `json:"username" panther:"username" description:"Username if basic-auth is performed for the request."`
+	Password        pantherlog.String `json:"password" description:"Password if basic-auth is performed for the request."`
+	Proxied         []string          `json:"proxied" description:"All of the headers that may indicate if the request was proxied."`
+	OrigFUIDs       []string          `json:"orig_fuids" description:"An ordered vector of file unique IDs sent by the originator."`
+	OrigFilenames   []string          `json:"orig_filenames" description:"An ordered vector of filenames from the client."`
+	OrigMIMETypes   []string          `json:"orig_mime_types" description:"An ordered vector of mime types sent by the originator."`
+	RespFUIDs       []string          `json:"resp_fuids" description:"An ordered vector of file unique IDs sent by the responder."`
+	RespFilenames   []string          `json:"resp_filenames" description:"An ordered vector of filenames from the server."`
+	RespMIMETypes   []string          `json:"resp_mime_types" description:"An ordered vector of mime types sent by the responder."`
+}
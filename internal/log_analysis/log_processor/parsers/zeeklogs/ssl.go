@@ -0,0 +1,51 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// SSL is a Zeek ssl.log entry for an SSL/TLS handshake
+// nolint:lll,maligned
+type SSL struct {
+	TS                   pantherlog.Time   `json:"ts" tcodec:"unix" event_time:"true" validate:"required" description:"Time when the SSL connection was first detected."`
+	UID                  pantherlog.String `json:"uid" validate:"required" panther:"trace_id" description:"A unique identifier of the connection."`
+	IDOrigH              pantherlog.String `json:"id.orig_h" validate:"required" panther:"ip" description:"The originator's IP address."`
+	IDOrigP              pantherlog.Uint16 `json:"id.orig_p" description:"The originator's port number."`
+	IDRespH              pantherlog.String `json:"id.resp_h" validate:"required" panther:"ip" description:"The responder's IP address."`
+	IDRespP              pantherlog.Uint16 `json:"id.resp_p" description:"The responder's port number."`
+	Version              pantherlog.String `json:"version" description:"SSL/TLS version that the server chose."`
+	Cipher               pantherlog.String `json:"cipher" description:"SSL/TLS cipher suite that the server chose."`
+	Curve                pantherlog.String `json:"curve" description:"Elliptic curve the server chose when using ECDH/ECDHE."`
+	ServerName           pantherlog.String `json:"server_name" panther:"domain" description:"Value of the Server Name Indicator SSL/TLS extension."`
+	Resumed              pantherlog.Bool   `json:"resumed" description:"Flag to indicate if the session was resumed reusing the key material exchanged in an earlier connection."`
+	LastAlert            pantherlog.String `json:"last_alert" description:"Last alert that was seen during the connection."`
+	NextProtocol         pantherlog.String `json:"next_protocol" description:"Next protocol the server chose using the application layer next protocol extension, if present."`
+	Established          pantherlog.Bool   `json:"established" validate:"required" description:"Flag to indicate if this SSL session has been established successfully, or if it was aborted during the handshake."`
+	CertChainFUIDs       []string          `json:"cert_chain_fuids" description:"An ordered vector of all certificate file unique IDs for the certificates offered by the server."`
+	ClientCertChainFUIDs []string          `json:"client_cert_chain_fuids" description:"An ordered vector of all certificate file unique IDs for the certificates offered by the client."`
+	Subject              pantherlog.String `json:"subject" description:"Subject of the X.509 certificate offered by the server."`
+	Issuer               pantherlog.String `json:"issuer" description:"Subject of the signer of the X.509 certificate offered by the server."`
+	ClientSubject        pantherlog.String `json:"client_subject" description:"Subject of the X.509 certificate offered by the client."`
+	ClientIssuer         pantherlog.String `json:"client_issuer" description:"Subject of the signer of the X.509 certificate offered by the client."`
+	ValidationStatus     pantherlog.String `json:"validation_status" description:"Result of certificate validation for this connection."`
+	JA3                  pantherlog.String `json:"ja3" panther:"ja3" description:"JA3 fingerprint of the client TLS handshake (requires the ja3 Zeek package)."`
+	JA3S                 pantherlog.String `json:"ja3s" panther:"ja3" description:"JA3S fingerprint of the server TLS handshake (requires the ja3 Zeek package)."`
+}
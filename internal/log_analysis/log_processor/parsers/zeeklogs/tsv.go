@@ -0,0 +1,222 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// Default values of the TSV header directives
+// See https://docs.zeek.org/en/current/frameworks/logging.html#ascii-writer
+const (
+	defaultSeparator    = "\t"
+	defaultSetSeparator = ","
+	defaultEmptyField   = "(empty)"
+	defaultUnsetField   = "-"
+)
+
+// IsTSV checks if the start of a stream is a Zeek TSV log header
+func IsTSV(head []byte) bool {
+	return strings.HasPrefix(string(head), "#separator ")
+}
+
+// SplitTSV reads Zeek TSV logs and emits each row as a JSON object in the format of Zeek JSON logs.
+//
+// The fields of each row are read from the `#fields` and `#types` header directives.
+// Concatenated logs with different headers are supported, each header applies to the rows that follow it.
+// Rows that do not match the header are emitted unchanged so that they are reported as classification failures.
+func SplitTSV(r *bufio.Reader, emit func(entry string)) error {
+	h := tsvHeader{}
+	h.reset()
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, nil, 512)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return errors.Wrap(err, "failed to read Zeek TSV log")
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#"):
+			h.readDirective(line)
+		default:
+			stream.Reset(nil)
+			if h.writeJSON(stream, line) {
+				emit(string(stream.Buffer()))
+			} else {
+				emit(line)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+type tsvHeader struct {
+	separator    string
+	setSeparator string
+	emptyField   string
+	unsetField   string
+	fields       []string
+	types        []string
+}
+
+func (h *tsvHeader) reset() {
+	*h = tsvHeader{
+		separator:    defaultSeparator,
+		setSeparator: defaultSetSeparator,
+		emptyField:   defaultEmptyField,
+		unsetField:   defaultUnsetField,
+	}
+}
+
+func (h *tsvHeader) readDirective(line string) {
+	// The separator directive is always separated by a space and its value is escaped (ie `#separator \x09`)
+	if strings.HasPrefix(line, "#separator ") {
+		h.reset()
+		h.separator = unescape(strings.TrimPrefix(line, "#separator "))
+		return
+	}
+	parts := strings.Split(line, h.separator)
+	values := parts[1:]
+	value := strings.Join(values, h.separator)
+	switch parts[0] {
+	case "#set_separator":
+		h.setSeparator = unescape(value)
+	case "#empty_field":
+		h.emptyField = unescape(value)
+	case "#unset_field":
+		h.unsetField = unescape(value)
+	case "#fields":
+		h.fields = values
+	case "#types":
+		h.types = values
+	}
+}
+
+func (h *tsvHeader) writeJSON(stream *jsoniter.Stream, line string) bool {
+	if len(h.fields) == 0 || len(h.fields) != len(h.types) {
+		return false
+	}
+	values := strings.Split(line, h.separator)
+	if len(values) != len(h.fields) {
+		return false
+	}
+	stream.WriteObjectStart()
+	more := false
+	for i, value := range values {
+		if value == h.unsetField {
+			continue
+		}
+		if more {
+			stream.WriteMore()
+		}
+		more = true
+		stream.WriteObjectField(h.fields[i])
+		h.writeValue(stream, h.types[i], value)
+	}
+	stream.WriteObjectEnd()
+	return stream.Error == nil
+}
+
+func (h *tsvHeader) writeValue(stream *jsoniter.Stream, typ, value string) {
+	if elem, ok := containerElemType(typ); ok {
+		stream.WriteArrayStart()
+		if value != h.emptyField {
+			for i, v := range strings.Split(value, h.setSeparator) {
+				if i > 0 {
+					stream.WriteMore()
+				}
+				writeScalar(stream, elem, v)
+			}
+		}
+		stream.WriteArrayEnd()
+		return
+	}
+	if value == h.emptyField {
+		value = ""
+	}
+	writeScalar(stream, typ, value)
+}
+
+// containerElemType returns the element type of `set[T]` and `vector[T]` types
+func containerElemType(typ string) (string, bool) {
+	for _, prefix := range []string{"set[", "vector["} {
+		if strings.HasPrefix(typ, prefix) && strings.HasSuffix(typ, "]") {
+			return typ[len(prefix) : len(typ)-1], true
+		}
+	}
+	return "", false
+}
+
+func writeScalar(stream *jsoniter.Stream, typ, value string) {
+	switch typ {
+	case "bool":
+		switch value {
+		case "T":
+			stream.WriteTrue()
+			return
+		case "F":
+			stream.WriteFalse()
+			return
+		}
+	case "count", "int", "port":
+		if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+			stream.WriteRaw(value)
+			return
+		}
+		if _, err := strconv.ParseUint(value, 10, 64); err == nil {
+			stream.WriteRaw(value)
+			return
+		}
+	case "double", "interval", "time":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			stream.WriteFloat64(f)
+			return
+		}
+	}
+	// Values that do not match their type are kept as strings so that they fail validation
+	stream.WriteString(unescape(value))
+}
+
+// unescape replaces `\xHH` escape sequences used by Zeek for non-printable characters and separators
+func unescape(s string) string {
+	if !strings.Contains(s, `\x`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) && s[i+1] == 'x' {
+			if n, err := strconv.ParseUint(s[i+2:i+4], 16, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
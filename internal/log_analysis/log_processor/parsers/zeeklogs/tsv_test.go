@@ -0,0 +1,62 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitTSV(t *testing.T) {
+	input := strings.Join([]string{
+		`#separator \x09`,
+		`#set_separator	,`,
+		`#empty_field	(empty)`,
+		`#unset_field	-`,
+		`#path	conn`,
+		`#fields	ts	uid	id.orig_h	id.orig_p	local_orig	service	tunnel_parents	history`,
+		`#types	time	string	addr	port	bool	string	set[string]	string`,
+		`1591367999.305988	CMdzit1AMNsmfAIiQc	192.168.4.76	36844	T	-	(empty)	a\x09b`,
+		`1591367999.305988	CMdzit1AMNsmfAIiQc	192.168.4.76`,
+		`#close	2020-06-05-15-00-00`,
+		`#separator \x20`,
+		`#fields ts name notice`,
+		`#types time string bool`,
+		`1591367999.630113 dns_unmatched_reply F`,
+		``,
+	}, "\n")
+	var entries []string
+	err := SplitTSV(bufio.NewReader(strings.NewReader(input)), func(entry string) {
+		entries = append(entries, entry)
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`{"ts":1591367999.305988,"uid":"CMdzit1AMNsmfAIiQc","id.orig_h":"192.168.4.76","id.orig_p":36844,"local_orig":true,"tunnel_parents":[],"history":"a\tb"}`,
+		"1591367999.305988\tCMdzit1AMNsmfAIiQc\t192.168.4.76",
+		`{"ts":1591367999.630113,"name":"dns_unmatched_reply","notice":false}`,
+	}, entries)
+}
+
+func TestIsTSV(t *testing.T) {
+	require.True(t, IsTSV([]byte("#separator \\x09\n#fields\tts")))
+	require.False(t, IsTSV([]byte(`{"ts":1591367999.305988}`)))
+}
@@ -0,0 +1,39 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// Weird is a Zeek weird.log entry for unexpected network or protocol activity
+// nolint:lll,maligned
+type Weird struct {
+	TS      pantherlog.Time   `json:"ts" tcodec:"unix" event_time:"true" validate:"required" description:"The time when the weird occurred."`
+	UID     pantherlog.String `json:"uid" panther:"trace_id" description:"If a connection is associated with this weird, this will be the connection's unique ID."`
+	IDOrigH pantherlog.String `json:"id.orig_h" panther:"ip" description:"The originator's IP address."`
+	IDOrigP pantherlog.Uint16 `json:"id.orig_p" description:"The originator's port number."`
+	IDRespH pantherlog.String `json:"id.resp_h" panther:"ip" description:"The responder's IP address."`
+	IDRespP pantherlog.Uint16 `json:"id.resp_p" description:"The responder's port number."`
+	Name    pantherlog.String `json:"name" validate:"required" description:"The name of the weird that occurred."`
+	Addl    pantherlog.String `json:"addl" description:"Additional information accompanying the weird if any."`
+	Notice  pantherlog.Bool   `json:"notice" validate:"required" description:"Indicate if this weird was also turned into a notice."`
+	Peer    pantherlog.String `json:"peer" description:"The peer that originated this weird."`
+	Source  pantherlog.String `json:"source" description:"The source of the weird."`
+}
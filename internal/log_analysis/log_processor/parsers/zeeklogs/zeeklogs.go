@@ -36,4 +36,68 @@ func init() {
 			Schema:       &ZeekDNS{},
 			NewParser:    parsers.AdapterFactory(&ZeekDNSParser{}),
 		})
+
+	logtypes.MustRegisterJSON(logtypes.Desc{
+		Name:         "Zeek.Conn",
+		Description:  `Zeek connection logs`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/conn/main.zeek.html#type-Conn::Info`,
+	}, func() interface{} {
+		return &Conn{}
+	})
+
+	logtypes.MustRegisterJSON(logtypes.Desc{
+		Name:         "Zeek.DHCP",
+		Description:  `Zeek DHCP lease activity`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/dhcp/main.zeek.html#type-DHCP::Info`,
+	}, func() interface{} {
+		return &DHCP{}
+	})
+
+	logtypes.MustRegisterJSON(logtypes.Desc{
+		Name:         "Zeek.Files",
+		Description:  `Zeek file analysis results`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/frameworks/files/main.zeek.html#type-Files::Info`,
+	}, func() interface{} {
+		return &Files{}
+	})
+
+	logtypes.MustRegisterJSON(logtypes.Desc{
+		Name:         "Zeek.HTTP",
+		Description:  `Zeek HTTP requests and replies`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/http/main.zeek.html#type-HTTP::Info`,
+	}, func() interface{} {
+		return &HTTP{}
+	})
+
+	logtypes.MustRegisterJSON(logtypes.Desc{
+		Name:         "Zeek.Notice",
+		Description:  `Zeek notices`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/frameworks/notice/main.zeek.html#type-Notice::Info`,
+	}, func() interface{} {
+		return &Notice{}
+	})
+
+	logtypes.MustRegisterJSON(logtypes.Desc{
+		Name:         "Zeek.SSL",
+		Description:  `Zeek SSL/TLS handshake info`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/ssl/main.zeek.html#type-SSL::Info`,
+	}, func() interface{} {
+		return &SSL{}
+	})
+
+	logtypes.MustRegisterJSON(logtypes.Desc{
+		Name:         "Zeek.Weird",
+		Description:  `Zeek unexpected network-level activity`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/frameworks/notice/weird.zeek.html#type-Weird::Info`,
+	}, func() interface{} {
+		return &Weird{}
+	})
+
+	logtypes.MustRegisterJSON(logtypes.Desc{
+		Name:         "Zeek.X509",
+		Description:  `Zeek X.509 certificate info`,
+		ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/files/x509/main.zeek.html#type-X509::Info`,
+	}, func() interface{} {
+		return &X509{}
+	})
 }
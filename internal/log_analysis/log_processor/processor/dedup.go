@@ -0,0 +1,163 @@
+package processor
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/dedup"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+const (
+	// dedupBatchSize is the number of events checked against the dedup store in a single batch
+	dedupBatchSize = 100
+	// maxDedupFingerprints limits the fingerprints kept in memory until the events are delivered (~100 bytes each).
+	// Events forwarded past the limit are not recorded, so their redeliveries are not dropped.
+	maxDedupFingerprints = 100000
+)
+
+var (
+	// DedupStore records event fingerprints, it is set at startup if event deduplication is enabled.
+	DedupStore dedup.Store
+	// DedupLogTypes are the log types with event deduplication enabled, events of other log types are always forwarded.
+	DedupLogTypes map[string]bool
+	// DedupFingerprinter computes the fingerprints of events when event deduplication is enabled.
+	DedupFingerprinter = &dedup.Fingerprinter{
+		Keys: dedup.DefaultKeys,
+	}
+)
+
+// deduplicator buffers events and drops the ones delivered by previous invocations within the dedup window.
+type deduplicator struct {
+	store         dedup.Store
+	fingerprinter *dedup.Fingerprinter
+	logTypes      map[string]bool
+	sourceID      string
+	pending       []pendingEvent
+	// fingerprints of the events forwarded from the stream, recorded once all events are delivered
+	seen map[string]bool
+	// max number of fingerprints in seen
+	maxFingerprints int
+	// number of forwarded events that will not be recorded because seen is full
+	unrecorded int
+	// number of dropped events by log type
+	duplicates map[string]uint64
+}
+
+type pendingEvent struct {
+	fingerprint string
+	event       *parsers.Result
+}
+
+func newDeduplicator(store dedup.Store, logTypes map[string]bool, sourceID string) *deduplicator {
+	if store == nil || len(logTypes) == 0 {
+		return nil
+	}
+	return &deduplicator{
+		store:           store,
+		fingerprinter:   DedupFingerprinter,
+		logTypes:        logTypes,
+		sourceID:        sourceID,
+		seen:            make(map[string]bool),
+		maxFingerprints: maxDedupFingerprints,
+		duplicates:      make(map[string]uint64),
+	}
+}
+
+// add adds the events parsed from a log line to the pending batch, sending the batch if it is full.
+func (d *deduplicator) add(line string, events []*parsers.Result, outputChan chan<- *parsers.Result) {
+	for i, event := range events {
+		if !d.logTypes[event.PantherLogType] {
+			outputChan <- event
+			continue
+		}
+		fp := d.fingerprinter.Fingerprint(event.PantherLogType, d.sourceID, line, i, len(events))
+		// Identical lines within a stream are not dropped, they cannot be told apart from repeated log messages
+		if len(d.seen) < d.maxFingerprints {
+			d.seen[fp] = true
+		} else if !d.seen[fp] {
+			d.unrecorded++
+		}
+		d.pending = append(d.pending, pendingEvent{
+			fingerprint: fp,
+			event:       event,
+		})
+	}
+	if len(d.pending) >= dedupBatchSize {
+		d.flush(outputChan)
+	}
+}
+
+// flush sends the pending events that were not seen before to the output channel.
+func (d *deduplicator) flush(outputChan chan<- *parsers.Result) {
+	if len(d.pending) == 0 {
+		return
+	}
+	fingerprints := make([]string, len(d.pending))
+	for i := range d.pending {
+		fingerprints[i] = d.pending[i].fingerprint
+	}
+	duplicates, err := d.store.Seen(fingerprints)
+	if err != nil {
+		// Storing duplicates is better than losing events, forward all events of the batch
+		zap.L().Warn("failed to check for duplicate events", zap.String("sourceId", d.sourceID), zap.Error(err))
+		duplicates = nil
+	}
+	for _, p := range d.pending {
+		if duplicates[p.fingerprint] {
+			delete(d.seen, p.fingerprint)
+			d.duplicates[p.event.PantherLogType]++
+			continue
+		}
+		outputChan <- p.event
+	}
+	d.pending = d.pending[:0]
+}
+
+// fingerprints returns the fingerprints of all forwarded events.
+func (d *deduplicator) fingerprints() []string {
+	if d == nil {
+		return nil
+	}
+	if d.unrecorded > 0 {
+		zap.L().Warn("too many events to record for deduplication",
+			zap.String("sourceId", d.sourceID), zap.Int("numUnrecorded", d.unrecorded))
+	}
+	fingerprints := make([]string, 0, len(d.seen))
+	for fp := range d.seen {
+		fingerprints = append(fingerprints, fp)
+	}
+	return fingerprints
+}
+
+// duplicateCount returns the number of dropped events for a log type or for all log types if logType is empty.
+func (d *deduplicator) duplicateCount(logType string) uint64 {
+	if d == nil {
+		return 0
+	}
+	if logType != "" {
+		return d.duplicates[logType]
+	}
+	var n uint64
+	for _, count := range d.duplicates {
+		n += count
+	}
+	return n
+}
@@ -0,0 +1,136 @@
+package processor
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+type testDedupStore struct {
+	recorded map[string]bool
+	err      error
+}
+
+func (s *testDedupStore) Seen(fingerprints []string) (map[string]bool, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	seen := make(map[string]bool)
+	for _, fp := range fingerprints {
+		if s.recorded[fp] {
+			seen[fp] = true
+		}
+	}
+	return seen, nil
+}
+
+func (s *testDedupStore) Record(fingerprints []string) error {
+	for _, fp := range fingerprints {
+		s.recorded[fp] = true
+	}
+	return nil
+}
+
+func TestProcessDedup(t *testing.T) {
+	defer func() {
+		DedupStore = nil
+		DedupLogTypes = nil
+	}()
+	store := &testDedupStore{
+		recorded: map[string]bool{
+			DedupFingerprinter.Fingerprint(testLogType, testSourceID, "b", 0, 1): true,
+		},
+	}
+	DedupStore = store
+	DedupLogTypes = map[string]bool{testLogType: true}
+
+	run := func(lines ...string) (*testDestination, *Processor) {
+		dataStream := makeDataStream()
+		dataStream.Reader = strings.NewReader(strings.Join(lines, "\n"))
+		p, err := NewFactory(testRegistry)(dataStream)
+		require.NoError(t, err)
+		mockClassifier := &testClassifier{}
+		mockClassifier.On("Classify", mock.Anything).Return(&classification.ClassifierResult{
+			Events:  []*parsers.Result{newTestLog()},
+			Matched: true,
+		}, nil)
+		mockClassifier.On("Stats", mock.Anything).Return(&classification.ClassifierStats{})
+		mockClassifier.On("ParserStats", mock.Anything).Return(map[string]*classification.ParserStats{
+			testLogType: {LogType: testLogType},
+		})
+		p.classifier = mockClassifier
+		destination := (&testDestination{}).standardMock()
+		streams := make(chan *common.DataStream, 1)
+		streams <- dataStream
+		close(streams)
+		require.NoError(t, Process(streams, destination, func(*common.DataStream) (*Processor, error) { return p, nil }))
+		return destination, p
+	}
+
+	destination, p := run("a", "b", "c", "c")
+	// identical lines within a stream are kept
+	assert.Equal(t, uint64(3), destination.nEvents)
+	assert.Equal(t, uint64(1), p.dedup.duplicateCount(testLogType))
+	assert.Equal(t, uint64(1), p.dedup.duplicateCount(""))
+	assert.Len(t, store.recorded, 3)
+
+	// replayed events are dropped
+	destination, p = run("a", "c", "d")
+	assert.Equal(t, uint64(1), destination.nEvents)
+	assert.Equal(t, uint64(2), p.dedup.duplicateCount(""))
+	assert.Len(t, store.recorded, 4)
+
+	// events are forwarded if the store fails
+	store.err = errors.New("failed")
+	destination, p = run("a", "b")
+	assert.Equal(t, uint64(2), destination.nEvents)
+	assert.Equal(t, uint64(0), p.dedup.duplicateCount(""))
+
+	// events of log types without deduplication are always forwarded
+	store.err = nil
+	DedupLogTypes = map[string]bool{"Other.LogType": true}
+	destination, p = run("a", "b", "e")
+	assert.Equal(t, uint64(3), destination.nEvents)
+	assert.Equal(t, uint64(0), p.dedup.duplicateCount(""))
+	assert.Len(t, store.recorded, 4)
+}
+
+func TestDeduplicatorMaxFingerprints(t *testing.T) {
+	d := newDeduplicator(&testDedupStore{}, map[string]bool{testLogType: true}, testSourceID)
+	d.maxFingerprints = 2
+	outputChan := make(chan *parsers.Result, 3)
+	for _, line := range []string{"a", "b", "c"} {
+		d.add(line, []*parsers.Result{newTestLog()}, outputChan)
+	}
+	d.flush(outputChan)
+	// all events are forwarded but only the first fingerprints are recorded
+	assert.Len(t, outputChan, 3)
+	assert.Len(t, d.fingerprints(), 2)
+	assert.Equal(t, 1, d.unrecorded)
+}
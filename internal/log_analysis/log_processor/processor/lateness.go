@@ -0,0 +1,51 @@
+package processor
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"time"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// sourceLateness returns the lateness policies of a source by log type
+func sourceLateness(src *models.SourceIntegration) map[string]*pantherlog.LatenessPolicy {
+	if len(src.Lateness) == 0 {
+		return nil
+	}
+	policies := make(map[string]*pantherlog.LatenessPolicy, len(src.Lateness))
+	for _, p := range src.Lateness {
+		policy := pantherlog.LatenessPolicy{
+			PartitionByParseTime: p.PartitionBy == models.PartitionByParseTime,
+			MaxDelay:             time.Duration(p.MaxDelaySeconds) * time.Second,
+			MaxFutureSkew:        time.Duration(p.MaxFutureSkewSeconds) * time.Second,
+		}
+		switch p.FutureEvents {
+		case models.FutureEventsClamp:
+			policy.FutureEvents = pantherlog.FutureEventsClamp
+		case models.FutureEventsQuarantine:
+			policy.FutureEvents = pantherlog.FutureEventsQuarantine
+		default:
+			policy.FutureEvents = pantherlog.FutureEventsAllow
+		}
+		policies[p.LogType] = &policy
+	}
+	return policies
+}
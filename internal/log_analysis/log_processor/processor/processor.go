@@ -20,7 +20,6 @@ package processor
 
 import (
 	"bufio"
-	"io"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -32,6 +31,8 @@ import (
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/multiline"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
 	"github.com/panther-labs/panther/pkg/metrics"
@@ -52,6 +53,9 @@ var (
 	// to avoid using up lot of memory.
 	// see also: https://golang.org/doc/effective_go.html#channels
 	ParsedEventBufferSize = 1000
+
+	// Enricher adds enrichment fields to parsed events, it is set at startup if enrichment data are available.
+	Enricher pantherlog.Enricher
 )
 
 type ProcessFunc func(streamCh <-chan *common.DataStream, dest destinations.Destination) error
@@ -69,6 +73,8 @@ func Process(
 		err            error
 		resultsChannel = make(chan *parsers.Result, ParsedEventBufferSize)
 		errorChannel   = make(chan error)
+		// fingerprints of the forwarded events, recorded only if all events are delivered
+		fingerprints []string
 		// Process streams serially to keep memory requirements low
 		processStreams = func() error {
 			defer close(resultsChannel)
@@ -85,6 +91,7 @@ func Process(
 				if err := processor.run(resultsChannel); err != nil {
 					return err
 				}
+				fingerprints = appendFingerprints(fingerprints, processor.dedup.fingerprints())
 			}
 			return nil
 		}
@@ -116,19 +123,62 @@ func Process(
 		err = multierr.Append(err, e)
 	}
 	zap.L().Debug("data processing goroutines finished")
+	if err == nil && len(fingerprints) > 0 {
+		recordFingerprints(fingerprints)
+	}
 	return err
 }
 
+// appendFingerprints appends the fingerprints of a stream up to maxDedupFingerprints for all streams.
+func appendFingerprints(fingerprints, streamFingerprints []string) []string {
+	if n := maxDedupFingerprints - len(fingerprints); len(streamFingerprints) > n {
+		zap.L().Warn("too many events to record for deduplication", zap.Int("numUnrecorded", len(streamFingerprints)-n))
+		streamFingerprints = streamFingerprints[:n]
+	}
+	return append(fingerprints, streamFingerprints...)
+}
+
+func recordFingerprints(fingerprints []string) {
+	// The events are already delivered, failing here would only cause them to be stored again
+	if err := DedupStore.Record(fingerprints); err != nil {
+		zap.L().Warn("failed to record event fingerprints", zap.Int("numFingerprints", len(fingerprints)), zap.Error(err))
+	}
+}
+
 type Processor struct {
 	input      *common.DataStream
 	classifier classification.ClassifierAPI
 	operation  *oplog.Operation
+	// multiline frames the lines of sources with multi-line framing rules, it is nil for all other sources.
+	// Envelopes are detected from the start of the input for all sources, see DetectMultilineSplitter.
+	multiline *multiline.Splitter
+	// builder builds results for log entries that failed classification
+	builder pantherlog.ResultBuilder
+	// transforms are applied to results before they are sent to the destination
+	transforms *sourceTransforms
+	// enricher is set on results so that they are enriched when they are written to the destination
+	enricher pantherlog.Enricher
+	// lateness holds the lateness policies of the source by log type, they are set on results of each log type
+	lateness map[string]*pantherlog.LatenessPolicy
+	// dedup drops events already delivered within the dedup window, it is nil if deduplication is disabled
+	dedup *deduplicator
+	// err is set if results could not be transformed
+	err error
 }
 
 type Factory func(r *common.DataStream) (*Processor, error)
 
 func NewFactory(resolver logtypes.Resolver) Factory {
+	// Transform policies are shared by all streams
+	transforms := newSourceTransforms()
 	return func(input *common.DataStream) (*Processor, error) {
+		if err := transforms.addSource(input.Source); err != nil {
+			return nil, err
+		}
+		ml, err := sourceMultiline(input.Source)
+		if err != nil {
+			return nil, err
+		}
 		switch src := input.Source; src.IntegrationType {
 		case models.IntegrationTypeSqs:
 			return &Processor{
@@ -138,9 +188,14 @@ func NewFactory(resolver logtypes.Resolver) Factory {
 					Resolver:   resolver,
 					LoadSource: sources.LoadSource,
 				},
+				multiline:  ml,
+				transforms: transforms,
+				enricher:   Enricher,
+				lateness:   sourceLateness(src),
+				dedup:      newDeduplicator(DedupStore, DedupLogTypes, src.IntegrationID),
 			}, nil
 		case models.IntegrationTypeAWS3:
-			c, err := sources.BuildClassifier(src, resolver)
+			c, err := sources.BuildS3ObjectClassifier(src, input.S3ObjectKey, resolver)
 			if err != nil {
 				return nil, err
 			}
@@ -148,6 +203,11 @@ func NewFactory(resolver logtypes.Resolver) Factory {
 				operation:  common.OpLogManager.Start(operationName),
 				input:      input,
 				classifier: c,
+				multiline:  ml,
+				transforms: transforms,
+				enricher:   Enricher,
+				lateness:   sourceLateness(src),
+				dedup:      newDeduplicator(DedupStore, DedupLogTypes, src.IntegrationID),
 			}, nil
 		default:
 			return nil, errors.Errorf("invalid source type %s", src.IntegrationType)
@@ -155,30 +215,53 @@ func NewFactory(resolver logtypes.Resolver) Factory {
 	}
 }
 
+// sourceMultiline returns the multi-line splitter for sources with multi-line framing rules or nil for all other sources.
+func sourceMultiline(src *models.SourceIntegration) (*multiline.Splitter, error) {
+	if src.Multiline == nil {
+		return nil, nil
+	}
+	splitter, err := multiline.New(src.Multiline)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid multi-line config for source %s", src.IntegrationID)
+	}
+	return splitter, nil
+}
+
 // processStream reads the data from an S3 the dataStream, parses it and writes events to the output channel
 func (p *Processor) run(outputChan chan<- *parsers.Result) error {
-	var err error
 	stream := bufio.NewReader(p.input.Reader)
-	for {
-		var line string
-		line, err = stream.ReadString(common.EventDelimiter)
-		if err != nil {
-			if err == io.EOF { // we are done
-				err = nil // not really an error
-				p.processLogLine(line, outputChan)
-			}
-			break
-		}
-		p.processLogLine(line, outputChan)
+	var splitter Splitter
+	if p.multiline != nil {
+		splitter = DetectMultilineSplitter(stream, p.multiline)
+	} else {
+		splitter = DetectSplitter(stream)
 	}
-	if err != nil {
-		err = errors.Wrap(err, "failed to ReadString()")
+	var err error
+	if s, ok := splitter.(EnvelopeSplitter); ok {
+		err = s.SplitEnvelopes(stream, func(entry string, fields *pantherlog.EnvelopeFields) {
+			p.processLogLine(entry, fields, outputChan)
+		})
+	} else {
+		err = splitter.Split(stream, func(entry string) {
+			p.processLogLine(entry, nil, outputChan)
+		})
+	}
+	if err == nil {
+		err = p.err
+	}
+	if err == nil && p.dedup != nil {
+		p.dedup.flush(outputChan)
 	}
 	p.logStats(err) // emit log line describing the processing of the file and any errors
 	return err
 }
 
-func (p *Processor) processLogLine(line string, outputChan chan<- *parsers.Result) {
+// processLogLine classifies a log entry and sends the results to the output channel.
+// If the entry was delivered in an envelope, fields are set on all results of the entry.
+func (p *Processor) processLogLine(line string, fields *pantherlog.EnvelopeFields, outputChan chan<- *parsers.Result) {
+	if p.err != nil {
+		return
+	}
 	result, err := p.classifier.Classify(line)
 	// A classifier returns an error when it cannot classify a non-empty log line
 	if err != nil {
@@ -190,30 +273,90 @@ func (p *Processor) processLogLine(line string, outputChan chan<- *parsers.Resul
 			zap.String("s3Bucket", p.input.S3Bucket),
 			zap.String("s3ObjectKey", p.input.S3ObjectKey),
 		)
+		// store the log line in the classification failures table so it can be inspected and replayed
+		failure, err := p.newFailureResult(line, result)
+		if err != nil {
+			// Failures must not be stored without knowing if their log line needs redaction
+			p.err = err
+			return
+		}
+		if fields != nil {
+			failure.EnvelopeFields = *fields
+		}
+		outputChan <- failure
 		return
 	}
 	if result == nil {
 		return
 	}
+	for _, event := range result.Events {
+		if err := p.transforms.Apply(event, p.input.Source.IntegrationID); err != nil {
+			// Results must not be stored without their transforms, fail the stream so that it can be retried
+			p.err = err
+			return
+		}
+		event.Enricher = p.enricher
+		// The lateness policy is applied after transforms since they can replace the event time
+		event.ApplyLateness(p.lateness[event.PantherLogType])
+		if fields != nil {
+			event.EnvelopeFields = *fields
+		}
+	}
+	if p.dedup != nil {
+		p.dedup.add(line, result.Events, outputChan)
+		return
+	}
 	for _, event := range result.Events {
 		outputChan <- event
 	}
 }
 
+// newFailureResult builds the classification failure result of a log line.
+// The transforms of a source cannot be applied to a log line that failed to parse, so the line is omitted
+// for sources with transforms. The S3 object key and line number of the failure still locate the original entry.
+func (p *Processor) newFailureResult(line string, result *classification.ClassifierResult) (*parsers.Result, error) {
+	redact, err := p.transforms.HasTransforms(p.input.Source.IntegrationID)
+	if err != nil {
+		return nil, err
+	}
+	failure := classification.Failure{
+		SourceID:    p.input.Source.IntegrationID,
+		SourceLabel: p.input.Source.IntegrationLabel,
+		S3Bucket:    p.input.S3Bucket,
+		S3ObjectKey: p.input.S3ObjectKey,
+		LineNumber:  p.classifier.Stats().LogLineCount,
+	}
+	if !redact {
+		failure.Line = line
+	}
+	if result != nil {
+		failure.Errors = classification.NewFailureErrors(result.ParserErrors)
+	}
+	// ResultBuilder.BuildResult never fails
+	failureResult, _ := p.builder.BuildResult(classification.FailureLogType, &failure)
+	return failureResult, nil
+}
+
 func (p *Processor) logStats(err error) {
 	p.operation.Stop()
-	p.operation.Log(err, zap.Any(statsKey, *p.classifier.Stats()))
+	// Stats are copied since some classifiers return aggregated stats
+	stats := *p.classifier.Stats()
+	stats.DuplicateEventCount = p.dedup.duplicateCount("")
+	p.operation.Log(err, zap.Any(statsKey, stats))
 	logType := metrics.Dimension{Name: "LogType"}
 	pMetrics := []metrics.Metric{
 		{Name: "BytesProcessed"},
 		{Name: "EventsProcessed"},
 		{Name: "CombinedLatency"},
+		{Name: "DuplicateEvents"},
 	}
-	for _, parserStats := range p.classifier.ParserStats() {
-		p.operation.Log(err, zap.Any(statsKey, *parserStats))
+	for _, s := range p.classifier.ParserStats() {
+		parserStats := *s
+		parserStats.DuplicateEventCount = p.dedup.duplicateCount(parserStats.LogType)
+		p.operation.Log(err, zap.Any(statsKey, parserStats))
 		logType.Value = parserStats.LogType
-		pMetrics[0].Value, pMetrics[1].Value, pMetrics[2].Value =
-			parserStats.BytesProcessedCount, parserStats.EventCount, parserStats.CombinedLatency
+		pMetrics[0].Value, pMetrics[1].Value, pMetrics[2].Value, pMetrics[3].Value =
+			parserStats.BytesProcessedCount, parserStats.EventCount, parserStats.CombinedLatency, parserStats.DuplicateEventCount
 		common.BytesProcessedLogger.Log(pMetrics, logType)
 	}
 }
@@ -37,6 +37,7 @@ import (
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
@@ -283,6 +284,8 @@ func TestProcessClassifyFailure(t *testing.T) {
 	close(streamChan)
 	err = Process(streamChan, destination, newProcessorFunc)
 	require.NoError(t, err)
+	// the line that failed classification is sent to the destination as a classification failure
+	require.Equal(t, testLogLines, destination.nEvents)
 
 	actual := logs.AllUntimed()
 	embeddedMetric := metrics.EmbeddedMetric{
@@ -303,6 +306,10 @@ func TestProcessClassifyFailure(t *testing.T) {
 						Name: "CombinedLatency",
 						Unit: metrics.UnitMilliseconds,
 					},
+					{
+						Name: "DuplicateEvents",
+						Unit: metrics.UnitCount,
+					},
 				},
 			},
 		},
@@ -395,6 +402,10 @@ func TestProcessClassifyFailure(t *testing.T) {
 					Key:     "CombinedLatency",
 					Integer: 0,
 				},
+				{
+					Key:     "DuplicateEvents",
+					Integer: 0,
+				},
 				{
 					Key:       "_aws",
 					Interface: embeddedMetric,
@@ -442,6 +453,116 @@ func assertLogEqual(t *testing.T, expected, actual observer.LoggedEntry) {
 	}
 }
 
+func TestNewFactoryMultiline(t *testing.T) {
+	src := *testSource
+	src.Multiline = &models.MultilineConfig{EventStartPattern: `^line`}
+	dataStream := makeDataStream()
+	dataStream.Source = &src
+	p, err := NewFactory(testRegistry)(dataStream)
+	require.NoError(t, err)
+	require.NotNil(t, p.multiline)
+
+	src.Multiline = &models.MultilineConfig{EventStartPattern: `^(`}
+	_, err = NewFactory(testRegistry)(dataStream)
+	require.Error(t, err)
+
+	// Sources without multi-line config split streams as detected
+	p, err = NewFactory(testRegistry)(makeDataStream())
+	require.NoError(t, err)
+	require.Nil(t, p.multiline)
+}
+
+type testEnricher struct{}
+
+func (testEnricher) Enrich(_ *pantherlog.EnrichmentFields, _ *pantherlog.ValueBuffer) {}
+
+func TestProcessLogLineEnricher(t *testing.T) {
+	defer func(enricher pantherlog.Enricher) {
+		Enricher = enricher
+	}(Enricher)
+	Enricher = testEnricher{}
+
+	p, err := NewFactory(testRegistry)(makeDataStream())
+	require.NoError(t, err)
+	mockClassifier := &testClassifier{}
+	mockClassifier.On("Classify", mock.Anything).Return(&classification.ClassifierResult{
+		Events:  []*parsers.Result{newTestLog()},
+		Matched: true,
+	}, nil)
+	p.classifier = mockClassifier
+
+	results := make(chan *parsers.Result, 1)
+	p.processLogLine(testLogLine, nil, results)
+	require.Len(t, results, 1)
+	require.Equal(t, testEnricher{}, (<-results).Enricher)
+}
+
+func TestProcessLogLineFailureRedacted(t *testing.T) {
+	src := *testSource
+	dataStream := makeDataStream()
+	dataStream.Source = &src
+	p, err := NewFactory(testRegistry)(dataStream)
+	require.NoError(t, err)
+	mockClassifier := &testClassifier{}
+	mockClassifier.On("Classify", mock.Anything).Return(&classification.ClassifierResult{}, errors.New("fail"))
+	mockClassifier.On("Stats").Return(&classification.ClassifierStats{LogLineCount: 1})
+	p.classifier = mockClassifier
+
+	results := make(chan *parsers.Result, 2)
+	p.processLogLine("secret line", nil, results)
+	require.Len(t, results, 1)
+	failure := (<-results).Event.(*classification.Failure)
+	require.Equal(t, "secret line", failure.Line)
+	require.Equal(t, uint64(1), failure.LineNumber)
+
+	// the transforms of a source cannot be applied to log lines that failed to parse
+	src.IntegrationID = "transformed"
+	src.Transforms = []models.FieldTransform{
+		{LogType: testLogType, Path: "host", Action: models.FieldTransformReplace, Value: "REDACTED"},
+	}
+	p, err = NewFactory(testRegistry)(dataStream)
+	require.NoError(t, err)
+	p.classifier = mockClassifier
+	p.processLogLine("secret line", nil, results)
+	require.NoError(t, p.err)
+	require.Len(t, results, 1)
+	failure = (<-results).Event.(*classification.Failure)
+	require.Empty(t, failure.Line)
+	require.Equal(t, testKey, failure.S3ObjectKey)
+	require.Equal(t, uint64(1), failure.LineNumber)
+}
+
+func TestProcessCloudWatchLogsEnvelope(t *testing.T) {
+	dataStream := makeDataStream()
+	dataStream.Reader = strings.NewReader(`{"messageType":"DATA_MESSAGE","logGroup":"group","logStream":"stream",` +
+		`"logEvents":[{"id":"1","timestamp":1577836861000,"message":"line"},{"id":"2","timestamp":1577836861000,"message":"bad line"}]}`)
+	p, err := NewFactory(testRegistry)(dataStream)
+	require.NoError(t, err)
+	mockClassifier := &testClassifier{}
+	mockClassifier.On("Classify", testLogLine).Return(&classification.ClassifierResult{
+		Events:  []*parsers.Result{newTestLog()},
+		Matched: true,
+	}, nil)
+	mockClassifier.On("Classify", "bad line").Return(&classification.ClassifierResult{}, errors.New("fail"))
+	mockClassifier.On("Stats").Return(&classification.ClassifierStats{})
+	mockClassifier.On("ParserStats").Return(map[string]*classification.ParserStats{})
+	p.classifier = mockClassifier
+
+	results := make(chan *parsers.Result, 2)
+	require.NoError(t, p.run(results))
+	require.Len(t, results, 2)
+	expect := pantherlog.EnvelopeFields{
+		PantherCloudWatchLogGroup:  "group",
+		PantherCloudWatchLogStream: "stream",
+	}
+	event := <-results
+	require.Equal(t, testLogType, event.PantherLogType)
+	require.Equal(t, expect, event.EnvelopeFields)
+	failure := <-results
+	require.Equal(t, classification.FailureLogType, failure.PantherLogType)
+	require.Equal(t, expect, failure.EnvelopeFields)
+}
+
 type testDestination struct {
 	destinations.Destination
 	mock.Mock
@@ -0,0 +1,241 @@
+package processor
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/multiline"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/zeeklogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
+)
+
+const (
+	// The size of the buffer used by the streaming JSON iterator.
+	// Elements larger than this are captured across buffer refills so this only affects I/O granularity.
+	jsonStreamBufferSize = 8192
+	// How many bytes to peek at the start of a stream to detect its framing.
+	detectPeekSize = 512
+	// Well-known envelope key used by AWS for 'document' JSON files (ie CloudTrail)
+	recordsEnvelopeKey = "Records"
+)
+
+// Splitter splits an input stream into log entries that are classified individually.
+type Splitter interface {
+	// Split reads r until EOF calling emit for each log entry.
+	Split(r *bufio.Reader, emit func(entry string)) error
+}
+
+// SplitterFunc is a function implementing the Splitter interface
+type SplitterFunc func(r *bufio.Reader, emit func(entry string)) error
+
+var _ Splitter = (SplitterFunc)(nil)
+
+// Split implements Splitter interface
+func (f SplitterFunc) Split(r *bufio.Reader, emit func(entry string)) error {
+	return f(r, emit)
+}
+
+// EnvelopeSplitter splits a stream of delivery envelopes into log entries.
+// The fields of each envelope are passed to emit along with the log entries it contains.
+type EnvelopeSplitter interface {
+	Splitter
+	// SplitEnvelopes reads r until EOF calling emit for each log entry.
+	SplitEnvelopes(r *bufio.Reader, emit func(entry string, fields *pantherlog.EnvelopeFields)) error
+}
+
+// CloudWatchLogsSplitter emits the message of each log event in CloudWatch Logs subscription envelopes as a separate entry.
+var CloudWatchLogsSplitter EnvelopeSplitter = cloudWatchLogsSplitter{}
+
+type cloudWatchLogsSplitter struct{}
+
+// Split implements Splitter interface
+func (cloudWatchLogsSplitter) Split(r *bufio.Reader, emit func(entry string)) error {
+	return sources.SplitCloudWatchLogs(r, func(message string, _ *pantherlog.EnvelopeFields) {
+		emit(message)
+	})
+}
+
+// SplitEnvelopes implements EnvelopeSplitter interface
+func (cloudWatchLogsSplitter) SplitEnvelopes(r *bufio.Reader, emit func(entry string, fields *pantherlog.EnvelopeFields)) error {
+	return sources.SplitCloudWatchLogs(r, emit)
+}
+
+// LineSplitter splits the stream in lines delimited by common.EventDelimiter
+var LineSplitter = SplitterFunc(splitLines)
+
+func splitLines(r *bufio.Reader, emit func(entry string)) error {
+	for {
+		line, err := r.ReadString(common.EventDelimiter)
+		if err != nil {
+			if err == io.EOF { // we are done
+				emit(line)
+				return nil
+			}
+			return errors.Wrap(err, "failed to ReadString()")
+		}
+		emit(line)
+	}
+}
+
+// JSONArraySplitter emits each element of top-level JSON arrays as a separate entry.
+// Multiple concatenated arrays in the same stream are supported.
+var JSONArraySplitter = SplitterFunc(func(r *bufio.Reader, emit func(entry string)) error {
+	return splitJSON(r, emit, func(iter *jsoniter.Iterator, emit func(string)) {
+		emitArrayElements(iter, emit)
+	})
+})
+
+// JSONEnvelopeSplitter emits each element of an array nested under Key in top-level JSON objects
+// (ie `{"Records":[...]}`) as a separate entry. Other fields of the envelope are skipped.
+type JSONEnvelopeSplitter struct {
+	Key string
+}
+
+var _ Splitter = (*JSONEnvelopeSplitter)(nil)
+
+// Split implements Splitter interface
+func (s *JSONEnvelopeSplitter) Split(r *bufio.Reader, emit func(entry string)) error {
+	return splitJSON(r, emit, func(iter *jsoniter.Iterator, emit func(string)) {
+		for key := iter.ReadObject(); key != ""; key = iter.ReadObject() {
+			if key != s.Key {
+				iter.Skip()
+				continue
+			}
+			emitArrayElements(iter, emit)
+		}
+	})
+}
+
+// splitJSON uses a streaming iterator so that only a single element needs to be held in memory at any time
+func splitJSON(r *bufio.Reader, emit func(string), readDocument func(iter *jsoniter.Iterator, emit func(string))) error {
+	iter := jsoniter.Parse(jsoniter.ConfigDefault, r, jsonStreamBufferSize)
+	for {
+		if iter.WhatIsNext() == jsoniter.InvalidValue {
+			break
+		}
+		readDocument(iter, emit)
+		if iter.Error != nil {
+			break
+		}
+	}
+	if err := iter.Error; err != nil && err != io.EOF {
+		return errors.Wrap(err, "failed to read JSON stream")
+	}
+	return nil
+}
+
+func emitArrayElements(iter *jsoniter.Iterator, emit func(string)) {
+	for iter.ReadArray() {
+		// Skip whitespace so that it is not captured along with the element
+		iter.WhatIsNext()
+		element := iter.SkipAndReturnBytes()
+		if iter.Error != nil {
+			return
+		}
+		emit(string(element))
+	}
+}
+
+var (
+	jsonArrayStart    = regexp.MustCompile(`^\s*\[`)
+	jsonEnvelopeStart = regexp.MustCompile(`^\s*\{\s*"` + recordsEnvelopeKey + `"\s*:\s*\[`)
+)
+
+// DetectSplitter peeks at the start of a stream to choose the appropriate Splitter.
+// Streams that start with a JSON array or a `{"Records":[` envelope are split using a streaming JSON iterator,
+// CloudWatch Logs subscription envelopes are split in the messages of their log events,
+// all other streams are split in lines.
+func DetectSplitter(r *bufio.Reader) Splitter {
+	if splitter := detectFraming(r); splitter != nil {
+		return splitter
+	}
+	return LineSplitter
+}
+
+// DetectMultilineSplitter is like DetectSplitter but frames lines into multi-line events using ml.
+// The messages of CloudWatch Logs subscription envelopes are framed within each envelope.
+// JSON and Zeek TSV streams are split as detected since each of their entries is a complete event.
+func DetectMultilineSplitter(r *bufio.Reader, ml *multiline.Splitter) Splitter {
+	switch splitter := detectFraming(r).(type) {
+	case nil:
+		return ml
+	case EnvelopeSplitter:
+		return &multilineEnvelopeSplitter{splitter: splitter, multiline: ml}
+	default:
+		return splitter
+	}
+}
+
+// detectFraming returns the splitter for streams that are not split in lines or nil
+func detectFraming(r *bufio.Reader) Splitter {
+	// Errors are ignored here, they will be returned by the splitter reading the stream
+	head, _ := r.Peek(detectPeekSize)
+	switch {
+	case jsonArrayStart.Match(head):
+		return JSONArraySplitter
+	case jsonEnvelopeStart.Match(head):
+		return &JSONEnvelopeSplitter{Key: recordsEnvelopeKey}
+	case sources.IsCloudWatchLogsEnvelope(head):
+		return CloudWatchLogsSplitter
+	case zeeklogs.IsTSV(head):
+		return SplitterFunc(zeeklogs.SplitTSV)
+	default:
+		return nil
+	}
+}
+
+// multilineEnvelopeSplitter frames the log entries of each envelope into multi-line events
+type multilineEnvelopeSplitter struct {
+	splitter  EnvelopeSplitter
+	multiline *multiline.Splitter
+}
+
+var _ EnvelopeSplitter = (*multilineEnvelopeSplitter)(nil)
+
+// Split implements Splitter interface
+func (s *multilineEnvelopeSplitter) Split(r *bufio.Reader, emit func(entry string)) error {
+	return s.SplitEnvelopes(r, func(entry string, _ *pantherlog.EnvelopeFields) {
+		emit(entry)
+	})
+}
+
+// SplitEnvelopes implements EnvelopeSplitter interface
+func (s *multilineEnvelopeSplitter) SplitEnvelopes(r *bufio.Reader, emit func(entry string, fields *pantherlog.EnvelopeFields)) error {
+	var fields *pantherlog.EnvelopeFields
+	add, flush := s.multiline.Frame(func(entry string) {
+		emit(entry, fields)
+	})
+	err := s.splitter.SplitEnvelopes(r, func(entry string, envelope *pantherlog.EnvelopeFields) {
+		if envelope != fields {
+			flush()
+			fields = envelope
+		}
+		add(entry)
+	})
+	flush()
+	return err
+}
@@ -0,0 +1,165 @@
+package processor
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/multiline"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+func TestDetectSplitter(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Input    string
+		Expect   []string
+		ExpectOK bool
+	}
+	for _, tc := range []testCase{
+		{
+			Name:     "lines",
+			Input:    "{\"foo\":1}\n{\"foo\":2}",
+			Expect:   []string{"{\"foo\":1}\n", `{"foo":2}`},
+			ExpectOK: true,
+		},
+		{
+			Name:     "array",
+			Input:    "  [{\"foo\":1},\n{\"foo\":[2, 3]}, \"bar\"]\n[4]",
+			Expect:   []string{`{"foo":1}`, `{"foo":[2, 3]}`, `"bar"`, `4`},
+			ExpectOK: true,
+		},
+		{
+			Name:     "envelope",
+			Input:    `{"Records":[{"foo":1},{"foo":{"Records":[]}}],"other":"skipped"}`,
+			Expect:   []string{`{"foo":1}`, `{"foo":{"Records":[]}}`},
+			ExpectOK: true,
+		},
+		{
+			Name:     "envelope not first key",
+			Input:    `{"other":1,"Records":[{"foo":1}]}`,
+			Expect:   []string{`{"other":1,"Records":[{"foo":1}]}`},
+			ExpectOK: true,
+		},
+		{
+			Name: "cloudwatch logs",
+			Input: `{"messageType":"CONTROL_MESSAGE","logEvents":[{"message":"CWL CONTROL MESSAGE"}]}` + "\n" +
+				`{"messageType":"DATA_MESSAGE","logGroup":"group","logStream":"stream","logEvents":[{"message":"foo"},{"message":"{\"bar\":1}"}]}`,
+			Expect:   []string{"foo", `{"bar":1}`},
+			ExpectOK: true,
+		},
+		{
+			Name:     "zeek tsv",
+			Input:    "#separator \\x09\n#fields\tts\tuid\ttags\n#types\ttime\tstring\tset[string]\n1591367999.305988\tCMdzit1AMNsmfAIiQc\ta,b\n#close\t2020-06-05-15-00-00\n",
+			Expect:   []string{`{"ts":1591367999.305988,"uid":"CMdzit1AMNsmfAIiQc","tags":["a","b"]}`},
+			ExpectOK: true,
+		},
+		{
+			Name:   "invalid array",
+			Input:  `[{"foo":1},{"foo"`,
+			Expect: []string{`{"foo":1}`},
+		},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tc.Input))
+			var entries []string
+			err := DetectSplitter(r).Split(r, func(entry string) {
+				entries = append(entries, entry)
+			})
+			if tc.ExpectOK {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+			require.Equal(t, tc.Expect, entries)
+		})
+	}
+}
+
+func TestDetectMultilineSplitter(t *testing.T) {
+	ml, err := multiline.New(&models.MultilineConfig{IndentedContinuation: true})
+	require.NoError(t, err)
+	type entry struct {
+		Entry    string
+		LogGroup string
+	}
+	for _, tc := range []struct {
+		Name   string
+		Input  string
+		Expect []entry
+	}{
+		{
+			Name:   "lines",
+			Input:  "first\n  continued\nsecond",
+			Expect: []entry{{Entry: "first\n  continued\n"}, {Entry: "second"}},
+		},
+		{
+			Name:   "array",
+			Input:  `[{"foo":1}, {"foo":2}]`,
+			Expect: []entry{{Entry: `{"foo":1}`}, {Entry: `{"foo":2}`}},
+		},
+		{
+			Name: "cloudwatch logs",
+			Input: `{"messageType":"DATA_MESSAGE","logGroup":"a","logEvents":[{"message":"first"},{"message":"  continued"}]}` + "\n" +
+				`{"messageType":"DATA_MESSAGE","logGroup":"b","logEvents":[{"message":"  other envelope"},{"message":"second"}]}`,
+			Expect: []entry{
+				{Entry: "first\n  continued", LogGroup: "a"},
+				{Entry: "  other envelope", LogGroup: "b"},
+				{Entry: "second", LogGroup: "b"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tc.Input))
+			var entries []entry
+			splitter := DetectMultilineSplitter(r, ml)
+			if s, ok := splitter.(EnvelopeSplitter); ok {
+				err = s.SplitEnvelopes(r, func(e string, fields *pantherlog.EnvelopeFields) {
+					entries = append(entries, entry{Entry: e, LogGroup: fields.PantherCloudWatchLogGroup})
+				})
+			} else {
+				err = splitter.Split(r, func(e string) {
+					entries = append(entries, entry{Entry: e})
+				})
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.Expect, entries)
+		})
+	}
+}
+
+func TestJSONSplitterLargeElement(t *testing.T) {
+	// Elements larger than the iterator buffer are captured across buffer refills
+	value := strings.Repeat("x", 3*jsonStreamBufferSize)
+	input := `{"Records":[{"foo":"` + value + `"},{"bar":"baz"}]}`
+	r := bufio.NewReader(strings.NewReader(input))
+	var entries []string
+	err := DetectSplitter(r).Split(r, func(entry string) {
+		entries = append(entries, entry)
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{`{"foo":"` + value + `"}`, `{"bar":"baz"}`}, entries)
+}
@@ -61,12 +61,13 @@ func StreamEvents(
 	process := func(streams <-chan *common.DataStream, dest destinations.Destination) error {
 		return Process(streams, dest, newProcessor)
 	}
-	return streamEvents(sqsClient, deadlineTime, event, process, sources.ReadSnsMessages)
+	return streamEvents(sqsClient, resolver, deadlineTime, event, process, sources.ReadSnsMessages)
 }
 
 // entry point for unit testing, pass in read/process functions
 func streamEvents(
 	sqsClient sqsiface.SQSAPI,
+	resolver logtypes.Resolver,
 	deadlineTime time.Time,
 	event events.SQSEvent,
 	processFunc ProcessFunc,
@@ -159,7 +160,7 @@ func streamEvents(
 	// Use a properly configured JSON API for Athena quirks
 	jsonAPI := common.BuildJSON()
 	// process streamChan until closed (blocks)
-	dest := destinations.CreateS3Destination(jsonAPI)
+	dest := destinations.CreateS3Destination(resolver, jsonAPI)
 	if err := processFunc(streamChan, dest); err != nil {
 		return 0, err
 	}
@@ -91,7 +91,7 @@ func TestStreamEventsLambdaPlusSQS(t *testing.T) {
 	streamTestSqsClient.On("GetQueueAttributes", mock.Anything).Return(streamTestMessagesBelowThreshold, nil).Once()
 	streamTestSqsClient.On("DeleteMessageBatch", mock.Anything).Return(&sqs.DeleteMessageBatchOutput{}, nil).Once()
 
-	sqsMessageCount, err := streamEvents(streamTestSqsClient, streamTestDeadline, streamTestLambdaEvent,
+	sqsMessageCount, err := streamEvents(streamTestSqsClient, nil, streamTestDeadline, streamTestLambdaEvent,
 		noopProcessorFunc, noopReadSnsMessagesFunc)
 	require.NoError(t, err)
 	assert.Equal(t, len(streamTestLambdaEvent.Records)+len(streamTestReceiveMessageOutput.Messages), sqsMessageCount)
@@ -105,7 +105,7 @@ func TestStreamEventsOnlyLambda(t *testing.T) {
 	// this one has no messages, which breaks the loop
 	streamTestSqsClient.On("GetQueueAttributes", mock.Anything).Return(streamTestMessagesBelowThreshold, nil).Once()
 
-	sqsMessageCount, err := streamEvents(streamTestSqsClient, streamTestDeadline, streamTestLambdaEvent,
+	sqsMessageCount, err := streamEvents(streamTestSqsClient, nil, streamTestDeadline, streamTestLambdaEvent,
 		noopProcessorFunc, noopReadSnsMessagesFunc)
 	require.NoError(t, err)
 	assert.Equal(t, len(streamTestLambdaEvent.Records), sqsMessageCount)
@@ -118,7 +118,7 @@ func TestStreamEventsProcessingTimeLimitExceeded(t *testing.T) {
 	// should only process the lambda events although there are sqs events in the q cuz of timeout
 	deadline := streamTestDeadline.Add(-defaultTestTimeLimit) // polling loop should not be entered
 
-	sqsMessageCount, err := streamEvents(streamTestSqsClient, deadline, streamTestLambdaEvent,
+	sqsMessageCount, err := streamEvents(streamTestSqsClient, nil, deadline, streamTestLambdaEvent,
 		noopProcessorFunc, noopReadSnsMessagesFunc)
 	require.NoError(t, err)
 	assert.Equal(t, len(streamTestLambdaEvent.Records), sqsMessageCount)
@@ -128,7 +128,7 @@ func TestStreamEventsProcessingTimeLimitExceeded(t *testing.T) {
 func TestStreamEventsReadEventError(t *testing.T) {
 	initTest()
 
-	_, err := streamEvents(streamTestSqsClient, streamTestDeadline, streamTestLambdaEvent,
+	_, err := streamEvents(streamTestSqsClient, nil, streamTestDeadline, streamTestLambdaEvent,
 		noopProcessorFunc, failReadSnsMessagesFunc)
 	require.Error(t, err)
 	assert.Equal(t, "readEventError", err.Error())
@@ -140,7 +140,7 @@ func TestStreamEventsProcessError(t *testing.T) {
 	// ensure sqs reading go routine exits quickly to avoid data races between tests
 	deadline := streamTestDeadline.Add(-defaultTestTimeLimit) // polling loop should not be entered
 
-	_, err := streamEvents(streamTestSqsClient, deadline, streamTestLambdaEvent,
+	_, err := streamEvents(streamTestSqsClient, nil, deadline, streamTestLambdaEvent,
 		failProcessorFunc, noopReadSnsMessagesFunc)
 	require.Error(t, err)
 	assert.Equal(t, "processError", err.Error())
@@ -149,7 +149,7 @@ func TestStreamEventsProcessError(t *testing.T) {
 func TestStreamEventsProcessErrorAndReadEventError(t *testing.T) {
 	initTest()
 
-	_, err := streamEvents(streamTestSqsClient, streamTestDeadline, streamTestLambdaEvent,
+	_, err := streamEvents(streamTestSqsClient, nil, streamTestDeadline, streamTestLambdaEvent,
 		failProcessorFunc, failReadSnsMessagesFunc)
 	require.Error(t, err)
 	assert.Equal(t, "processError", err.Error()) // expect the processError NOT readEventError
@@ -163,7 +163,7 @@ func TestStreamEventsReceiveSQSError(t *testing.T) {
 	streamTestSqsClient.On("ReceiveMessage", mock.Anything).Return(&sqs.ReceiveMessageOutput{},
 		fmt.Errorf("receiveError")).Once()
 
-	sqsMessageCount, err := streamEvents(streamTestSqsClient, streamTestDeadline, streamTestLambdaEvent,
+	sqsMessageCount, err := streamEvents(streamTestSqsClient, nil, streamTestDeadline, streamTestLambdaEvent,
 		noopProcessorFunc, noopReadSnsMessagesFunc)
 	assert.Error(t, err)
 	assert.Equal(t, 0, sqsMessageCount)
@@ -186,7 +186,7 @@ func TestStreamEventsDeleteSQSError(t *testing.T) {
 		Successful: []*sqs.DeleteMessageBatchResultEntry{},
 	}, fmt.Errorf("deleteError")).Once()
 
-	sqsMessageCount, err := streamEvents(streamTestSqsClient, streamTestDeadline, streamTestLambdaEvent,
+	sqsMessageCount, err := streamEvents(streamTestSqsClient, nil, streamTestDeadline, streamTestLambdaEvent,
 		noopProcessorFunc, noopReadSnsMessagesFunc)
 
 	// keep sure we get error logging
@@ -0,0 +1,123 @@
+package processor
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/transform"
+	"github.com/panther-labs/panther/pkg/encryption"
+)
+
+// decryptTransformsKey decrypts the key used by the hash transforms of a source
+var decryptTransformsKey = func(ciphertext []byte) ([]byte, error) {
+	// KMS resolves the key from the ciphertext
+	return encryption.New("", common.Session).DecryptDataKey(ciphertext)
+}
+
+// sourceTransforms applies the field transforms of each source to the results it produced
+type sourceTransforms struct {
+	loadSource func(id string) (*models.SourceIntegration, error)
+	decryptKey func(ciphertext []byte) ([]byte, error)
+	policies   map[string]*transform.Policy
+}
+
+func newSourceTransforms() *sourceTransforms {
+	return &sourceTransforms{
+		loadSource: sources.LoadSource,
+		decryptKey: decryptTransformsKey,
+		policies:   make(map[string]*transform.Policy),
+	}
+}
+
+// Apply transforms a result using the policy of its source.
+// Results without a source id are transformed using the policy of the default source.
+func (t *sourceTransforms) Apply(result *parsers.Result, defaultSourceID string) error {
+	if t == nil {
+		return nil
+	}
+	sourceID := result.PantherSourceID
+	if sourceID == "" {
+		sourceID = defaultSourceID
+	}
+	policy, err := t.policy(sourceID)
+	if err != nil {
+		return err
+	}
+	policy.Apply(result)
+	return nil
+}
+
+// HasTransforms checks if a source has field transforms.
+// Transforms only apply to parsed events, so the raw log entries of such sources must not be stored as is.
+func (t *sourceTransforms) HasTransforms(sourceID string) (bool, error) {
+	if t == nil {
+		return false, nil
+	}
+	policy, err := t.policy(sourceID)
+	if err != nil {
+		return false, err
+	}
+	return policy != nil, nil
+}
+
+// policy returns the transform policy of a source, loading the source if needed
+func (t *sourceTransforms) policy(sourceID string) (*transform.Policy, error) {
+	if policy, ok := t.policies[sourceID]; ok {
+		return policy, nil
+	}
+	src, err := t.loadSource(sourceID)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to load source %q", sourceID)
+	}
+	if err := t.addSource(src); err != nil {
+		return nil, err
+	}
+	return t.policies[sourceID], nil
+}
+
+// addSource builds the transform policy of a source
+func (t *sourceTransforms) addSource(src *models.SourceIntegration) error {
+	if _, ok := t.policies[src.IntegrationID]; ok {
+		return nil
+	}
+	if len(src.Transforms) == 0 {
+		// A nil policy does not modify results
+		t.policies[src.IntegrationID] = nil
+		return nil
+	}
+	var hashKey []byte
+	if len(src.TransformsHashKey) > 0 {
+		key, err := t.decryptKey(src.TransformsHashKey)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to decrypt transforms key of source %q", src.IntegrationID)
+		}
+		hashKey = key
+	}
+	policy, err := transform.New(src.Transforms, hashKey)
+	if err != nil {
+		return errors.WithMessagef(err, "invalid transforms for source %q", src.IntegrationID)
+	}
+	t.policies[src.IntegrationID] = policy
+	return nil
+}
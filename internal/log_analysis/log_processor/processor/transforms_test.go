@@ -0,0 +1,135 @@
+package processor
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+type transformEvent struct {
+	UserName string `json:"userName"`
+	Host     string `json:"host"`
+}
+
+func newTransformResult(sourceID string) *parsers.Result {
+	return &parsers.Result{
+		CoreFields: pantherlog.CoreFields{
+			PantherLogType:  testLogType,
+			PantherSourceID: sourceID,
+		},
+		Event: &transformEvent{
+			UserName: "alice",
+			Host:     "host.example.com",
+		},
+	}
+}
+
+func TestSourceTransforms(t *testing.T) {
+	sources := map[string]*models.SourceIntegration{
+		"s3": {
+			SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+				IntegrationID: "s3",
+				Transforms: []models.FieldTransform{
+					{LogType: testLogType, Path: "userName", Action: models.FieldTransformDrop},
+				},
+			},
+		},
+		"sqs": {
+			SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+				IntegrationID: "sqs",
+				Transforms: []models.FieldTransform{
+					{LogType: testLogType, Path: "host", Action: models.FieldTransformReplace, Value: "REDACTED"},
+				},
+			},
+		},
+		"none": {
+			SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+				IntegrationID: "none",
+			},
+		},
+	}
+	loaded := map[string]int{}
+	transforms := newSourceTransforms()
+	transforms.loadSource = func(id string) (*models.SourceIntegration, error) {
+		loaded[id]++
+		if src, ok := sources[id]; ok {
+			return src, nil
+		}
+		return nil, errors.New("not found")
+	}
+	require.NoError(t, transforms.addSource(sources["s3"]))
+
+	result := newTransformResult("s3")
+	require.NoError(t, transforms.Apply(result, "s3"))
+	assert.Equal(t, &transformEvent{Host: "host.example.com"}, result.Event)
+
+	// results without a source id use the default source
+	result = newTransformResult("")
+	require.NoError(t, transforms.Apply(result, "s3"))
+	assert.Equal(t, &transformEvent{Host: "host.example.com"}, result.Event)
+
+	for i := 0; i < 2; i++ {
+		result = newTransformResult("sqs")
+		require.NoError(t, transforms.Apply(result, "s3"))
+		assert.Equal(t, &transformEvent{UserName: "alice", Host: "REDACTED"}, result.Event)
+		result = newTransformResult("none")
+		require.NoError(t, transforms.Apply(result, "s3"))
+		assert.Equal(t, &transformEvent{UserName: "alice", Host: "host.example.com"}, result.Event)
+	}
+	// sources are loaded once
+	assert.Equal(t, map[string]int{"sqs": 1, "none": 1}, loaded)
+
+	require.Error(t, transforms.Apply(newTransformResult("missing"), "s3"))
+}
+
+func TestSourceTransformsHashKey(t *testing.T) {
+	src := &models.SourceIntegration{
+		SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+			IntegrationID: "s3",
+			Transforms: []models.FieldTransform{
+				{LogType: testLogType, Path: "userName", Action: models.FieldTransformHash},
+			},
+			TransformsHashKey: []byte("ciphertext"),
+		},
+	}
+	transforms := newSourceTransforms()
+	transforms.decryptKey = func(ciphertext []byte) ([]byte, error) {
+		return nil, errors.New("access denied")
+	}
+	require.Error(t, transforms.addSource(src))
+
+	transforms.decryptKey = func(ciphertext []byte) ([]byte, error) {
+		assert.Equal(t, []byte("ciphertext"), ciphertext)
+		return []byte("key"), nil
+	}
+	require.NoError(t, transforms.addSource(src))
+	result := newTransformResult("s3")
+	require.NoError(t, transforms.Apply(result, "s3"))
+	userName := result.Event.(*transformEvent).UserName
+	assert.Len(t, userName, 64)
+	assert.NotEqual(t, "alice", userName)
+}
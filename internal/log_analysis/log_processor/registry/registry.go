@@ -28,14 +28,19 @@ import (
 	// Register log types in init() blocks
 	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/apachelogs"
 	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/awslogs"
+	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/cefleeflogs"
 	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/cloudflarelogs"
+	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/duologs"
 	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/fastlylogs"
 	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/fluentdsyslogs"
 	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/gitlablogs"
 	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/gravitationallogs"
+	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/gsuitelogs"
 	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/juniperlogs"
+	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/kuberneteslogs"
 	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/laceworklogs"
 	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/nginxlogs"
+	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/oktalogs"
 	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/osquerylogs"
 	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/osseclogs"
 	_ "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/sophoslogs"
@@ -0,0 +1,87 @@
+package sources
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"io"
+	"regexp"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+const (
+	// CloudWatchLogsDataMessage is the message type of envelopes containing log events
+	CloudWatchLogsDataMessage = "DATA_MESSAGE"
+	// CloudWatchLogsControlMessage is the message type of envelopes sent to check that the destination is reachable
+	CloudWatchLogsControlMessage = "CONTROL_MESSAGE"
+)
+
+// CloudWatchLogsEnvelope is the payload delivered by CloudWatch Logs subscription filters.
+// See https://docs.aws.amazon.com/AmazonCloudWatch/latest/logs/SubscriptionFilters.html
+type CloudWatchLogsEnvelope struct {
+	MessageType         string                `json:"messageType"`
+	Owner               string                `json:"owner"`
+	LogGroup            string                `json:"logGroup"`
+	LogStream           string                `json:"logStream"`
+	SubscriptionFilters []string              `json:"subscriptionFilters"`
+	LogEvents           []CloudWatchLogsEvent `json:"logEvents"`
+}
+
+// CloudWatchLogsEvent is a single log event in a CloudWatch Logs subscription envelope
+type CloudWatchLogsEvent struct {
+	ID        string `json:"id"`
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+// CloudWatch Logs always writes the message type as the first key of the envelope
+var cloudWatchLogsEnvelopeStart = regexp.MustCompile(`^\s*\{\s*"messageType"\s*:`)
+
+// IsCloudWatchLogsEnvelope checks if the start of a stream is a CloudWatch Logs subscription envelope.
+// Envelopes are gzipped by CloudWatch Logs, so head should be the start of the decompressed stream.
+func IsCloudWatchLogsEnvelope(head []byte) bool {
+	return cloudWatchLogsEnvelopeStart.Match(head)
+}
+
+// SplitCloudWatchLogs reads concatenated CloudWatch Logs subscription envelopes from r until EOF.
+// It calls emit for the message of each log event along with the log group and stream of its envelope.
+// Control messages are skipped.
+func SplitCloudWatchLogs(r io.Reader, emit func(message string, fields *pantherlog.EnvelopeFields)) error {
+	dec := jsoniter.NewDecoder(r)
+	for dec.More() {
+		envelope := CloudWatchLogsEnvelope{}
+		if err := dec.Decode(&envelope); err != nil {
+			return errors.Wrap(err, "failed to read CloudWatch Logs envelope")
+		}
+		if envelope.MessageType != CloudWatchLogsDataMessage {
+			continue
+		}
+		fields := pantherlog.EnvelopeFields{
+			PantherCloudWatchLogGroup:  envelope.LogGroup,
+			PantherCloudWatchLogStream: envelope.LogStream,
+		}
+		for i := range envelope.LogEvents {
+			emit(envelope.LogEvents[i].Message, &fields)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package sources
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+func TestSplitCloudWatchLogs(t *testing.T) {
+	// Firehose concatenates the gzipped envelopes of a CloudWatch Logs subscription in a single object
+	var buf bytes.Buffer
+	for _, envelope := range []string{
+		`{"messageType":"CONTROL_MESSAGE","owner":"CloudwatchLogs","logGroup":"","logStream":"","subscriptionFilters":[],"logEvents":[{"id":"","timestamp":1432826855000,"message":"CWL CONTROL MESSAGE: Checking health of destination Firehose."}]}`,
+		`{"messageType":"DATA_MESSAGE","owner":"123456789012","logGroup":"/aws/lambda/foo","logStream":"2020/10/01/[$LATEST]abc","subscriptionFilters":["panther"],"logEvents":[{"id":"1","timestamp":1601510400000,"message":"{\"foo\":1}"},{"id":"2","timestamp":1601510400001,"message":"{\"foo\":2}"}]}`,
+		`{"messageType":"DATA_MESSAGE","owner":"123456789012","logGroup":"vpc-flow","logStream":"eni-123","subscriptionFilters":["panther"],"logEvents":[{"id":"3","timestamp":1601510400002,"message":"2 123456789012 eni-123 - - - - - - - 1601510400 1601510460 - NODATA"}]}`,
+	} {
+		w := gzip.NewWriter(&buf)
+		_, err := w.Write([]byte(envelope))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	}
+	r, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+
+	type entry struct {
+		Message string
+		Fields  pantherlog.EnvelopeFields
+	}
+	var entries []entry
+	err = SplitCloudWatchLogs(r, func(message string, fields *pantherlog.EnvelopeFields) {
+		entries = append(entries, entry{Message: message, Fields: *fields})
+	})
+	require.NoError(t, err)
+	lambda := pantherlog.EnvelopeFields{
+		PantherCloudWatchLogGroup:  "/aws/lambda/foo",
+		PantherCloudWatchLogStream: "2020/10/01/[$LATEST]abc",
+	}
+	flow := pantherlog.EnvelopeFields{
+		PantherCloudWatchLogGroup:  "vpc-flow",
+		PantherCloudWatchLogStream: "eni-123",
+	}
+	require.Equal(t, []entry{
+		{Message: `{"foo":1}`, Fields: lambda},
+		{Message: `{"foo":2}`, Fields: lambda},
+		{Message: "2 123456789012 eni-123 - - - - - - - 1601510400 1601510460 - NODATA", Fields: flow},
+	}, entries)
+}
+
+func TestSplitCloudWatchLogsInvalid(t *testing.T) {
+	r := io.MultiReader(bytes.NewBufferString(`{"messageType":"DATA_MESSAGE","logEvents":[{"message":"foo"}]}`),
+		bytes.NewBufferString(`{"messageType":"DATA_MESSAGE","logEvents":[`))
+	var messages []string
+	err := SplitCloudWatchLogs(r, func(message string, _ *pantherlog.EnvelopeFields) {
+		messages = append(messages, message)
+	})
+	require.Error(t, err)
+	require.Equal(t, []string{"foo"}, messages)
+}
+
+func TestIsCloudWatchLogsEnvelope(t *testing.T) {
+	require.True(t, IsCloudWatchLogsEnvelope([]byte(` { "messageType" : "DATA_MESSAGE"`)))
+	require.False(t, IsCloudWatchLogsEnvelope([]byte(`{"Records":[{"messageType":"DATA_MESSAGE"}]}`)))
+	require.False(t, IsCloudWatchLogsEnvelope([]byte(`messageType=DATA_MESSAGE`)))
+}
@@ -30,11 +30,13 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/sns"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/panther-labs/panther/api/lambda/source/models"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 )
 
@@ -143,25 +145,25 @@ func readS3Object(s3Object *S3ObjectInfo) (dataStream *common.DataStream, err er
 			s3Object.S3Bucket, s3Object.S3ObjectKey)
 		return
 	}
+	return ReadS3Object(s3Client, sourceInfo, s3Object.S3Bucket, s3Object.S3ObjectKey)
+}
 
+// ReadS3Object reads an S3 object of a source as a data stream.
+// Gzip compressed objects are decompressed.
+func ReadS3Object(s3Client s3iface.S3API, sourceInfo *models.SourceIntegration, bucket, key string) (*common.DataStream, error) {
 	getObjectInput := &s3.GetObjectInput{
-		Bucket: &s3Object.S3Bucket,
-		Key:    &s3Object.S3ObjectKey,
+		Bucket: &bucket,
+		Key:    &key,
 	}
 	output, err := s3Client.GetObject(getObjectInput)
 	if err != nil {
-		err = errors.Wrapf(err, "GetObject() failed for s3://%s/%s",
-			s3Object.S3Bucket, s3Object.S3ObjectKey)
-		return
+		return nil, errors.Wrapf(err, "GetObject() failed for s3://%s/%s", bucket, key)
 	}
 
 	bufferedReader := bufio.NewReader(output.Body)
 	contentType, err := detectContentType(bufferedReader)
-
 	if err != nil {
-		err = errors.Wrapf(err, "failed to detect content type of S3 payload for s3://%s/%s",
-			s3Object.S3Bucket, s3Object.S3ObjectKey)
-		return
+		return nil, errors.Wrapf(err, "failed to detect content type of S3 payload for s3://%s/%s", bucket, key)
 	}
 
 	var streamReader io.Reader
@@ -171,27 +173,22 @@ func readS3Object(s3Object *S3ObjectInfo) (dataStream *common.DataStream, err er
 		// if it's plain text, just return the buffered reader
 		streamReader = bufferedReader
 	} else if strings.HasPrefix(contentType, "application/x-gzip") {
-		var gzipReader *gzip.Reader
-		gzipReader, err = gzip.NewReader(bufferedReader)
+		gzipReader, err := gzip.NewReader(bufferedReader)
 		if err != nil {
-			err = errors.Wrapf(err, "failed to created gzip reader for s3://%s/%s",
-				s3Object.S3Bucket, s3Object.S3ObjectKey)
-			return
+			return nil, errors.Wrapf(err, "failed to created gzip reader for s3://%s/%s", bucket, key)
 		}
 		streamReader = gzipReader
 	} else {
-		err = &ErrUnsupportedFileType{Type: contentType}
-		return
+		return nil, &ErrUnsupportedFileType{Type: contentType}
 	}
 
-	dataStream = &common.DataStream{
+	return &common.DataStream{
 		Reader:      streamReader,
 		Source:      sourceInfo,
-		S3Bucket:    s3Object.S3Bucket,
-		S3ObjectKey: s3Object.S3ObjectKey,
+		S3Bucket:    bucket,
+		S3ObjectKey: key,
 		ContentType: contentType,
-	}
-	return dataStream, err
+	}, nil
 }
 
 func detectContentType(r *bufio.Reader) (string, error) {
@@ -205,6 +205,16 @@ func getS3Client(bucketName, objectKey string) (s3iface.S3API, *models.SourceInt
 	if sourceInfo == nil {
 		return nil, nil, errors.Errorf("there is no source configured for S3 object %s/%s", bucketName, objectKey)
 	}
+	client, err := SourceS3Client(sourceInfo, bucketName)
+	if err != nil {
+		return nil, nil, errors.WithMessagef(err, "failed to get S3 client to read %s/%s", bucketName, objectKey)
+	}
+	return client, sourceInfo, nil
+}
+
+// SourceS3Client returns an S3 client with permissions to read data of a source from an S3 bucket.
+// Clients are cached per role and bucket region.
+func SourceS3Client(sourceInfo *models.SourceIntegration, bucketName string) (s3iface.S3API, error) {
 	var awsCreds *credentials.Credentials // lazy create below
 	roleArn := getSourceLogProcessingRole(sourceInfo)
 
@@ -213,12 +223,12 @@ func getS3Client(bucketName, objectKey string) (s3iface.S3API, *models.SourceInt
 		zap.L().Debug("bucket region was not cached, fetching it", zap.String("bucket", bucketName))
 		awsCreds = getAwsCredentials(roleArn)
 		if awsCreds == nil {
-			return nil, nil, errors.Errorf("failed to fetch credentials for assumed role %s to read %s/%s",
-				roleArn, bucketName, objectKey)
+			return nil, errors.Errorf("failed to fetch credentials for assumed role %s", roleArn)
 		}
+		var err error
 		bucketRegion, err = getBucketRegion(bucketName, awsCreds)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		bucketCache.Add(bucketName, bucketRegion)
 	}
@@ -235,14 +245,13 @@ func getS3Client(bucketName, objectKey string) (s3iface.S3API, *models.SourceInt
 		if awsCreds == nil {
 			awsCreds = getAwsCredentials(roleArn)
 			if awsCreds == nil {
-				return nil, nil, errors.Errorf("failed to fetch credentials for assumed role %s to read %s/%s",
-					roleArn, bucketName, objectKey)
+				return nil, errors.Errorf("failed to fetch credentials for assumed role %s", roleArn)
 			}
 		}
 		client = newS3ClientFunc(box.String(cacheKey.awsRegion), awsCreds)
 		s3ClientCache.Add(cacheKey, client)
 	}
-	return client.(s3iface.S3API), sourceInfo, nil
+	return client.(s3iface.S3API), nil
 }
 
 func getBucketRegion(s3Bucket string, awsCreds *credentials.Credentials) (string, error) {
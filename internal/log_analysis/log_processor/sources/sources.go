@@ -87,6 +87,30 @@ func BuildClassifier(src *models.SourceIntegration, r logtypes.Resolver) (classi
 	return classification.NewClassifier(parserIndex), nil
 }
 
+// BuildS3ObjectClassifier builds a classifier for an S3 object of a source.
+// Objects matching an entry of the source S3PrefixLogTypes are only parsed with the parser of the assigned log type.
+// Lines that fail to parse are reported as classification failures instead of trying the other log types.
+func BuildS3ObjectClassifier(src *models.SourceIntegration, objectKey string, r logtypes.Resolver) (classification.ClassifierAPI, error) {
+	logType := src.S3ObjectLogType(objectKey)
+	if logType == "" {
+		return BuildClassifier(src, r)
+	}
+	entry, err := r.Resolve(context.TODO(), logType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not resolve log type %q of S3 object %q", logType, objectKey)
+	}
+	if entry == nil {
+		return nil, errors.Errorf("unresolved log type %q of S3 object %q", logType, objectKey)
+	}
+	parser, err := entry.NewParser(nil)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to create %q parser", logType)
+	}
+	return classification.NewClassifier(map[string]parsers.Interface{
+		logType: newSourceFieldsParser(src.IntegrationID, src.IntegrationLabel, parser),
+	}), nil
+}
+
 func newSourceFieldsParser(id, label string, parser parsers.Interface) parsers.Interface {
 	return &sourceFieldsParser{
 		Interface:   parser,
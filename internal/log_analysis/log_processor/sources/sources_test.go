@@ -0,0 +1,94 @@
+package sources
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
+)
+
+func TestBuildS3ObjectClassifier(t *testing.T) {
+	registry := logtypes.Registry{}
+	for _, name := range []string{"Test.Foo", "Test.Bar"} {
+		name := name
+		registry.MustRegister(logtypes.Config{
+			Name:         name,
+			Description:  "Test log type",
+			ReferenceURL: "-",
+			Schema: &struct {
+				LogLine string `json:"logLine" description:"log line"`
+			}{},
+			NewParser: parsers.FactoryFunc(func(_ interface{}) (parsers.Interface, error) {
+				// Both log types parse the same line
+				return testutil.ParserConfig{
+					"line": &parsers.Result{CoreFields: pantherlog.CoreFields{PantherLogType: name}},
+				}.Parser(), nil
+			}),
+		})
+	}
+	src := &models.SourceIntegration{
+		SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+			IntegrationID:   "testSource",
+			IntegrationType: models.IntegrationTypeAWS3,
+			LogTypes:        []string{"Test.Foo", "Test.Bar"},
+			S3PrefixLogTypes: []models.S3PrefixLogType{
+				{Prefix: "bar/", LogType: "Test.Bar"},
+				{Prefix: "*/bar-*.log", LogType: "Test.Bar"},
+				{Prefix: "foo/", LogType: "Test.Foo"},
+			},
+		},
+	}
+
+	for key, logType := range map[string]string{
+		"bar/2020/01/01/log.gz": "Test.Bar",
+		"2020/bar-1.log":        "Test.Bar",
+		"foo/2020/bar-1.log":    "Test.Foo", // `*` does not match `/`
+	} {
+		c, err := BuildS3ObjectClassifier(src, key, &registry)
+		require.NoError(t, err)
+		result, err := c.Classify("line")
+		require.NoError(t, err, key)
+		require.Len(t, result.Events, 1)
+		assert.Equal(t, logType, result.Events[0].PantherLogType, key)
+		assert.Len(t, c.ParserStats(), 1)
+
+		// Lines that do not match the assigned log type are failures
+		_, err = c.Classify("other")
+		require.Error(t, err)
+		assert.Equal(t, uint64(1), c.Stats().ClassificationFailureCount)
+	}
+
+	// Objects without a matching prefix use all log types
+	c, err := BuildS3ObjectClassifier(src, "baz/log.gz", &registry)
+	require.NoError(t, err)
+	_, err = c.Classify("line")
+	require.NoError(t, err)
+
+	src.S3PrefixLogTypes = []models.S3PrefixLogType{{Prefix: "baz/", LogType: "Test.Baz"}}
+	_, err = BuildS3ObjectClassifier(src, "baz/log.gz", &registry)
+	require.Error(t, err)
+}
@@ -0,0 +1,254 @@
+package transform
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/null"
+)
+
+// fieldFunc applies a transform to the fields of a value
+type fieldFunc func(p *Policy, v reflect.Value)
+
+var (
+	typeNullString   = reflect.TypeOf(null.String{})
+	typeNullNonEmpty = reflect.TypeOf(null.NonEmpty{})
+)
+
+// compile resolves the field path in a type and builds a function to transform the field values.
+// Paths are resolved through pointers, struct fields (by JSON name), map keys and all elements of slices.
+func (r *rule) compile(typ reflect.Type, path []string) (fieldFunc, error) {
+	if len(path) == 0 {
+		return r.compileValue(typ)
+	}
+	switch typ.Kind() {
+	case reflect.Ptr:
+		elem, err := r.compile(typ.Elem(), path)
+		if err != nil {
+			return nil, err
+		}
+		return func(p *Policy, v reflect.Value) {
+			if !v.IsNil() {
+				elem(p, v.Elem())
+			}
+		}, nil
+	case reflect.Slice, reflect.Array:
+		return r.compileElements(typ, path)
+	case reflect.Struct:
+		if typ.PkgPath() == typeNullString.PkgPath() {
+			return nil, errors.Errorf("cannot resolve %q in %s value", path[0], typ)
+		}
+		field, ok := lookupFieldJSON(typ, path[0])
+		if !ok {
+			return nil, errors.Errorf("field %q not found", strings.Join(r.path[:len(r.path)-len(path)+1], "."))
+		}
+		next, err := r.compile(field.Type, path[1:])
+		if err != nil {
+			return nil, err
+		}
+		index := field.Index
+		return func(p *Policy, v reflect.Value) {
+			if v, ok := fieldByIndex(v, index); ok {
+				next(p, v)
+			}
+		}, nil
+	case reflect.Map:
+		if typ.Key().Kind() != reflect.String {
+			return nil, errors.Errorf("cannot resolve %q in map with %s keys", path[0], typ.Key())
+		}
+		key := reflect.ValueOf(path[0]).Convert(typ.Key())
+		if len(path) == 1 && r.action == models.FieldTransformDrop {
+			return func(_ *Policy, v reflect.Value) {
+				if !v.IsNil() {
+					v.SetMapIndex(key, reflect.Value{})
+				}
+			}, nil
+		}
+		next, err := r.compile(typ.Elem(), path[1:])
+		if err != nil {
+			return nil, err
+		}
+		elemType := typ.Elem()
+		return func(p *Policy, v reflect.Value) {
+			if v.IsNil() {
+				return
+			}
+			value := v.MapIndex(key)
+			if !value.IsValid() {
+				return
+			}
+			// Map values are not addressable
+			tmp := reflect.New(elemType).Elem()
+			tmp.Set(value)
+			next(p, tmp)
+			v.SetMapIndex(key, tmp)
+		}, nil
+	default:
+		return nil, errors.Errorf("cannot resolve %q in %s value", path[0], typ)
+	}
+}
+
+func (r *rule) compileElements(typ reflect.Type, path []string) (fieldFunc, error) {
+	elem, err := r.compile(typ.Elem(), path)
+	if err != nil {
+		return nil, err
+	}
+	return func(p *Policy, v reflect.Value) {
+		for i := 0; i < v.Len(); i++ {
+			elem(p, v.Index(i))
+		}
+	}, nil
+}
+
+// compileValue builds a function to transform the value of a field
+func (r *rule) compileValue(typ reflect.Type) (fieldFunc, error) {
+	if r.action == models.FieldTransformDrop {
+		zero := reflect.Zero(typ)
+		return func(p *Policy, v reflect.Value) {
+			if s, ok := stringValue(v); ok {
+				p.redacted = append(p.redacted, s)
+			}
+			v.Set(zero)
+		}, nil
+	}
+	switch {
+	case typ.Kind() == reflect.String:
+		return func(p *Policy, v reflect.Value) {
+			v.SetString(r.replace(p, v.String()))
+		}, nil
+	case typ == typeNullString, typ == typeNullNonEmpty:
+		// Both types have the same layout {Value string, Exists bool}
+		return func(p *Policy, v reflect.Value) {
+			if v.Field(1).Bool() {
+				s := v.Field(0)
+				s.SetString(r.replace(p, s.String()))
+			}
+		}, nil
+	case typ.Kind() == reflect.Ptr:
+		elem, err := r.compileValue(typ.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return func(p *Policy, v reflect.Value) {
+			if !v.IsNil() {
+				elem(p, v.Elem())
+			}
+		}, nil
+	case typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array:
+		elem, err := r.compileValue(typ.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return func(p *Policy, v reflect.Value) {
+			for i := 0; i < v.Len(); i++ {
+				elem(p, v.Index(i))
+			}
+		}, nil
+	default:
+		return nil, errors.Errorf("cannot %s field %q of type %s", r.action, strings.Join(r.path, "."), typ)
+	}
+}
+
+// replace returns the replacement for a string value
+func (r *rule) replace(p *Policy, value string) string {
+	if value == "" {
+		return ""
+	}
+	p.redacted = append(p.redacted, value)
+	if r.action == models.FieldTransformHash {
+		return p.hash(value)
+	}
+	return r.value
+}
+
+// stringValue returns the value of string fields
+func stringValue(v reflect.Value) (string, bool) {
+	switch typ := v.Type(); {
+	case typ.Kind() == reflect.String:
+		return v.String(), true
+	case typ == typeNullString, typ == typeNullNonEmpty:
+		return v.Field(0).String(), v.Field(1).Bool()
+	case typ.Kind() == reflect.Ptr && !v.IsNil():
+		return stringValue(v.Elem())
+	default:
+		return "", false
+	}
+}
+
+// lookupFieldJSON finds a struct field by its JSON name, including the fields of embedded structs
+func lookupFieldJSON(typ reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldName := fieldNameJSON(&field)
+		switch {
+		case fieldName == name:
+			return field, true
+		case fieldName == "" && field.Anonymous:
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() != reflect.Struct {
+				continue
+			}
+			if f, ok := lookupFieldJSON(embedded, name); ok {
+				f.Index = append([]int{i}, f.Index...)
+				return f, true
+			}
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func fieldNameJSON(field *reflect.StructField) string {
+	if field.PkgPath != "" && !field.Anonymous {
+		// unexported field
+		return ""
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name := strings.SplitN(tag, ",", 2)[0]; name != "" {
+		return name
+	}
+	if field.Anonymous {
+		return ""
+	}
+	return field.Name
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but returns false for fields of nil embedded pointers
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
@@ -0,0 +1,171 @@
+package transform
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// Policy transforms the fields of events before they are stored.
+//
+// Transforms are applied to the event values so that any indicator fields (`p_any_*`) are collected from the
+// transformed values. A Policy is not safe for concurrent use.
+type Policy struct {
+	rules    map[string][]*rule
+	fields   map[eventType][]fieldFunc
+	hmac     hash.Hash
+	redacted []string
+}
+
+type eventType struct {
+	logType string
+	typ     reflect.Type
+}
+
+// New creates a policy for a set of field transforms.
+// The hash key is required if any transform uses the `hash` action.
+func New(transforms []models.FieldTransform, hashKey []byte) (*Policy, error) {
+	p := Policy{
+		rules:  make(map[string][]*rule),
+		fields: make(map[eventType][]fieldFunc),
+	}
+	for i := range transforms {
+		r, err := newRule(&transforms[i])
+		if err != nil {
+			return nil, err
+		}
+		if r.action == models.FieldTransformHash && p.hmac == nil {
+			if len(hashKey) == 0 {
+				return nil, errors.New("missing hash key")
+			}
+			p.hmac = hmac.New(sha256.New, hashKey)
+		}
+		p.rules[r.logType] = append(p.rules[r.logType], r)
+	}
+	return &p, nil
+}
+
+// Validate checks that a field transform can be applied to events with the schema of a log type
+func Validate(schema interface{}, transform *models.FieldTransform) error {
+	r, err := newRule(transform)
+	if err != nil {
+		return err
+	}
+	_, err = r.compile(reflect.TypeOf(schema), r.path)
+	return err
+}
+
+// anyRemover is implemented by events that collect indicator values at parse time (parsers.PantherLog)
+type anyRemover interface {
+	RemoveAny(values ...string)
+}
+
+// Apply transforms the event of a result.
+func (p *Policy) Apply(result *pantherlog.Result) {
+	if p == nil || len(p.rules[result.PantherLogType]) == 0 {
+		return
+	}
+	// All events are pointers to structs so that their fields can be modified
+	event := reflect.ValueOf(result.Event)
+	if event.Kind() != reflect.Ptr || event.IsNil() {
+		return
+	}
+	p.redacted = p.redacted[:0]
+	for _, fn := range p.eventFields(result.PantherLogType, event.Type()) {
+		fn(p, event)
+	}
+	if e, ok := result.Event.(anyRemover); ok && len(p.redacted) > 0 {
+		e.RemoveAny(p.redacted...)
+	}
+}
+
+// eventFields compiles the transforms of a log type for an event type.
+// Transforms are validated against the log type schema when they are stored but the schema of user-defined log types
+// can change afterwards. Transforms for fields that no longer exist are ignored and fields whose value type cannot
+// be replaced are dropped so that no values leak to the data lake.
+func (p *Policy) eventFields(logType string, typ reflect.Type) []fieldFunc {
+	key := eventType{logType: logType, typ: typ}
+	if fields, ok := p.fields[key]; ok {
+		return fields
+	}
+	var fields []fieldFunc
+	for _, r := range p.rules[logType] {
+		fn, err := r.compile(typ, r.path)
+		if err != nil && r.action != models.FieldTransformDrop {
+			drop := *r
+			drop.action = models.FieldTransformDrop
+			fn, err = drop.compile(typ, r.path)
+		}
+		if err != nil {
+			continue
+		}
+		fields = append(fields, fn)
+	}
+	p.fields[key] = fields
+	return fields
+}
+
+func (p *Policy) hash(value string) string {
+	p.hmac.Reset()
+	_, _ = p.hmac.Write([]byte(value))
+	return hex.EncodeToString(p.hmac.Sum(nil))
+}
+
+type rule struct {
+	logType string
+	path    []string
+	action  string
+	value   string
+}
+
+func newRule(t *models.FieldTransform) (*rule, error) {
+	if t.LogType == "" {
+		return nil, errors.New("missing log type")
+	}
+	switch t.Action {
+	case models.FieldTransformDrop, models.FieldTransformReplace, models.FieldTransformHash:
+	default:
+		return nil, errors.Errorf("invalid transform action %q", t.Action)
+	}
+	path := strings.Split(t.Path, ".")
+	for _, name := range path {
+		if name == "" {
+			return nil, errors.Errorf("invalid field path %q", t.Path)
+		}
+	}
+	if strings.HasPrefix(path[0], "p_") {
+		return nil, errors.Errorf("cannot transform panther field %q", t.Path)
+	}
+	return &rule{
+		logType: t.LogType,
+		path:    path,
+		action:  t.Action,
+		value:   t.Value,
+	}, nil
+}
@@ -0,0 +1,261 @@
+package transform
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/null"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/awslogs"
+)
+
+type testUser struct {
+	Name   null.String `json:"name"`
+	Emails []string    `json:"emails"`
+}
+
+type testEvent struct {
+	User     *testUser         `json:"user"`
+	Users    []testUser        `json:"users"`
+	Password *string           `json:"password"`
+	Count    null.Int64        `json:"count"`
+	Tags     map[string]string `json:"tags"`
+	Host     string
+}
+
+const testLogType = "Test.Event"
+
+func newTestEvent() *testEvent {
+	password := "secret"
+	return &testEvent{
+		User: &testUser{
+			Name:   null.FromString("alice"),
+			Emails: []string{"alice@example.com", ""},
+		},
+		Users: []testUser{
+			{Name: null.FromString("bob")},
+			{},
+		},
+		Password: &password,
+		Count:    null.FromInt64(42),
+		Tags: map[string]string{
+			"owner": "alice",
+			"env":   "prod",
+		},
+		Host: "host.example.com",
+	}
+}
+
+func testHash(key, value string) string {
+	h := hmac.New(sha256.New, []byte(key))
+	_, _ = h.Write([]byte(value))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func TestPolicy(t *testing.T) {
+	policy, err := New([]models.FieldTransform{
+		{LogType: testLogType, Path: "user.name", Action: models.FieldTransformHash},
+		{LogType: testLogType, Path: "user.emails", Action: models.FieldTransformReplace, Value: "REDACTED"},
+		{LogType: testLogType, Path: "users.name", Action: models.FieldTransformReplace, Value: "REDACTED"},
+		{LogType: testLogType, Path: "password", Action: models.FieldTransformDrop},
+		{LogType: testLogType, Path: "tags.owner", Action: models.FieldTransformHash},
+		{LogType: testLogType, Path: "tags.env", Action: models.FieldTransformDrop},
+		{LogType: testLogType, Path: "Host", Action: models.FieldTransformReplace},
+		{LogType: "Other.Event", Path: "count", Action: models.FieldTransformDrop},
+	}, []byte("key"))
+	require.NoError(t, err)
+
+	event := newTestEvent()
+	policy.Apply(&pantherlog.Result{
+		CoreFields: pantherlog.CoreFields{PantherLogType: testLogType},
+		Event:      event,
+	})
+	expect := &testEvent{
+		User: &testUser{
+			Name:   null.FromString(testHash("key", "alice")),
+			Emails: []string{"REDACTED", ""},
+		},
+		Users: []testUser{
+			{Name: null.FromString("REDACTED")},
+			{},
+		},
+		Count: null.FromInt64(42),
+		Tags: map[string]string{
+			"owner": testHash("key", "alice"),
+		},
+	}
+	assert.Equal(t, expect, event)
+
+	// Results of other log types are not modified
+	event = newTestEvent()
+	policy.Apply(&pantherlog.Result{
+		CoreFields: pantherlog.CoreFields{PantherLogType: "Other.Event"},
+		Event:      event,
+	})
+	assert.Equal(t, null.Int64{}, event.Count)
+	assert.Equal(t, "alice", event.User.Name.Value)
+}
+
+func TestPolicyDropsInvalidFields(t *testing.T) {
+	policy, err := New([]models.FieldTransform{
+		// The schema of the log type changed after the transforms were validated
+		{LogType: testLogType, Path: "count", Action: models.FieldTransformHash},
+		{LogType: testLogType, Path: "missing", Action: models.FieldTransformReplace},
+	}, []byte("key"))
+	require.NoError(t, err)
+	event := newTestEvent()
+	policy.Apply(&pantherlog.Result{
+		CoreFields: pantherlog.CoreFields{PantherLogType: testLogType},
+		Event:      event,
+	})
+	expect := newTestEvent()
+	expect.Count = null.Int64{}
+	assert.Equal(t, expect, event)
+}
+
+type testPantherLogEvent struct {
+	SourceIP *string `json:"sourceIP"`
+	parsers.PantherLog
+}
+
+func TestPolicyRemovesIndicators(t *testing.T) {
+	policy, err := New([]models.FieldTransform{
+		{LogType: testLogType, Path: "sourceIP", Action: models.FieldTransformHash},
+	}, []byte("key"))
+	require.NoError(t, err)
+
+	sourceIP := "192.168.1.1"
+	event := &testPantherLogEvent{SourceIP: &sourceIP}
+	event.AppendAnyIPAddressPtr(event.SourceIP)
+	event.AppendAnyIPAddress("10.0.0.1")
+	policy.Apply(&pantherlog.Result{
+		CoreFields: pantherlog.CoreFields{PantherLogType: testLogType},
+		Event:      event,
+	})
+	assert.Equal(t, testHash("key", "192.168.1.1"), *event.SourceIP)
+	values, err := event.PantherAnyIPAddresses.MarshalJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `["10.0.0.1"]`, string(values))
+}
+
+type testAWSEvent struct {
+	UserARN   *string `json:"userARN"`
+	AccountID *string `json:"accountId"`
+	URL       *string `json:"url"`
+	awslogs.AWSPantherLog
+}
+
+func TestPolicyRemovesDerivedIndicators(t *testing.T) {
+	policy, err := New([]models.FieldTransform{
+		{LogType: testLogType, Path: "userARN", Action: models.FieldTransformReplace, Value: "REDACTED"},
+		{LogType: testLogType, Path: "accountId", Action: models.FieldTransformHash},
+		{LogType: testLogType, Path: "url", Action: models.FieldTransformDrop},
+	}, []byte("key"))
+	require.NoError(t, err)
+
+	event := &testAWSEvent{
+		UserARN:   aws.String("arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0"),
+		AccountID: aws.String("210987654321"),
+		URL:       aws.String("https://www.example.com/login"),
+	}
+	// Values are collected the same way parsers do
+	awslogs.NewAWSExtractor(&event.AWSPantherLog).Extract(gjson.Result{}, gjson.Result{Type: gjson.String, Str: *event.UserARN})
+	event.AppendAnyAWSAccountIdPtrs(event.AccountID)
+	pantherlog.ScanURL(event, *event.URL)
+	// Values of fields that are not redacted are kept
+	event.AppendAnyAWSAccountIds("111111111111")
+	event.AppendAnyDomainNames("example.org")
+
+	policy.Apply(&pantherlog.Result{
+		CoreFields: pantherlog.CoreFields{PantherLogType: testLogType},
+		Event:      event,
+	})
+	for _, tc := range []struct {
+		values *parsers.PantherAnyString
+		expect string
+	}{
+		{event.PantherAnyAWSARNs, `[]`},
+		{event.PantherAnyAWSAccountIds, `["111111111111"]`},
+		{event.PantherAnyAWSInstanceIds, `[]`},
+		{event.PantherAnyURLs, `[]`},
+		{event.PantherAnyDomainNames, `["example.org"]`},
+	} {
+		values, err := tc.values.MarshalJSON()
+		require.NoError(t, err)
+		assert.JSONEq(t, tc.expect, string(values))
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New([]models.FieldTransform{
+		{LogType: testLogType, Path: "user.name", Action: models.FieldTransformHash},
+	}, nil)
+	assert.Error(t, err, "hash transforms require a key")
+	_, err = New([]models.FieldTransform{
+		{LogType: testLogType, Path: "user.name", Action: models.FieldTransformDrop},
+	}, nil)
+	assert.NoError(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		Path   string
+		Action string
+		Valid  bool
+	}{
+		{"user.name", models.FieldTransformHash, true},
+		{"user.emails", models.FieldTransformReplace, true},
+		{"users.name", models.FieldTransformHash, true},
+		{"tags.owner", models.FieldTransformReplace, true},
+		{"password", models.FieldTransformHash, true},
+		{"Host", models.FieldTransformHash, true},
+		{"count", models.FieldTransformDrop, true},
+		{"user", models.FieldTransformDrop, true},
+		{"count", models.FieldTransformHash, false},
+		{"user", models.FieldTransformReplace, false},
+		{"user.missing", models.FieldTransformDrop, false},
+		{"user..name", models.FieldTransformDrop, false},
+		{"user.name.value", models.FieldTransformDrop, false},
+		{"user.name.Value", models.FieldTransformDrop, false},
+		{"p_any_ip_addresses", models.FieldTransformDrop, false},
+		{"user.name", "encrypt", false},
+	} {
+		err := Validate(testEvent{}, &models.FieldTransform{
+			LogType: testLogType,
+			Path:    tc.Path,
+			Action:  tc.Action,
+		})
+		if tc.Valid {
+			assert.NoError(t, err, "%s %s", tc.Action, tc.Path)
+		} else {
+			assert.Error(t, err, "%s %s", tc.Action, tc.Path)
+		}
+	}
+}
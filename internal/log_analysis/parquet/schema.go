@@ -0,0 +1,343 @@
+package parquet
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+)
+
+// Schema is the Parquet schema of a Glue table.
+//
+// All values are optional. Glue types are mapped to Parquet types as follows:
+//   - boolean -> BOOLEAN
+//   - tinyint, smallint, int -> INT32 (with INT_8, INT_16 annotations for tinyint and smallint)
+//   - bigint -> INT64
+//   - float -> FLOAT
+//   - double -> DOUBLE
+//   - string -> BYTE_ARRAY (UTF8)
+//   - timestamp -> INT96
+//   - struct<...> -> group
+//   - array<T> -> LIST annotated group
+//   - map<K,V> -> MAP annotated group
+type Schema struct {
+	root    *node
+	columns []*node
+}
+
+// SchemaFromTable builds the Parquet schema for the columns of a Glue table.
+func SchemaFromTable(table *awsglue.GlueTableMetadata) (*Schema, error) {
+	columns, _ := awsglue.InferJSONColumns(table.EventStruct(), awsglue.GlueMappings...)
+	return NewSchema(columns)
+}
+
+// NewSchema builds a Parquet schema for Glue columns.
+func NewSchema(columns []awsglue.Column) (*Schema, error) {
+	root := &node{
+		name: "schema",
+		kind: kindStruct,
+	}
+	for i := range columns {
+		col := &columns[i]
+		child, err := parseGlueType(col.Type)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid type for column %q", col.Name)
+		}
+		child.name = col.Name
+		root.children = append(root.children, child)
+	}
+	if len(root.children) == 0 {
+		return nil, errors.New("empty schema")
+	}
+	s := Schema{
+		root: root,
+	}
+	root.index = make(map[string]int, len(root.children))
+	for i, child := range root.children {
+		root.index[child.name] = i
+		s.columns = child.init(nil, 0, 0, s.columns)
+	}
+	return &s, nil
+}
+
+type nodeKind int
+
+const (
+	kindPrimitive nodeKind = iota
+	kindStruct
+	kindList
+	kindMap
+)
+
+// node is a node in the schema tree
+type node struct {
+	name     string
+	kind     nodeKind
+	typ      primitiveType
+	children []*node
+	// path from the root of the schema
+	path []string
+	// definition level when the value of this node is present
+	defLevel int
+	// repetition level for the repeated group of a list or a map
+	repLevel int
+	// max definition/repetition levels and column index for leaf columns
+	maxDef    int
+	maxRep    int
+	leafIndex int
+	// child index by name for struct nodes
+	index map[string]int
+}
+
+// init computes levels and paths and collects the leaf columns of the tree
+func (n *node) init(parent []string, def, rep int, leaves []*node) []*node {
+	n.path = append(append([]string(nil), parent...), n.name)
+	n.defLevel = def + 1 // all values are optional
+	switch n.kind {
+	case kindPrimitive:
+		n.maxDef, n.maxRep = n.defLevel, rep
+		n.leafIndex = len(leaves)
+		return append(leaves, n)
+	case kindStruct:
+		n.index = make(map[string]int, len(n.children))
+		for i, child := range n.children {
+			n.index[child.name] = i
+			leaves = child.init(n.path, n.defLevel, rep, leaves)
+		}
+		return leaves
+	case kindList:
+		// optional group <name> (LIST) { repeated group list { optional <element> } }
+		n.repLevel = rep + 1
+		elem := n.children[0]
+		return elem.init(append(n.path, "list"), n.defLevel+1, n.repLevel, leaves)
+	case kindMap:
+		// optional group <name> (MAP) { repeated group key_value { required <key>; optional <value> } }
+		n.repLevel = rep + 1
+		key, value := n.children[0], n.children[1]
+		kvPath := append(n.path, "key_value")
+		leaves = key.init(kvPath, n.defLevel, n.repLevel, leaves) // key is required so it adds no definition level
+		return value.init(kvPath, n.defLevel+1, n.repLevel, leaves)
+	default:
+		panic("invalid node kind")
+	}
+}
+
+// parseGlueType parses a Glue type string such as `struct<foo:string,bar:array<bigint>>`
+func parseGlueType(glueType string) (*node, error) {
+	p := typeParser{input: glueType}
+	n, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.input) {
+		return nil, errors.Errorf("unexpected input at %d in %q", p.pos, glueType)
+	}
+	return n, nil
+}
+
+type typeParser struct {
+	input string
+	pos   int
+}
+
+func (p *typeParser) parse() (*node, error) {
+	name := p.ident()
+	switch name {
+	case "struct":
+		n := node{kind: kindStruct}
+		if err := p.expect('<'); err != nil {
+			return nil, err
+		}
+		for {
+			fieldName := p.fieldName()
+			if fieldName == "" {
+				return nil, errors.Errorf("missing struct field name at %d in %q", p.pos, p.input)
+			}
+			if err := p.expect(':'); err != nil {
+				return nil, err
+			}
+			child, err := p.parse()
+			if err != nil {
+				return nil, err
+			}
+			child.name = fieldName
+			n.children = append(n.children, child)
+			if p.peek() != ',' {
+				break
+			}
+			p.pos++
+		}
+		if err := p.expect('>'); err != nil {
+			return nil, err
+		}
+		return &n, nil
+	case "array":
+		if err := p.expect('<'); err != nil {
+			return nil, err
+		}
+		elem, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		elem.name = "element"
+		if err := p.expect('>'); err != nil {
+			return nil, err
+		}
+		return &node{
+			kind:     kindList,
+			children: []*node{elem},
+		}, nil
+	case "map":
+		if err := p.expect('<'); err != nil {
+			return nil, err
+		}
+		key, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		if key.kind != kindPrimitive || key.typ != typeString {
+			return nil, errors.Errorf("unsupported map key type in %q", p.input)
+		}
+		key.name = "key"
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+		value, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		value.name = "value"
+		if err := p.expect('>'); err != nil {
+			return nil, err
+		}
+		return &node{
+			kind:     kindMap,
+			children: []*node{key, value},
+		}, nil
+	default:
+		typ, ok := primitiveTypes[name]
+		if !ok {
+			return nil, errors.Errorf("unsupported type %q in %q", name, p.input)
+		}
+		return &node{
+			kind: kindPrimitive,
+			typ:  typ,
+		}, nil
+	}
+}
+
+func (p *typeParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if !('a' <= c && c <= 'z') && !('A' <= c && c <= 'Z') && !('0' <= c && c <= '9') {
+			break
+		}
+		p.pos++
+	}
+	return strings.ToLower(p.input[start:p.pos])
+}
+
+// fieldName reads a struct field name, field names can contain any character except ':'
+func (p *typeParser) fieldName() string {
+	start := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] != ':' {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *typeParser) peek() byte {
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+func (p *typeParser) expect(c byte) error {
+	if p.peek() != c {
+		return errors.Errorf("expected %q at %d in %q", c, p.pos, p.input)
+	}
+	p.pos++
+	return nil
+}
+
+// primitiveType is a Glue primitive type
+type primitiveType int
+
+const (
+	typeBoolean primitiveType = iota
+	typeTinyInt
+	typeSmallInt
+	typeInt
+	typeBigInt
+	typeFloat
+	typeDouble
+	typeString
+	typeTimestamp
+)
+
+var primitiveTypes = map[string]primitiveType{
+	"boolean":   typeBoolean,
+	"tinyint":   typeTinyInt,
+	"smallint":  typeSmallInt,
+	"int":       typeInt,
+	"bigint":    typeBigInt,
+	"float":     typeFloat,
+	"double":    typeDouble,
+	"string":    typeString,
+	"timestamp": typeTimestamp,
+}
+
+// physicalType returns the Parquet physical type for a primitive type
+func (t primitiveType) physicalType() int32 {
+	switch t {
+	case typeBoolean:
+		return physicalBoolean
+	case typeTinyInt, typeSmallInt, typeInt:
+		return physicalInt32
+	case typeBigInt:
+		return physicalInt64
+	case typeFloat:
+		return physicalFloat
+	case typeDouble:
+		return physicalDouble
+	case typeTimestamp:
+		return physicalInt96
+	default:
+		return physicalByteArray
+	}
+}
+
+// convertedType returns the Parquet converted type annotation for a primitive type or -1 if there is none
+func (t primitiveType) convertedType() int32 {
+	switch t {
+	case typeTinyInt:
+		return convertedInt8
+	case typeSmallInt:
+		return convertedInt16
+	case typeString:
+		return convertedUTF8
+	default:
+		return -1
+	}
+}
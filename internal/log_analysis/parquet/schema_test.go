@@ -0,0 +1,93 @@
+package parquet
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/core/log_analysis/log_processor/models"
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+)
+
+func TestNewSchema(t *testing.T) {
+	schema, err := NewSchema([]awsglue.Column{
+		{Name: "s", Type: "string"},
+		{Name: "arr", Type: "array<bigint>"},
+		{Name: "st", Type: "struct<a:string,b:array<struct<c:int>>>"},
+		{Name: "m", Type: "map<string,array<string>>"},
+	})
+	require.NoError(t, err)
+
+	type leaf struct {
+		Path   []string
+		MaxDef int
+		MaxRep int
+	}
+	var leaves []leaf
+	for _, col := range schema.columns {
+		leaves = append(leaves, leaf{
+			Path:   col.path,
+			MaxDef: col.maxDef,
+			MaxRep: col.maxRep,
+		})
+	}
+	expect := []leaf{
+		{Path: []string{"s"}, MaxDef: 1, MaxRep: 0},
+		{Path: []string{"arr", "list", "element"}, MaxDef: 3, MaxRep: 1},
+		{Path: []string{"st", "a"}, MaxDef: 2, MaxRep: 0},
+		{Path: []string{"st", "b", "list", "element", "c"}, MaxDef: 5, MaxRep: 1},
+		{Path: []string{"m", "key_value", "key"}, MaxDef: 2, MaxRep: 1},
+		{Path: []string{"m", "key_value", "value", "list", "element"}, MaxDef: 5, MaxRep: 2},
+	}
+	assert.Equal(t, expect, leaves)
+}
+
+func TestNewSchemaErrors(t *testing.T) {
+	for _, typ := range []string{
+		"",
+		"varchar",
+		"array<string",
+		"struct<>",
+		"struct<foo:string",
+		"map<int,string>",
+		"string>",
+	} {
+		_, err := NewSchema([]awsglue.Column{{Name: "col", Type: typ}})
+		assert.Error(t, err, typ)
+	}
+	_, err := NewSchema(nil)
+	assert.Error(t, err)
+}
+
+func TestSchemaFromTable(t *testing.T) {
+	type event struct {
+		Name string            `json:"name" description:"name"`
+		Tags map[string]string `json:"tags" description:"tags"`
+	}
+	table := awsglue.NewGlueTableMetadata(models.LogData, "Test.Event", "test", awsglue.GlueTableHourly, event{})
+	schema, err := SchemaFromTable(table)
+	require.NoError(t, err)
+	require.Len(t, schema.columns, 3)
+	assert.Equal(t, []string{"name"}, schema.columns[0].path)
+	assert.Equal(t, []string{"tags", "key_value", "key"}, schema.columns[1].path)
+	assert.Equal(t, []string{"tags", "key_value", "value"}, schema.columns[2].path)
+}
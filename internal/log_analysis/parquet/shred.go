@@ -0,0 +1,264 @@
+package parquet
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+)
+
+// JSON values are 'shredded' to columns using definition and repetition levels.
+// See https://github.com/apache/parquet-format#nested-encoding
+
+// writeStruct writes the fields of a JSON object.
+// Fields are written in the order they appear in the input, missing fields are written as NULL.
+func (w *Writer) writeStruct(iter *jsoniter.Iterator, n *node, rep, def int) {
+	seen := make([]bool, len(n.children))
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, field string) bool {
+		i, ok := n.index[field]
+		if !ok || seen[i] {
+			iter.Skip()
+			return true
+		}
+		seen[i] = true
+		w.writeValue(iter, n.children[i], rep, def)
+		return true
+	})
+	for i, child := range n.children {
+		if !seen[i] {
+			w.writeNull(child, rep, def)
+		}
+	}
+}
+
+// writeValue writes a JSON value for a node.
+// The definition level def is the level of the parent of the node.
+func (w *Writer) writeValue(iter *jsoniter.Iterator, n *node, rep, def int) {
+	next := iter.WhatIsNext()
+	if next == jsoniter.NilValue {
+		iter.Skip()
+		w.writeNull(n, rep, def)
+		return
+	}
+	switch n.kind {
+	case kindPrimitive:
+		col := w.columns[n.leafIndex]
+		if col.appendValue(iter, next) {
+			col.appendLevels(rep, n.defLevel)
+			return
+		}
+		col.appendLevels(rep, def)
+	case kindStruct:
+		if next != jsoniter.ObjectValue {
+			iter.Skip()
+			w.writeNull(n, rep, def)
+			return
+		}
+		w.writeStruct(iter, n, rep, n.defLevel)
+	case kindList:
+		if next != jsoniter.ArrayValue {
+			iter.Skip()
+			w.writeNull(n, rep, def)
+			return
+		}
+		elem := n.children[0]
+		numElements := 0
+		iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+			r := rep
+			if numElements > 0 {
+				r = n.repLevel
+			}
+			w.writeValue(iter, elem, r, n.defLevel+1)
+			numElements++
+			return true
+		})
+		if numElements == 0 {
+			w.writeNull(elem, rep, n.defLevel)
+		}
+	case kindMap:
+		if next != jsoniter.ObjectValue {
+			iter.Skip()
+			w.writeNull(n, rep, def)
+			return
+		}
+		key, value := n.children[0], n.children[1]
+		keyColumn := w.columns[key.leafIndex]
+		numEntries := 0
+		iter.ReadMapCB(func(iter *jsoniter.Iterator, k string) bool {
+			r := rep
+			if numEntries > 0 {
+				r = n.repLevel
+			}
+			keyColumn.appendString(k)
+			keyColumn.appendLevels(r, n.defLevel+1)
+			w.writeValue(iter, value, r, n.defLevel+1)
+			numEntries++
+			return true
+		})
+		if numEntries == 0 {
+			w.writeNull(n.children[0], rep, n.defLevel)
+			w.writeNull(n.children[1], rep, n.defLevel)
+		}
+	}
+}
+
+// writeNull writes NULL values for all columns of a node
+func (w *Writer) writeNull(n *node, rep, def int) {
+	if n.kind == kindPrimitive {
+		w.columns[n.leafIndex].appendLevels(rep, def)
+		return
+	}
+	for _, child := range n.children {
+		w.writeNull(child, rep, def)
+	}
+}
+
+// appendValue reads a JSON value and appends it to the column.
+// It returns false if the value cannot be converted to the column type.
+func (c *column) appendValue(iter *jsoniter.Iterator, next jsoniter.ValueType) bool {
+	switch typ := c.node.typ; typ {
+	case typeBoolean:
+		switch next {
+		case jsoniter.BoolValue:
+			c.appendBool(iter.ReadBool())
+			return true
+		case jsoniter.StringValue:
+			b, err := strconv.ParseBool(iter.ReadString())
+			if err != nil {
+				return false
+			}
+			c.appendBool(b)
+			return true
+		}
+	case typeTinyInt, typeSmallInt, typeInt, typeBigInt:
+		var s string
+		switch next {
+		case jsoniter.NumberValue:
+			s = string(iter.ReadNumber())
+		case jsoniter.StringValue:
+			s = iter.ReadString()
+		default:
+			iter.Skip()
+			return false
+		}
+		n, ok := parseInt(s)
+		if !ok {
+			return false
+		}
+		switch typ {
+		case typeTinyInt:
+			ok = math.MinInt8 <= n && n <= math.MaxInt8
+		case typeSmallInt:
+			ok = math.MinInt16 <= n && n <= math.MaxInt16
+		case typeInt:
+			ok = math.MinInt32 <= n && n <= math.MaxInt32
+		default:
+			c.appendInt64(n)
+			return true
+		}
+		if ok {
+			c.appendInt32(int32(n))
+		}
+		return ok
+	case typeFloat, typeDouble:
+		var f float64
+		switch next {
+		case jsoniter.NumberValue:
+			f = iter.ReadFloat64()
+		case jsoniter.StringValue:
+			var err error
+			if f, err = strconv.ParseFloat(iter.ReadString(), 64); err != nil {
+				return false
+			}
+		default:
+			iter.Skip()
+			return false
+		}
+		if typ == typeFloat {
+			c.appendFloat(float32(f))
+		} else {
+			c.appendDouble(f)
+		}
+		return true
+	case typeString:
+		if next == jsoniter.StringValue {
+			c.appendString(iter.ReadString())
+			return true
+		}
+		// Store the JSON text of non-string values like the JSON SerDe does
+		c.appendBytes(iter.SkipAndReturnBytes())
+		return true
+	case typeTimestamp:
+		if next != jsoniter.StringValue {
+			iter.Skip()
+			return false
+		}
+		tm, ok := parseTimestamp(iter.ReadString())
+		if !ok {
+			return false
+		}
+		c.appendTimestamp(tm)
+		return true
+	}
+	iter.Skip()
+	return false
+}
+
+func parseInt(s string) (int64, bool) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || f < math.MinInt64 || f > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+func parseTimestamp(s string) (time.Time, bool) {
+	if tm, err := time.Parse(awsglue.TimestampLayout, s); err == nil {
+		return tm, true
+	}
+	if tm, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return tm, true
+	}
+	return time.Time{}, false
+}
+
+const (
+	julianDayOfEpoch = 2440588
+	nanosPerDay      = int64(24 * time.Hour)
+)
+
+// appendTimestamp appends a timestamp as INT96 (nanoseconds of the day and Julian day)
+func (c *column) appendTimestamp(tm time.Time) {
+	unixNano := tm.UnixNano()
+	days := unixNano / nanosPerDay
+	nanos := unixNano % nanosPerDay
+	if nanos < 0 {
+		days--
+		nanos += nanosPerDay
+	}
+	c.appendInt96(uint64(nanos), uint32(days+julianDayOfEpoch))
+}
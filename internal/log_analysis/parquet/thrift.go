@@ -0,0 +1,159 @@
+package parquet
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Parquet metadata is serialized using the Thrift compact protocol.
+// We only need to write a small subset of the format metadata so we use a minimal encoder instead of
+// depending on a Thrift library.
+// See https://github.com/apache/parquet-format/blob/master/src/main/thrift/parquet.thrift
+// and https://github.com/apache/thrift/blob/master/doc/specs/thrift-compact-protocol.md
+
+// Compact protocol field types
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// Parquet physical types
+const (
+	physicalBoolean   = 0
+	physicalInt32     = 1
+	physicalInt64     = 2
+	physicalInt96     = 3
+	physicalFloat     = 4
+	physicalDouble    = 5
+	physicalByteArray = 6
+)
+
+// Parquet converted types
+const (
+	convertedUTF8  = 0
+	convertedMap   = 1
+	convertedList  = 3
+	convertedInt8  = 15
+	convertedInt16 = 16
+)
+
+// Parquet field repetition types
+const (
+	repetitionRequired = 0
+	repetitionOptional = 1
+	repetitionRepeated = 2
+)
+
+// Parquet encodings
+const (
+	encodingPlain = 0
+	encodingRLE   = 3
+)
+
+const (
+	codecGzip    = 2
+	pageTypeData = 0
+)
+
+// thriftWriter encodes Thrift structs using the compact protocol
+type thriftWriter struct {
+	buf []byte
+	// last field id of each nested struct
+	lastField []int16
+}
+
+func (w *thriftWriter) structBegin() {
+	w.lastField = append(w.lastField, 0)
+}
+
+func (w *thriftWriter) structEnd() {
+	w.buf = append(w.buf, 0) // field stop
+	w.lastField = w.lastField[:len(w.lastField)-1]
+}
+
+func (w *thriftWriter) fieldBegin(id int16, typ byte) {
+	last := &w.lastField[len(w.lastField)-1]
+	if delta := id - *last; 0 < delta && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.varint(int64(id))
+	}
+	*last = id
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.fieldBegin(id, compactI32)
+	w.varint(int64(v))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.fieldBegin(id, compactI64)
+	w.varint(v)
+}
+
+func (w *thriftWriter) str(id int16, v string) {
+	w.fieldBegin(id, compactBinary)
+	w.uvarint(uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// structField begins a nested struct field, it must be followed by a call to structEnd()
+func (w *thriftWriter) structField(id int16) {
+	w.fieldBegin(id, compactStruct)
+	w.structBegin()
+}
+
+// listField begins a list field, it must be followed by size elements
+func (w *thriftWriter) listField(id int16, elemType byte, size int) {
+	w.fieldBegin(id, compactList)
+	if size < 15 {
+		w.buf = append(w.buf, byte(size)<<4|elemType)
+	} else {
+		w.buf = append(w.buf, 0xf0|elemType)
+		w.uvarint(uint64(size))
+	}
+}
+
+// listI32 writes an i32 list element
+func (w *thriftWriter) listI32(v int32) {
+	w.varint(int64(v))
+}
+
+// listStr writes a string list element
+func (w *thriftWriter) listStr(v string) {
+	w.uvarint(uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+func (w *thriftWriter) varint(v int64) {
+	w.uvarint(uint64((v << 1) ^ (v >> 63))) // zigzag
+}
+
+func (w *thriftWriter) uvarint(v uint64) {
+	w.buf = appendUvarint(w.buf, v)
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	for v >= 0x80 {
+		buf = append(buf, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(buf, byte(v))
+}
@@ -0,0 +1,486 @@
+package parquet
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"math"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultRowGroupSize is the default size of buffered column data before a row group is written
+	DefaultRowGroupSize = 32 * 1024 * 1024
+
+	magic     = "PAR1"
+	createdBy = "panther"
+)
+
+// Writer writes JSON events as rows of a Parquet file.
+//
+// Rows are buffered in memory in columnar form and written as a row group when the buffered
+// data exceed RowGroupSize. Each column chunk is written as a single GZIP compressed data page.
+// The file footer is written on Close.
+type Writer struct {
+	// RowGroupSize is the size of buffered column data that triggers writing a row group
+	RowGroupSize int
+
+	w         io.Writer
+	schema    *Schema
+	columns   []*column
+	offset    int64
+	numRows   int64
+	totalRows int64
+	rowGroups []*rowGroup
+	gz        *gzip.Writer
+	page      bytes.Buffer
+	err       error
+}
+
+// NewWriter creates a new Parquet writer.
+func NewWriter(w io.Writer, schema *Schema) *Writer {
+	columns := make([]*column, len(schema.columns))
+	for i, leaf := range schema.columns {
+		columns[i] = &column{
+			node: leaf,
+		}
+	}
+	return &Writer{
+		RowGroupSize: DefaultRowGroupSize,
+		w:            w,
+		schema:       schema,
+		columns:      columns,
+	}
+}
+
+// NumRows returns the number of rows written
+func (w *Writer) NumRows() int64 {
+	return w.totalRows + w.numRows
+}
+
+// WriteJSON writes a JSON object as a row.
+// Values that do not match the type of their column are stored as NULL.
+// Strings columns store the JSON text of non-string values.
+func (w *Writer) WriteJSON(data []byte) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.offset == 0 {
+		if err := w.write([]byte(magic)); err != nil {
+			return err
+		}
+	}
+	iter := jsoniter.ConfigDefault.BorrowIterator(data)
+	defer jsoniter.ConfigDefault.ReturnIterator(iter)
+	if iter.WhatIsNext() != jsoniter.ObjectValue {
+		return errors.New("JSON value is not an object")
+	}
+	for _, col := range w.columns {
+		col.mark()
+	}
+	w.writeStruct(iter, w.schema.root, 0, 0)
+	if iter.Error != nil && iter.Error != io.EOF {
+		// Discard the partially written row
+		for _, col := range w.columns {
+			col.reset()
+		}
+		return errors.Wrap(iter.Error, "failed to read JSON")
+	}
+	w.numRows++
+	if w.bufferedSize() >= w.RowGroupSize {
+		return w.flushRowGroup()
+	}
+	return nil
+}
+
+// Close writes any buffered rows and the file footer.
+// It does not close the underlying io.Writer.
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.offset == 0 {
+		if err := w.write([]byte(magic)); err != nil {
+			return err
+		}
+	}
+	if w.numRows > 0 {
+		if err := w.flushRowGroup(); err != nil {
+			return err
+		}
+	}
+	footer := w.fileMetaData()
+	if err := w.write(footer); err != nil {
+		return err
+	}
+	size := make([]byte, 4)
+	binary.LittleEndian.PutUint32(size, uint32(len(footer)))
+	if err := w.write(size); err != nil {
+		return err
+	}
+	if err := w.write([]byte(magic)); err != nil {
+		return err
+	}
+	w.err = errors.New("writer is closed")
+	return nil
+}
+
+func (w *Writer) write(p []byte) error {
+	n, err := w.w.Write(p)
+	w.offset += int64(n)
+	if err != nil {
+		w.err = err
+	}
+	return err
+}
+
+func (w *Writer) bufferedSize() (size int) {
+	for _, col := range w.columns {
+		size += len(col.values) + len(col.defLevels) + len(col.repLevels)
+	}
+	return size
+}
+
+type rowGroup struct {
+	numRows   int64
+	totalSize int64
+	chunks    []columnChunk
+}
+
+type columnChunk struct {
+	offset           int64
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+func (w *Writer) flushRowGroup() error {
+	group := rowGroup{
+		numRows: w.numRows,
+		chunks:  make([]columnChunk, len(w.columns)),
+	}
+	for i, col := range w.columns {
+		chunk, err := w.writeColumnChunk(col)
+		if err != nil {
+			return err
+		}
+		group.chunks[i] = chunk
+		group.totalSize += chunk.uncompressedSize
+		col.clear()
+	}
+	w.rowGroups = append(w.rowGroups, &group)
+	w.totalRows += w.numRows
+	w.numRows = 0
+	return nil
+}
+
+func (w *Writer) writeColumnChunk(col *column) (columnChunk, error) {
+	var data []byte
+	if col.node.maxRep > 0 {
+		data = appendLevels(data, col.repLevels, col.node.maxRep)
+	}
+	if col.node.maxDef > 0 {
+		data = appendLevels(data, col.defLevels, col.node.maxDef)
+	}
+	data = append(data, col.values...)
+
+	w.page.Reset()
+	if w.gz == nil {
+		w.gz = gzip.NewWriter(&w.page)
+	} else {
+		w.gz.Reset(&w.page)
+	}
+	if _, err := w.gz.Write(data); err != nil {
+		return columnChunk{}, err
+	}
+	if err := w.gz.Close(); err != nil {
+		return columnChunk{}, err
+	}
+
+	numValues := len(col.defLevels)
+	header := thriftWriter{}
+	header.structBegin()
+	header.i32(1, pageTypeData)
+	header.i32(2, int32(len(data)))
+	header.i32(3, int32(w.page.Len()))
+	header.structField(5)
+	header.i32(1, int32(numValues))
+	header.i32(2, encodingPlain)
+	header.i32(3, encodingRLE)
+	header.i32(4, encodingRLE)
+	header.structEnd()
+	header.structEnd()
+
+	chunk := columnChunk{
+		offset:           w.offset,
+		numValues:        int64(numValues),
+		uncompressedSize: int64(len(header.buf) + len(data)),
+		compressedSize:   int64(len(header.buf) + w.page.Len()),
+	}
+	if err := w.write(header.buf); err != nil {
+		return columnChunk{}, err
+	}
+	if err := w.write(w.page.Bytes()); err != nil {
+		return columnChunk{}, err
+	}
+	return chunk, nil
+}
+
+func (w *Writer) fileMetaData() []byte {
+	t := thriftWriter{}
+	t.structBegin()
+	t.i32(1, 1) // version
+	elements := schemaElements(nil, w.schema.root, -1)
+	t.listField(2, compactStruct, len(elements))
+	for i := range elements {
+		elements[i].write(&t)
+	}
+	t.i64(3, w.totalRows)
+	t.listField(4, compactStruct, len(w.rowGroups))
+	for _, group := range w.rowGroups {
+		t.structBegin()
+		t.listField(1, compactStruct, len(group.chunks))
+		for i := range group.chunks {
+			chunk := &group.chunks[i]
+			leaf := w.columns[i].node
+			t.structBegin()
+			t.i64(2, chunk.offset)
+			t.structField(3)
+			t.i32(1, leaf.typ.physicalType())
+			t.listField(2, compactI32, 2)
+			t.listI32(encodingPlain)
+			t.listI32(encodingRLE)
+			t.listField(3, compactBinary, len(leaf.path))
+			for _, name := range leaf.path {
+				t.listStr(name)
+			}
+			t.i32(4, codecGzip)
+			t.i64(5, chunk.numValues)
+			t.i64(6, chunk.uncompressedSize)
+			t.i64(7, chunk.compressedSize)
+			t.i64(9, chunk.offset)
+			t.structEnd()
+			t.structEnd()
+		}
+		t.i64(2, group.totalSize)
+		t.i64(3, group.numRows)
+		t.structEnd()
+	}
+	t.str(6, createdBy)
+	t.structEnd()
+	return t.buf
+}
+
+type schemaElement struct {
+	name          string
+	physicalType  int32
+	repetition    int32
+	numChildren   int32
+	convertedType int32
+}
+
+func (e *schemaElement) write(t *thriftWriter) {
+	t.structBegin()
+	if e.physicalType >= 0 {
+		t.i32(1, e.physicalType)
+	}
+	if e.repetition >= 0 {
+		t.i32(3, e.repetition)
+	}
+	t.str(4, e.name)
+	if e.numChildren > 0 {
+		t.i32(5, e.numChildren)
+	}
+	if e.convertedType >= 0 {
+		t.i32(6, e.convertedType)
+	}
+	t.structEnd()
+}
+
+// schemaElements flattens the schema tree depth first
+func schemaElements(elements []schemaElement, n *node, repetition int32) []schemaElement {
+	group := schemaElement{
+		name:          n.name,
+		physicalType:  -1,
+		repetition:    repetition,
+		numChildren:   int32(len(n.children)),
+		convertedType: -1,
+	}
+	switch n.kind {
+	case kindPrimitive:
+		return append(elements, schemaElement{
+			name:          n.name,
+			physicalType:  n.typ.physicalType(),
+			repetition:    repetition,
+			convertedType: n.typ.convertedType(),
+		})
+	case kindStruct:
+		elements = append(elements, group)
+		for _, child := range n.children {
+			elements = schemaElements(elements, child, repetitionOptional)
+		}
+		return elements
+	case kindList:
+		group.numChildren = 1
+		group.convertedType = convertedList
+		elements = append(elements, group, schemaElement{
+			name:          "list",
+			physicalType:  -1,
+			repetition:    repetitionRepeated,
+			numChildren:   1,
+			convertedType: -1,
+		})
+		return schemaElements(elements, n.children[0], repetitionOptional)
+	case kindMap:
+		group.numChildren = 1
+		group.convertedType = convertedMap
+		elements = append(elements, group, schemaElement{
+			name:          "key_value",
+			physicalType:  -1,
+			repetition:    repetitionRepeated,
+			numChildren:   2,
+			convertedType: -1,
+		})
+		elements = schemaElements(elements, n.children[0], repetitionRequired)
+		return schemaElements(elements, n.children[1], repetitionOptional)
+	default:
+		panic("invalid node kind")
+	}
+}
+
+// appendLevels appends levels using the RLE/bit-packing hybrid encoding prefixed by the encoded length.
+// We only use RLE runs which is valid, if not optimal, and keeps the encoder simple.
+func appendLevels(buf []byte, levels []uint8, maxLevel int) []byte {
+	start := len(buf)
+	buf = append(buf, 0, 0, 0, 0) // length placeholder
+	bitWidth := 0
+	for maxLevel>>bitWidth > 0 {
+		bitWidth++
+	}
+	byteWidth := (bitWidth + 7) / 8
+	for i := 0; i < len(levels); {
+		value := levels[i]
+		j := i + 1
+		for j < len(levels) && levels[j] == value {
+			j++
+		}
+		buf = appendUvarint(buf, uint64(j-i)<<1)
+		for b := 0; b < byteWidth; b++ {
+			buf = append(buf, value) // levels never exceed 255 so only the first byte is non-zero
+			value = 0
+		}
+		i = j
+	}
+	binary.LittleEndian.PutUint32(buf[start:], uint32(len(buf)-start-4))
+	return buf
+}
+
+// column buffers the levels and PLAIN encoded values of a leaf column
+type column struct {
+	node      *node
+	defLevels []uint8
+	repLevels []uint8
+	values    []byte
+	numBools  int
+	// marks to discard a partially written row
+	markLevels int
+	markValues int
+	markBools  int
+}
+
+func (c *column) mark() {
+	c.markLevels, c.markValues, c.markBools = len(c.defLevels), len(c.values), c.numBools
+}
+
+func (c *column) reset() {
+	c.defLevels = c.defLevels[:c.markLevels]
+	c.repLevels = c.repLevels[:c.markLevels]
+	c.values = c.values[:c.markValues]
+	c.numBools = c.markBools
+	if n := c.numBools % 8; n != 0 {
+		c.values[len(c.values)-1] &= 1<<n - 1 // clear bits of discarded values
+	}
+}
+
+func (c *column) clear() {
+	c.defLevels = c.defLevels[:0]
+	c.repLevels = c.repLevels[:0]
+	c.values = c.values[:0]
+	c.numBools = 0
+}
+
+func (c *column) appendLevels(rep, def int) {
+	c.repLevels = append(c.repLevels, uint8(rep))
+	c.defLevels = append(c.defLevels, uint8(def))
+}
+
+func (c *column) appendBool(b bool) {
+	if c.numBools%8 == 0 {
+		c.values = append(c.values, 0)
+	}
+	if b {
+		c.values[len(c.values)-1] |= 1 << (c.numBools % 8)
+	}
+	c.numBools++
+}
+
+func (c *column) appendInt32(v int32) {
+	c.values = append(c.values, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(c.values[len(c.values)-4:], uint32(v))
+}
+
+func (c *column) appendInt64(v int64) {
+	c.values = append(c.values, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint64(c.values[len(c.values)-8:], uint64(v))
+}
+
+func (c *column) appendFloat(v float32) {
+	c.values = append(c.values, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(c.values[len(c.values)-4:], math.Float32bits(v))
+}
+
+func (c *column) appendDouble(v float64) {
+	c.values = append(c.values, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint64(c.values[len(c.values)-8:], math.Float64bits(v))
+}
+
+func (c *column) appendBytes(b []byte) {
+	c.values = append(c.values, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(c.values[len(c.values)-4:], uint32(len(b)))
+	c.values = append(c.values, b...)
+}
+
+func (c *column) appendString(s string) {
+	c.values = append(c.values, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(c.values[len(c.values)-4:], uint32(len(s)))
+	c.values = append(c.values, s...)
+}
+
+// appendInt96 appends a timestamp as nanoseconds of the day and Julian day
+func (c *column) appendInt96(nanos uint64, julianDay uint32) {
+	c.values = append(c.values, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)
+	buf := c.values[len(c.values)-12:]
+	binary.LittleEndian.PutUint64(buf, nanos)
+	binary.LittleEndian.PutUint32(buf[8:], julianDay)
+}
@@ -0,0 +1,145 @@
+package parquet
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/awsglue"
+)
+
+func TestWriterLevels(t *testing.T) {
+	schema, err := NewSchema([]awsglue.Column{
+		{Name: "s", Type: "string"},
+		{Name: "i", Type: "int"},
+		{Name: "arr", Type: "array<string>"},
+		{Name: "m", Type: "map<string,bigint>"},
+	})
+	require.NoError(t, err)
+	w := NewWriter(&bytes.Buffer{}, schema)
+	for _, row := range []string{
+		`{"s":"foo","i":1,"arr":["a","b"],"m":{"x":1,"y":2}}`,
+		`{"s":{"raw":true},"i":"42","arr":[],"m":{}}`,
+		`{"unknown":"field","i":"NaN","arr":[null]}`,
+	} {
+		require.NoError(t, w.WriteJSON([]byte(row)))
+	}
+	assert.Equal(t, int64(3), w.NumRows())
+
+	type levels struct {
+		Def []uint8
+		Rep []uint8
+	}
+	expect := []levels{
+		{Def: []uint8{1, 1, 0}, Rep: []uint8{0, 0, 0}},       // s
+		{Def: []uint8{1, 1, 0}, Rep: []uint8{0, 0, 0}},       // i
+		{Def: []uint8{3, 3, 1, 2}, Rep: []uint8{0, 1, 0, 0}}, // arr.list.element
+		{Def: []uint8{2, 2, 1, 0}, Rep: []uint8{0, 1, 0, 0}}, // m.key_value.key
+		{Def: []uint8{3, 3, 1, 0}, Rep: []uint8{0, 1, 0, 0}}, // m.key_value.value
+	}
+	for i, col := range w.columns {
+		assert.Equal(t, expect[i], levels{Def: col.defLevels, Rep: col.repLevels}, col.node.path)
+	}
+
+	// non-string values are stored as JSON text in string columns
+	s := w.columns[0].values
+	assert.Equal(t, "foo", string(s[4:7]))
+	assert.Equal(t, `{"raw":true}`, string(s[11:]))
+	// numeric strings are parsed
+	assert.Equal(t, uint32(42), binary.LittleEndian.Uint32(w.columns[1].values[4:]))
+}
+
+func TestWriterInvalidJSON(t *testing.T) {
+	schema, err := NewSchema([]awsglue.Column{
+		{Name: "ok", Type: "boolean"},
+		{Name: "arr", Type: "array<string>"},
+	})
+	require.NoError(t, err)
+	w := NewWriter(&bytes.Buffer{}, schema)
+	require.NoError(t, w.WriteJSON([]byte(`{"ok":true}`)))
+	require.Error(t, w.WriteJSON([]byte(`{"ok":true,"arr":["a",`)))
+	require.Error(t, w.WriteJSON([]byte(`[]`)))
+	assert.Equal(t, int64(1), w.NumRows())
+	// the partially written row is discarded
+	assert.Equal(t, []uint8{1}, w.columns[0].defLevels)
+	assert.Equal(t, []byte{1}, w.columns[0].values)
+	assert.Equal(t, []uint8{0}, w.columns[1].defLevels)
+	assert.Empty(t, w.columns[1].values)
+}
+
+// TestWriterGoldenFile compares the written file to a file that was read back with an independent
+// Parquet implementation (github.com/xitongsys/parquet-go). Changes to the encoding must be checked the
+// same way before the file in testdata is replaced.
+func TestWriterGoldenFile(t *testing.T) {
+	schema, err := NewSchema([]awsglue.Column{
+		{Name: "s", Type: "string"},
+		{Name: "b", Type: "boolean"},
+		{Name: "ti", Type: "tinyint"},
+		{Name: "i", Type: "int"},
+		{Name: "big", Type: "bigint"},
+		{Name: "f", Type: "float"},
+		{Name: "d", Type: "double"},
+		{Name: "ts", Type: "timestamp"},
+		{Name: "st", Type: "struct<name:string,tags:array<string>>"},
+		{Name: "arr", Type: "array<string>"},
+		{Name: "m", Type: "map<string,bigint>"},
+	})
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	w := NewWriter(&buf, schema)
+	w.RowGroupSize = 1 // write a row group for each row
+	for _, row := range []string{
+		`{"s":"foo","b":true,"ti":-3,"i":1,"big":1234567890123,"f":1.5,"d":2.25,"ts":"2020-01-01 10:00:00.000001000",` +
+			`"st":{"name":"x","tags":["a","b"]},"arr":["a","b"],"m":{"x":1,"y":2}}`,
+		`{"s":"bar","ts":"2020-01-01T10:00:00Z","arr":[],"m":{}}`,
+		`{"arr":[null],"st":{}}`,
+	} {
+		require.NoError(t, w.WriteJSON([]byte(row)))
+	}
+	require.NoError(t, w.Close())
+	require.Error(t, w.WriteJSON([]byte(`{}`)))
+	assert.Len(t, w.rowGroups, 3)
+
+	expect, err := ioutil.ReadFile("testdata/golden.parquet")
+	require.NoError(t, err)
+	require.Equal(t, expect, buf.Bytes())
+}
+
+func TestAppendTimestamp(t *testing.T) {
+	c := column{}
+	c.appendTimestamp(time.Date(1970, 1, 1, 0, 0, 0, 1, time.UTC))
+	c.appendTimestamp(time.Date(1969, 12, 31, 23, 0, 0, 0, time.UTC))
+	assert.Equal(t, uint64(1), binary.LittleEndian.Uint64(c.values[0:]))
+	assert.Equal(t, uint32(julianDayOfEpoch), binary.LittleEndian.Uint32(c.values[8:]))
+	assert.Equal(t, uint64(23*time.Hour), binary.LittleEndian.Uint64(c.values[12:]))
+	assert.Equal(t, uint32(julianDayOfEpoch-1), binary.LittleEndian.Uint32(c.values[20:]))
+}
+
+func TestAppendLevels(t *testing.T) {
+	buf := appendLevels(nil, []uint8{1, 1, 1, 0, 2}, 2)
+	// 4 byte length followed by RLE runs of (count << 1, value)
+	assert.Equal(t, []byte{6, 0, 0, 0, 6, 1, 2, 0, 2, 2}, buf)
+}
@@ -0,0 +1,58 @@
+package encryption
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+// dataKeySpec is the spec of generated data keys (256-bit)
+const dataKeySpec = kms.DataKeySpecAes256
+
+// GenerateDataKey uses KMS to generate a new data key.
+//
+// The plaintext key should only be kept in memory, the ciphertext can be stored and decrypted with DecryptDataKey.
+func (key *Key) GenerateDataKey() (plaintext, ciphertext []byte, err error) {
+	response, err := key.client.GenerateDataKey(&kms.GenerateDataKeyInput{
+		KeyId:   key.ID,
+		KeySpec: aws.String(dataKeySpec),
+	})
+	if err != nil {
+		return nil, nil, &genericapi.AWSError{Method: "kms.GenerateDataKey", Err: err}
+	}
+	return response.Plaintext, response.CiphertextBlob, nil
+}
+
+// DecryptDataKey uses KMS to decrypt a data key generated by GenerateDataKey.
+//
+// If the key has no ID, KMS resolves the key from the ciphertext metadata.
+func (key *Key) DecryptDataKey(ciphertext []byte) ([]byte, error) {
+	input := &kms.DecryptInput{CiphertextBlob: ciphertext}
+	if aws.StringValue(key.ID) != "" {
+		input.KeyId = key.ID
+	}
+	response, err := key.client.Decrypt(input)
+	if err != nil {
+		return nil, &genericapi.AWSError{Method: "kms.Decrypt", Err: err}
+	}
+	return response.Plaintext, nil
+}
@@ -0,0 +1,84 @@
+package encryption
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+type mockDataKeyClient struct {
+	kmsiface.KMSAPI
+	err bool
+}
+
+func (m *mockDataKeyClient) GenerateDataKey(input *kms.GenerateDataKeyInput) (*kms.GenerateDataKeyOutput, error) {
+	if m.err {
+		return nil, errors.New("internal error")
+	}
+	return &kms.GenerateDataKeyOutput{
+		KeyId:          input.KeyId,
+		Plaintext:      []byte("plaintext"),
+		CiphertextBlob: []byte("ciphertext"),
+	}, nil
+}
+
+func (m *mockDataKeyClient) Decrypt(input *kms.DecryptInput) (*kms.DecryptOutput, error) {
+	if m.err {
+		return nil, errors.New("internal error")
+	}
+	return &kms.DecryptOutput{Plaintext: []byte("plaintext")}, nil
+}
+
+func TestGenerateDataKey(t *testing.T) {
+	key := &Key{ID: aws.String("key-id"), client: &mockDataKeyClient{}}
+	plaintext, ciphertext, err := key.GenerateDataKey()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("plaintext"), plaintext)
+	assert.Equal(t, []byte("ciphertext"), ciphertext)
+}
+
+func TestGenerateDataKeyServiceError(t *testing.T) {
+	key := &Key{ID: aws.String("key-id"), client: &mockDataKeyClient{err: true}}
+	plaintext, ciphertext, err := key.GenerateDataKey()
+	assert.Nil(t, plaintext)
+	assert.Nil(t, ciphertext)
+	assert.NotNil(t, err.(*genericapi.AWSError))
+}
+
+func TestDecryptDataKey(t *testing.T) {
+	key := &Key{ID: aws.String("key-id"), client: &mockDataKeyClient{}}
+	plaintext, err := key.DecryptDataKey([]byte("ciphertext"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("plaintext"), plaintext)
+}
+
+func TestDecryptDataKeyServiceError(t *testing.T) {
+	key := &Key{ID: aws.String("key-id"), client: &mockDataKeyClient{err: true}}
+	plaintext, err := key.DecryptDataKey([]byte("ciphertext"))
+	assert.Nil(t, plaintext)
+	assert.NotNil(t, err.(*genericapi.AWSError))
+}
@@ -32,6 +32,12 @@ type API interface {
 	EncryptConfig(interface{}) ([]byte, error)
 }
 
+// DataKeyAPI defines the interface for data keys which can be used for mocking.
+type DataKeyAPI interface {
+	GenerateDataKey() (plaintext, ciphertext []byte, err error)
+	DecryptDataKey(ciphertext []byte) ([]byte, error)
+}
+
 // Key encapsulates a connection to the KMS encryption key.
 type Key struct {
 	ID     *string
@@ -40,6 +46,7 @@ type Key struct {
 
 // The EncryptionKey must satisfy the API interface.
 var _ API = (*Key)(nil)
+var _ DataKeyAPI = (*Key)(nil)
 
 // New creates AWS clients to interface with the encryption key.
 func New(ID string, sess *session.Session) *Key {
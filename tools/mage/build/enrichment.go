@@ -0,0 +1,65 @@
+package build
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/tools/mage/util"
+)
+
+// The log processor loads enrichment data from the "enrichment" directory next to its binary
+var enrichmentTargetDir = filepath.Join("out", "bin", "internal", "log_analysis", "log_processor", "main", "enrichment")
+
+// Copy GeoIP databases (*.mmdb) and threat intel lists (*.csv) from srcDir into the log processor package.
+//
+// Enrichment data from previous builds is always removed, so an empty srcDir disables enrichment.
+func EnrichmentData(log *zap.SugaredLogger, srcDir string) error {
+	if err := os.RemoveAll(enrichmentTargetDir); err != nil {
+		return fmt.Errorf("failed to remove enrichment directory %s: %v", enrichmentTargetDir, err)
+	}
+	if srcDir == "" {
+		return nil
+	}
+
+	files, err := ioutil.ReadDir(srcDir)
+	if err != nil {
+		return fmt.Errorf("failed to read enrichment data directory %s: %v", srcDir, err)
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(file.Name())) {
+		case ".mmdb", ".csv":
+		default:
+			continue
+		}
+		log.Infof("packaging enrichment data %s", file.Name())
+		data := util.MustReadFile(filepath.Join(srcDir, file.Name()))
+		util.MustWriteFile(filepath.Join(enrichmentTargetDir, file.Name()), data)
+	}
+	return nil
+}
@@ -34,16 +34,19 @@ type PantherConfig struct {
 }
 
 type Infra struct {
-	BaseLayerVersionArns          string   `yaml:"BaseLayerVersionArns"`
-	LoadBalancerSecurityGroupCidr string   `yaml:"LoadBalancerSecurityGroupCidr"`
-	LogProcessorLambdaMemorySize  int      `yaml:"LogProcessorLambdaMemorySize"`
-	LogProcessorSQSDelaySeconds   int      `yaml:"LogProcessorSQSDelaySeconds"`
-	PipLayer                      []string `yaml:"PipLayer"`
-	PythonLayerVersionArn         string   `yaml:"PythonLayerVersionArn"`
-	SecurityGroupID               string   `yaml:"SecurityGroupID"`
-	SubnetOneIPRange              string   `yaml:"SubnetOneIPRange"`
-	SubnetTwoIPRange              string   `yaml:"SubnetTwoIPRange"`
-	VpcID                         string   `yaml:"VpcID"`
+	BaseLayerVersionArns           string   `yaml:"BaseLayerVersionArns"`
+	EnrichmentDataPath             string   `yaml:"EnrichmentDataPath"`
+	LoadBalancerSecurityGroupCidr  string   `yaml:"LoadBalancerSecurityGroupCidr"`
+	LogProcessorDedupLogTypes      []string `yaml:"LogProcessorDedupLogTypes"`
+	LogProcessorDedupWindowMinutes int      `yaml:"LogProcessorDedupWindowMinutes"`
+	LogProcessorLambdaMemorySize   int      `yaml:"LogProcessorLambdaMemorySize"`
+	LogProcessorSQSDelaySeconds    int      `yaml:"LogProcessorSQSDelaySeconds"`
+	PipLayer                       []string `yaml:"PipLayer"`
+	PythonLayerVersionArn          string   `yaml:"PythonLayerVersionArn"`
+	SecurityGroupID                string   `yaml:"SecurityGroupID"`
+	SubnetOneIPRange               string   `yaml:"SubnetOneIPRange"`
+	SubnetTwoIPRange               string   `yaml:"SubnetTwoIPRange"`
+	VpcID                          string   `yaml:"VpcID"`
 }
 
 type Monitoring struct {
@@ -472,6 +472,7 @@ func deployBootstrapGatewayStack(
 		"ProcessedDataBucket":        outputs["ProcessedDataBucket"],
 		"PythonLayerVersionArn":      settings.Infra.PythonLayerVersionArn,
 		"TracingMode":                settings.Monitoring.TracingMode,
+		"TransformsKeyId":            outputs["TransformsEncryptionKeyId"],
 		"UserPoolId":                 outputs["UserPoolId"],
 	})
 }
@@ -529,6 +530,7 @@ func deployCoreStack(settings *PantherConfig, outputs map[string]string) error {
 		"OutputsKeyId":               outputs["OutputsEncryptionKeyId"],
 		"SqsKeyId":                   outputs["QueueEncryptionKeyId"],
 		"TracingMode":                settings.Monitoring.TracingMode,
+		"TransformsKeyId":            outputs["TransformsEncryptionKeyId"],
 		"UserPoolId":                 outputs["UserPoolId"],
 	})
 	return err
@@ -540,6 +542,15 @@ func deployDashboardStack(bucket string) error {
 }
 
 func deployLogAnalysisStack(settings *PantherConfig, outputs map[string]string) error {
+	if err := build.EnrichmentData(log, settings.Infra.EnrichmentDataPath); err != nil {
+		return err
+	}
+
+	// fail early if the updated log schemas cannot read the data of the deployed tables
+	if err := validateGlueSchemas(); err != nil {
+		return err
+	}
+
 	// this computes a signature of the deployed glue tables used for change detection, for CF use the Panther version
 	tablesSignature, err := gluetables.DeployedTablesSignature(clients.Glue())
 	if err != nil {
@@ -547,27 +558,48 @@ func deployLogAnalysisStack(settings *PantherConfig, outputs map[string]string)
 	}
 
 	_, err = deployTemplate(cfnstacks.LogAnalysisTemplate, outputs["SourceBucket"], cfnstacks.LogAnalysis, map[string]string{
-		"AlarmTopicArn":                outputs["AlarmTopicArn"],
-		"AnalysisApiId":                outputs["AnalysisApiId"],
-		"AthenaResultsBucket":          outputs["AthenaResultsBucket"],
-		"CloudWatchLogRetentionDays":   strconv.Itoa(settings.Monitoring.CloudWatchLogRetentionDays),
-		"CustomResourceVersion":        customResourceVersion(),
-		"Debug":                        strconv.FormatBool(settings.Monitoring.Debug),
-		"InputDataBucket":              outputs["InputDataBucket"],
-		"InputDataTopicArn":            outputs["InputDataTopicArn"],
-		"LayerVersionArns":             settings.Infra.BaseLayerVersionArns,
-		"LogProcessorLambdaMemorySize": strconv.Itoa(settings.Infra.LogProcessorLambdaMemorySize),
-		"LogProcessorSQSDelaySeconds":  strconv.Itoa(settings.Infra.LogProcessorSQSDelaySeconds),
-		"ProcessedDataBucket":          outputs["ProcessedDataBucket"],
-		"ProcessedDataTopicArn":        outputs["ProcessedDataTopicArn"],
-		"PythonLayerVersionArn":        outputs["PythonLayerVersionArn"],
-		"SqsKeyId":                     outputs["QueueEncryptionKeyId"],
-		"TablesSignature":              tablesSignature,
-		"TracingMode":                  settings.Monitoring.TracingMode,
+		"AlarmTopicArn":                  outputs["AlarmTopicArn"],
+		"AnalysisApiId":                  outputs["AnalysisApiId"],
+		"AthenaResultsBucket":            outputs["AthenaResultsBucket"],
+		"CloudWatchLogRetentionDays":     strconv.Itoa(settings.Monitoring.CloudWatchLogRetentionDays),
+		"CustomResourceVersion":          customResourceVersion(),
+		"Debug":                          strconv.FormatBool(settings.Monitoring.Debug),
+		"InputDataBucket":                outputs["InputDataBucket"],
+		"InputDataTopicArn":              outputs["InputDataTopicArn"],
+		"LayerVersionArns":               settings.Infra.BaseLayerVersionArns,
+		"LogProcessorDedupLogTypes":      strings.Join(settings.Infra.LogProcessorDedupLogTypes, ","),
+		"LogProcessorDedupWindowMinutes": strconv.Itoa(settings.Infra.LogProcessorDedupWindowMinutes),
+		"LogProcessorLambdaMemorySize":   strconv.Itoa(settings.Infra.LogProcessorLambdaMemorySize),
+		"LogProcessorSQSDelaySeconds":    strconv.Itoa(settings.Infra.LogProcessorSQSDelaySeconds),
+		"ProcessedDataBucket":            outputs["ProcessedDataBucket"],
+		"ProcessedDataTopicArn":          outputs["ProcessedDataTopicArn"],
+		"PythonLayerVersionArn":          outputs["PythonLayerVersionArn"],
+		"SqsKeyId":                       outputs["QueueEncryptionKeyId"],
+		"TablesSignature":                tablesSignature,
+		"TracingMode":                    settings.Monitoring.TracingMode,
+		"TransformsKeyId":                outputs["TransformsEncryptionKeyId"],
 	})
 	return err
 }
 
+// validateGlueSchemas reports schema changes of deployed glue tables and fails on incompatible changes
+func validateGlueSchemas() error {
+	deployedLogTables, err := gluetables.DeployedLogTables(clients.Glue())
+	if err != nil {
+		return err
+	}
+	diffs, err := gluetables.DiffDeployedTables(clients.Glue(), gluetables.ExpandLogTables(deployedLogTables...)...)
+	if err != nil {
+		return err
+	}
+	for _, diff := range diffs {
+		if !diff.IsEmpty() && diff.IsSafe() {
+			log.Infof("schema changes %s", diff.Report())
+		}
+	}
+	return gluetables.CheckSchemaDiffs(diffs...)
+}
+
 func deployOnboardStack(settings *PantherConfig, outputs map[string]string) error {
 	var err error
 	if settings.Setup.OnboardSelf {